@@ -1,16 +1,34 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/Kantha2004/SimpleJWT/internal/api"
 	"github.com/Kantha2004/SimpleJWT/internal/api/handlers"
 	"github.com/Kantha2004/SimpleJWT/internal/auth"
 	"github.com/Kantha2004/SimpleJWT/internal/config"
+	"github.com/Kantha2004/SimpleJWT/internal/console"
 	"github.com/Kantha2004/SimpleJWT/internal/db"
+	"github.com/Kantha2004/SimpleJWT/internal/grpcapi"
+	"github.com/Kantha2004/SimpleJWT/internal/logging"
+	"github.com/Kantha2004/SimpleJWT/internal/metrics"
+	"github.com/Kantha2004/SimpleJWT/internal/service"
+	"github.com/Kantha2004/SimpleJWT/internal/tracing"
+	"github.com/Kantha2004/SimpleJWT/internal/webhooks"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"google.golang.org/grpc"
 
 	// Updated Swagger imports
 	swaggerFiles "github.com/swaggo/files" // This replaces swaggerFiles
@@ -20,6 +38,9 @@ import (
 	_ "github.com/Kantha2004/SimpleJWT/docs"
 )
 
+// readHeaderTimeout limits how long a client may take to send request headers
+const readHeaderTimeout = 10 * time.Second
+
 // @title SimpleJWT API
 // @version 1.0
 // @description A simple JWT authentication service
@@ -33,7 +54,7 @@ import (
 // @license.url http://www.apache.org/licenses/LICENSE-2.0.html
 
 // @host localhost:9000
-// @BasePath /api/v1
+// @BasePath /api
 // @schemes http https
 
 // @securityDefinitions.apikey BearerAuth
@@ -44,42 +65,169 @@ import (
 func main() {
 	_ = godotenv.Load()
 
-	loadConfig := config.LoadConfig()
+	flags, err := config.ParseFlags(os.Args[0], os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	loadConfig, err := config.Load(flags)
+	if flags.PrintConfig && loadConfig != nil {
+		if err := loadConfig.Print(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	if flags.PrintConfig {
+		return
+	}
+
+	// Packages without an injected logger, and the standard log package,
+	// write through the default logger
+	logger := logging.New(os.Stdout, loadConfig.Logging)
+	slog.SetDefault(logger)
+
+	shutdownTracing, err := tracing.Setup(context.Background(), loadConfig.Tracing)
+	if err != nil {
+		fatal(logger, "Failed to set up tracing", err)
+	}
 
 	database, err := db.NewDatabase(&loadConfig.DBConfig)
 	if err != nil {
-		log.Fatal("Error while connecting to DB", err.Error())
+		fatal(logger, "Error while connecting to DB", err)
+	}
+	sqlDB, err := database.DB.DB()
+	if err != nil {
+		fatal(logger, "Failed to access DB connection pool", err)
+	}
+	if err := metrics.RegisterDBStats(sqlDB, database.Driver); err != nil {
+		fatal(logger, "Failed to register DB pool metrics", err)
 	}
-	defer func(database *db.Database) {
-		err := database.Close()
-		if err != nil {
-			log.Fatal("Failed to close DB connection")
-		}
-	}(database)
 
 	// Set Gin to release mode in production
 	if loadConfig.Environment == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
+	gin.DebugPrintFunc = func(format string, values ...any) {
+		logger.Debug(strings.TrimSpace(fmt.Sprintf(format, values...)))
+	}
+	gin.DebugPrintRouteFunc = func(method, path, handler string, _ int) {
+		logger.Debug("Route registered", "method", method, "path", path, "handler", handler)
+	}
 
 	// Get port from loadConfig
 	port := ":" + loadConfig.Port
 
-	jwtService := auth.NewJWTService(loadConfig.JWTSecret)
+	var signingKeys *auth.KeySet
+	if loadConfig.JWTKeysDir != "" {
+		signingKeys, err = auth.LoadSigningKeys(loadConfig.JWTKeysDir)
+		if err != nil {
+			fatal(logger, "Failed to load signing keys", err)
+		}
+		logger.Info("Signing tokens with key", "kid", signingKeys.Active().ID)
+	}
+
+	jwtService := auth.NewJWTService(loadConfig.JWTSecret, signingKeys, loadConfig.JWTIssuer, loadConfig.JWTAudience)
 
 	// Add nil check
 	if jwtService == nil {
-		log.Fatal("Failed to create JWT service")
+		fatal(logger, "Failed to create JWT service", nil)
 	}
 
-	deps := api.NewDependencies(jwtService)
-	handlerDeps := handlers.NewDependencies(database, jwtService)
+	var breachedPasswords *auth.BreachedPasswordList
+	if loadConfig.BreachedPasswordsFile != "" {
+		breachedPasswords, err = auth.LoadBreachedPasswords(loadConfig.BreachedPasswordsFile)
+		if err != nil {
+			fatal(logger, "Failed to load breached passwords list", err)
+		}
+		logger.Info("Loaded breached password list", "entries", breachedPasswords.Len())
+	}
+
+	passwordValidator := auth.NewPasswordValidator(loadConfig.PasswordPolicy, breachedPasswords)
+
+	hasher, err := newPasswordHasher(loadConfig.PasswordHashing)
+	if err != nil {
+		fatal(logger, "Failed to create password hasher", err)
+	}
+	passwordHasher := auth.NewReloadablePasswordHasher(hasher)
 
-	router := gin.Default()
+	webhookConfig := loadConfig.Webhooks
+	webhookDispatcher := webhooks.NewDispatcher(database, webhooks.Config{
+		PollInterval:          webhookConfig.PollInterval,
+		Timeout:               webhookConfig.Timeout,
+		MaxAttempts:           webhookConfig.MaxAttempts,
+		InitialBackoff:        webhookConfig.InitialBackoff,
+		MaxBackoff:            webhookConfig.MaxBackoff,
+		AllowPrivateAddresses: webhookConfig.AllowPrivateAddresses,
+	}, logger)
 
-	api.SetupGinRoutes(router, deps, handlerDeps)
+	// Deliver queued webhooks in the background until shutdown
+	webhookCtx, stopWebhooks := context.WithCancel(context.Background())
+	webhooksDone := make(chan struct{})
+	go func() {
+		defer close(webhooksDone)
+		webhookDispatcher.Run(webhookCtx)
+	}()
 
-	fmt.Printf("SimpleJWT server starting on port %s\n", port)
+	// The HTTP and gRPC APIs share the repositories and the login service
+	users := db.NewUserRepository(database)
+	clients := db.NewClientRepository(database)
+	clientUsers := db.NewClientUserRepositoryFactory(database)
+	roles := db.NewRoleRepositoryFactory(database)
+	sessions := db.NewSessionRepository(database)
+	authService := service.NewAuthService(database, users, clients, clientUsers, roles, sessions, jwtService, passwordHasher, webhookDispatcher, logger)
+
+	healthChecker := api.NewHealthChecker(database, jwtService, logger)
+	idempotencyKeys := db.NewIdempotencyRepository(database)
+	deps := api.NewDependencies(jwtService, sessions, idempotencyKeys, loadConfig.IdempotencyWindow, healthChecker, logger)
+	deps.CORS.Reload(loadConfig.CORS.AllowedOrigins)
+	deps.RateLimiter.Reload(loadConfig.RateLimit.RequestsPerSecond, loadConfig.RateLimit.Burst)
+
+	// Reload the configuration and key material on SIGHUP until shutdown
+	reloads := &reloader{
+		flags:             flags,
+		logger:            logger,
+		jwtService:        jwtService,
+		passwordValidator: passwordValidator,
+		passwordHasher:    passwordHasher,
+		cors:              deps.CORS,
+		rateLimiter:       deps.RateLimiter,
+		current:           loadConfig,
+	}
+	reloadCtx, stopReloads := context.WithCancel(context.Background())
+	defer stopReloads()
+	go reloads.run(reloadCtx)
+
+	// Delete idempotency keys past their window until shutdown
+	purgeCtx, stopPurge := context.WithCancel(context.Background())
+	defer stopPurge()
+	go api.PurgeIdempotencyKeys(purgeCtx, idempotencyKeys, logger)
+	handlerDeps := handlers.NewDependencies(database, users, clients, clientUsers, roles, jwtService, passwordValidator, passwordHasher, authService, logger)
+	handlerDeps.SecureCookies = loadConfig.Environment == config.Production
+
+	// Request logging and panic recovery are set up by SetupGinRoutes
+	router := gin.New()
+
+	// Client addresses recorded in sessions and audit events come from
+	// X-Forwarded-For only when a trusted proxy set it
+	if err := router.SetTrustedProxies(loadConfig.TrustedProxies); err != nil {
+		fatal(logger, "Invalid trusted proxies", err)
+	}
+
+	api.SetupGinRoutes(router, loadConfig.Tracing.ServiceName, loadConfig.V1Deprecation, loadConfig.V1Sunset, deps, handlerDeps)
+
+	if loadConfig.ConsoleEnabled {
+		if err := api.SetupConsoleRoutes(router, deps, handlerDeps); err != nil {
+			fatal(logger, "Failed to set up the web console", err)
+		}
+		logger.Info("Web console available", "path", console.BasePath+"/")
+	}
+
+	logger.Info("SimpleJWT server starting", "port", loadConfig.Port, "environment", loadConfig.Environment)
 
 	url := ginSwagger.URL("http://localhost:9000/swagger/doc.json")
 
@@ -90,9 +238,86 @@ func main() {
 			swaggerFiles.Handler, url,
 			ginSwagger.PersistAuthorization(true),
 		))
-		fmt.Printf("Swagger documentation available at: http://localhost%s/swagger/index.html\n", port)
+		logger.Info("Swagger documentation available", "url", "http://localhost"+port+"/swagger/index.html")
+	}
+
+	server := &http.Server{
+		Addr:              port,
+		Handler:           router,
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	// The gRPC API shares the token service and repositories of the HTTP API
+	var grpcServer *grpc.Server
+	if loadConfig.GRPCPort != "" {
+		grpcService := grpcapi.NewServer(users, clients, clientUsers, sessions, jwtService, authService, logger)
+		if err := grpcService.SetTrustedProxies(loadConfig.TrustedProxies); err != nil {
+			fatal(logger, "Invalid trusted proxies", err)
+		}
+		grpcServer = grpcapi.NewGRPCServer(grpcService, deps.RateLimiter, logger)
+		logger.Info("gRPC API enabled", "port", loadConfig.GRPCPort, "shared_with_http", loadConfig.GRPCPort == loadConfig.Port)
+	}
+
+	serverErr := make(chan error, 3)
+	if err := serve(server, grpcServer, loadConfig.Port, loadConfig.GRPCPort, serverErr); err != nil {
+		fatal(logger, "Failed to listen", err)
 	}
 
-	// Start server using Gin's Run method
-	log.Fatal(router.Run(port))
+	signals, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
+
+	var serveErr error
+	select {
+	case serveErr = <-serverErr:
+	case <-signals.Done():
+		// A second signal terminates immediately
+		stopSignals()
+		// Keep serving while the readiness probe fails, so load balancers
+		// stop routing here before the listeners close
+		healthChecker.SetDraining()
+		if loadConfig.DrainDelay > 0 {
+			logger.Info("Draining, waiting for load balancers to stop routing requests", "delay", loadConfig.DrainDelay.String())
+			time.Sleep(loadConfig.DrainDelay)
+		}
+
+		logger.Info("Shutting down, waiting for in-flight requests", "timeout", loadConfig.ShutdownTimeout.String())
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), loadConfig.ShutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			logger.Warn("Requests still running after the drain timeout were dropped", "error", err)
+		}
+		if grpcServer != nil && !stopGRPC(shutdownCtx, grpcServer) {
+			logger.Warn("gRPC calls still running after the drain timeout were dropped")
+		}
+	}
+
+	stopReloads()
+	stopWebhooks()
+	<-webhooksDone
+
+	// Flush the spans of the last requests
+	tracingCtx, cancelTracing := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelTracing()
+	if err := shutdownTracing(tracingCtx); err != nil {
+		logger.Warn("Failed to flush traces", "error", err)
+	}
+
+	if err := database.Close(); err != nil {
+		fatal(logger, "Failed to close DB connection", err)
+	}
+	if serveErr != nil {
+		fatal(logger, "Server failed", serveErr)
+	}
+	logger.Info("Server stopped")
+}
+
+// fatal logs the error and exits without running deferred functions, like
+// log.Fatal
+func fatal(logger *slog.Logger, msg string, err error) {
+	if err != nil {
+		logger.Error(msg, "error", err)
+	} else {
+		logger.Error(msg)
+	}
+	os.Exit(1)
 }
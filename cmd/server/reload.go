@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Kantha2004/SimpleJWT/internal/api"
+	"github.com/Kantha2004/SimpleJWT/internal/auth"
+	"github.com/Kantha2004/SimpleJWT/internal/config"
+	"github.com/Kantha2004/SimpleJWT/internal/logging"
+)
+
+// reloadablePrefixes are the settings applied without a restart; changes to
+// any other setting are logged and take effect on the next start
+var reloadablePrefixes = []string{
+	"jwt.",
+	"password_policy.",
+	"password_hashing.",
+	"cors.",
+	"rate_limit.",
+	"logging.level",
+}
+
+// reloader re-reads the configuration and key material and swaps the
+// components built from them. An invalid configuration or unreadable key is
+// logged and the running configuration is kept.
+type reloader struct {
+	flags             *config.Flags
+	logger            *slog.Logger
+	jwtService        *auth.JWTService
+	passwordValidator *auth.PasswordValidator
+	passwordHasher    *auth.ReloadablePasswordHasher
+	cors              *api.CORSPolicy
+	rateLimiter       *api.RateLimiter
+
+	mu      sync.Mutex
+	current *config.Config
+}
+
+// run reloads on SIGHUP, and when the watched files change if the reload
+// interval is set, until ctx is done
+func (r *reloader) run(ctx context.Context) {
+	hangups := make(chan os.Signal, 1)
+	signal.Notify(hangups, syscall.SIGHUP)
+	defer signal.Stop(hangups)
+
+	var changes <-chan time.Time
+	fingerprint := r.fingerprint()
+	if interval := r.current.ReloadInterval; interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		changes = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hangups:
+			r.reload("SIGHUP")
+			fingerprint = r.fingerprint()
+		case <-changes:
+			if next := r.fingerprint(); next != fingerprint {
+				fingerprint = next
+				r.reload("file change")
+			}
+		}
+	}
+}
+
+func (r *reloader) reload(trigger string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	logger := r.logger.With("trigger", trigger)
+
+	next, err := config.Load(r.flags)
+	if err != nil {
+		logger.Error("Configuration reload failed, keeping the running configuration", "error", err)
+		return
+	}
+
+	// Build everything before swapping anything, so a failure leaves the
+	// running components untouched
+	var keys *auth.KeySet
+	if next.JWTKeysDir != "" {
+		if keys, err = auth.LoadSigningKeys(next.JWTKeysDir); err != nil {
+			logger.Error("Signing key reload failed, keeping the running configuration", "error", err)
+			return
+		}
+	}
+
+	var breachedPasswords *auth.BreachedPasswordList
+	if next.BreachedPasswordsFile != "" {
+		if breachedPasswords, err = auth.LoadBreachedPasswords(next.BreachedPasswordsFile); err != nil {
+			logger.Error("Breached passwords list reload failed, keeping the running configuration", "error", err)
+			return
+		}
+	}
+
+	hasher, err := newPasswordHasher(next.PasswordHashing)
+	if err != nil {
+		logger.Error("Password hasher reload failed, keeping the running configuration", "error", err)
+		return
+	}
+
+	previousKey, _ := r.jwtService.SigningKeyID()
+
+	r.jwtService.Reload(next.JWTSecret, keys, next.JWTIssuer, next.JWTAudience)
+	r.passwordValidator.Reload(next.PasswordPolicy, breachedPasswords)
+	r.passwordHasher.Reload(hasher)
+	r.cors.Reload(next.CORS.AllowedOrigins)
+	r.rateLimiter.Reload(next.RateLimit.RequestsPerSecond, next.RateLimit.Burst)
+	logging.SetLevel(next.Logging.Level)
+
+	changes := config.Diff(r.current, next)
+	restartRequired := 0
+	for _, change := range changes {
+		if reloadable(change.Key) {
+			logger.Info("Setting changed", "key", change.Key, "old", change.Old, "new", change.New)
+		} else {
+			restartRequired++
+			logger.Warn("Setting changed, restart the server to apply it", "key", change.Key, "old", change.Old, "new", change.New)
+		}
+	}
+
+	signingKey, _ := r.jwtService.SigningKeyID()
+	if signingKey != previousKey {
+		logger.Info("Signing key changed", "old", previousKey, "new", signingKey)
+	}
+
+	r.current = next
+
+	logger.Info("Configuration reloaded", "changes", len(changes), "restart_required", restartRequired, "signing_key", signingKey)
+}
+
+func reloadable(key string) bool {
+	for _, prefix := range reloadablePrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// fingerprint summarizes the size and modification time of the config file
+// and of the signing key files
+func (r *reloader) fingerprint() string {
+	var b strings.Builder
+
+	paths := []string{r.current.File, r.current.BreachedPasswordsFile}
+	if r.current.JWTKeysDir != "" {
+		keyFiles, _ := filepath.Glob(filepath.Join(r.current.JWTKeysDir, "*"))
+		paths = append(paths, keyFiles...)
+	}
+
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		if info, err := os.Stat(path); err == nil {
+			fmt.Fprintf(&b, "%s:%d:%d;", path, info.Size(), info.ModTime().UnixNano())
+		} else {
+			fmt.Fprintf(&b, "%s:missing;", path)
+		}
+	}
+	return b.String()
+}
+
+// newPasswordHasher builds the hasher of the configured algorithm
+func newPasswordHasher(hashing config.PasswordHashingConfig) (auth.PasswordHasher, error) {
+	return auth.NewPasswordHasher(
+		hashing.Algorithm,
+		auth.Argon2Params{
+			Memory:      hashing.Argon2Memory,
+			Time:        hashing.Argon2Time,
+			Parallelism: hashing.Argon2Parallelism,
+		},
+		hashing.BcryptCost,
+	)
+}
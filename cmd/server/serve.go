@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"net"
+	"net/http"
+
+	"github.com/soheilhy/cmux"
+	"google.golang.org/grpc"
+)
+
+// serve starts the HTTP server, and the gRPC server when there is one, on
+// their ports. When both ports are the same, connections are told apart by
+// their first bytes. What the servers return is sent to errs.
+func serve(httpServer *http.Server, grpcServer *grpc.Server, httpPort, grpcPort string, errs chan<- error) error {
+	httpListener, err := net.Listen("tcp", ":"+httpPort)
+	if err != nil {
+		return err
+	}
+
+	if grpcServer != nil {
+		var grpcListener net.Listener
+		if grpcPort == httpPort {
+			mux := cmux.New(httpListener)
+			// gRPC clients wait for the server settings before sending
+			// their headers, so the matcher has to send them first
+			grpcListener = mux.MatchWithWriters(cmux.HTTP2MatchHeaderFieldSendSettings("content-type", "application/grpc"))
+			httpListener = mux.Match(cmux.Any())
+			go func() {
+				errs <- mux.Serve()
+			}()
+		} else {
+			grpcListener, err = net.Listen("tcp", ":"+grpcPort)
+			if err != nil {
+				httpListener.Close()
+				return err
+			}
+		}
+		go func() {
+			errs <- grpcServer.Serve(grpcListener)
+		}()
+	}
+
+	go func() {
+		errs <- httpServer.Serve(httpListener)
+	}()
+	return nil
+}
+
+// stopGRPC lets the calls in flight finish, and cancels those still running
+// when ctx is done
+func stopGRPC(ctx context.Context, grpcServer *grpc.Server) bool {
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return true
+	case <-ctx.Done():
+		grpcServer.Stop()
+		return false
+	}
+}
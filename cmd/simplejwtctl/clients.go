@@ -0,0 +1,280 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/Kantha2004/SimpleJWT/internal/db"
+	"github.com/Kantha2004/SimpleJWT/internal/models"
+)
+
+var clientCommands = map[string]command{
+	"list":    {"list clients, optionally of one owner", listClients},
+	"create":  {"create a client for an admin user and print its secret", createClient},
+	"suspend": {"suspend a client and revoke the sessions of its users", suspendClient},
+	"resume":  {"lift the suspension of a client", resumeClient},
+}
+
+// clientView is the JSON form of a client including its owner's username
+type clientView struct {
+	models.Client
+	Owner        string `json:"owner"`
+	ClientSecret string `json:"client_secret,omitempty"`
+}
+
+func listClients(a *app, args []string) error {
+	flags := a.newFlags("clients list")
+	owner := flags.String("owner", "", "only list clients of this admin username")
+	if err := a.parse(flags, args, 0); err != nil {
+		return err
+	}
+
+	database, err := a.db()
+	if err != nil {
+		return err
+	}
+
+	users := db.NewUserRepository(database)
+	clientRepo := db.NewClientRepository(database)
+
+	var clients []models.Client
+	if *owner != "" {
+		user, err := users.GetUserByUsername(*owner)
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			return fmt.Errorf("admin user %q not found", *owner)
+		}
+		clients, err = clientRepo.GetAllClientsByUserId(user.ID)
+		if err != nil {
+			return err
+		}
+	} else if clients, err = clientRepo.GetAllClients(); err != nil {
+		return err
+	}
+
+	views := make([]clientView, 0, len(clients))
+	owners := make(map[uint]string)
+	for _, client := range clients {
+		if _, ok := owners[client.UserID]; !ok {
+			user, err := users.GetUserByID(client.UserID)
+			if err != nil {
+				return err
+			}
+			if user != nil {
+				owners[client.UserID] = user.Username
+			}
+		}
+		client.ClientSecret = ""
+		views = append(views, clientView{Client: client, Owner: owners[client.UserID]})
+	}
+
+	return a.out.print(views, func() *table {
+		t := &table{headers: []string{"ID", "NAME", "OWNER", "SCHEMA", "STATUS", "CREATED"}}
+		for _, view := range views {
+			t.add(strconv.FormatUint(uint64(view.ID), 10), view.ClientName, view.Owner, view.SchemaName, clientStatus(&view.Client), formatTime(&view.CreatedAt))
+		}
+		return t
+	})
+}
+
+func createClient(a *app, args []string) error {
+	flags := a.newFlags("clients create")
+	owner := flags.String("owner", "", "username of the admin user owning the client")
+	name := flags.String("name", "", "client name")
+	if err := a.parse(flags, args, 0); err != nil {
+		return err
+	}
+	if err := requireFlag(flags, "owner", *owner != ""); err != nil {
+		return err
+	}
+	if err := requireFlag(flags, "name", *name != ""); err != nil {
+		return err
+	}
+
+	database, err := a.db()
+	if err != nil {
+		return err
+	}
+
+	user, err := db.NewUserRepository(database).GetUserByUsername(*owner)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return fmt.Errorf("admin user %q not found", *owner)
+	}
+
+	schemaName, err := db.ClientSchemaName(*name)
+	if err != nil {
+		return fmt.Errorf("client name must be 1 to %d lowercase letters, digits or underscores", db.MaxClientNameLength)
+	}
+
+	clientRepo := db.NewClientRepository(database)
+	if existing, err := clientRepo.GetClientByNameForUser(*name, user.ID); err != nil {
+		return err
+	} else if existing != nil {
+		return fmt.Errorf("client %q already exists", *name)
+	}
+
+	client := &models.Client{
+		ClientName: *name,
+		UserID:     user.ID,
+		SchemaName: schemaName,
+	}
+
+	secret, err := clientRepo.CreateClient(client)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	if err := database.CreateClientSchema(client.SchemaName); err != nil {
+		return fmt.Errorf("failed to create client schema: %w", err)
+	}
+	if err := database.MigrateTenant(client.SchemaName); err != nil {
+		return fmt.Errorf("failed to migrate client schema: %w", err)
+	}
+
+	a.audit(database, models.AuditEvent{
+		Action:      models.AuditActionClientCreate,
+		TargetType:  "client",
+		TargetID:    strconv.FormatUint(uint64(client.ID), 10),
+		ClientID:    &client.ID,
+		OwnerUserID: &client.UserID,
+	})
+
+	view := clientView{Client: *client, Owner: user.Username, ClientSecret: secret}
+	view.Client.ClientSecret = ""
+	return a.out.printFields(view, [][2]string{
+		{"ID", strconv.FormatUint(uint64(client.ID), 10)},
+		{"NAME", client.ClientName},
+		{"OWNER", user.Username},
+		{"SCHEMA", client.SchemaName},
+		{"SECRET", secret},
+	})
+}
+
+func suspendClient(a *app, args []string) error {
+	return setClientSuspended(a, "clients suspend", args, true)
+}
+
+func resumeClient(a *app, args []string) error {
+	return setClientSuspended(a, "clients resume", args, false)
+}
+
+func setClientSuspended(a *app, name string, args []string, suspend bool) error {
+	flags := a.newFlags(name)
+	id := flags.Uint("id", 0, "client ID")
+	reason := flags.String("reason", "", "reason recorded in the audit log")
+	if err := a.parse(flags, args, 0); err != nil {
+		return err
+	}
+	if err := requireFlag(flags, "id", *id != 0); err != nil {
+		return err
+	}
+
+	database, err := a.db()
+	if err != nil {
+		return err
+	}
+
+	client, err := getClient(database, *id)
+	if err != nil {
+		return err
+	}
+
+	clientRepo := db.NewClientRepository(database)
+	var revoked int64
+	if client.IsSuspended() != suspend {
+		action := models.AuditActionClientResume
+		client.SuspendedAt = nil
+		if suspend {
+			now := time.Now()
+			action = models.AuditActionClientSuspend
+			client.SuspendedAt = &now
+		}
+
+		if err := clientRepo.UpdateClient(client); err != nil {
+			return fmt.Errorf("failed to update client: %w", err)
+		}
+
+		if suspend {
+			if revoked, err = db.NewSessionRepository(database).RevokeClientSessions(client.ID); err != nil {
+				return fmt.Errorf("client suspended but revoking its sessions failed: %w", err)
+			}
+		}
+
+		a.audit(database, models.AuditEvent{
+			Action:      action,
+			TargetType:  "client",
+			TargetID:    strconv.FormatUint(uint64(client.ID), 10),
+			ClientID:    &client.ID,
+			OwnerUserID: &client.UserID,
+			Reason:      *reason,
+		})
+	}
+
+	client.ClientSecret = ""
+	result := struct {
+		*models.Client
+		RevokedSessions int64 `json:"revoked_sessions"`
+	}{client, revoked}
+
+	return a.out.printFields(result, [][2]string{
+		{"ID", strconv.FormatUint(uint64(client.ID), 10)},
+		{"NAME", client.ClientName},
+		{"STATUS", clientStatus(client)},
+		{"REVOKED SESSIONS", strconv.FormatInt(revoked, 10)},
+	})
+}
+
+func clientStatus(client *models.Client) string {
+	if client.IsSuspended() {
+		return "suspended"
+	}
+	return "active"
+}
+
+// audit appends an operator event to the audit log. Failures are reported
+// but do not fail the command, matching the server.
+func (a *app) audit(database *db.Database, event models.AuditEvent) {
+	event.OccurredAt = time.Now()
+	event.ActorType = models.AuditActorOperator
+	event.ActorName = operatorName()
+	event.UserAgent = "simplejwtctl"
+	if event.Outcome == "" {
+		event.Outcome = models.AuditOutcomeSuccess
+	}
+	if len(event.Reason) > 255 {
+		event.Reason = event.Reason[:255]
+	}
+
+	if err := db.NewAuditRepository(database).CreateAuditEvent(&event); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to record audit event %s: %v\n", event.Action, err)
+	}
+}
+
+// operatorName identifies the person running the command in the audit log
+func operatorName() string {
+	for _, key := range []string{"SUDO_USER", "USER", "USERNAME"} {
+		if name := os.Getenv(key); name != "" {
+			return name
+		}
+	}
+	return "unknown"
+}
+
+// getClient fetches a client by ID and fails when it does not exist
+func getClient(database *db.Database, id uint) (*models.Client, error) {
+	client, err := db.NewClientRepository(database).GetClientId(id)
+	if err != nil {
+		return nil, err
+	}
+	if client == nil {
+		return nil, fmt.Errorf("client %d not found", id)
+	}
+	return client, nil
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/Kantha2004/SimpleJWT/internal/auth"
+)
+
+var keyCommands = map[string]command{
+	"list":   {"list the signing keys and which one is active", listKeys},
+	"rotate": {"generate a new signing key that becomes the active key", rotateKeys},
+	"prune":  {"delete retired keys that no unexpired token can use anymore", pruneKeys},
+}
+
+// keyView is the JSON form of a signing key
+type keyView struct {
+	ID        string `json:"kid"`
+	Algorithm string `json:"alg"`
+	CreatedAt string `json:"created_at"`
+	Active    bool   `json:"active"`
+}
+
+func keysDirFlag(flags *flag.FlagSet) *string {
+	return flags.String("dir", os.Getenv("JWT_KEYS_DIR"), "signing keys directory (default $JWT_KEYS_DIR)")
+}
+
+func listKeys(a *app, args []string) error {
+	flags := a.newFlags("keys list")
+	dir := keysDirFlag(flags)
+	if err := a.parse(flags, args, 0); err != nil {
+		return err
+	}
+	if err := requireFlag(flags, "dir", *dir != ""); err != nil {
+		return err
+	}
+
+	keys, err := auth.LoadSigningKeys(*dir)
+	if err != nil {
+		return err
+	}
+
+	active := keys.Active()
+	views := make([]keyView, 0, len(keys.Keys()))
+	for _, key := range keys.Keys() {
+		views = append(views, keyView{
+			ID:        key.ID,
+			Algorithm: key.Algorithm,
+			CreatedAt: formatTime(&key.CreatedAt),
+			Active:    key == active,
+		})
+	}
+
+	return a.out.print(views, func() *table {
+		t := &table{headers: []string{"KID", "ALG", "CREATED", "STATUS"}}
+		for _, view := range views {
+			status := "retired"
+			if view.Active {
+				status = "active"
+			}
+			t.add(view.ID, view.Algorithm, view.CreatedAt, status)
+		}
+		return t
+	})
+}
+
+func rotateKeys(a *app, args []string) error {
+	flags := a.newFlags("keys rotate")
+	dir := keysDirFlag(flags)
+	if err := a.parse(flags, args, 0); err != nil {
+		return err
+	}
+	if err := requireFlag(flags, "dir", *dir != ""); err != nil {
+		return err
+	}
+
+	key, err := auth.GenerateSigningKey(*dir)
+	if err != nil {
+		return fmt.Errorf("failed to generate signing key: %w", err)
+	}
+
+	// Previous keys stay in the directory so tokens they signed keep verifying
+	fmt.Fprintln(os.Stderr, "Send SIGHUP to the server, or restart it, to start signing with the new key.")
+
+	view := keyView{ID: key.ID, Algorithm: key.Algorithm, CreatedAt: formatTime(&key.CreatedAt), Active: true}
+	return a.out.printFields(view, [][2]string{
+		{"KID", view.ID},
+		{"ALG", view.Algorithm},
+		{"CREATED", view.CreatedAt},
+	})
+}
+
+func pruneKeys(a *app, args []string) error {
+	flags := a.newFlags("keys prune")
+	dir := keysDirFlag(flags)
+	if err := a.parse(flags, args, 0); err != nil {
+		return err
+	}
+	if err := requireFlag(flags, "dir", *dir != ""); err != nil {
+		return err
+	}
+
+	pruned, err := auth.PruneSigningKeys(*dir)
+	if err != nil {
+		return err
+	}
+	if pruned == nil {
+		pruned = []string{}
+	}
+
+	return a.out.print(pruned, func() *table {
+		t := &table{headers: []string{"PRUNED KID"}}
+		for _, kid := range pruned {
+			t.add(kid)
+		}
+		return t
+	})
+}
@@ -0,0 +1,186 @@
+// Command simplejwtctl operates a SimpleJWT deployment directly against its
+// database and key files, using the same configuration as the server.
+//
+// Usage:
+//
+//	simplejwtctl <group> <command> [flags]
+//
+// Run "simplejwtctl help" for the list of commands. Every command accepts
+// -o table (default) or -o json.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/Kantha2004/SimpleJWT/internal/auth"
+	"github.com/Kantha2004/SimpleJWT/internal/config"
+	"github.com/Kantha2004/SimpleJWT/internal/db"
+	"github.com/joho/godotenv"
+)
+
+// command is a subcommand of a group such as "clients list"
+type command struct {
+	summary string
+	run     func(app *app, args []string) error
+}
+
+var groups = map[string]map[string]command{
+	"clients": clientCommands,
+	"users":   userCommands,
+	"keys":    keyCommands,
+	"migrate": migrateCommands,
+	"token":   tokenCommands,
+}
+
+// errUsage is returned when a command was called with invalid arguments;
+// the flag set has already printed the details
+var errUsage = errors.New("invalid arguments")
+
+func main() {
+	_ = godotenv.Load()
+
+	if len(os.Args) < 3 {
+		printUsage()
+		if len(os.Args) == 2 && (os.Args[1] == "help" || os.Args[1] == "-h") {
+			return
+		}
+		os.Exit(2)
+	}
+
+	group, ok := groups[os.Args[1]]
+	if !ok {
+		printUsage()
+		os.Exit(2)
+	}
+
+	cmd, ok := group[os.Args[2]]
+	if !ok {
+		printUsage()
+		os.Exit(2)
+	}
+
+	app := &app{}
+	defer app.close()
+
+	if err := cmd.run(app, os.Args[3:]); err != nil {
+		if !errors.Is(err, errUsage) {
+			fmt.Fprintf(os.Stderr, "simplejwtctl %s %s: %v\n", os.Args[1], os.Args[2], err)
+		}
+		app.close()
+		os.Exit(1)
+	}
+}
+
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "Usage: simplejwtctl <group> <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		commands := make([]string, 0, len(groups[name]))
+		for cmd := range groups[name] {
+			commands = append(commands, cmd)
+		}
+		sort.Strings(commands)
+
+		for _, cmd := range commands {
+			fmt.Fprintf(os.Stderr, "  %-24s %s\n", name+" "+cmd, groups[name][cmd].summary)
+		}
+	}
+
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Run a command with -h for its flags.")
+}
+
+// app lazily loads the configuration and connects to the database, so
+// commands such as "token decode" work without either
+type app struct {
+	cfg      *config.Config
+	database *db.Database
+	out      *output
+}
+
+// newFlags returns the flag set of a command with the shared output flag
+func (a *app) newFlags(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet("simplejwtctl "+name, flag.ContinueOnError)
+	a.out = &output{}
+	flags.Var(a.out, "o", "output format: table or json")
+	return flags
+}
+
+// parse parses the flags and rejects unexpected positional arguments unless
+// the command takes them
+func (a *app) parse(flags *flag.FlagSet, args []string, positional int) error {
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+	if flags.NArg() != positional {
+		fmt.Fprintf(os.Stderr, "expected %d arguments, got %q\n", positional, strings.Join(flags.Args(), " "))
+		flags.Usage()
+		return errUsage
+	}
+	return nil
+}
+
+func (a *app) config() *config.Config {
+	if a.cfg == nil {
+		a.cfg = config.LoadConfig()
+	}
+	return a.cfg
+}
+
+// db connects without applying migrations; use "migrate up" for that
+func (a *app) db() (*db.Database, error) {
+	if a.database == nil {
+		dbConfig := config.LoadDBConfig()
+		database, err := db.Connect(&dbConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to database: %w", err)
+		}
+		database.DB = database.DB.Session(&quietSession)
+		a.database = database
+	}
+	return a.database, nil
+}
+
+func (a *app) close() {
+	if a.database != nil {
+		_ = a.database.Close()
+		a.database = nil
+	}
+}
+
+// jwtService builds the token service the server would use
+func (a *app) jwtService() (*auth.JWTService, error) {
+	cfg := a.config()
+
+	var keys *auth.KeySet
+	if cfg.JWTKeysDir != "" {
+		var err error
+		if keys, err = auth.LoadSigningKeys(cfg.JWTKeysDir); err != nil {
+			return nil, err
+		}
+	}
+
+	return auth.NewJWTService(cfg.JWTSecret, keys, cfg.JWTIssuer, cfg.JWTAudience), nil
+}
+
+// requireFlag reports a missing mandatory flag
+func requireFlag(flags *flag.FlagSet, name string, set bool) error {
+	if set {
+		return nil
+	}
+	fmt.Fprintf(os.Stderr, "flag -%s is required\n", name)
+	flags.Usage()
+	return errUsage
+}
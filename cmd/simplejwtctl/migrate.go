@@ -0,0 +1,213 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/Kantha2004/SimpleJWT/internal/db"
+)
+
+var migrateCommands = map[string]command{
+	"up":     {"apply pending control-plane and tenant migrations", migrateUp},
+	"down":   {"revert migrations of the control plane or of one client schema", migrateDown},
+	"status": {"print the version and pending migrations of every schema", migrateStatus},
+}
+
+// migrationTargets selects the schemas a migrate command works on
+type migrationTargets struct {
+	control *bool
+	tenants *bool
+	schema  *string
+}
+
+func addMigrationTargets(flags *flag.FlagSet) migrationTargets {
+	return migrationTargets{
+		control: flags.Bool("control", false, "only the control-plane schema"),
+		tenants: flags.Bool("tenants", false, "only the client schemas"),
+		schema:  flags.String("schema", "", "only this client schema"),
+	}
+}
+
+func (t migrationTargets) includeControl() bool {
+	return !*t.tenants && *t.schema == ""
+}
+
+func (t migrationTargets) includeTenants() bool {
+	return !*t.control
+}
+
+func (t migrationTargets) tenantSchemas(database *db.Database) ([]string, error) {
+	if *t.schema != "" {
+		return []string{*t.schema}, nil
+	}
+	schemaNames, err := database.TenantSchemas()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list client schemas: %w", err)
+	}
+	return schemaNames, nil
+}
+
+// migrationResult reports a migrate command for one schema
+type migrationResult struct {
+	Schema      string   `json:"schema"`
+	FromVersion uint     `json:"from_version"`
+	ToVersion   uint     `json:"to_version"`
+	Changed     int      `json:"changed"`
+	Pending     []string `json:"pending,omitempty"`
+	Error       string   `json:"error,omitempty"`
+}
+
+const controlPlaneLabel = "(control plane)"
+
+func migrateUp(a *app, args []string) error {
+	flags := a.newFlags("migrate up")
+	targets := addMigrationTargets(flags)
+	if err := a.parse(flags, args, 0); err != nil {
+		return err
+	}
+
+	return a.runMigrations(targets, func(database *db.Database, migrator *db.Migrator, schema string) (int, error) {
+		if schema != controlPlaneLabel {
+			if err := database.CreateClientSchema(schema); err != nil {
+				return 0, err
+			}
+		}
+		return migrator.Up()
+	})
+}
+
+func migrateDown(a *app, args []string) error {
+	flags := a.newFlags("migrate down")
+	targets := addMigrationTargets(flags)
+	steps := flags.Int("steps", 1, "number of migrations to revert")
+	if err := a.parse(flags, args, 0); err != nil {
+		return err
+	}
+	// Reverting every tenant at once is almost never intended
+	if !*targets.control && *targets.schema == "" {
+		fmt.Fprintln(os.Stderr, "migrate down needs -control or -schema")
+		flags.Usage()
+		return errUsage
+	}
+	if *targets.control {
+		*targets.tenants = false
+	}
+
+	return a.runMigrations(targets, func(_ *db.Database, migrator *db.Migrator, _ string) (int, error) {
+		return migrator.Down(*steps)
+	})
+}
+
+func migrateStatus(a *app, args []string) error {
+	flags := a.newFlags("migrate status")
+	targets := addMigrationTargets(flags)
+	if err := a.parse(flags, args, 0); err != nil {
+		return err
+	}
+
+	return a.runMigrations(targets, nil)
+}
+
+// runMigrations applies migrate to every selected schema, or only reports
+// their status when migrate is nil. A failing client schema does not stop
+// the others.
+func (a *app) runMigrations(targets migrationTargets, migrate func(*db.Database, *db.Migrator, string) (int, error)) error {
+	database, err := a.db()
+	if err != nil {
+		return err
+	}
+
+	var results []migrationResult
+	if targets.includeControl() {
+		migrator, err := database.ControlPlaneMigrator()
+		if err != nil {
+			return err
+		}
+		result := runMigrator(database, migrator, controlPlaneLabel, migrate)
+		results = append(results, result)
+
+		// Tenant migrations rely on the control plane being current
+		if result.Error != "" {
+			return a.printMigrationResults(results)
+		}
+	}
+
+	if targets.includeTenants() {
+		schemaNames, err := targets.tenantSchemas(database)
+		if err != nil {
+			return err
+		}
+
+		for _, schemaName := range schemaNames {
+			migrator, err := database.TenantMigrator(schemaName)
+			if err != nil {
+				return err
+			}
+			results = append(results, runMigrator(database, migrator, schemaName, migrate))
+		}
+	}
+
+	return a.printMigrationResults(results)
+}
+
+func runMigrator(database *db.Database, migrator *db.Migrator, schema string, migrate func(*db.Database, *db.Migrator, string) (int, error)) migrationResult {
+	result := migrationResult{Schema: schema}
+
+	var err error
+	if result.FromVersion, err = migrator.Version(); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	if migrate != nil {
+		if result.Changed, err = migrate(database, migrator, schema); err != nil {
+			result.Error = err.Error()
+		}
+	}
+
+	if result.ToVersion, err = migrator.Version(); err != nil && result.Error == "" {
+		result.Error = err.Error()
+	}
+
+	pending, err := migrator.Pending()
+	if err != nil && result.Error == "" {
+		result.Error = err.Error()
+	}
+	for _, migration := range pending {
+		result.Pending = append(result.Pending, fmt.Sprintf("%04d_%s", migration.Version, migration.Name))
+	}
+
+	return result
+}
+
+func (a *app) printMigrationResults(results []migrationResult) error {
+	failed := 0
+	for _, result := range results {
+		if result.Error != "" {
+			failed++
+		}
+	}
+
+	if err := a.out.print(results, func() *table {
+		t := &table{headers: []string{"SCHEMA", "FROM", "TO", "CHANGED", "PENDING", "ERROR"}}
+		for _, result := range results {
+			t.add(
+				result.Schema,
+				fmt.Sprint(result.FromVersion),
+				fmt.Sprint(result.ToVersion),
+				fmt.Sprint(result.Changed),
+				fmt.Sprint(len(result.Pending)),
+				result.Error,
+			)
+		}
+		return t
+	}); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d schemas failed", failed, len(results))
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// quietSession keeps GORM's SQL logging out of the command output
+var quietSession = gorm.Session{Logger: logger.Default.LogMode(logger.Silent)}
+
+// output prints command results as an aligned table or as JSON. It
+// implements flag.Value for the -o flag.
+type output struct {
+	json bool
+}
+
+func (o *output) String() string {
+	if o != nil && o.json {
+		return "json"
+	}
+	return "table"
+}
+
+func (o *output) Set(value string) error {
+	switch value {
+	case "table":
+		o.json = false
+	case "json":
+		o.json = true
+	default:
+		return fmt.Errorf("unknown output format %q, use table or json", value)
+	}
+	return nil
+}
+
+// table is the tabular form of a result
+type table struct {
+	headers []string
+	rows    [][]string
+}
+
+func (t *table) add(cells ...string) {
+	t.rows = append(t.rows, cells)
+}
+
+// print writes value as JSON, or the table built by toTable
+func (o *output) print(value any, toTable func() *table) error {
+	if o.json {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(value)
+	}
+
+	t := toTable()
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if len(t.headers) > 0 {
+		fmt.Fprintln(writer, strings.Join(t.headers, "\t"))
+	}
+	for _, row := range t.rows {
+		fmt.Fprintln(writer, strings.Join(row, "\t"))
+	}
+	return writer.Flush()
+}
+
+// printFields prints a single record as "field  value" lines
+func (o *output) printFields(value any, fields [][2]string) error {
+	return o.print(value, func() *table {
+		t := &table{}
+		for _, field := range fields {
+			t.add(field[0], field[1])
+		}
+		return t
+	})
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return "-"
+	}
+	return t.UTC().Format(time.RFC3339)
+}
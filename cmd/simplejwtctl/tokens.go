@@ -0,0 +1,265 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Kantha2004/SimpleJWT/internal/auth"
+	"github.com/Kantha2004/SimpleJWT/internal/db"
+	"github.com/Kantha2004/SimpleJWT/internal/models"
+	"github.com/google/uuid"
+)
+
+var tokenCommands = map[string]command{
+	"mint":   {"start a session for a user and print a token for debugging", mintToken},
+	"decode": {"print the header and claims of a token without verifying it", decodeToken},
+	"verify": {"verify a token's signature, expiry and session", verifyToken},
+}
+
+// tokenView is the JSON form of a token
+type tokenView struct {
+	Token     string         `json:"token,omitempty"`
+	Valid     *bool          `json:"valid,omitempty"`
+	Error     string         `json:"error,omitempty"`
+	Header    map[string]any `json:"header,omitempty"`
+	Claims    map[string]any `json:"claims"`
+	Session   string         `json:"session,omitempty"`
+	ExpiresAt string         `json:"expires_at,omitempty"`
+}
+
+func mintToken(a *app, args []string) error {
+	flags := a.newFlags("token mint")
+	userID := flags.Uint("user", 0, "user ID; an admin user unless -client is set")
+	clientID := flags.Uint("client", 0, "client ID, to mint a token for a user of this client")
+	if err := a.parse(flags, args, 0); err != nil {
+		return err
+	}
+	if err := requireFlag(flags, "user", *userID != 0); err != nil {
+		return err
+	}
+
+	jwtService, err := a.jwtService()
+	if err != nil {
+		return err
+	}
+
+	database, err := a.db()
+	if err != nil {
+		return err
+	}
+
+	var client *models.Client
+	var user *models.AdminUser
+	if *clientID != 0 {
+		if client, err = getClient(database, *clientID); err != nil {
+			return err
+		}
+		user, err = db.NewClientUserRepository(database, client.SchemaName).GetClientUserByID(*userID)
+	} else {
+		user, err = db.NewUserRepository(database).GetUserByID(*userID)
+	}
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return fmt.Errorf("user %d not found", *userID)
+	}
+	if user.IsDisabled() {
+		return fmt.Errorf("user %d is disabled", *userID)
+	}
+
+	// Tokens are only accepted with an active session, so mint one like a login
+	now := time.Now()
+	session := &models.Session{
+		SessionID:  uuid.NewString(),
+		UserID:     user.ID,
+		FamilyID:   uuid.NewString(),
+		UserAgent:  "simplejwtctl",
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(auth.TokenTTL),
+	}
+	if client != nil {
+		session.ClientID = &client.ID
+	}
+
+	if err := db.NewSessionRepository(database).CreateSession(session); err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+
+	var token string
+	if client != nil {
+		var roles []string
+		roles, err = db.NewRoleRepository(database, client.SchemaName).GetUserRoleNames(user.ID)
+		if err == nil {
+			token, err = jwtService.CreateClientUserToken(client.ID, user.ID, session.SessionID, roles)
+		}
+	} else {
+		token, err = jwtService.CreateToken(user.ID, session.SessionID)
+	}
+	if err != nil {
+		return err
+	}
+
+	event := models.AuditEvent{
+		Action:      models.AuditActionUserLogin,
+		TargetType:  "session",
+		TargetID:    session.SessionID,
+		OwnerUserID: &user.ID,
+		Reason:      "token minted with simplejwtctl",
+	}
+	if client != nil {
+		event.Action = models.AuditActionClientUserLogin
+		event.ClientID = &client.ID
+		event.OwnerUserID = &client.UserID
+	}
+	a.audit(database, event)
+
+	view := tokenView{Token: token, Session: session.SessionID, ExpiresAt: formatTime(&session.ExpiresAt)}
+	return a.out.printFields(view, [][2]string{
+		{"TOKEN", token},
+		{"SESSION", session.SessionID},
+		{"EXPIRES", view.ExpiresAt},
+	})
+}
+
+func decodeToken(a *app, args []string) error {
+	flags := a.newFlags("token decode")
+	if err := a.parse(flags, args, 1); err != nil {
+		return err
+	}
+
+	header, claims, err := splitToken(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	view := tokenView{Header: header, Claims: claims}
+	return a.out.print(view, func() *table {
+		t := &table{headers: []string{"PART", "FIELD", "VALUE"}}
+		addFields(t, "header", header)
+		addFields(t, "claims", claims)
+		return t
+	})
+}
+
+func verifyToken(a *app, args []string) error {
+	flags := a.newFlags("token verify")
+	if err := a.parse(flags, args, 1); err != nil {
+		return err
+	}
+	tokenString := flags.Arg(0)
+
+	jwtService, err := a.jwtService()
+	if err != nil {
+		return err
+	}
+
+	header, claims, err := splitToken(tokenString)
+	if err != nil {
+		return err
+	}
+
+	view := tokenView{Header: header, Claims: claims}
+	valid := true
+
+	if _, err := jwtService.VerifyToken(tokenString); err != nil {
+		valid = false
+		view.Error = err.Error()
+	} else if sessionID, _ := claims["sid"].(string); sessionID == "" {
+		valid = false
+		view.Error = "token is not bound to a session"
+	} else {
+		database, err := a.db()
+		if err != nil {
+			return err
+		}
+
+		session, err := db.NewSessionRepository(database).GetSessionBySessionID(sessionID)
+		switch {
+		case err != nil:
+			return err
+		case session == nil:
+			view.Session = "missing"
+		case session.RevokedAt != nil:
+			view.Session = "revoked at " + formatTime(session.RevokedAt)
+		case !session.IsActive(time.Now()):
+			view.Session = "expired"
+		default:
+			view.Session = "active"
+		}
+
+		if view.Session != "active" {
+			valid = false
+			view.Error = "session is " + view.Session
+		}
+	}
+	view.Valid = &valid
+
+	if err := a.out.print(view, func() *table {
+		t := &table{headers: []string{"PART", "FIELD", "VALUE"}}
+		t.add("result", "valid", fmt.Sprint(valid))
+		if view.Error != "" {
+			t.add("result", "error", view.Error)
+		}
+		if view.Session != "" {
+			t.add("result", "session", view.Session)
+		}
+		addFields(t, "header", header)
+		addFields(t, "claims", claims)
+		return t
+	}); err != nil {
+		return err
+	}
+
+	if !valid {
+		return fmt.Errorf("token is invalid: %s", view.Error)
+	}
+	return nil
+}
+
+// splitToken decodes the header and claims of a JWT without verifying it
+func splitToken(token string) (map[string]any, map[string]any, error) {
+	parts := strings.Split(strings.TrimPrefix(strings.TrimSpace(token), "Bearer "), ".")
+	if len(parts) != 3 {
+		return nil, nil, fmt.Errorf("a token has 3 dot-separated parts, got %d", len(parts))
+	}
+
+	var header, claims map[string]any
+	for i, target := range []*map[string]any{&header, &claims} {
+		raw, err := base64.RawURLEncoding.DecodeString(parts[i])
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid token encoding: %w", err)
+		}
+		if err := json.Unmarshal(raw, target); err != nil {
+			return nil, nil, fmt.Errorf("invalid token JSON: %w", err)
+		}
+	}
+
+	return header, claims, nil
+}
+
+// addFields adds the sorted fields of a token part; timestamps are shown
+// as dates as well
+func addFields(t *table, part string, fields map[string]any) {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := fmt.Sprint(fields[key])
+		if number, ok := fields[key].(float64); ok {
+			value = fmt.Sprintf("%.0f", number)
+			if key == "exp" || key == "iat" || key == "nbf" {
+				at := time.Unix(int64(number), 0)
+				value += " (" + formatTime(&at) + ")"
+			}
+		}
+		t.add(part, key, value)
+	}
+}
@@ -0,0 +1,435 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Kantha2004/SimpleJWT/internal/auth"
+	"github.com/Kantha2004/SimpleJWT/internal/db"
+	"github.com/Kantha2004/SimpleJWT/internal/models"
+	"github.com/Kantha2004/SimpleJWT/internal/webhooks"
+)
+
+var userCommands = map[string]command{
+	"list":           {"list the users of a client", listClientUsers},
+	"create":         {"create a user of a client", createClientUser},
+	"reset-password": {"set a new password for a user of a client and revoke their sessions", resetClientUserPassword},
+	"disable":        {"disable a user of a client and revoke their sessions", disableClientUser},
+}
+
+// generatedPasswordLength is the minimum length of passwords generated when
+// -password is omitted
+const generatedPasswordLength = 20
+
+func listClientUsers(a *app, args []string) error {
+	flags := a.newFlags("users list")
+	clientID := flags.Uint("client", 0, "client ID")
+	if err := a.parse(flags, args, 0); err != nil {
+		return err
+	}
+	if err := requireFlag(flags, "client", *clientID != 0); err != nil {
+		return err
+	}
+
+	database, err := a.db()
+	if err != nil {
+		return err
+	}
+
+	client, err := getClient(database, *clientID)
+	if err != nil {
+		return err
+	}
+
+	users, err := db.NewClientUserRepository(database, client.SchemaName).GetClientAllUser()
+	if err != nil {
+		return err
+	}
+
+	return a.out.print(users, func() *table {
+		t := &table{headers: []string{"ID", "USERNAME", "EMAIL", "STATUS", "CREATED"}}
+		for _, user := range *users {
+			t.add(strconv.FormatUint(uint64(user.ID), 10), user.Username, user.Email, userStatus(&user), formatTime(&user.CreatedAt))
+		}
+		return t
+	})
+}
+
+func createClientUser(a *app, args []string) error {
+	flags := a.newFlags("users create")
+	clientID := flags.Uint("client", 0, "client ID")
+	username := flags.String("username", "", "username")
+	email := flags.String("email", "", "email address")
+	password := flags.String("password", "", "password; a random one is generated and printed when empty")
+	if err := a.parse(flags, args, 0); err != nil {
+		return err
+	}
+	if err := requireFlag(flags, "client", *clientID != 0); err != nil {
+		return err
+	}
+	if err := requireFlag(flags, "username", *username != ""); err != nil {
+		return err
+	}
+	if err := requireFlag(flags, "email", *email != ""); err != nil {
+		return err
+	}
+
+	database, err := a.db()
+	if err != nil {
+		return err
+	}
+
+	client, err := getClient(database, *clientID)
+	if err != nil {
+		return err
+	}
+
+	userRepo := db.NewClientUserRepository(database, client.SchemaName)
+	if exists, err := userRepo.ClientUserNameExists(*username); err != nil {
+		return err
+	} else if exists {
+		return fmt.Errorf("username %q already exists", *username)
+	}
+	if exists, err := userRepo.ClientUserEmailExists(*email); err != nil {
+		return err
+	} else if exists {
+		return fmt.Errorf("email %q already exists", *email)
+	}
+
+	passwords, err := a.newPasswordTools(database, client)
+	if err != nil {
+		return err
+	}
+
+	generated := *password == ""
+	if generated {
+		if *password, err = generatePassword(passwords.policy); err != nil {
+			return err
+		}
+	}
+
+	hash, err := passwords.check(*password, auth.PasswordContext{Username: *username, Email: *email})
+	if err != nil {
+		return err
+	}
+
+	user, err := userRepo.CreateClientUser(&models.ClientUser{
+		Username:     *username,
+		Email:        *email,
+		PasswordHash: hash,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+
+	passwords.remember(user.ID, hash)
+
+	a.audit(database, clientUserEvent(client, user, models.AuditActionClientUserCreate))
+	a.publish(database, client, user, models.WebhookEventClientUserCreated)
+
+	return a.printClientUser(user, generated, *password)
+}
+
+func resetClientUserPassword(a *app, args []string) error {
+	flags := a.newFlags("users reset-password")
+	clientID := flags.Uint("client", 0, "client ID")
+	username := flags.String("username", "", "username")
+	password := flags.String("password", "", "new password; a random one is generated and printed when empty")
+	if err := a.parse(flags, args, 0); err != nil {
+		return err
+	}
+	if err := requireFlag(flags, "client", *clientID != 0); err != nil {
+		return err
+	}
+	if err := requireFlag(flags, "username", *username != ""); err != nil {
+		return err
+	}
+
+	database, err := a.db()
+	if err != nil {
+		return err
+	}
+
+	client, user, err := findClientUser(database, *clientID, *username)
+	if err != nil {
+		return err
+	}
+
+	passwords, err := a.newPasswordTools(database, client)
+	if err != nil {
+		return err
+	}
+
+	generated := *password == ""
+	if generated {
+		if *password, err = generatePassword(passwords.policy); err != nil {
+			return err
+		}
+	}
+
+	previousHashes, err := passwords.history.GetRecentPasswordHashes(user.ID, passwords.policy.HistorySize)
+	if err != nil {
+		return fmt.Errorf("failed to fetch password history: %w", err)
+	}
+
+	hash, err := passwords.check(*password, auth.PasswordContext{
+		Username:       user.Username,
+		Email:          user.Email,
+		PreviousHashes: append([]string{user.PasswordHash}, previousHashes...),
+	})
+	if err != nil {
+		return err
+	}
+
+	user.PasswordHash = hash
+	if err := db.NewClientUserRepository(database, client.SchemaName).UpdateClientUser(user); err != nil {
+		return fmt.Errorf("failed to reset password: %w", err)
+	}
+
+	passwords.remember(user.ID, hash)
+
+	if _, err := db.NewSessionRepository(database).RevokeUserSessions(user.ID, &client.ID, ""); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to revoke sessions: %v\n", err)
+	}
+
+	a.audit(database, clientUserEvent(client, user, models.AuditActionClientUserPasswordReset))
+	a.publish(database, client, user, models.WebhookEventClientUserPasswordChanged)
+
+	return a.printClientUser(user, generated, *password)
+}
+
+func disableClientUser(a *app, args []string) error {
+	flags := a.newFlags("users disable")
+	clientID := flags.Uint("client", 0, "client ID")
+	username := flags.String("username", "", "username")
+	if err := a.parse(flags, args, 0); err != nil {
+		return err
+	}
+	if err := requireFlag(flags, "client", *clientID != 0); err != nil {
+		return err
+	}
+	if err := requireFlag(flags, "username", *username != ""); err != nil {
+		return err
+	}
+
+	database, err := a.db()
+	if err != nil {
+		return err
+	}
+
+	client, user, err := findClientUser(database, *clientID, *username)
+	if err != nil {
+		return err
+	}
+
+	if !user.IsDisabled() {
+		now := time.Now()
+		user.DisabledAt = &now
+
+		if err := db.NewClientUserRepository(database, client.SchemaName).UpdateClientUser(user); err != nil {
+			return fmt.Errorf("failed to disable user: %w", err)
+		}
+
+		if _, err := db.NewSessionRepository(database).RevokeUserSessions(user.ID, &client.ID, ""); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to revoke sessions: %v\n", err)
+		}
+
+		a.audit(database, clientUserEvent(client, user, models.AuditActionClientUserDisable))
+		a.publish(database, client, user, models.WebhookEventClientUserDisabled)
+	}
+
+	return a.printClientUser(user, false, "")
+}
+
+func findClientUser(database *db.Database, clientID uint, username string) (*models.Client, *models.ClientUser, error) {
+	client, err := getClient(database, clientID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user, err := db.NewClientUserRepository(database, client.SchemaName).GetClientUserByUsername(username)
+	if err != nil {
+		return nil, nil, err
+	}
+	if user == nil {
+		return nil, nil, fmt.Errorf("user %q not found in client %d", username, clientID)
+	}
+
+	return client, user, nil
+}
+
+func (a *app) printClientUser(user *models.ClientUser, showPassword bool, password string) error {
+	fields := [][2]string{
+		{"ID", strconv.FormatUint(uint64(user.ID), 10)},
+		{"USERNAME", user.Username},
+		{"EMAIL", user.Email},
+		{"STATUS", userStatus(user)},
+	}
+
+	result := struct {
+		*models.ClientUser
+		Password string `json:"password,omitempty"`
+	}{ClientUser: user}
+
+	if showPassword {
+		result.Password = password
+		fields = append(fields, [2]string{"PASSWORD", password})
+	}
+
+	return a.out.printFields(result, fields)
+}
+
+func userStatus(user *models.ClientUser) string {
+	if user.IsDisabled() {
+		return "disabled"
+	}
+	return "active"
+}
+
+func clientUserEvent(client *models.Client, user *models.ClientUser, action string) models.AuditEvent {
+	return models.AuditEvent{
+		Action:      action,
+		TargetType:  "client_user",
+		TargetID:    strconv.FormatUint(uint64(user.ID), 10),
+		ClientID:    &client.ID,
+		OwnerUserID: &client.UserID,
+	}
+}
+
+// publish queues a webhook event; the running server delivers it
+func (a *app) publish(database *db.Database, client *models.Client, user *models.ClientUser, eventType string) {
+	dispatcher := webhooks.NewDispatcher(database, webhooks.Config{}, slog.Default())
+	data := models.ClientUserEventData{
+		UserID:   user.ID,
+		Username: user.Username,
+		Email:    user.Email,
+	}
+
+	if err := dispatcher.Publish(context.Background(), client.ID, eventType, data); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to publish webhook event %s: %v\n", eventType, err)
+	}
+}
+
+// passwordTools checks and hashes passwords of one client the way the
+// server does
+type passwordTools struct {
+	validator *auth.PasswordValidator
+	hasher    auth.PasswordHasher
+	policy    models.PasswordPolicy
+	history   *db.PasswordHistoryRepository
+}
+
+func (a *app) newPasswordTools(database *db.Database, client *models.Client) (*passwordTools, error) {
+	cfg := a.config()
+
+	var breached *auth.BreachedPasswordList
+	if cfg.BreachedPasswordsFile != "" {
+		var err error
+		if breached, err = auth.LoadBreachedPasswords(cfg.BreachedPasswordsFile); err != nil {
+			return nil, fmt.Errorf("failed to load breached passwords list: %w", err)
+		}
+	}
+
+	hasher, err := auth.NewPasswordHasher(
+		cfg.PasswordHashing.Algorithm,
+		auth.Argon2Params{
+			Memory:      cfg.PasswordHashing.Argon2Memory,
+			Time:        cfg.PasswordHashing.Argon2Time,
+			Parallelism: cfg.PasswordHashing.Argon2Parallelism,
+		},
+		cfg.PasswordHashing.BcryptCost,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	validator := auth.NewPasswordValidator(cfg.PasswordPolicy, breached)
+
+	settings, err := db.NewClientConfigRepository(database, client.SchemaName).GetClientSettings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load client settings: %w", err)
+	}
+
+	policy, err := validator.PolicyForClient(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	return &passwordTools{
+		validator: validator,
+		hasher:    hasher,
+		policy:    policy,
+		history:   db.NewClientPasswordHistoryRepository(database, client.SchemaName),
+	}, nil
+}
+
+// check validates the password against the policy and returns its hash
+func (p *passwordTools) check(password string, ctx auth.PasswordContext) (string, error) {
+	violations := p.validator.Validate(p.policy, password, ctx)
+	if violation, tooLong := auth.HasherLimitViolation(p.hasher, password); tooLong {
+		violations = append(violations, violation)
+	}
+	if len(violations) > 0 {
+		messages := make([]string, 0, len(violations))
+		for _, violation := range violations {
+			messages = append(messages, violation.Message)
+		}
+		return "", fmt.Errorf("password rejected: %s", strings.Join(messages, "; "))
+	}
+
+	return p.hasher.Hash(password)
+}
+
+// remember records the hash in the password history when the policy keeps one
+func (p *passwordTools) remember(userID uint, hash string) {
+	if p.policy.HistorySize == 0 {
+		return
+	}
+	if err := p.history.AddPasswordHash(userID, hash, p.policy.HistorySize); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to record password history: %v\n", err)
+	}
+}
+
+// generatePassword returns a random password with every character class so
+// it satisfies any combination of the policy's character rules
+func generatePassword(policy models.PasswordPolicy) (string, error) {
+	classes := []string{
+		"ABCDEFGHJKLMNPQRSTUVWXYZ",
+		"abcdefghijkmnopqrstuvwxyz",
+		"23456789",
+		"!#$%&*+-=?@^_",
+	}
+
+	length := max(generatedPasswordLength, policy.MinLength)
+	if policy.MaxLength > 0 {
+		length = min(length, policy.MaxLength)
+	}
+
+	password := make([]byte, 0, length)
+	for i := 0; i < length; i++ {
+		class := classes[i%len(classes)]
+		index, err := rand.Int(rand.Reader, big.NewInt(int64(len(class))))
+		if err != nil {
+			return "", err
+		}
+		password = append(password, class[index.Int64()])
+	}
+
+	// Shuffle so the character classes do not follow a fixed pattern
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", err
+		}
+		password[i], password[j.Int64()] = password[j.Int64()], password[i]
+	}
+
+	return string(password), nil
+}
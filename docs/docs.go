@@ -24,7 +24,287 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/createUser": {
+        "/problems": {
+            "get": {
+                "description": "List every error code with its problem type, title and HTTP status. Codes are stable and safe to branch on.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "errors"
+                ],
+                "summary": "List error codes",
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved error codes",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.ProblemType"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/problems/{code}": {
+            "get": {
+                "description": "Describe the error code a problem type URI points to",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "errors"
+                ],
+                "summary": "Describe an error code",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Error code",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved error code",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.ProblemType"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Unknown error code",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/audit": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve audit events of the authenticated user and of their clients, newest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "audit"
+                ],
+                "summary": "Query the audit log",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only events at or after this time (RFC 3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only events at or before this time (RFC 3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only events performed by this actor",
+                        "name": "actor_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "admin_user",
+                            "client_user",
+                            "anonymous",
+                            "operator"
+                        ],
+                        "type": "string",
+                        "description": "Only events performed by this kind of actor",
+                        "name": "actor_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only events with this action, e.g. client.create",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only events of this client",
+                        "name": "client_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 100, max 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the next page, from pagination.next_cursor; takes precedence over page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "occurred_at",
+                            "-occurred_at"
+                        ],
+                        "type": "string",
+                        "description": "Sort field, prefixed with - for descending order (default -occurred_at)",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved audit events",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.AuditEvent"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - validation error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/clientUserLogin": {
+            "post": {
+                "description": "Authenticate a user of a client with username and password",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Client user login",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "description": "Login credentials",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.ClientUserLoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Login successful",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.LoginResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid credentials",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/createUser": {
             "post": {
                 "description": "Create a new user account in the system",
                 "consumes": [
@@ -37,6 +317,7 @@ const docTemplate = `{
                     "users"
                 ],
                 "summary": "Create a new user",
+                "deprecated": true,
                 "parameters": [
                     {
                         "description": "User creation data",
@@ -46,6 +327,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.CreateUser"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key that makes retries of the request safe",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -68,27 +355,33 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Bad request - validation error",
+                        "description": "Bad request - validation error or password policy violation",
                         "schema": {
-                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.ErrorResponse"
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
                         }
                     },
                     "409": {
                         "description": "Conflict - username or email already exists",
                         "schema": {
-                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.ErrorResponse"
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable - Idempotency-Key reused for a different request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.ErrorResponse"
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
                         }
                     }
                 }
             }
         },
-        "/login": {
+        "/v1/login": {
             "post": {
                 "description": "Authenticate user with username and password",
                 "consumes": [
@@ -101,6 +394,7 @@ const docTemplate = `{
                     "auth"
                 ],
                 "summary": "User login",
+                "deprecated": true,
                 "parameters": [
                     {
                         "description": "Login credentials",
@@ -134,25 +428,25 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad request",
                         "schema": {
-                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.ErrorResponse"
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
                         }
                     },
                     "401": {
                         "description": "Invalid credentials",
                         "schema": {
-                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.ErrorResponse"
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.ErrorResponse"
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
                         }
                     }
                 }
             }
         },
-        "/ping": {
+        "/v1/ping": {
             "get": {
                 "description": "Ping endpoint to check if the service is running",
                 "consumes": [
@@ -176,7 +470,65 @@ const docTemplate = `{
                 }
             }
         },
-        "/protected/createClient": {
+        "/v1/protected/changePassword": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change the password of the authenticated user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Change password",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "description": "Current and new password",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.ChangePasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Password changed successfully",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - validation error or password policy violation",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid current password",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/createClient": {
             "post": {
                 "security": [
                     {
@@ -194,6 +546,7 @@ const docTemplate = `{
                     "Client"
                 ],
                 "summary": "Create a new user",
+                "deprecated": true,
                 "parameters": [
                     {
                         "description": "Client creation data",
@@ -203,6 +556,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.CreateClient"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key that makes retries of the request safe; a replayed response has no body, since it held the secret",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -227,25 +586,31 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad request - validation error",
                         "schema": {
-                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.ErrorResponse"
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
                         }
                     },
                     "409": {
                         "description": "Conflict - client name already exists",
                         "schema": {
-                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.ErrorResponse"
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable - Idempotency-Key reused for a different request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.ErrorResponse"
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
                         }
                     }
                 }
             }
         },
-        "/protected/createClientUser": {
+        "/v1/protected/createClientUser": {
             "post": {
                 "security": [
                     {
@@ -263,6 +628,7 @@ const docTemplate = `{
                     "Client"
                 ],
                 "summary": "Create a new ClientUser",
+                "deprecated": true,
                 "parameters": [
                     {
                         "description": "User creation data",
@@ -272,6 +638,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.CreateClientUser"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key that makes retries of the request safe",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -294,34 +666,274 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Bad request - validation error",
+                        "description": "Bad request - validation error or password policy violation",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Client not found",
                         "schema": {
-                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.ErrorResponse"
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
                         }
                     },
                     "409": {
                         "description": "Conflict - username or email already exists",
                         "schema": {
-                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.ErrorResponse"
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
                         }
                     },
-                    "500": {
+                    "422": {
+                        "description": "Unprocessable - Idempotency-Key reused for a different request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/createWebhook": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Subscribe a URL to events of one of the authenticated user's clients. Deliveries are signed with HMAC-SHA256 using the returned secret, which is only shown once.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Subscribe to webhooks",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "description": "Webhook subscription",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.CreateWebhookRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key that makes retries of the request safe; a replayed response has no body, since it held the secret",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Webhook created successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.CreateWebhookResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - validation error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Client not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable - Idempotency-Key reused for a different request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/deleteWebhook": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a webhook subscription. Pending deliveries are marked failed on their next attempt.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Delete a webhook",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "description": "Webhook to delete",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.WebhookIDRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Webhook deleted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - validation error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/disableClientUser": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Disable a user of one of the authenticated user's clients and revoke all their sessions",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Client"
+                ],
+                "summary": "Disable a ClientUser",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "description": "Client user to disable",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.ClientUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User disabled successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.ClientUser"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - validation error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Client or user not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.ErrorResponse"
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
                         }
                     }
                 }
             }
         },
-        "/protected/getAllClients": {
+        "/v1/protected/getAllClients": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve all clients belonging to the authenticated user",
+                "description": "Retrieve the clients belonging to the authenticated user, one page at a time",
                 "consumes": [
                     "application/json"
                 ],
@@ -332,13 +944,60 @@ const docTemplate = `{
                     "Client"
                 ],
                 "summary": "Get all clients associated with the user",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only clients whose name contains this text, ignoring case",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only suspended (true) or active (false) clients",
+                        "name": "suspended",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the next page, from pagination.next_cursor; takes precedence over page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "id",
+                            "-id",
+                            "client_name",
+                            "-client_name",
+                            "created_at",
+                            "-created_at"
+                        ],
+                        "type": "string",
+                        "description": "Sort field, prefixed with - for descending order (default id)",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "Successfully retrieved all clients",
                         "schema": {
                             "allOf": [
                                 {
-                                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.SuccessResponse"
+                                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.PaginatedResponse"
                                 },
                                 {
                                     "type": "object",
@@ -357,38 +1016,32 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad request - validation error",
                         "schema": {
-                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.ErrorResponse"
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
                         }
                     },
                     "401": {
                         "description": "Unauthorized - invalid user",
                         "schema": {
-                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "No clients found",
-                        "schema": {
-                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.ErrorResponse"
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.ErrorResponse"
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
                         }
                     }
                 }
             }
         },
-        "/protected/test": {
+        "/v1/protected/getClientUserSessions": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Returns a success response if JWT is valid",
+                "description": "List the active sessions of a user of one of the authenticated user's clients",
                 "consumes": [
                     "application/json"
                 ],
@@ -396,253 +1049,4900 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Protected"
+                    "sessions"
+                ],
+                "summary": "List a client user's sessions",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "client_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Client user ID",
+                        "name": "user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the next page, from pagination.next_cursor; takes precedence over page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "last_seen_at",
+                            "-last_seen_at",
+                            "created_at",
+                            "-created_at",
+                            "expires_at",
+                            "-expires_at"
+                        ],
+                        "type": "string",
+                        "description": "Sort field, prefixed with - for descending order (default -last_seen_at)",
+                        "name": "sort",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Test endpoint",
                 "responses": {
-                    "201": {
-                        "description": "User created successfully",
+                    "200": {
+                        "description": "Successfully retrieved sessions",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.Session"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - validation error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
                         }
                     },
                     "401": {
-                        "description": "Unauthorized",
+                        "description": "Unauthorized - invalid user",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Client not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "github_com_Kantha2004_SimpleJWT_internal_apiResponse.ErrorResponse": {
+        },
+        "/v1/protected/getSessions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the active sessions of the authenticated user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "List my sessions",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the next page, from pagination.next_cursor; takes precedence over page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "last_seen_at",
+                            "-last_seen_at",
+                            "created_at",
+                            "-created_at",
+                            "expires_at",
+                            "-expires_at"
+                        ],
+                        "type": "string",
+                        "description": "Sort field, prefixed with - for descending order (default -last_seen_at)",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved sessions",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.Session"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - validation error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/getWebhookDeliveries": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve the delivery log of a webhook subscription with every attempt, newest deliveries first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhook deliveries",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "webhook_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "succeeded",
+                            "failed"
+                        ],
+                        "type": "string",
+                        "description": "Only deliveries with this status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of deliveries (default 50, max 500)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved deliveries",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.WebhookDeliveryLog"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - validation error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/getWebhooks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve the webhook subscriptions of one of the authenticated user's clients",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhooks of a client",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "client_id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved webhooks",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.WebhookSubscription"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - validation error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Client not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/redeliverWebhook": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Queue a delivery to be sent again right away",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Redeliver a webhook",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "description": "Delivery to send again",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.RedeliverWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Delivery queued successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.WebhookDelivery"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - validation error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Delivery not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/resetClientUserPassword": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Set a new password for a user of one of the authenticated user's clients and revoke all their sessions",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Client"
+                ],
+                "summary": "Reset a ClientUser's password",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "description": "Client user and new password",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.ResetClientUserPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Password reset successfully",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - validation error or password policy violation",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Client or user not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/revokeClientUserSessions": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revoke one session, or all sessions when session_id is omitted, of a user of one of the authenticated user's clients",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Revoke a client user's sessions",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "description": "Client user and optional session",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.ClientUserSessionsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Sessions revoked successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.RevokeSessionsResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - validation error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Client or session not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/revokeOtherSessions": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revoke every session of the authenticated user except the current one",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Revoke all my other sessions",
+                "deprecated": true,
+                "responses": {
+                    "200": {
+                        "description": "Sessions revoked successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.RevokeSessionsResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/revokeSession": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revoke a session of the authenticated user, signing out that device",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Revoke one of my sessions",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "description": "Session to revoke",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.RevokeSessionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Session revoked successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.RevokeSessionsResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - validation error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Session not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/protected/test": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Returns a success response if JWT is valid",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Protected"
+                ],
+                "summary": "Test endpoint",
+                "deprecated": true,
+                "responses": {
+                    "201": {
+                        "description": "User created successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/audit-events": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve audit events of the authenticated user and of their clients, newest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "audit"
+                ],
+                "summary": "Query the audit log",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only events at or after this time (RFC 3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only events at or before this time (RFC 3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only events performed by this actor",
+                        "name": "actor_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "admin_user",
+                            "client_user",
+                            "anonymous",
+                            "operator"
+                        ],
+                        "type": "string",
+                        "description": "Only events performed by this kind of actor",
+                        "name": "actor_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only events with this action, e.g. client.create",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only events of this client",
+                        "name": "client_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 100, max 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the next page, from pagination.next_cursor; takes precedence over page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "occurred_at",
+                            "-occurred_at"
+                        ],
+                        "type": "string",
+                        "description": "Sort field, prefixed with - for descending order (default -occurred_at)",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved audit events",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.AuditEvent"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - validation error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/clients": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve the clients belonging to the authenticated user, one page at a time",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Client"
+                ],
+                "summary": "List my clients",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only clients whose name contains this text, ignoring case",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only suspended (true) or active (false) clients",
+                        "name": "suspended",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the next page, from pagination.next_cursor; takes precedence over page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "id",
+                            "-id",
+                            "client_name",
+                            "-client_name",
+                            "created_at",
+                            "-created_at"
+                        ],
+                        "type": "string",
+                        "description": "Sort field, prefixed with - for descending order (default id)",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved all clients",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.Client"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - validation error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a client of the authenticated user with its own schema. The response holds the client secret.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Client"
+                ],
+                "summary": "Create a new client",
+                "parameters": [
+                    {
+                        "description": "Client creation data",
+                        "name": "client",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.CreateClient"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key that makes retries of the request safe; a replayed response has no body, since it held the secret",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Client created successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.Client"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the new client"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - validation error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - client name already exists",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable - Idempotency-Key reused for a different request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/clients/{clientId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve one of the authenticated user's clients",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Client"
+                ],
+                "summary": "Get a client",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "clientId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached copy of the client",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved client",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.Client"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the client"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "The cached copy is current"
+                    },
+                    "400": {
+                        "description": "Bad request - validation error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Client not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/clients/{clientId}/config": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve the settings of one of the authenticated user's clients. A client that was never configured has empty settings at version 0.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Client"
+                ],
+                "summary": "Get a client's configuration",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "clientId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached copy of the configuration",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved client configuration",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.ClientSettings"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the configuration"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "The cached copy is current"
+                    },
+                    "400": {
+                        "description": "Bad request - validation error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Client not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace the settings of one of the authenticated user's clients. The password policy holds overrides of the global policy; fields left out keep their global value.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Client"
+                ],
+                "summary": "Replace a client's configuration",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "clientId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New settings",
+                        "name": "settings",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.ClientSettings"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the configuration, \\",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Client configuration updated successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.ClientSettings"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the updated configuration"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - validation error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Client not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition failed - the configuration was modified since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition required - If-Match is missing",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/clients/{clientId}/roles": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve the roles of one of the authenticated user's clients by name",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Client"
+                ],
+                "summary": "List a client's roles",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "clientId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved roles",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.Role"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - validation error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Client not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add a role to one of the authenticated user's clients. Role names may hold letters, digits and _ . : -",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Client"
+                ],
+                "summary": "Create a role",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "clientId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Role creation data",
+                        "name": "role",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.CreateRoleRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key that makes retries of the request safe",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Role created successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.Role"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the role"
+                            },
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the new role"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - validation error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Client not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - the client already has a role of that name",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable - Idempotency-Key reused for a different request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/clients/{clientId}/roles/{roleId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a role of one of the authenticated user's clients",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Client"
+                ],
+                "summary": "Get a role",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "clientId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Role ID",
+                        "name": "roleId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved role",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.Role"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the role"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - validation error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Client or role not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a role of one of the authenticated user's clients; the users it was granted to lose it. Tokens issued before keep listing it until they expire.",
+                "tags": [
+                    "Client"
+                ],
+                "summary": "Delete a role",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "clientId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Role ID",
+                        "name": "roleId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the role",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Role deleted successfully"
+                    },
+                    "400": {
+                        "description": "Bad request - validation error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Client or role not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition failed - the role was modified since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition required - If-Match is missing",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/clients/{clientId}/secret": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Replace the secret of one of the authenticated user's clients. The old secret stops working at once; the response holds the new one.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Client"
+                ],
+                "summary": "Rotate a client's secret",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "clientId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the client",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key that makes retries of the request safe; a replayed response has no body, since it held the secret",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Client secret rotated successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.Client"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the updated client"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - validation error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Client not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition failed - the client was modified since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition required - If-Match is missing",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/clients/{clientId}/sessions": {
+            "post": {
+                "description": "Authenticate a user of a client with username and password, starting a session",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Client user login",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "clientId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Login credentials",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Login successful",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.LoginResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid credentials",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/clients/{clientId}/users": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve the users of one of the authenticated user's clients, one page at a time",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Client"
+                ],
+                "summary": "List ClientUsers",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "clientId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only users whose username contains this text, ignoring case",
+                        "name": "username",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only users whose email contains this text, ignoring case",
+                        "name": "email",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only disabled (true) or enabled (false) users",
+                        "name": "disabled",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the next page, from pagination.next_cursor; takes precedence over page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "id",
+                            "-id",
+                            "username",
+                            "-username",
+                            "email",
+                            "-email",
+                            "created_at",
+                            "-created_at"
+                        ],
+                        "type": "string",
+                        "description": "Sort field, prefixed with - for descending order (default id)",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved users",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.ClientUser"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - validation error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Client not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create and add a new user account to one of the authenticated user's clients",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Client"
+                ],
+                "summary": "Create a new ClientUser",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "clientId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User creation data",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.CreateUser"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key that makes retries of the request safe",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "User created successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.ClientUser"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the new user"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - validation error or password policy violation",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Client not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - username or email already exists",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable - Idempotency-Key reused for a different request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/clients/{clientId}/users/{userId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a user of one of the authenticated user's clients",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Client"
+                ],
+                "summary": "Get a ClientUser",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "clientId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Client user ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached copy of the user",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved user",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.ClientUser"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the user"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "The cached copy is current"
+                    },
+                    "400": {
+                        "description": "Bad request - validation error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Client or user not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Disable a user of one of the authenticated user's clients and revoke all their sessions. Disabled users cannot be enabled again.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Client"
+                ],
+                "summary": "Update a ClientUser",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "clientId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Client user ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Changes to the user",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.UpdateClientUserRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the user the changes are based on",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User updated successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.ClientUser"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the updated user"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - validation error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Client or user not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition failed - the user was modified since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition required - If-Match is missing",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/clients/{clientId}/users/{userId}/password": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Set a new password for a user of one of the authenticated user's clients and revoke all their sessions",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Client"
+                ],
+                "summary": "Reset a ClientUser's password",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "clientId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Client user ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New password",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.NewPasswordRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the user",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Password reset successfully",
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the updated user"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - validation error or password policy violation",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Client or user not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition failed - the user was modified since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition required - If-Match is missing",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/clients/{clientId}/users/{userId}/roles": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve the roles granted to a user of one of the authenticated user's clients",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Client"
+                ],
+                "summary": "List a ClientUser's roles",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "clientId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Client user ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved roles",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.Role"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the user, which changes with their roles"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - validation error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Client or user not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Grant a user of one of the authenticated user's clients exactly the given roles of the client. The roles are versioned with the user, so the change increments the user's version. Tokens list the roles granted when they were issued, so the change applies from the user's next login or refresh.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Client"
+                ],
+                "summary": "Replace a ClientUser's roles",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "clientId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Client user ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Names of the roles to grant",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.SetClientUserRolesRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the user or of their roles",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Roles updated successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.Role"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the updated user"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - validation error or unknown role",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Client or user not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition failed - the user or their roles were modified since they were read",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition required - If-Match is missing",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/clients/{clientId}/users/{userId}/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the active sessions of a user of one of the authenticated user's clients",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "List a client user's sessions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "clientId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Client user ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the next page, from pagination.next_cursor; takes precedence over page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "last_seen_at",
+                            "-last_seen_at",
+                            "created_at",
+                            "-created_at",
+                            "expires_at",
+                            "-expires_at"
+                        ],
+                        "type": "string",
+                        "description": "Sort field, prefixed with - for descending order (default -last_seen_at)",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved sessions",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.Session"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - validation error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Client or user not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revoke every session of a user of one of the authenticated user's clients",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Revoke a client user's sessions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "clientId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Client user ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Sessions revoked successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.RevokeSessionsResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - validation error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Client or user not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/clients/{clientId}/users/{userId}/sessions/{sessionId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revoke one session of a user of one of the authenticated user's clients",
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Revoke a client user's session",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "clientId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Client user ID",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Session revoked successfully"
+                    },
+                    "400": {
+                        "description": "Bad request - validation error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Client, user or session not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/clients/{clientId}/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve the webhook subscriptions of one of the authenticated user's clients",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhooks of a client",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "clientId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved webhooks",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.WebhookSubscription"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - validation error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Client not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Subscribe a URL to events of one of the authenticated user's clients. Deliveries are signed with HMAC-SHA256 using the returned secret, which is only shown once.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Subscribe to webhooks",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "clientId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Webhook subscription",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.WebhookSubscriptionRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key that makes retries of the request safe; a replayed response has no body, since it held the secret",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Webhook created successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.CreateWebhookResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - validation error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Client not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable - Idempotency-Key reused for a different request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/clients/{clientId}/webhooks/{webhookId}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a webhook subscription of one of the authenticated user's clients",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Get a webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "clientId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "webhookId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached copy of the webhook",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved webhook",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.WebhookSubscription"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the webhook"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "The cached copy is current"
+                    },
+                    "400": {
+                        "description": "Bad request - validation error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Client or webhook not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a webhook subscription. Pending deliveries are marked failed on their next attempt.",
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Delete a webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "clientId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "webhookId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the webhook",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Webhook deleted successfully"
+                    },
+                    "400": {
+                        "description": "Bad request - validation error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Client or webhook not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition failed - the webhook was modified since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition required - If-Match is missing",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/clients/{clientId}/webhooks/{webhookId}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve the delivery log of a webhook subscription with every attempt, newest deliveries first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhook deliveries",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "clientId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "webhookId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "succeeded",
+                            "failed"
+                        ],
+                        "type": "string",
+                        "description": "Only deliveries with this status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of deliveries (default 50, max 500)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved deliveries",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.WebhookDeliveryLog"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - validation error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Client or webhook not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/clients/{clientId}/webhooks/{webhookId}/deliveries/{deliveryId}/attempts": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Queue a delivery to be sent again right away",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Redeliver a webhook",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Client ID",
+                        "name": "clientId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Webhook ID",
+                        "name": "webhookId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Delivery ID",
+                        "name": "deliveryId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Delivery queued successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.WebhookDelivery"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - validation error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Client, webhook or delivery not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/ping": {
+            "get": {
+                "description": "Ping endpoint to check if the service is running",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Health check endpoint",
+                "responses": {
+                    "200": {
+                        "description": "Service status response",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the active sessions of the authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "List my sessions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of the next page, from pagination.next_cursor; takes precedence over page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "last_seen_at",
+                            "-last_seen_at",
+                            "created_at",
+                            "-created_at",
+                            "expires_at",
+                            "-expires_at"
+                        ],
+                        "type": "string",
+                        "description": "Sort field, prefixed with - for descending order (default -last_seen_at)",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved sessions",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.PaginatedResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.Session"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - validation error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Authenticate user with username and password, starting a session",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "User login",
+                "parameters": [
+                    {
+                        "description": "Login credentials",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Login successful",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.LoginResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid credentials",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revoke every session of the authenticated user except the current one",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Revoke all my other sessions",
+                "responses": {
+                    "200": {
+                        "description": "Sessions revoked successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.RevokeSessionsResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/sessions/{sessionId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revoke a session of the authenticated user, signing out that device",
+                "tags": [
+                    "sessions"
+                ],
+                "summary": "Revoke one of my sessions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Session ID",
+                        "name": "sessionId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Session revoked successfully"
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Session not found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/users": {
+            "post": {
+                "description": "Create a new user account in the system",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Create a new user",
+                "parameters": [
+                    {
+                        "description": "User creation data",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.CreateUser"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key that makes retries of the request safe",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "User created successfully",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.CreateUserResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "URL of the authenticated user"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - validation error or password policy violation",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict - username or email already exists",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable - Idempotency-Key reused for a different request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/users/me": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve the account of the authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get my account",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached copy of the user",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully retrieved user",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.SuccessResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.UserInfo"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the user"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "The cached copy is current"
+                    },
+                    "401": {
+                        "description": "Unauthorized - invalid user",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/users/me/password": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change the password of the authenticated user",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Change password",
+                "parameters": [
+                    {
+                        "description": "Current and new password",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.ChangePasswordRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the user, from GET /v2/users/me",
+                        "name": "If-Match",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Password changed successfully",
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the updated user"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request - validation error or password policy violation",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid current password",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition failed - the user was modified since it was read",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition required - If-Match is missing",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "github_com_Kantha2004_SimpleJWT_internal_apiResponse.ErrorCode": {
+            "type": "string",
+            "enum": [
+                "validation_error",
+                "malformed_request",
+                "password_policy_violation",
+                "unauthorized",
+                "missing_token",
+                "invalid_token",
+                "token_expired",
+                "session_revoked",
+                "invalid_csrf_token",
+                "invalid_credentials",
+                "user_disabled",
+                "client_suspended",
+                "not_found",
+                "method_not_allowed",
+                "conflict",
+                "already_exists",
+                "precondition_failed",
+                "precondition_required",
+                "idempotency_key_reused",
+                "idempotency_request_in_progress",
+                "rate_limited",
+                "internal_error"
+            ],
+            "x-enum-varnames": [
+                "CodeValidation",
+                "CodeMalformedRequest",
+                "CodePasswordPolicy",
+                "CodeUnauthorized",
+                "CodeMissingToken",
+                "CodeInvalidToken",
+                "CodeTokenExpired",
+                "CodeSessionRevoked",
+                "CodeInvalidCSRFToken",
+                "CodeInvalidCredentials",
+                "CodeUserDisabled",
+                "CodeClientSuspended",
+                "CodeNotFound",
+                "CodeMethodNotAllowed",
+                "CodeConflict",
+                "CodeAlreadyExists",
+                "CodePreconditionFailed",
+                "CodePreconditionRequired",
+                "CodeIdempotencyKeyReused",
+                "CodeIdempotencyInProgress",
+                "CodeRateLimited",
+                "CodeInternal"
+            ]
+        },
+        "github_com_Kantha2004_SimpleJWT_internal_apiResponse.FieldError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "email"
+                },
+                "field": {
+                    "description": "Field is the JSON name of the invalid field, empty when the error\nconcerns the request as a whole",
+                    "type": "string",
+                    "example": "email"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "must be a valid email address"
+                }
+            }
+        },
+        "github_com_Kantha2004_SimpleJWT_internal_apiResponse.PaginatedResponse": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "message": {
+                    "type": "string",
+                    "example": "Operation completed successfully"
+                },
+                "pagination": {
+                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.Pagination"
+                },
+                "status": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "github_com_Kantha2004_SimpleJWT_internal_apiResponse.Pagination": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "next_cursor": {
+                    "type": "string",
+                    "example": "eyJzIjoiaWQiLCJ2IjoxMCwiaWQiOjEwfQ"
+                },
+                "page": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "total_pages": {
+                    "type": "integer",
+                    "example": 10
+                }
+            }
+        },
+        "github_com_Kantha2004_SimpleJWT_internal_apiResponse.Problem": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Code is the stable, machine-readable error code",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.ErrorCode"
+                        }
+                    ],
+                    "example": "validation_error"
+                },
+                "detail": {
+                    "description": "Detail explains this occurrence of the problem",
+                    "type": "string",
+                    "example": "The request has invalid fields"
+                },
+                "errors": {
+                    "description": "Errors lists the invalid fields or failed rules, if any",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.FieldError"
+                    }
+                },
+                "instance": {
+                    "type": "string",
+                    "example": "/api/v2/users"
+                },
+                "request_id": {
+                    "description": "RequestID matches the X-Request-ID response header and the server logs",
+                    "type": "string",
+                    "example": "6f1c2a9e-3b7d-4c55-9a43-0d5f7e2b8c11"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 400
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Validation failed"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "/api/problems/validation_error"
+                }
+            }
+        },
+        "github_com_Kantha2004_SimpleJWT_internal_apiResponse.ProblemType": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_apiResponse.ErrorCode"
+                        }
+                    ],
+                    "example": "validation_error"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 400
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Validation failed"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "/api/problems/validation_error"
+                }
+            }
+        },
+        "github_com_Kantha2004_SimpleJWT_internal_apiResponse.SuccessResponse": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "message": {
+                    "type": "string",
+                    "example": "Operation completed successfully"
+                },
+                "status": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "github_com_Kantha2004_SimpleJWT_internal_models.AuditEvent": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "client.create"
+                },
+                "actor_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "actor_name": {
+                    "type": "string",
+                    "example": "john_doe"
+                },
+                "actor_type": {
+                    "type": "string",
+                    "example": "admin_user"
+                },
+                "client_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "ip_address": {
+                    "type": "string",
+                    "example": "203.0.113.7"
+                },
+                "occurred_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                },
+                "outcome": {
+                    "type": "string",
+                    "example": "success"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "invalid password"
+                },
+                "target_id": {
+                    "type": "string",
+                    "example": "1"
+                },
+                "target_type": {
+                    "type": "string",
+                    "example": "client"
+                },
+                "user_agent": {
+                    "type": "string",
+                    "example": "Mozilla/5.0"
+                }
+            }
+        },
+        "github_com_Kantha2004_SimpleJWT_internal_models.ChangePasswordRequest": {
+            "type": "object",
+            "required": [
+                "current_password",
+                "new_password"
+            ],
+            "properties": {
+                "current_password": {
+                    "type": "string",
+                    "example": "password123"
+                },
+                "new_password": {
+                    "type": "string",
+                    "example": "N3w-Passw0rd!"
+                }
+            }
+        },
+        "github_com_Kantha2004_SimpleJWT_internal_models.Client": {
+            "type": "object",
+            "properties": {
+                "client_name": {
+                    "type": "string"
+                },
+                "client_secret": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "schema_name": {
+                    "type": "string"
+                },
+                "suspended_at": {
+                    "description": "SuspendedAt is set while the client is suspended; its users cannot log in",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "version": {
+                    "description": "Version is incremented by every update; the ETag of the client",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "github_com_Kantha2004_SimpleJWT_internal_models.ClientSettings": {
+            "type": "object",
+            "properties": {
+                "default_locale": {
+                    "description": "DefaultLocale is the language of error messages for requests whose\nAccept-Language header names no supported language (e.g. \"fr\")",
+                    "type": "string",
+                    "example": "fr"
+                },
+                "password_policy": {
+                    "description": "PasswordPolicy holds per-client overrides of the global password policy.\nOnly the fields present in the JSON are overridden.",
+                    "type": "object"
+                }
+            }
+        },
+        "github_com_Kantha2004_SimpleJWT_internal_models.ClientUser": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "disabled_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "john@example.com"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "john_doe"
+                },
+                "version": {
+                    "description": "Version is incremented by every update; the ETag of the user",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "github_com_Kantha2004_SimpleJWT_internal_models.ClientUserLoginRequest": {
+            "type": "object",
+            "required": [
+                "client_id",
+                "password",
+                "username"
+            ],
+            "properties": {
+                "client_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "password": {
+                    "type": "string",
+                    "example": "password123"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "john_doe"
+                }
+            }
+        },
+        "github_com_Kantha2004_SimpleJWT_internal_models.ClientUserRequest": {
+            "type": "object",
+            "required": [
+                "client_id",
+                "user_id"
+            ],
+            "properties": {
+                "client_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "github_com_Kantha2004_SimpleJWT_internal_models.ClientUserSessionsRequest": {
+            "type": "object",
+            "required": [
+                "client_id",
+                "user_id"
+            ],
+            "properties": {
+                "client_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "session_id": {
+                    "description": "SessionID limits a revocation to a single session; empty revokes all of them",
+                    "type": "string",
+                    "example": "3f1c8f9e-7c1e-4a8e-9a4e-1b2c3d4e5f60"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "github_com_Kantha2004_SimpleJWT_internal_models.CreateClient": {
+            "type": "object",
+            "required": [
+                "client_name"
+            ],
+            "properties": {
+                "client_name": {
+                    "description": "ClientName names the schema of the client, so it may only hold\nlowercase letters, digits and underscores",
+                    "type": "string",
+                    "example": "acme"
+                }
+            }
+        },
+        "github_com_Kantha2004_SimpleJWT_internal_models.CreateClientReponse": {
+            "type": "object",
+            "required": [
+                "client_secret"
+            ],
+            "properties": {
+                "client_secret": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_Kantha2004_SimpleJWT_internal_models.CreateClientUser": {
+            "type": "object",
+            "required": [
+                "client_id",
+                "email",
+                "password",
+                "username"
+            ],
+            "properties": {
+                "client_id": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "email": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "john@example.com"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "password123"
+                },
+                "username": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 3,
+                    "example": "john_doe"
+                }
+            }
+        },
+        "github_com_Kantha2004_SimpleJWT_internal_models.CreateRoleRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "example": "Can edit articles"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "example": "editor"
+                }
+            }
+        },
+        "github_com_Kantha2004_SimpleJWT_internal_models.CreateUser": {
+            "type": "object",
+            "required": [
+                "email",
+                "password",
+                "username"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "john@example.com"
+                },
+                "password": {
+                    "type": "string",
+                    "example": "password123"
+                },
+                "username": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 3,
+                    "example": "john_doe"
+                }
+            }
+        },
+        "github_com_Kantha2004_SimpleJWT_internal_models.CreateUserResponse": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "john@example.com"
+                },
+                "userId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "username": {
+                    "type": "string",
+                    "example": "john_doe"
+                }
+            }
+        },
+        "github_com_Kantha2004_SimpleJWT_internal_models.CreateWebhookRequest": {
+            "type": "object",
+            "required": [
+                "client_id",
+                "url"
+            ],
+            "properties": {
+                "client_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "client_user.created"
+                    ]
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://example.com/hooks/simplejwt"
+                }
+            }
+        },
+        "github_com_Kantha2004_SimpleJWT_internal_models.CreateWebhookResponse": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "client_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "client_user.created"
+                    ]
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "secret": {
+                    "type": "string",
+                    "example": "4f9c0b1e..."
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://example.com/hooks/simplejwt"
+                },
+                "version": {
+                    "description": "Version is incremented by every update; the ETag of the subscription",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "github_com_Kantha2004_SimpleJWT_internal_models.LoginRequest": {
+            "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "example": "password123"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "john_doe"
+                }
+            }
+        },
+        "github_com_Kantha2004_SimpleJWT_internal_models.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "expiresAt": {
+                    "type": "string",
+                    "example": "2023-01-02T00:00:00Z"
+                },
+                "sessionId": {
+                    "type": "string",
+                    "example": "3f1c8f9e-7c1e-4a8e-9a4e-1b2c3d4e5f60"
+                },
+                "token": {
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+                },
+                "user": {
+                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.UserInfo"
+                }
+            }
+        },
+        "github_com_Kantha2004_SimpleJWT_internal_models.NewPasswordRequest": {
+            "type": "object",
+            "required": [
+                "new_password"
+            ],
+            "properties": {
+                "new_password": {
+                    "type": "string",
+                    "example": "N3w-Passw0rd!"
+                }
+            }
+        },
+        "github_com_Kantha2004_SimpleJWT_internal_models.RedeliverWebhookRequest": {
+            "type": "object",
+            "required": [
+                "delivery_id"
+            ],
+            "properties": {
+                "delivery_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "github_com_Kantha2004_SimpleJWT_internal_models.ResetClientUserPasswordRequest": {
+            "type": "object",
+            "required": [
+                "client_id",
+                "new_password",
+                "user_id"
+            ],
+            "properties": {
+                "client_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "new_password": {
+                    "type": "string",
+                    "example": "N3w-Passw0rd!"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "github_com_Kantha2004_SimpleJWT_internal_models.RevokeSessionRequest": {
             "type": "object",
+            "required": [
+                "session_id"
+            ],
             "properties": {
-                "error": {
-                    "type": "string",
-                    "example": "validation_error"
-                },
-                "message": {
+                "session_id": {
                     "type": "string",
-                    "example": "Operation completed successfully"
-                },
-                "status": {
-                    "type": "boolean",
-                    "example": true
+                    "example": "3f1c8f9e-7c1e-4a8e-9a4e-1b2c3d4e5f60"
                 }
             }
         },
-        "github_com_Kantha2004_SimpleJWT_internal_apiResponse.SuccessResponse": {
+        "github_com_Kantha2004_SimpleJWT_internal_models.RevokeSessionsResponse": {
             "type": "object",
             "properties": {
-                "data": {},
-                "message": {
-                    "type": "string",
-                    "example": "Operation completed successfully"
-                },
-                "status": {
-                    "type": "boolean",
-                    "example": true
+                "revoked": {
+                    "type": "integer",
+                    "example": 2
                 }
             }
         },
-        "github_com_Kantha2004_SimpleJWT_internal_models.Client": {
+        "github_com_Kantha2004_SimpleJWT_internal_models.Role": {
             "type": "object",
             "properties": {
-                "client_name": {
-                    "type": "string"
-                },
-                "client_secret": {
-                    "type": "string"
-                },
                 "created_at": {
                     "type": "string"
                 },
+                "description": {
+                    "type": "string",
+                    "example": "Can edit articles"
+                },
                 "id": {
-                    "type": "integer"
+                    "type": "integer",
+                    "example": 1
                 },
-                "schema_name": {
-                    "type": "string"
+                "name": {
+                    "type": "string",
+                    "example": "editor"
                 },
                 "updated_at": {
                     "type": "string"
                 },
-                "user_id": {
-                    "type": "integer"
+                "version": {
+                    "type": "integer",
+                    "example": 1
                 }
             }
         },
-        "github_com_Kantha2004_SimpleJWT_internal_models.ClientUser": {
+        "github_com_Kantha2004_SimpleJWT_internal_models.Session": {
             "type": "object",
             "properties": {
+                "client_id": {
+                    "type": "integer",
+                    "example": 1
+                },
                 "created_at": {
                     "type": "string"
                 },
-                "email": {
+                "current": {
+                    "type": "boolean"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "family_id": {
                     "type": "string",
-                    "example": "john@example.com"
+                    "example": "9b2d7c4a-1f3e-4d5c-8b6a-7e8f9a0b1c2d"
                 },
                 "id": {
                     "type": "integer"
                 },
+                "ip_address": {
+                    "type": "string",
+                    "example": "203.0.113.7"
+                },
+                "last_seen_at": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "session_id": {
+                    "type": "string",
+                    "example": "3f1c8f9e-7c1e-4a8e-9a4e-1b2c3d4e5f60"
+                },
                 "updated_at": {
                     "type": "string"
                 },
-                "username": {
+                "user_agent": {
                     "type": "string",
-                    "example": "john_doe"
+                    "example": "Mozilla/5.0"
+                },
+                "user_id": {
+                    "type": "integer",
+                    "example": 1
                 }
             }
         },
-        "github_com_Kantha2004_SimpleJWT_internal_models.CreateClient": {
+        "github_com_Kantha2004_SimpleJWT_internal_models.SetClientUserRolesRequest": {
             "type": "object",
             "required": [
-                "client_name"
+                "roles"
             ],
             "properties": {
-                "client_name": {
-                    "type": "string"
+                "roles": {
+                    "type": "array",
+                    "maxItems": 50,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "editor"
+                    ]
                 }
             }
         },
-        "github_com_Kantha2004_SimpleJWT_internal_models.CreateClientReponse": {
+        "github_com_Kantha2004_SimpleJWT_internal_models.UpdateClientUserRequest": {
             "type": "object",
             "required": [
-                "client_secret"
+                "disabled"
             ],
             "properties": {
-                "client_secret": {
-                    "type": "string"
+                "disabled": {
+                    "type": "boolean",
+                    "example": true
                 }
             }
         },
-        "github_com_Kantha2004_SimpleJWT_internal_models.CreateClientUser": {
+        "github_com_Kantha2004_SimpleJWT_internal_models.UserInfo": {
             "type": "object",
-            "required": [
-                "client_id",
-                "email",
-                "password",
-                "username"
-            ],
             "properties": {
-                "client_id": {
-                    "type": "integer",
-                    "example": 1
-                },
                 "email": {
                     "type": "string",
-                    "maxLength": 100,
                     "example": "john@example.com"
                 },
-                "password": {
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 8,
-                    "example": "password123"
+                "id": {
+                    "type": "integer",
+                    "example": 1
                 },
                 "username": {
                     "type": "string",
-                    "maxLength": 50,
-                    "minLength": 3,
                     "example": "john_doe"
                 }
             }
         },
-        "github_com_Kantha2004_SimpleJWT_internal_models.CreateUser": {
+        "github_com_Kantha2004_SimpleJWT_internal_models.WebhookDelivery": {
             "type": "object",
-            "required": [
-                "email",
-                "password",
-                "username"
-            ],
             "properties": {
-                "email": {
+                "attempts": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delivered_at": {
+                    "type": "string"
+                },
+                "event_id": {
                     "type": "string",
-                    "maxLength": 100,
-                    "example": "john@example.com"
+                    "example": "0f8e6c1a-2b3d-4e5f-8a9b-0c1d2e3f4a5b"
                 },
-                "password": {
+                "event_type": {
                     "type": "string",
-                    "maxLength": 100,
-                    "minLength": 8,
-                    "example": "password123"
+                    "example": "client_user.created"
                 },
-                "username": {
+                "id": {
+                    "type": "integer"
+                },
+                "last_attempt_at": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_status_code": {
+                    "type": "integer",
+                    "example": 200
+                },
+                "next_attempt_at": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "status": {
                     "type": "string",
-                    "maxLength": 50,
-                    "minLength": 3,
-                    "example": "john_doe"
+                    "example": "pending"
+                },
+                "subscription_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
-        "github_com_Kantha2004_SimpleJWT_internal_models.CreateUserResponse": {
+        "github_com_Kantha2004_SimpleJWT_internal_models.WebhookDeliveryAttempt": {
             "type": "object",
             "properties": {
-                "email": {
-                    "type": "string",
-                    "example": "john@example.com"
+                "attempt": {
+                    "type": "integer",
+                    "example": 1
                 },
-                "userId": {
+                "attempted_at": {
+                    "type": "string"
+                },
+                "delivery_id": {
                     "type": "integer",
                     "example": 1
                 },
-                "username": {
-                    "type": "string",
-                    "example": "john_doe"
+                "duration_ms": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "status_code": {
+                    "type": "integer",
+                    "example": 500
                 }
             }
         },
-        "github_com_Kantha2004_SimpleJWT_internal_models.LoginRequest": {
+        "github_com_Kantha2004_SimpleJWT_internal_models.WebhookDeliveryLog": {
             "type": "object",
-            "required": [
-                "password",
-                "username"
-            ],
             "properties": {
-                "password": {
+                "attempt_log": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.WebhookDeliveryAttempt"
+                    }
+                },
+                "attempts": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delivered_at": {
+                    "type": "string"
+                },
+                "event_id": {
                     "type": "string",
-                    "example": "password123"
+                    "example": "0f8e6c1a-2b3d-4e5f-8a9b-0c1d2e3f4a5b"
                 },
-                "username": {
+                "event_type": {
                     "type": "string",
-                    "example": "john_doe"
+                    "example": "client_user.created"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_attempt_at": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_status_code": {
+                    "type": "integer",
+                    "example": 200
+                },
+                "next_attempt_at": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "pending"
+                },
+                "subscription_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
-        "github_com_Kantha2004_SimpleJWT_internal_models.LoginResponse": {
+        "github_com_Kantha2004_SimpleJWT_internal_models.WebhookIDRequest": {
             "type": "object",
+            "required": [
+                "webhook_id"
+            ],
             "properties": {
-                "expiresAt": {
-                    "type": "string",
-                    "example": "2023-01-02T00:00:00Z"
-                },
-                "token": {
-                    "type": "string",
-                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
-                },
-                "user": {
-                    "$ref": "#/definitions/github_com_Kantha2004_SimpleJWT_internal_models.UserInfo"
+                "webhook_id": {
+                    "type": "integer",
+                    "example": 1
                 }
             }
         },
-        "github_com_Kantha2004_SimpleJWT_internal_models.UserInfo": {
+        "github_com_Kantha2004_SimpleJWT_internal_models.WebhookSubscription": {
             "type": "object",
             "properties": {
-                "email": {
-                    "type": "string",
-                    "example": "john@example.com"
+                "active": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "client_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "client_user.created"
+                    ]
                 },
                 "id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://example.com/hooks/simplejwt"
+                },
+                "version": {
+                    "description": "Version is incremented by every update; the ETag of the subscription",
                     "type": "integer",
                     "example": 1
+                }
+            }
+        },
+        "github_com_Kantha2004_SimpleJWT_internal_models.WebhookSubscriptionRequest": {
+            "type": "object",
+            "required": [
+                "url"
+            ],
+            "properties": {
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "client_user.created"
+                    ]
                 },
-                "username": {
+                "url": {
                     "type": "string",
-                    "example": "john_doe"
+                    "example": "https://example.com/hooks/simplejwt"
                 }
             }
         }
@@ -661,7 +5961,7 @@ const docTemplate = `{
 var SwaggerInfo = &swag.Spec{
 	Version:          "1.0",
 	Host:             "localhost:9000",
-	BasePath:         "/api/v1",
+	BasePath:         "/api",
 	Schemes:          []string{"http", "https"},
 	Title:            "SimpleJWT API",
 	Description:      "A simple JWT authentication service",
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"io/fs"
+	"net/http"
+	"path"
+
+	"github.com/Kantha2004/SimpleJWT/internal/api/handlers"
+	"github.com/Kantha2004/SimpleJWT/internal/console"
+	"github.com/gin-gonic/gin"
+)
+
+// SetupConsoleRoutes mounts the admin web console and the endpoints its
+// cookie sessions are started and ended with. Each asset gets its own route
+// so the session endpoints can share the console's path.
+func SetupConsoleRoutes(router *gin.Engine, deps *Dependencies, handlerDeps *handlers.Dependencies) error {
+	assets := gin.WrapH(console.Handler())
+
+	router.GET(console.BasePath, func(c *gin.Context) {
+		c.Redirect(http.StatusMovedPermanently, console.BasePath+"/")
+	})
+	err := fs.WalkDir(console.Files(), ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		route := path.Join(console.BasePath, name)
+		if name == "index.html" {
+			route = console.BasePath + "/"
+		}
+		router.GET(route, assets)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	session := router.Group(console.BasePath + "/session")
+	session.POST("", handlerDeps.CreateConsoleSession)
+	session.GET("", JWTMiddleware(deps.JWTService, deps.Sessions, deps.Logger), handlerDeps.GetConsoleSession)
+	session.DELETE("", JWTMiddleware(deps.JWTService, deps.Sessions, deps.Logger), handlerDeps.DeleteConsoleSession)
+
+	return nil
+}
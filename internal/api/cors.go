@@ -0,0 +1,78 @@
+package api
+
+import (
+	"net/http"
+	"slices"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	corsAllowedMethods = "GET, POST, PUT, PATCH, DELETE, OPTIONS"
+	corsAllowedHeaders = "Content-Type, Authorization, If-Match, If-None-Match, " + RequestIDHeader + ", " + IdempotencyKeyHeader
+	corsExposedHeaders = RequestIDHeader + ", Location, ETag, Deprecation, Sunset, Link, Retry-After, " + IdempotentReplayedHeader
+)
+
+// CORSPolicy decides which browser origins may call the API. Its origins are
+// swapped on configuration reloads while requests read them.
+type CORSPolicy struct {
+	origins atomic.Pointer[[]string]
+}
+
+// NewCORSPolicy allows the given origins; "*" allows any origin and none are
+// allowed when the list is empty
+func NewCORSPolicy(allowedOrigins []string) *CORSPolicy {
+	p := &CORSPolicy{}
+	p.Reload(allowedOrigins)
+	return p
+}
+
+// Reload replaces the allowed origins
+func (p *CORSPolicy) Reload(allowedOrigins []string) {
+	origins := slices.Clone(allowedOrigins)
+	p.origins.Store(&origins)
+}
+
+// allowOrigin returns the Access-Control-Allow-Origin value for the origin
+// of a request, empty when it is not allowed
+func (p *CORSPolicy) allowOrigin(origin string) string {
+	origins := *p.origins.Load()
+	switch {
+	case origin == "":
+		return ""
+	case slices.Contains(origins, "*"):
+		return "*"
+	case slices.Contains(origins, origin):
+		return origin
+	default:
+		return ""
+	}
+}
+
+// Middleware adds the CORS headers to responses for allowed origins and
+// answers their preflight requests. Requests of other origins are served
+// without the headers, so browsers keep the responses from their scripts.
+func (p *CORSPolicy) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// The answer depends on the origin, so caches must not share it
+		c.Writer.Header().Add("Vary", "Origin")
+
+		allowed := p.allowOrigin(c.GetHeader("Origin"))
+		if allowed == "" {
+			c.Next()
+			return
+		}
+
+		c.Header("Access-Control-Allow-Origin", allowed)
+		c.Header("Access-Control-Expose-Headers", corsExposedHeaders)
+
+		if c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != "" {
+			c.Header("Access-Control-Allow-Methods", corsAllowedMethods)
+			c.Header("Access-Control-Allow-Headers", corsAllowedHeaders)
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+		c.Next()
+	}
+}
@@ -1,15 +1,37 @@
 package api
 
 import (
+	"log/slog"
+	"time"
+
 	"github.com/Kantha2004/SimpleJWT/internal/auth"
+	"github.com/Kantha2004/SimpleJWT/internal/db"
 )
 
 type Dependencies struct {
 	JWTService *auth.JWTService
+	Sessions   *db.SessionRepository
+	// IdempotencyKeys keep the responses of requests with an Idempotency-Key
+	// for IdempotencyWindow
+	IdempotencyKeys   *db.IdempotencyRepository
+	IdempotencyWindow time.Duration
+	Health            *HealthChecker
+	// CORS and RateLimiter allow no origin and no limit until reloaded with
+	// the configured ones
+	CORS        *CORSPolicy
+	RateLimiter *RateLimiter
+	Logger      *slog.Logger
 }
 
-func NewDependencies(jwtService *auth.JWTService) *Dependencies {
+func NewDependencies(jwtService *auth.JWTService, sessions *db.SessionRepository, idempotencyKeys *db.IdempotencyRepository, idempotencyWindow time.Duration, health *HealthChecker, logger *slog.Logger) *Dependencies {
 	return &Dependencies{
-		JWTService: jwtService,
+		JWTService:        jwtService,
+		Sessions:          sessions,
+		IdempotencyKeys:   idempotencyKeys,
+		IdempotencyWindow: idempotencyWindow,
+		Health:            health,
+		CORS:              NewCORSPolicy(nil),
+		RateLimiter:       NewRateLimiter(0, 0),
+		Logger:            logger,
 	}
 }
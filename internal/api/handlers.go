@@ -3,6 +3,7 @@ package api
 import (
 	"net/http"
 
+	apiresponse "github.com/Kantha2004/SimpleJWT/internal/apiResponse"
 	"github.com/gin-gonic/gin"
 )
 
@@ -13,7 +14,8 @@ import (
 // @Accept json
 // @Produce json
 // @Success 200 {object} map[string]interface{} "Service status response"
-// @Router /ping [get]
+// @Router /v1/ping [get]
+// @Router /v2/ping [get]
 func PingHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"status":  true,
@@ -21,3 +23,43 @@ func PingHandler(c *gin.Context) {
 		"message": "Application is running...",
 	})
 }
+
+// ProblemCatalogHandler godoc
+// @Summary List error codes
+// @Description List every error code with its problem type, title and HTTP status. Codes are stable and safe to branch on.
+// @Tags errors
+// @Produce json
+// @Success 200 {object} apiresponse.SuccessResponse{data=[]apiresponse.ProblemType} "Successfully retrieved error codes"
+// @Router /problems [get]
+func ProblemCatalogHandler(c *gin.Context) {
+	apiresponse.SendSuccess(c, http.StatusOK, apiresponse.Catalog(), "Successfully retrieved error codes")
+}
+
+// ProblemTypeHandler godoc
+// @Summary Describe an error code
+// @Description Describe the error code a problem type URI points to
+// @Tags errors
+// @Produce json
+// @Param code path string true "Error code"
+// @Success 200 {object} apiresponse.SuccessResponse{data=apiresponse.ProblemType} "Successfully retrieved error code"
+// @Failure 404 {object} apiresponse.Problem "Unknown error code"
+// @Router /problems/{code} [get]
+func ProblemTypeHandler(c *gin.Context) {
+	problemType, ok := apiresponse.LookupProblemType(apiresponse.ErrorCode(c.Param("code")))
+	if !ok {
+		apiresponse.SendNotFound(c, "Unknown error code")
+		return
+	}
+	apiresponse.SendSuccess(c, http.StatusOK, problemType, "Successfully retrieved error code")
+}
+
+// NoRouteHandler answers requests matching no route
+func NoRouteHandler(c *gin.Context) {
+	apiresponse.SendError(c, apiresponse.CodeNotFound, "No route matches %s", c.Request.URL.Path)
+}
+
+// NoMethodHandler answers requests for a route that exists with another
+// method
+func NoMethodHandler(c *gin.Context) {
+	apiresponse.SendError(c, apiresponse.CodeMethodNotAllowed, "%s is not allowed on %s", c.Request.Method, c.Request.URL.Path)
+}
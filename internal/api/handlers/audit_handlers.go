@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"strconv"
+
+	apiresponse "github.com/Kantha2004/SimpleJWT/internal/apiResponse"
+	"github.com/Kantha2004/SimpleJWT/internal/db"
+	"github.com/Kantha2004/SimpleJWT/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// RecordAudit appends an event to the audit log, filling in the request
+// details. Every mutation should record one. Failures are only logged so
+// auditing never breaks the request itself.
+func (d *Dependencies) RecordAudit(c *gin.Context, event models.AuditEvent) {
+	d.authService.RecordAudit(c.Request.Context(), caller(c), event)
+}
+
+// GetAuditEvents godoc
+// @Summary Query the audit log
+// @Description Retrieve audit events of the authenticated user and of their clients, newest first
+// @Tags audit
+// @Accept json
+// @Produce json
+// @Param from query string false "Only events at or after this time (RFC 3339)"
+// @Param to query string false "Only events at or before this time (RFC 3339)"
+// @Param actor_id query int false "Only events performed by this actor"
+// @Param actor_type query string false "Only events performed by this kind of actor" Enums(admin_user, client_user, anonymous, operator)
+// @Param action query string false "Only events with this action, e.g. client.create"
+// @Param client_id query int false "Only events of this client"
+// @Param page query int false "Page number, starting at 1"
+// @Param limit query int false "Page size (default 100, max 1000)"
+// @Param cursor query string false "Cursor of the next page, from pagination.next_cursor; takes precedence over page"
+// @Param sort query string false "Sort field, prefixed with - for descending order (default -occurred_at)" Enums(occurred_at, -occurred_at)
+// @Success 200 {object} apiresponse.PaginatedResponse{data=[]models.AuditEvent} "Successfully retrieved audit events"
+// @Failure 400 {object} apiresponse.Problem "Bad request - validation error"
+// @Failure 401 {object} apiresponse.Problem "Unauthorized - invalid user"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Router /v1/audit [get]
+// @Router /v2/audit-events [get]
+// @Security BearerAuth
+func (d *Dependencies) GetAuditEvents(c *gin.Context) {
+	var query models.AuditEventQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		apiresponse.SendValidationError(c, err)
+		return
+	}
+
+	user, ok := d.ValidateUserFromContext(c)
+	if !ok {
+		return
+	}
+
+	page, err := db.NewAuditRepository(d.requestDB(c)).ListAuditEventsForOwner(user.ID, query)
+	if err != nil {
+		d.handleListError(c, "Error fetching audit events", err, "user_id", user.ID)
+		return
+	}
+
+	sendPage(c, page, "Successfully retrieved audit events")
+}
+
+func formatID(id uint) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	apiresponse "github.com/Kantha2004/SimpleJWT/internal/apiResponse"
+	"github.com/Kantha2004/SimpleJWT/internal/db"
+	"github.com/Kantha2004/SimpleJWT/internal/models"
+	"github.com/Kantha2004/SimpleJWT/internal/service"
+	"github.com/Kantha2004/SimpleJWT/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// GetClientConfigV2 godoc
+// @Summary Get a client's configuration
+// @Description Retrieve the settings of one of the authenticated user's clients. A client that was never configured has empty settings at version 0.
+// @Tags Client
+// @Produce json
+// @Param clientId path int true "Client ID"
+// @Param If-None-Match header string false "ETag of a cached copy of the configuration"
+// @Success 200 {object} apiresponse.SuccessResponse{data=models.ClientSettings} "Successfully retrieved client configuration"
+// @Header 200 {string} ETag "Version of the configuration"
+// @Success 304 "The cached copy is current"
+// @Failure 400 {object} apiresponse.Problem "Bad request - validation error"
+// @Failure 401 {object} apiresponse.Problem "Unauthorized - invalid user"
+// @Failure 404 {object} apiresponse.Problem "Client not found"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Router /v2/clients/{clientId}/config [get]
+// @Security BearerAuth
+func (d *Dependencies) GetClientConfigV2(c *gin.Context) {
+	_, client, ok := d.GetClientFromPath(c)
+	if !ok {
+		return
+	}
+
+	config, settings, ok := d.getClientConfig(c, client)
+	if !ok {
+		return
+	}
+
+	sendVersioned(c, http.StatusOK, settings, config.Version, "Successfully retrieved client configuration")
+}
+
+// UpdateClientConfigV2 godoc
+// @Summary Replace a client's configuration
+// @Description Replace the settings of one of the authenticated user's clients. The password policy holds overrides of the global policy; fields left out keep their global value.
+// @Tags Client
+// @Accept json
+// @Produce json
+// @Param clientId path int true "Client ID"
+// @Param settings body models.ClientSettings true "New settings"
+// @Param If-Match header string true "ETag of the configuration, \"0\" for a client that was never configured"
+// @Success 200 {object} apiresponse.SuccessResponse{data=models.ClientSettings} "Client configuration updated successfully"
+// @Header 200 {string} ETag "Version of the updated configuration"
+// @Failure 400 {object} apiresponse.Problem "Bad request - validation error"
+// @Failure 401 {object} apiresponse.Problem "Unauthorized - invalid user"
+// @Failure 404 {object} apiresponse.Problem "Client not found"
+// @Failure 412 {object} apiresponse.Problem "Precondition failed - the configuration was modified since it was read"
+// @Failure 428 {object} apiresponse.Problem "Precondition required - If-Match is missing"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Router /v2/clients/{clientId}/config [put]
+// @Security BearerAuth
+func (d *Dependencies) UpdateClientConfigV2(c *gin.Context) {
+	var req models.ClientSettings
+
+	if verified := utils.VerifyRequestModel(c, &req); !verified {
+		return
+	}
+
+	if fieldErrors := d.validateClientSettings(&req); len(fieldErrors) > 0 {
+		apiresponse.SendErrorWithDetails(c, apiresponse.CodeValidation, "The request has invalid fields", fieldErrors)
+		return
+	}
+
+	user, client, ok := d.GetClientFromPath(c)
+	if !ok {
+		return
+	}
+
+	config, _, ok := d.getClientConfig(c, client)
+	if !ok {
+		return
+	}
+
+	if !checkIfMatch(c, config.Version) {
+		return
+	}
+
+	settings, err := json.Marshal(req)
+	if err != nil {
+		d.logError(c, "Error encoding client settings", err, "client_id", client.ID)
+		apiresponse.SendInternalError(c, "Failed to update client configuration")
+		return
+	}
+	config.Settings = settings
+
+	if err := db.NewClientConfigRepository(d.requestDB(c), client.SchemaName).SaveClientConfig(config); err != nil {
+		d.handleUpdateError(c, "Error updating client configuration", "Failed to update client configuration", err, "client_id", client.ID)
+		return
+	}
+
+	event := service.AdminAuditEvent(user, models.AuditActionClientConfigUpdate)
+	event.TargetType = "client"
+	event.TargetID = formatID(client.ID)
+	event.ClientID = &client.ID
+	d.RecordAudit(c, event)
+
+	sendVersioned(c, http.StatusOK, req, config.Version, "Client configuration updated successfully")
+}
+
+// getClientConfig loads the configuration of a client and its decoded
+// settings, handling HTTP error responses automatically. A client that was
+// never configured gets an unsaved configuration at version 0.
+func (d *Dependencies) getClientConfig(c *gin.Context, client *models.Client) (*models.ClientConfig, *models.ClientSettings, bool) {
+	config, err := db.NewClientConfigRepository(d.requestDB(c), client.SchemaName).GetClientConfig()
+	if err != nil {
+		d.logError(c, "Error fetching client configuration", err, "client_id", client.ID)
+		apiresponse.SendInternalError(c, "Error fetching client configuration")
+		return nil, nil, false
+	}
+
+	var settings models.ClientSettings
+	if config == nil {
+		return &models.ClientConfig{}, &settings, true
+	}
+
+	if len(config.Settings) > 0 {
+		if err := json.Unmarshal(config.Settings, &settings); err != nil {
+			d.logError(c, "Error decoding client settings", err, "client_id", client.ID)
+			apiresponse.SendInternalError(c, "Error fetching client configuration")
+			return nil, nil, false
+		}
+	}
+
+	return config, &settings, true
+}
+
+// validateClientSettings checks new client settings: the password policy may
+// only override known fields, and must still be a valid policy once merged
+// with the global one
+func (d *Dependencies) validateClientSettings(settings *models.ClientSettings) []apiresponse.FieldError {
+	var fieldErrors []apiresponse.FieldError
+
+	if settings.DefaultLocale != "" && !apiresponse.IsSupportedLanguage(settings.DefaultLocale) {
+		fieldErrors = append(fieldErrors, apiresponse.NewFieldError("default_locale", "oneof", "must be one of: %s", strings.Join(apiresponse.SupportedLanguages(), ", ")))
+	}
+
+	overrides := bytes.TrimSpace(settings.PasswordPolicy)
+	if len(overrides) == 0 || bytes.Equal(overrides, []byte("null")) {
+		settings.PasswordPolicy = nil
+		return fieldErrors
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(overrides))
+	decoder.DisallowUnknownFields()
+	var override models.PasswordPolicy
+	if err := decoder.Decode(&override); err != nil {
+		return append(fieldErrors, apiresponse.NewFieldError("password_policy", "invalid", "is invalid"))
+	}
+
+	policy, err := d.passwordValidator.PolicyForClient(settings)
+	if err != nil {
+		return append(fieldErrors, apiresponse.NewFieldError("password_policy", "invalid", "is invalid"))
+	}
+
+	if policy.MinLength < 1 {
+		fieldErrors = append(fieldErrors, apiresponse.NewFieldError("password_policy.min_length", "min", "must be at least %s", "1"))
+	}
+	if policy.MaxLength < policy.MinLength {
+		fieldErrors = append(fieldErrors, apiresponse.NewFieldError("password_policy.max_length", "gtefield", "must not be less than password_policy.min_length"))
+	}
+	if limit := d.passwordHasher.MaxPasswordBytes(); limit > 0 && policy.MaxLength > limit {
+		fieldErrors = append(fieldErrors, apiresponse.NewFieldError("password_policy.max_length", "max", "must be at most %s", strconv.Itoa(limit)))
+	}
+	if policy.MaxRepeatedChars < 0 {
+		fieldErrors = append(fieldErrors, apiresponse.NewFieldError("password_policy.max_repeated_chars", "min", "must be at least %s", "0"))
+	}
+	if policy.HistorySize < 0 {
+		fieldErrors = append(fieldErrors, apiresponse.NewFieldError("password_policy.history_size", "min", "must be at least %s", "0"))
+	}
+
+	return fieldErrors
+}
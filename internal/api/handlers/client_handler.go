@@ -2,12 +2,13 @@ package handlers
 
 import (
 	"fmt"
-	"log"
 	"net/http"
+	"strconv"
 
 	apiresponse "github.com/Kantha2004/SimpleJWT/internal/apiResponse"
 	"github.com/Kantha2004/SimpleJWT/internal/db"
 	"github.com/Kantha2004/SimpleJWT/internal/models"
+	"github.com/Kantha2004/SimpleJWT/internal/service"
 	"github.com/Kantha2004/SimpleJWT/internal/utils"
 	"github.com/gin-gonic/gin"
 )
@@ -19,11 +20,14 @@ import (
 // @Accept json
 // @Produce json
 // @Param user body models.CreateClient true "Client creation data"
+// @Param Idempotency-Key header string false "Key that makes retries of the request safe; a replayed response has no body, since it held the secret"
 // @Success 201 {object} apiresponse.SuccessResponse{data=models.CreateClientReponse} "Client created successfully"
-// @Failure 400 {object} apiresponse.ErrorResponse "Bad request - validation error"
-// @Failure 409 {object} apiresponse.ErrorResponse "Conflict - client name already exists"
-// @Failure 500 {object} apiresponse.ErrorResponse "Internal server error"
-// @Router /protected/createClient [post]
+// @Failure 400 {object} apiresponse.Problem "Bad request - validation error"
+// @Failure 409 {object} apiresponse.Problem "Conflict - client name already exists"
+// @Failure 422 {object} apiresponse.Problem "Unprocessable - Idempotency-Key reused for a different request"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Deprecated
+// @Router /v1/protected/createClient [post]
 // @Security BearerAuth
 func (d *Dependencies) CreateClient(c *gin.Context) {
 	var req models.CreateClient
@@ -39,25 +43,50 @@ func (d *Dependencies) CreateClient(c *gin.Context) {
 		return
 	}
 
+	_, clientSecret, ok := d.createClient(c, user, req)
+	if !ok {
+		return
+	}
+
+	response := models.CreateClientReponse{
+		ClientSecret: clientSecret,
+	}
+
+	markSecret(c)
+	apiresponse.SendSuccess(c, http.StatusCreated, response, "Client created successfully")
+}
+
+// createClient registers a client of the user and creates its schema,
+// handling HTTP error responses automatically. The secret is returned
+// separately since it is only shown once.
+func (d *Dependencies) createClient(c *gin.Context, user *models.AdminUser, req models.CreateClient) (*models.Client, string, bool) {
 	userID := user.ID
 
+	// The schema of the client is named after it
+	schemaName, err := db.ClientSchemaName(req.ClientName)
+	if err != nil {
+		apiresponse.SendErrorWithDetails(c, apiresponse.CodeValidation, "The request has invalid fields", []apiresponse.FieldError{
+			apiresponse.NewFieldError("client_name", "identifier", "must be 1 to %s lowercase letters, digits or underscores", strconv.Itoa(db.MaxClientNameLength)),
+		})
+		return nil, "", false
+	}
+
 	// Check if client name already exists for this user
-	clientRepo := db.NewClientRepository(d.DB)
+	clientRepo := d.clients(c)
 	exists, err := clientRepo.GetClientByNameForUser(req.ClientName, userID)
 	if err != nil {
-		log.Printf("Error checking client existence: %v", err)
+		d.logError(c, "Error checking client existence", err)
 		apiresponse.SendInternalError(c, "Error validating client name")
-		return
+		return nil, "", false
 	}
 
 	if exists != nil {
-		log.Printf("Client name '%s' already exists for user ID %d", req.ClientName, userID)
+		d.logger.InfoContext(c.Request.Context(), "Client name already exists", "client_name", req.ClientName, "user_id", userID)
 		apiresponse.SendAlreadyExistError(c, "Client name already exists")
-		return
+		return nil, "", false
 	}
 
 	// Create client
-	schemaName := fmt.Sprintf("%s_%s_client", user.Username, req.ClientName)
 	client := &models.Client{
 		ClientName: req.ClientName,
 		UserID:     userID,
@@ -66,69 +95,213 @@ func (d *Dependencies) CreateClient(c *gin.Context) {
 
 	clientSecret, err := clientRepo.CreateClient(client)
 	if err != nil {
-		log.Printf("Error creating client: %v", err)
+		d.logError(c, "Error creating client", err)
 		apiresponse.SendInternalError(c, "Failed to create client")
-		return
+		return nil, "", false
 	}
 
+	traceClient(c, client.ID, schemaName)
+
 	// Create client schema and migrate tables
-	if err := d.DB.CreateClientSchema(schemaName); err != nil {
-		log.Printf("Error creating client schema: %v", err)
+	database := d.requestDB(c)
+	if err := database.CreateClientSchema(schemaName); err != nil {
+		d.logError(c, "Error creating client schema", err)
 		apiresponse.SendInternalError(c, "Failed to initialize client schema")
-		return
+		return nil, "", false
 	}
 
-	if err := d.DB.MigrateClientTables(schemaName); err != nil {
-		log.Printf("Error migrating client tables: %v", err)
+	if err := database.MigrateTenant(schemaName); err != nil {
+		d.logError(c, "Error migrating client tables", err)
 		apiresponse.SendInternalError(c, "Failed to migrate client tables")
-		return
+		return nil, "", false
 	}
 
-	response := models.CreateClientReponse{
-		ClientSecret: clientSecret,
-	}
+	event := service.AdminAuditEvent(user, models.AuditActionClientCreate)
+	event.TargetType = "client"
+	event.TargetID = formatID(client.ID)
+	event.ClientID = &client.ID
+	d.RecordAudit(c, event)
 
-	apiresponse.SendSuccess(c, http.StatusCreated, response, "Client created successfully")
+	return client, clientSecret, true
 }
 
 // GetAllClients godoc
 // @Summary Get all clients associated with the user
-// @Description Retrieve all clients belonging to the authenticated user
+// @Description Retrieve the clients belonging to the authenticated user, one page at a time
 // @Tags Client
 // @Accept json
 // @Produce json
-// @Success 200 {object} apiresponse.SuccessResponse{data=[]models.Client} "Successfully retrieved all clients"
-// @Failure 400 {object} apiresponse.ErrorResponse "Bad request - validation error"
-// @Failure 401 {object} apiresponse.ErrorResponse "Unauthorized - invalid user"
-// @Failure 404 {object} apiresponse.ErrorResponse "No clients found"
-// @Failure 500 {object} apiresponse.ErrorResponse "Internal server error"
-// @Router /protected/getAllClients [get]
+// @Param name query string false "Only clients whose name contains this text, ignoring case"
+// @Param suspended query bool false "Only suspended (true) or active (false) clients"
+// @Param page query int false "Page number, starting at 1"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param cursor query string false "Cursor of the next page, from pagination.next_cursor; takes precedence over page"
+// @Param sort query string false "Sort field, prefixed with - for descending order (default id)" Enums(id, -id, client_name, -client_name, created_at, -created_at)
+// @Success 200 {object} apiresponse.PaginatedResponse{data=[]models.Client} "Successfully retrieved all clients"
+// @Failure 400 {object} apiresponse.Problem "Bad request - validation error"
+// @Failure 401 {object} apiresponse.Problem "Unauthorized - invalid user"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Deprecated
+// @Router /v1/protected/getAllClients [get]
 // @Security BearerAuth
 func (d *Dependencies) GetAllClients(c *gin.Context) {
+	d.sendClients(c)
+}
+
+// sendClients sends the page of the user's clients asked for by the query
+// string
+func (d *Dependencies) sendClients(c *gin.Context) {
+	var query models.ClientListQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		apiresponse.SendValidationError(c, err)
+		return
+	}
+
+	user, ok := d.ValidateUserFromContext(c)
+	if !ok {
+		return
+	}
+
+	page, err := d.clients(c).ListClientsByUserId(user.ID, query)
+	if err != nil {
+		d.handleListError(c, "Error fetching clients", err, "user_id", user.ID)
+		return
+	}
+
+	sendPage(c, page, "Successfully retrieved all clients")
+}
+
+// CreateClientV2 godoc
+// @Summary Create a new client
+// @Description Create a client of the authenticated user with its own schema. The response holds the client secret.
+// @Tags Client
+// @Accept json
+// @Produce json
+// @Param client body models.CreateClient true "Client creation data"
+// @Param Idempotency-Key header string false "Key that makes retries of the request safe; a replayed response has no body, since it held the secret"
+// @Success 201 {object} apiresponse.SuccessResponse{data=models.Client} "Client created successfully"
+// @Header 201 {string} Location "URL of the new client"
+// @Failure 400 {object} apiresponse.Problem "Bad request - validation error"
+// @Failure 401 {object} apiresponse.Problem "Unauthorized - invalid user"
+// @Failure 409 {object} apiresponse.Problem "Conflict - client name already exists"
+// @Failure 422 {object} apiresponse.Problem "Unprocessable - Idempotency-Key reused for a different request"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Router /v2/clients [post]
+// @Security BearerAuth
+func (d *Dependencies) CreateClientV2(c *gin.Context) {
+	var req models.CreateClient
+
+	if verified := utils.VerifyRequestModel(c, &req); !verified {
+		return
+	}
 
 	user, ok := d.ValidateUserFromContext(c)
+	if !ok {
+		return
+	}
 
+	client, _, ok := d.createClient(c, user, req)
 	if !ok {
 		return
 	}
 
-	userID := user.ID
+	c.Header("Location", fmt.Sprintf("%s/clients/%d", V2BasePath, client.ID))
+	markSecret(c)
+	apiresponse.SendSuccess(c, http.StatusCreated, client, "Client created successfully")
+}
 
-	// Fetch all clients for the user
-	clientRepo := db.NewClientRepository(d.DB)
-	clients, err := clientRepo.GetAllClientsByUserId(userID)
+// ListClientsV2 godoc
+// @Summary List my clients
+// @Description Retrieve the clients belonging to the authenticated user, one page at a time
+// @Tags Client
+// @Produce json
+// @Param name query string false "Only clients whose name contains this text, ignoring case"
+// @Param suspended query bool false "Only suspended (true) or active (false) clients"
+// @Param page query int false "Page number, starting at 1"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param cursor query string false "Cursor of the next page, from pagination.next_cursor; takes precedence over page"
+// @Param sort query string false "Sort field, prefixed with - for descending order (default id)" Enums(id, -id, client_name, -client_name, created_at, -created_at)
+// @Success 200 {object} apiresponse.PaginatedResponse{data=[]models.Client} "Successfully retrieved all clients"
+// @Failure 400 {object} apiresponse.Problem "Bad request - validation error"
+// @Failure 401 {object} apiresponse.Problem "Unauthorized - invalid user"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Router /v2/clients [get]
+// @Security BearerAuth
+func (d *Dependencies) ListClientsV2(c *gin.Context) {
+	d.sendClients(c)
+}
 
-	if err != nil {
-		log.Printf("Error fetching clients for user ID %d: %v", userID, err)
-		apiresponse.SendInternalError(c, "Error fetching clients")
+// GetClientV2 godoc
+// @Summary Get a client
+// @Description Retrieve one of the authenticated user's clients
+// @Tags Client
+// @Produce json
+// @Param clientId path int true "Client ID"
+// @Param If-None-Match header string false "ETag of a cached copy of the client"
+// @Success 200 {object} apiresponse.SuccessResponse{data=models.Client} "Successfully retrieved client"
+// @Header 200 {string} ETag "Version of the client"
+// @Success 304 "The cached copy is current"
+// @Failure 400 {object} apiresponse.Problem "Bad request - validation error"
+// @Failure 401 {object} apiresponse.Problem "Unauthorized - invalid user"
+// @Failure 404 {object} apiresponse.Problem "Client not found"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Router /v2/clients/{clientId} [get]
+// @Security BearerAuth
+func (d *Dependencies) GetClientV2(c *gin.Context) {
+	_, client, ok := d.GetClientFromPath(c)
+	if !ok {
+		return
+	}
+
+	sendVersioned(c, http.StatusOK, client, client.Version, "Successfully retrieved client")
+}
+
+// RotateClientSecretV2 godoc
+// @Summary Rotate a client's secret
+// @Description Replace the secret of one of the authenticated user's clients. The old secret stops working at once; the response holds the new one.
+// @Tags Client
+// @Produce json
+// @Param clientId path int true "Client ID"
+// @Param If-Match header string true "ETag of the client"
+// @Param Idempotency-Key header string false "Key that makes retries of the request safe; a replayed response has no body, since it held the secret"
+// @Success 200 {object} apiresponse.SuccessResponse{data=models.Client} "Client secret rotated successfully"
+// @Header 200 {string} ETag "Version of the updated client"
+// @Failure 400 {object} apiresponse.Problem "Bad request - validation error"
+// @Failure 401 {object} apiresponse.Problem "Unauthorized - invalid user"
+// @Failure 404 {object} apiresponse.Problem "Client not found"
+// @Failure 412 {object} apiresponse.Problem "Precondition failed - the client was modified since it was read"
+// @Failure 428 {object} apiresponse.Problem "Precondition required - If-Match is missing"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Router /v2/clients/{clientId}/secret [post]
+// @Security BearerAuth
+func (d *Dependencies) RotateClientSecretV2(c *gin.Context) {
+	user, client, ok := d.GetClientFromPath(c)
+	if !ok {
+		return
+	}
+
+	if !checkIfMatch(c, client.Version) {
 		return
 	}
 
-	// Handle empty results
-	if len(clients) == 0 {
-		apiresponse.SendError(c, http.StatusNotFound, "No clients found")
+	if err := client.RotateSecret(); err != nil {
+		d.logError(c, "Error generating client secret", err, "client_id", client.ID)
+		apiresponse.SendInternalError(c, "Failed to rotate client secret")
 		return
 	}
 
-	apiresponse.SendSuccess(c, http.StatusOK, clients, "Successfully retrieved all clients")
+	if err := d.clients(c).UpdateClient(client); err != nil {
+		d.handleUpdateError(c, "Error rotating client secret", "Failed to rotate client secret", err, "client_id", client.ID)
+		return
+	}
+
+	event := service.AdminAuditEvent(user, models.AuditActionClientSecretRotate)
+	event.TargetType = "client"
+	event.TargetID = formatID(client.ID)
+	event.ClientID = &client.ID
+	d.RecordAudit(c, event)
+
+	markSecret(c)
+	sendVersioned(c, http.StatusOK, client, client.Version, "Client secret rotated successfully")
 }
@@ -1,14 +1,15 @@
 package handlers
 
 import (
-	"errors"
-	"log"
+	"fmt"
 	"net/http"
+	"time"
 
 	apiresponse "github.com/Kantha2004/SimpleJWT/internal/apiResponse"
 	"github.com/Kantha2004/SimpleJWT/internal/auth"
 	"github.com/Kantha2004/SimpleJWT/internal/db"
 	"github.com/Kantha2004/SimpleJWT/internal/models"
+	"github.com/Kantha2004/SimpleJWT/internal/service"
 	"github.com/Kantha2004/SimpleJWT/internal/utils"
 	"github.com/gin-gonic/gin"
 )
@@ -20,11 +21,15 @@ import (
 // @Accept json
 // @Produce json
 // @Param user body models.CreateClientUser true "User creation data"
+// @Param Idempotency-Key header string false "Key that makes retries of the request safe"
 // @Success 201 {object} apiresponse.SuccessResponse{data=models.ClientUser} "User created successfully"
-// @Failure 400 {object} apiresponse.ErrorResponse "Bad request - validation error"
-// @Failure 409 {object} apiresponse.ErrorResponse "Conflict - username or email already exists"
-// @Failure 500 {object} apiresponse.ErrorResponse "Internal server error"
-// @Router /protected/createClientUser [post]
+// @Failure 400 {object} apiresponse.Problem "Bad request - validation error or password policy violation"
+// @Failure 404 {object} apiresponse.Problem "Client not found"
+// @Failure 409 {object} apiresponse.Problem "Conflict - username or email already exists"
+// @Failure 422 {object} apiresponse.Problem "Unprocessable - Idempotency-Key reused for a different request"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Deprecated
+// @Router /v1/protected/createClientUser [post]
 // @Security BearerAuth
 func (d *Dependencies) CreateClientUser(c *gin.Context) {
 	var req models.CreateClientUser
@@ -33,54 +38,67 @@ func (d *Dependencies) CreateClientUser(c *gin.Context) {
 		return
 	}
 
-	clientRepo := db.NewClientRepository(d.DB)
-
-	_, ok := d.ValidateUserFromContext(c)
-
+	user, ok := d.ValidateUserFromContext(c)
 	if !ok {
 		return
 	}
 
-	client, err := clientRepo.GetClientId(req.ClientID)
-
-	if err != nil {
-		apiresponse.SendInternalError(c, "Internal Server Error")
+	client, ok := d.GetOwnedClient(c, user, req.ClientID)
+	if !ok {
 		return
 	}
 
-	if client == nil {
-		apiresponse.SendValidationError(c, errors.New("Client Not Found"))
+	clientUser, ok := d.createClientUser(c, user, client, req.CreateUser)
+	if !ok {
 		return
 	}
 
-	clientUserRepo := db.NewClientUserRepository(d.DB, client.SchemaName)
+	apiresponse.SendSuccess(c, http.StatusCreated, clientUser, "User successfully added")
+}
+
+// createClientUser adds a user to a client, handling HTTP error responses
+// automatically
+func (d *Dependencies) createClientUser(c *gin.Context, user *models.AdminUser, client *models.Client, req models.CreateUser) (*models.ClientUser, bool) {
+	clientUserRepo := d.clientUsers(c, client.SchemaName)
 
 	// Check if username exists
 	if exists, err := clientUserRepo.ClientUserNameExists(req.Username); err != nil {
-		log.Printf("Error checking username existence: %v", err)
+		d.logError(c, "Error checking username existence", err)
 		apiresponse.SendInternalError(c, "Failed to validate username")
-		return
+		return nil, false
 	} else if exists {
 		apiresponse.SendConflict(c, "Username already exists")
-		return
+		return nil, false
 	}
 
 	// Check if email exists
 	if exists, err := clientUserRepo.ClientUserEmailExists(req.Email); err != nil {
-		log.Printf("Error checking email existence: %v", err)
+		d.logError(c, "Error checking email existence", err)
 		apiresponse.SendInternalError(c, "Failed to validate email")
-		return
+		return nil, false
 	} else if exists {
 		apiresponse.SendConflict(c, "Email already exists")
-		return
+		return nil, false
+	}
+
+	policy, err := d.GetClientPasswordPolicy(c, client.SchemaName)
+	if err != nil {
+		d.logError(c, "Error resolving password policy", err)
+		apiresponse.SendInternalError(c, "Failed to validate password")
+		return nil, false
+	}
+
+	passwordCtx := auth.PasswordContext{Username: req.Username, Email: req.Email}
+	if ok := d.ValidatePasswordPolicy(c, policy, req.Password, passwordCtx); !ok {
+		return nil, false
 	}
 
 	// Hash password
-	hashedPassword, err := auth.HashPassword(req.Password)
+	hashedPassword, err := d.passwordHasher.Hash(req.Password)
 	if err != nil {
-		log.Printf("Error hashing password: %v", err)
+		d.logError(c, "Error hashing password", err)
 		apiresponse.SendInternalError(c, "Failed to process password")
-		return
+		return nil, false
 	}
 
 	// Create ClientUser
@@ -93,11 +111,434 @@ func (d *Dependencies) CreateClientUser(c *gin.Context) {
 	clientUser, err := clientUserRepo.CreateClientUser(clientUserModel)
 
 	if err != nil {
-		log.Printf("Error creating user: %v", err)
+		d.logError(c, "Error creating user", err)
 		apiresponse.SendInternalError(c, "Failed to create user")
+		return nil, false
+	}
+
+	if policy.HistorySize > 0 {
+		historyRepo := db.NewClientPasswordHistoryRepository(d.requestDB(c), client.SchemaName)
+		if err := historyRepo.AddPasswordHash(clientUser.ID, hashedPassword, policy.HistorySize); err != nil {
+			d.logError(c, "Error recording password history", err)
+		}
+	}
+
+	event := service.AdminAuditEvent(user, models.AuditActionClientUserCreate)
+	event.TargetType = "client_user"
+	event.TargetID = formatID(clientUser.ID)
+	event.ClientID = &client.ID
+	event.OwnerUserID = &client.UserID
+	d.RecordAudit(c, event)
+	d.PublishClientUserEvent(c, client, clientUser, models.WebhookEventClientUserCreated)
+
+	return clientUser, true
+}
+
+// ClientUserLogin godoc
+// @Summary Client user login
+// @Description Authenticate a user of a client with username and password
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param credentials body models.ClientUserLoginRequest true "Login credentials"
+// @Success 200 {object} apiresponse.SuccessResponse{data=models.LoginResponse} "Login successful"
+// @Failure 400 {object} apiresponse.Problem "Bad request"
+// @Failure 401 {object} apiresponse.Problem "Invalid credentials"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Deprecated
+// @Router /v1/clientUserLogin [post]
+func (d *Dependencies) ClientUserLogin(c *gin.Context) {
+	var req models.ClientUserLoginRequest
+
+	if verified := utils.VerifyRequestModel(c, &req); !verified {
+		return
+	}
+
+	response, ok := d.clientUserLogin(c, req.ClientID, req.LoginRequest)
+	if !ok {
+		return
+	}
+
+	apiresponse.SendSuccess(c, http.StatusOK, response, "Login successful")
+}
+
+// clientUserLogin authenticates a user of a client and starts a session,
+// handling HTTP error responses automatically
+func (d *Dependencies) clientUserLogin(c *gin.Context, clientID uint, req models.LoginRequest) (*models.LoginResponse, bool) {
+	response, client, err := d.authService.ClientUserLogin(c.Request.Context(), caller(c), clientID, req.Username, req.Password)
+	if client != nil {
+		d.useClientLocale(c, client.SchemaName)
+	}
+	if err != nil {
+		d.sendLoginError(c, err)
+		return nil, false
+	}
+	return response, true
+}
+
+// DisableClientUser godoc
+// @Summary Disable a ClientUser
+// @Description Disable a user of one of the authenticated user's clients and revoke all their sessions
+// @Tags Client
+// @Accept json
+// @Produce json
+// @Param request body models.ClientUserRequest true "Client user to disable"
+// @Success 200 {object} apiresponse.SuccessResponse{data=models.ClientUser} "User disabled successfully"
+// @Failure 400 {object} apiresponse.Problem "Bad request - validation error"
+// @Failure 401 {object} apiresponse.Problem "Unauthorized - invalid user"
+// @Failure 404 {object} apiresponse.Problem "Client or user not found"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Deprecated
+// @Router /v1/protected/disableClientUser [post]
+// @Security BearerAuth
+func (d *Dependencies) DisableClientUser(c *gin.Context) {
+	var req models.ClientUserRequest
+
+	if verified := utils.VerifyRequestModel(c, &req); !verified {
+		return
+	}
+
+	user, ok := d.ValidateUserFromContext(c)
+	if !ok {
+		return
+	}
+
+	client, clientUser, ok := d.GetOwnedClientUser(c, user, req.ClientID, req.UserID)
+	if !ok {
+		return
+	}
+
+	if !d.disableClientUser(c, user, client, clientUser) {
+		return
+	}
+
+	apiresponse.SendSuccess(c, http.StatusOK, clientUser, "User disabled successfully")
+}
+
+// disableClientUser disables a client user and revokes their sessions,
+// handling HTTP error responses automatically. Disabling a disabled user
+// does nothing.
+func (d *Dependencies) disableClientUser(c *gin.Context, user *models.AdminUser, client *models.Client, clientUser *models.ClientUser) bool {
+	if !clientUser.IsDisabled() {
+		now := time.Now()
+		clientUser.DisabledAt = &now
+
+		if err := d.clientUsers(c, client.SchemaName).UpdateClientUser(clientUser); err != nil {
+			d.handleUpdateError(c, "Error disabling client user", "Failed to disable user", err)
+			return false
+		}
+
+		if _, err := db.NewSessionRepository(d.requestDB(c)).RevokeUserSessions(clientUser.ID, &client.ID, ""); err != nil {
+			d.logError(c, "Error revoking sessions of disabled client user", err)
+		}
+
+		event := service.AdminAuditEvent(user, models.AuditActionClientUserDisable)
+		event.TargetType = "client_user"
+		event.TargetID = formatID(clientUser.ID)
+		event.ClientID = &client.ID
+		d.RecordAudit(c, event)
+		d.PublishClientUserEvent(c, client, clientUser, models.WebhookEventClientUserDisabled)
+	}
+
+	return true
+}
+
+// ResetClientUserPassword godoc
+// @Summary Reset a ClientUser's password
+// @Description Set a new password for a user of one of the authenticated user's clients and revoke all their sessions
+// @Tags Client
+// @Accept json
+// @Produce json
+// @Param request body models.ResetClientUserPasswordRequest true "Client user and new password"
+// @Success 200 {object} apiresponse.SuccessResponse "Password reset successfully"
+// @Failure 400 {object} apiresponse.Problem "Bad request - validation error or password policy violation"
+// @Failure 401 {object} apiresponse.Problem "Unauthorized - invalid user"
+// @Failure 404 {object} apiresponse.Problem "Client or user not found"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Deprecated
+// @Router /v1/protected/resetClientUserPassword [post]
+// @Security BearerAuth
+func (d *Dependencies) ResetClientUserPassword(c *gin.Context) {
+	var req models.ResetClientUserPasswordRequest
+
+	if verified := utils.VerifyRequestModel(c, &req); !verified {
+		return
+	}
+
+	user, ok := d.ValidateUserFromContext(c)
+	if !ok {
+		return
+	}
+
+	client, clientUser, ok := d.GetOwnedClientUser(c, user, req.ClientID, req.UserID)
+	if !ok {
+		return
+	}
+
+	if !d.resetClientUserPassword(c, user, client, clientUser, req.NewPassword) {
+		return
+	}
+
+	apiresponse.SendSuccess(c, http.StatusOK, struct{}{}, "Password reset successfully")
+}
+
+// resetClientUserPassword sets a new password for a client user and revokes
+// their sessions, handling HTTP error responses automatically
+func (d *Dependencies) resetClientUserPassword(c *gin.Context, user *models.AdminUser, client *models.Client, clientUser *models.ClientUser, newPassword string) bool {
+	policy, err := d.GetClientPasswordPolicy(c, client.SchemaName)
+	if err != nil {
+		d.logError(c, "Error resolving password policy", err)
+		apiresponse.SendInternalError(c, "Failed to validate password")
+		return false
+	}
+
+	historyRepo := db.NewClientPasswordHistoryRepository(d.requestDB(c), client.SchemaName)
+	previousHashes, err := historyRepo.GetRecentPasswordHashes(clientUser.ID, policy.HistorySize)
+	if err != nil {
+		d.logError(c, "Error fetching password history", err)
+		apiresponse.SendInternalError(c, "Failed to validate password")
+		return false
+	}
+
+	passwordCtx := auth.PasswordContext{
+		Username:       clientUser.Username,
+		Email:          clientUser.Email,
+		PreviousHashes: append([]string{clientUser.PasswordHash}, previousHashes...),
+	}
+	if ok := d.ValidatePasswordPolicy(c, policy, newPassword, passwordCtx); !ok {
+		return false
+	}
+
+	hashedPassword, err := d.passwordHasher.Hash(newPassword)
+	if err != nil {
+		d.logError(c, "Error hashing password", err)
+		apiresponse.SendInternalError(c, "Failed to process password")
+		return false
+	}
+
+	clientUser.PasswordHash = hashedPassword
+	if err := d.clientUsers(c, client.SchemaName).UpdateClientUser(clientUser); err != nil {
+		d.handleUpdateError(c, "Error resetting client user password", "Failed to reset password", err)
+		return false
+	}
+
+	if policy.HistorySize > 0 {
+		if err := historyRepo.AddPasswordHash(clientUser.ID, hashedPassword, policy.HistorySize); err != nil {
+			d.logError(c, "Error recording password history", err)
+		}
+	}
+
+	if _, err := db.NewSessionRepository(d.requestDB(c)).RevokeUserSessions(clientUser.ID, &client.ID, ""); err != nil {
+		d.logError(c, "Error revoking sessions after password reset", err)
+	}
+
+	event := service.AdminAuditEvent(user, models.AuditActionClientUserPasswordReset)
+	event.TargetType = "client_user"
+	event.TargetID = formatID(clientUser.ID)
+	event.ClientID = &client.ID
+	d.RecordAudit(c, event)
+	d.PublishClientUserEvent(c, client, clientUser, models.WebhookEventClientUserPasswordChanged)
+
+	return true
+}
+
+// CreateClientUserV2 godoc
+// @Summary Create a new ClientUser
+// @Description Create and add a new user account to one of the authenticated user's clients
+// @Tags Client
+// @Accept json
+// @Produce json
+// @Param clientId path int true "Client ID"
+// @Param user body models.CreateUser true "User creation data"
+// @Param Idempotency-Key header string false "Key that makes retries of the request safe"
+// @Success 201 {object} apiresponse.SuccessResponse{data=models.ClientUser} "User created successfully"
+// @Header 201 {string} Location "URL of the new user"
+// @Failure 400 {object} apiresponse.Problem "Bad request - validation error or password policy violation"
+// @Failure 401 {object} apiresponse.Problem "Unauthorized - invalid user"
+// @Failure 404 {object} apiresponse.Problem "Client not found"
+// @Failure 409 {object} apiresponse.Problem "Conflict - username or email already exists"
+// @Failure 422 {object} apiresponse.Problem "Unprocessable - Idempotency-Key reused for a different request"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Router /v2/clients/{clientId}/users [post]
+// @Security BearerAuth
+func (d *Dependencies) CreateClientUserV2(c *gin.Context) {
+	var req models.CreateUser
+
+	if verified := utils.VerifyRequestModel(c, &req); !verified {
 		return
 	}
 
+	user, client, ok := d.GetClientFromPath(c)
+	if !ok {
+		return
+	}
+
+	clientUser, ok := d.createClientUser(c, user, client, req)
+	if !ok {
+		return
+	}
+
+	c.Header("Location", fmt.Sprintf("%s/clients/%d/users/%d", V2BasePath, client.ID, clientUser.ID))
 	apiresponse.SendSuccess(c, http.StatusCreated, clientUser, "User successfully added")
+}
+
+// ListClientUsersV2 godoc
+// @Summary List ClientUsers
+// @Description Retrieve the users of one of the authenticated user's clients, one page at a time
+// @Tags Client
+// @Produce json
+// @Param clientId path int true "Client ID"
+// @Param username query string false "Only users whose username contains this text, ignoring case"
+// @Param email query string false "Only users whose email contains this text, ignoring case"
+// @Param disabled query bool false "Only disabled (true) or enabled (false) users"
+// @Param page query int false "Page number, starting at 1"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param cursor query string false "Cursor of the next page, from pagination.next_cursor; takes precedence over page"
+// @Param sort query string false "Sort field, prefixed with - for descending order (default id)" Enums(id, -id, username, -username, email, -email, created_at, -created_at)
+// @Success 200 {object} apiresponse.PaginatedResponse{data=[]models.ClientUser} "Successfully retrieved users"
+// @Failure 400 {object} apiresponse.Problem "Bad request - validation error"
+// @Failure 401 {object} apiresponse.Problem "Unauthorized - invalid user"
+// @Failure 404 {object} apiresponse.Problem "Client not found"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Router /v2/clients/{clientId}/users [get]
+// @Security BearerAuth
+func (d *Dependencies) ListClientUsersV2(c *gin.Context) {
+	var query models.ClientUserListQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		apiresponse.SendValidationError(c, err)
+		return
+	}
+
+	_, client, ok := d.GetClientFromPath(c)
+	if !ok {
+		return
+	}
+
+	page, err := d.clientUsers(c, client.SchemaName).ListClientUsers(query)
+	if err != nil {
+		d.handleListError(c, "Error fetching users", err, "client_id", client.ID)
+		return
+	}
+
+	sendPage(c, page, "Successfully retrieved users")
+}
+
+// GetClientUserV2 godoc
+// @Summary Get a ClientUser
+// @Description Retrieve a user of one of the authenticated user's clients
+// @Tags Client
+// @Produce json
+// @Param clientId path int true "Client ID"
+// @Param userId path int true "Client user ID"
+// @Param If-None-Match header string false "ETag of a cached copy of the user"
+// @Success 200 {object} apiresponse.SuccessResponse{data=models.ClientUser} "Successfully retrieved user"
+// @Header 200 {string} ETag "Version of the user"
+// @Success 304 "The cached copy is current"
+// @Failure 400 {object} apiresponse.Problem "Bad request - validation error"
+// @Failure 401 {object} apiresponse.Problem "Unauthorized - invalid user"
+// @Failure 404 {object} apiresponse.Problem "Client or user not found"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Router /v2/clients/{clientId}/users/{userId} [get]
+// @Security BearerAuth
+func (d *Dependencies) GetClientUserV2(c *gin.Context) {
+	_, _, clientUser, ok := d.GetClientUserFromPath(c)
+	if !ok {
+		return
+	}
+
+	sendVersioned(c, http.StatusOK, clientUser, clientUser.Version, "Successfully retrieved user")
+}
+
+// UpdateClientUserV2 godoc
+// @Summary Update a ClientUser
+// @Description Disable a user of one of the authenticated user's clients and revoke all their sessions. Disabled users cannot be enabled again.
+// @Tags Client
+// @Accept json
+// @Produce json
+// @Param clientId path int true "Client ID"
+// @Param userId path int true "Client user ID"
+// @Param request body models.UpdateClientUserRequest true "Changes to the user"
+// @Param If-Match header string true "ETag of the user the changes are based on"
+// @Success 200 {object} apiresponse.SuccessResponse{data=models.ClientUser} "User updated successfully"
+// @Header 200 {string} ETag "Version of the updated user"
+// @Failure 400 {object} apiresponse.Problem "Bad request - validation error"
+// @Failure 401 {object} apiresponse.Problem "Unauthorized - invalid user"
+// @Failure 404 {object} apiresponse.Problem "Client or user not found"
+// @Failure 412 {object} apiresponse.Problem "Precondition failed - the user was modified since it was read"
+// @Failure 428 {object} apiresponse.Problem "Precondition required - If-Match is missing"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Router /v2/clients/{clientId}/users/{userId} [patch]
+// @Security BearerAuth
+func (d *Dependencies) UpdateClientUserV2(c *gin.Context) {
+	var req models.UpdateClientUserRequest
+
+	if verified := utils.VerifyRequestModel(c, &req); !verified {
+		return
+	}
+
+	if !*req.Disabled {
+		apiresponse.SendErrorWithDetails(c, apiresponse.CodeValidation, "The request has invalid fields", []apiresponse.FieldError{
+			apiresponse.NewFieldError("disabled", "eq", "must be true, disabled users cannot be enabled again"),
+		})
+		return
+	}
+
+	user, client, clientUser, ok := d.GetClientUserFromPath(c)
+	if !ok {
+		return
+	}
+
+	if !checkIfMatch(c, clientUser.Version) {
+		return
+	}
+
+	if !d.disableClientUser(c, user, client, clientUser) {
+		return
+	}
+
+	sendVersioned(c, http.StatusOK, clientUser, clientUser.Version, "User updated successfully")
+}
+
+// SetClientUserPasswordV2 godoc
+// @Summary Reset a ClientUser's password
+// @Description Set a new password for a user of one of the authenticated user's clients and revoke all their sessions
+// @Tags Client
+// @Accept json
+// @Param clientId path int true "Client ID"
+// @Param userId path int true "Client user ID"
+// @Param request body models.NewPasswordRequest true "New password"
+// @Param If-Match header string true "ETag of the user"
+// @Success 204 "Password reset successfully"
+// @Header 204 {string} ETag "Version of the updated user"
+// @Failure 400 {object} apiresponse.Problem "Bad request - validation error or password policy violation"
+// @Failure 401 {object} apiresponse.Problem "Unauthorized - invalid user"
+// @Failure 404 {object} apiresponse.Problem "Client or user not found"
+// @Failure 412 {object} apiresponse.Problem "Precondition failed - the user was modified since it was read"
+// @Failure 428 {object} apiresponse.Problem "Precondition required - If-Match is missing"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Router /v2/clients/{clientId}/users/{userId}/password [put]
+// @Security BearerAuth
+func (d *Dependencies) SetClientUserPasswordV2(c *gin.Context) {
+	var req models.NewPasswordRequest
+
+	if verified := utils.VerifyRequestModel(c, &req); !verified {
+		return
+	}
+
+	user, client, clientUser, ok := d.GetClientUserFromPath(c)
+	if !ok {
+		return
+	}
+
+	if !checkIfMatch(c, clientUser.Version) {
+		return
+	}
+
+	if !d.resetClientUserPassword(c, user, client, clientUser, req.NewPassword) {
+		return
+	}
 
+	c.Header("ETag", etag(clientUser.Version))
+	c.Status(http.StatusNoContent)
 }
@@ -0,0 +1,173 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Kantha2004/SimpleJWT/internal/api"
+	"github.com/Kantha2004/SimpleJWT/internal/api/handlers"
+	"github.com/Kantha2004/SimpleJWT/internal/auth"
+	"github.com/Kantha2004/SimpleJWT/internal/config"
+	"github.com/Kantha2004/SimpleJWT/internal/db"
+	"github.com/Kantha2004/SimpleJWT/internal/models"
+	"github.com/Kantha2004/SimpleJWT/internal/service"
+	"github.com/Kantha2004/SimpleJWT/internal/webhooks"
+	"github.com/Kantha2004/SimpleJWT/pkg/client"
+	"github.com/gin-gonic/gin"
+)
+
+// The handlers run behind the routes of the server, on a SQLite database in
+// a new file for every test
+
+const testPassword = "Passw0rd!x"
+
+func newTestServer(t *testing.T) (*httptest.Server, *db.Database) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	database, err := db.NewDatabase(&config.DBConfig{
+		Driver:     config.DriverSQLite,
+		SQLitePath: filepath.Join(t.TempDir(), "simplejwt.db"),
+	})
+	if err != nil {
+		t.Fatalf("NewDatabase: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	jwtService := auth.NewJWTService("handlers-test-secret", nil, "simplejwt", "")
+
+	policy := config.Default().PasswordPolicy
+	policy.CheckBreached = false
+	passwordValidator := auth.NewPasswordValidator(policy, nil)
+
+	// The lowest cost keeps logins fast
+	passwordHasher, err := auth.NewBcryptHasher(4)
+	if err != nil {
+		t.Fatalf("NewBcryptHasher: %v", err)
+	}
+
+	dispatcher := webhooks.NewDispatcher(database, webhooks.Config{}, logger)
+	users := db.NewUserRepository(database)
+	clients := db.NewClientRepository(database)
+	clientUsers := db.NewClientUserRepositoryFactory(database)
+	roles := db.NewRoleRepositoryFactory(database)
+	sessions := db.NewSessionRepository(database)
+	authService := service.NewAuthService(database, users, clients, clientUsers, roles, sessions, jwtService, passwordHasher, dispatcher, logger)
+
+	deps := api.NewDependencies(jwtService, sessions, db.NewIdempotencyRepository(database), time.Hour, api.NewHealthChecker(database, jwtService, logger), logger)
+	handlerDeps := handlers.NewDependencies(database, users, clients, clientUsers, roles, jwtService, passwordValidator, passwordHasher, authService, logger)
+
+	router := gin.New()
+	defaults := config.Default()
+	api.SetupGinRoutes(router, "simplejwt-test", defaults.V1Deprecation, defaults.V1Sunset, deps, handlerDeps)
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return server, database
+}
+
+// newAdmin signs up an admin user and returns a client logged in as them
+func newAdmin(t *testing.T, server *httptest.Server, username string) *client.Client {
+	t.Helper()
+	ctx := context.Background()
+
+	admin, err := client.New(client.Config{BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if _, err := admin.CreateUser(ctx, client.CreateUserRequest{Username: username, Email: username + "@example.com", Password: testPassword}); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	if _, err := admin.Login(ctx, username, testPassword); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	return admin
+}
+
+// post sends a JSON request with the token of admin and returns the status
+func post(t *testing.T, server *httptest.Server, admin *client.Client, path string, body any) int {
+	t.Helper()
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("encoding body: %v", err)
+	}
+	req, err := http.NewRequest(http.MethodPost, server.URL+path, bytes.NewReader(payload))
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+admin.Session().Token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST %s: %v", path, err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func count(t *testing.T, database *db.Database, model any, condition string, args ...any) int64 {
+	t.Helper()
+
+	var n int64
+	if err := database.DB.Model(model).Where(condition, args...).Count(&n).Error; err != nil {
+		t.Fatalf("counting %T: %v", model, err)
+	}
+	return n
+}
+
+func TestCreateClientUserV1RequiresOwnedClient(t *testing.T) {
+	server, database := newTestServer(t)
+	ctx := context.Background()
+
+	owner := newAdmin(t, server, "bob")
+	tenant, err := owner.CreateClient(ctx, "acme")
+	if err != nil {
+		t.Fatalf("CreateClient: %v", err)
+	}
+	// A subscription to every event of the client would queue a delivery
+	// for the created user
+	subscription := &models.WebhookSubscription{ClientID: tenant.ID, URL: "https://example.com/hooks", Secret: "secret", Active: true}
+	if err := db.NewWebhookRepository(database).CreateSubscription(subscription); err != nil {
+		t.Fatalf("CreateSubscription: %v", err)
+	}
+
+	other := newAdmin(t, server, "alice")
+	newUser := func(clientID uint, username string) map[string]any {
+		return map[string]any{"client_id": clientID, "username": username, "email": username + "@example.com", "password": testPassword}
+	}
+
+	if status := post(t, server, other, "/api/v1/protected/createClientUser", newUser(tenant.ID, "mallory")); status != http.StatusNotFound {
+		t.Fatalf("creating a user in another admin's client: got %d, want %d", status, http.StatusNotFound)
+	}
+	if status := post(t, server, other, "/api/v1/protected/createClientUser", newUser(tenant.ID+1, "mallory")); status != http.StatusNotFound {
+		t.Fatalf("creating a user in a missing client: got %d, want %d", status, http.StatusNotFound)
+	}
+	if n := count(t, database, &models.AuditEvent{}, "action = ?", models.AuditActionClientUserCreate); n != 0 {
+		t.Fatalf("rejected creations recorded %d audit events", n)
+	}
+	if n := count(t, database, &models.WebhookDelivery{}, "subscription_id = ?", subscription.ID); n != 0 {
+		t.Fatalf("rejected creations queued %d webhook deliveries", n)
+	}
+
+	// The owner still can, which is audited and delivered
+	if status := post(t, server, owner, "/api/v1/protected/createClientUser", newUser(tenant.ID, "carol")); status != http.StatusCreated {
+		t.Fatalf("creating a user in an owned client: got %d, want %d", status, http.StatusCreated)
+	}
+	if n := count(t, database, &models.AuditEvent{}, "action = ? AND owner_user_id = ?", models.AuditActionClientUserCreate, tenant.UserID); n != 1 {
+		t.Fatalf("creation recorded %d audit events for the owner, want 1", n)
+	}
+	if n := count(t, database, &models.WebhookDelivery{}, "subscription_id = ?", subscription.ID); n != 1 {
+		t.Fatalf("creation queued %d webhook deliveries, want 1", n)
+	}
+}
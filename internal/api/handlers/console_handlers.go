@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"net/http"
+
+	apiresponse "github.com/Kantha2004/SimpleJWT/internal/apiResponse"
+	"github.com/Kantha2004/SimpleJWT/internal/console"
+	"github.com/Kantha2004/SimpleJWT/internal/db"
+	"github.com/Kantha2004/SimpleJWT/internal/models"
+	"github.com/Kantha2004/SimpleJWT/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// The console session endpoints live outside the versioned API and are not
+// part of the Swagger documentation: only the embedded console calls them.
+
+// CreateConsoleSession logs an admin user in to the web console. The token
+// is set in the session cookie instead of being returned; the response holds
+// the CSRF token the console sends with requests that change something.
+func (d *Dependencies) CreateConsoleSession(c *gin.Context) {
+	// Forms of other sites can post text/plain bodies shaped like JSON, but
+	// not application/json, so they cannot log a browser in to an account
+	// of theirs
+	if c.ContentType() != "application/json" {
+		apiresponse.SendError(c, apiresponse.CodeMalformedRequest, "The request body must be sent as application/json")
+		return
+	}
+
+	var req models.LoginRequest
+
+	if verified := utils.VerifyRequestModel(c, &req); !verified {
+		return
+	}
+
+	response, ok := d.login(c, req)
+	if !ok {
+		return
+	}
+
+	csrfToken, err := console.NewCSRFToken()
+	if err == nil {
+		err = db.NewSessionRepository(d.requestDB(c)).SetCSRFToken(response.SessionID, csrfToken)
+	}
+	if err != nil {
+		d.logError(c, "Error storing CSRF token", err)
+		apiresponse.SendInternalError(c, "Authentication failed")
+		return
+	}
+
+	console.SetSessionCookie(c.Writer, response.Token, response.ExpiresAt, d.SecureCookies)
+
+	session := models.ConsoleSession{
+		ExpiresAt: response.ExpiresAt,
+		SessionID: response.SessionID,
+		CSRFToken: csrfToken,
+		User:      response.User,
+	}
+	apiresponse.SendSuccess(c, http.StatusCreated, session, "Login successful")
+}
+
+// GetConsoleSession describes the console session of the request, so the
+// console can pick up its CSRF token after a reload
+func (d *Dependencies) GetConsoleSession(c *gin.Context) {
+	if token, err := c.Cookie(console.SessionCookie); err != nil || token == "" {
+		apiresponse.SendError(c, apiresponse.CodeMissingToken, "Console session cookie required")
+		return
+	}
+
+	user, ok := d.ValidateUserFromContext(c)
+	if !ok {
+		return
+	}
+
+	sessionID, err := utils.GetSessionIDFromContext(c)
+	if err != nil {
+		apiresponse.SendValidationError(c, err)
+		return
+	}
+
+	session, err := db.NewSessionRepository(d.requestDB(c)).GetSessionBySessionID(sessionID)
+	if err != nil {
+		d.logError(c, "Error fetching session", err)
+		apiresponse.SendInternalError(c, "Error fetching sessions")
+		return
+	}
+	if session == nil {
+		apiresponse.SendError(c, apiresponse.CodeSessionRevoked, "Session has been revoked or has expired")
+		return
+	}
+	if session.CSRFToken == "" {
+		apiresponse.SendError(c, apiresponse.CodeInvalidToken, "The session was not started by the console")
+		return
+	}
+
+	response := models.ConsoleSession{
+		ExpiresAt: session.ExpiresAt,
+		SessionID: session.SessionID,
+		CSRFToken: session.CSRFToken,
+		User: models.UserInfo{
+			ID:       user.ID,
+			Username: user.Username,
+			Email:    user.Email,
+		},
+	}
+	apiresponse.SendSuccess(c, http.StatusOK, response, "Successfully retrieved session")
+}
+
+// DeleteConsoleSession logs out of the web console: the session is revoked
+// and its cookie removed
+func (d *Dependencies) DeleteConsoleSession(c *gin.Context) {
+	user, ok := d.ValidateUserFromContext(c)
+	if !ok {
+		return
+	}
+
+	sessionID, err := utils.GetSessionIDFromContext(c)
+	if err != nil {
+		apiresponse.SendValidationError(c, err)
+		return
+	}
+
+	if _, ok := d.revokeSession(c, user, sessionID); !ok {
+		return
+	}
+
+	console.ClearSessionCookie(c.Writer, d.SecureCookies)
+	c.Status(http.StatusNoContent)
+}
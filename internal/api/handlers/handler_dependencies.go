@@ -1,18 +1,117 @@
 package handlers
 
 import (
+	"log/slog"
+
+	apiresponse "github.com/Kantha2004/SimpleJWT/internal/apiResponse"
 	"github.com/Kantha2004/SimpleJWT/internal/auth"
 	"github.com/Kantha2004/SimpleJWT/internal/db"
+	"github.com/Kantha2004/SimpleJWT/internal/service"
+	"github.com/Kantha2004/SimpleJWT/internal/tracing"
+	"github.com/gin-gonic/gin"
 )
 
+// V2BasePath is where the resource-oriented API is mounted; its handlers use
+// it to build Location headers
+const V2BasePath = "/api/v2"
+
+// Dependencies holds what the handlers need. The repositories are injected so
+// they can be swapped, e.g. for the in-memory implementations in tests; the
+// AuthService must be built on the same ones.
 type Dependencies struct {
-	DB         *db.Database
-	jwtService *auth.JWTService
+	DB                *db.Database
+	Users             db.UserRepository
+	Clients           db.ClientRepository
+	ClientUsers       db.ClientUserRepositoryFactory
+	Roles             db.RoleRepositoryFactory
+	jwtService        *auth.JWTService
+	passwordValidator *auth.PasswordValidator
+	passwordHasher    auth.PasswordHasher
+	authService       *service.AuthService
+	logger            *slog.Logger
+
+	// SecureCookies restricts the session cookie of the web console to HTTPS
+	SecureCookies bool
 }
 
-func NewDependencies(db *db.Database, jwt *auth.JWTService) *Dependencies {
+func NewDependencies(database *db.Database, users db.UserRepository, clients db.ClientRepository, clientUsers db.ClientUserRepositoryFactory, roles db.RoleRepositoryFactory, jwt *auth.JWTService, passwordValidator *auth.PasswordValidator, passwordHasher auth.PasswordHasher, authService *service.AuthService, logger *slog.Logger) *Dependencies {
 	return &Dependencies{
-		DB:         db,
-		jwtService: jwt,
+		DB:                database,
+		Users:             users,
+		Clients:           clients,
+		ClientUsers:       clientUsers,
+		Roles:             roles,
+		jwtService:        jwt,
+		passwordValidator: passwordValidator,
+		passwordHasher:    passwordHasher,
+		authService:       authService,
+		logger:            logger,
 	}
 }
+
+// requestDB returns the database bound to the request context, so queries
+// join the request's trace
+func (d *Dependencies) requestDB(c *gin.Context) *db.Database {
+	return d.DB.WithContext(c.Request.Context())
+}
+
+func (d *Dependencies) users(c *gin.Context) db.UserRepository {
+	return d.Users.WithContext(c.Request.Context())
+}
+
+func (d *Dependencies) clients(c *gin.Context) db.ClientRepository {
+	return d.Clients.WithContext(c.Request.Context())
+}
+
+func (d *Dependencies) clientUsers(c *gin.Context, schemaName string) db.ClientUserRepository {
+	return d.ClientUsers(schemaName).WithContext(c.Request.Context())
+}
+
+func (d *Dependencies) roles(c *gin.Context, schemaName string) db.RoleRepository {
+	return d.Roles(schemaName).WithContext(c.Request.Context())
+}
+
+// caller describes the client of the request for sessions and audit events
+func caller(c *gin.Context) service.Caller {
+	return service.Caller{IP: c.ClientIP(), UserAgent: c.Request.UserAgent()}
+}
+
+// logError logs a failure of the request; the record carries its request ID
+// and trace
+func (d *Dependencies) logError(c *gin.Context, msg string, err error, args ...any) {
+	d.logger.ErrorContext(c.Request.Context(), msg, append([]any{"error", err}, args...)...)
+}
+
+// traceClient records the client a request works on, on the request's span
+func traceClient(c *gin.Context, clientID uint, schemaName string) {
+	tracing.SetClient(c.Request.Context(), clientID, schemaName)
+}
+
+// nonNil returns an empty slice for nil, so empty collections are encoded as
+// [] rather than null
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
+
+// sendPage sends one page of a list with its pagination
+func sendPage[T any](c *gin.Context, page *db.Page[T], message string) {
+	totalPages := (page.Total + int64(page.Limit) - 1) / int64(page.Limit)
+
+	apiresponse.SendPaginated(c, nonNil(page.Items), apiresponse.Pagination{
+		Page:       page.Page,
+		Limit:      page.Limit,
+		Total:      int(page.Total),
+		TotalPages: int(totalPages),
+		NextCursor: page.NextCursor,
+	}, message)
+}
+
+// markSecret keeps a response holding a secret out of caches. The
+// idempotency middleware then only stores its status and headers, so a
+// retried request does not get the secret again.
+func markSecret(c *gin.Context) {
+	c.Header("Cache-Control", "no-store")
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	apiresponse "github.com/Kantha2004/SimpleJWT/internal/apiResponse"
+	"github.com/Kantha2004/SimpleJWT/internal/db"
+	"github.com/gin-gonic/gin"
+)
+
+// etag is the entity tag of a resource at a version. Versions change with
+// every update, so the tag is strong and can be used with If-Match.
+func etag(version uint) string {
+	return `"` + strconv.FormatUint(uint64(version), 10) + `"`
+}
+
+// etagMatches reports whether an If-Match or If-None-Match header lists tag.
+// If-None-Match compares weakly, so weak allows W/ tags to match.
+func etagMatches(header, tag string, weak bool) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+		if weak {
+			candidate = strings.TrimPrefix(candidate, "W/")
+		}
+		if candidate == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// sendVersioned sends a resource with the ETag of its version. A GET whose
+// If-None-Match lists the ETag is answered with 304 Not Modified instead.
+func sendVersioned(c *gin.Context, status int, data any, version uint, message string) {
+	tag := etag(version)
+	c.Header("ETag", tag)
+
+	if c.Request.Method == http.MethodGet {
+		if header := c.GetHeader("If-None-Match"); header != "" && etagMatches(header, tag, true) {
+			c.AbortWithStatus(http.StatusNotModified)
+			return
+		}
+	}
+
+	apiresponse.SendSuccess(c, status, data, message)
+}
+
+// checkIfMatch requires the If-Match header of a request changing a resource
+// to list the ETag of its current version, so changes made since the client
+// read the resource are not overwritten. Handles HTTP error responses
+// automatically.
+func checkIfMatch(c *gin.Context, version uint) bool {
+	header := c.GetHeader("If-Match")
+	if header == "" {
+		apiresponse.SendError(c, apiresponse.CodePreconditionRequired, "The If-Match header is required to change this resource")
+		return false
+	}
+
+	if !etagMatches(header, etag(version), false) {
+		apiresponse.SendError(c, apiresponse.CodePreconditionFailed, "The resource was modified since it was read")
+		return false
+	}
+
+	return true
+}
+
+// handleUpdateError sends the response of an update or delete that failed: a
+// conflict when the resource changed since it was read, an internal error
+// with detail otherwise
+func (d *Dependencies) handleUpdateError(c *gin.Context, msg, detail string, err error, args ...any) {
+	if errors.Is(err, db.ErrVersionConflict) {
+		// The resource changed between the If-Match check and the update
+		if c.GetHeader("If-Match") != "" {
+			apiresponse.SendError(c, apiresponse.CodePreconditionFailed, "The resource was modified since it was read")
+			return
+		}
+		apiresponse.SendError(c, apiresponse.CodeConflict, "The resource was modified by another request")
+		return
+	}
+
+	d.logError(c, msg, err, args...)
+	apiresponse.SendInternalError(c, detail)
+}
@@ -1,12 +1,14 @@
 package handlers
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"log"
+	"strconv"
 	"strings"
 
 	apiresponse "github.com/Kantha2004/SimpleJWT/internal/apiResponse"
+	"github.com/Kantha2004/SimpleJWT/internal/auth"
 	"github.com/Kantha2004/SimpleJWT/internal/db"
 	"github.com/Kantha2004/SimpleJWT/internal/models"
 	"github.com/Kantha2004/SimpleJWT/internal/utils"
@@ -19,15 +21,13 @@ const (
 
 // ValidateUser fetches and validates user existence
 // Returns the user if found, otherwise returns an error
-func (d *Dependencies) ValidateUser(userID uint) (*models.AdminUser, error) {
-	userRepo := db.NewUserRepository(d.DB)
+func (d *Dependencies) ValidateUser(ctx context.Context, userID uint) (*models.AdminUser, error) {
+	userRepo := d.Users.WithContext(ctx)
 	user, err := userRepo.GetUserByID(userID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch user: %w", err)
 	}
 
-	fmt.Println("user", user)
-
 	if user == nil {
 		return nil, errors.New(USER_NOT_FOUND)
 	}
@@ -41,13 +41,13 @@ func (d *Dependencies) ValidateUserFromContext(c *gin.Context) (*models.AdminUse
 	// Extract and validate user ID from context
 	userID, err := utils.GetUserIDFromContext(c)
 	if err != nil {
-		log.Printf("Failed to extract user ID from context: %v", err)
+		d.logError(c, "Failed to extract user ID from context", err)
 		apiresponse.SendValidationError(c, err)
 		return nil, false
 	}
 
 	// Validate user existence
-	user, err := d.ValidateUser(userID)
+	user, err := d.ValidateUser(c.Request.Context(), userID)
 	if err != nil {
 		d.handleUserValidationError(c, err)
 		return nil, false
@@ -58,7 +58,7 @@ func (d *Dependencies) ValidateUserFromContext(c *gin.Context) (*models.AdminUse
 
 // handleUserValidationError centralizes error handling for user validation failures
 func (d *Dependencies) handleUserValidationError(c *gin.Context, err error) {
-	log.Printf("User validation failed: %v", err)
+	d.logger.WarnContext(c.Request.Context(), "User validation failed", "error", err)
 
 	// Check for specific error types
 	if errors.Is(err, errors.New(USER_NOT_FOUND)) ||
@@ -70,3 +70,193 @@ func (d *Dependencies) handleUserValidationError(c *gin.Context, err error) {
 	// Default to internal server error for other validation failures
 	apiresponse.SendInternalError(c, "Authentication failed")
 }
+
+// handleListError sends the response of a list query that failed: a
+// validation problem when a list parameter cannot be used, an internal error
+// otherwise
+func (d *Dependencies) handleListError(c *gin.Context, msg string, err error, args ...any) {
+	var paramErr *db.ListParamError
+	if errors.As(err, &paramErr) {
+		apiresponse.SendErrorWithDetails(c, apiresponse.CodeValidation, "The request has invalid fields", []apiresponse.FieldError{
+			apiresponse.NewFieldError(paramErr.Param, paramErr.Code, paramErr.Format, paramErr.Args...),
+		})
+		return
+	}
+
+	d.logError(c, msg, err, args...)
+	apiresponse.SendInternalError(c, msg)
+}
+
+// ValidatePasswordPolicy checks a password against the policy and sends every
+// failed rule in the response. Returns false when the password was rejected.
+func (d *Dependencies) ValidatePasswordPolicy(c *gin.Context, policy models.PasswordPolicy, password string, ctx auth.PasswordContext) bool {
+	violations := d.passwordValidator.Validate(policy, password, ctx)
+	if violation, tooLong := auth.HasherLimitViolation(d.passwordHasher, password); tooLong {
+		violations = append(violations, violation)
+	}
+	if len(violations) == 0 {
+		return true
+	}
+
+	failedRules := make([]apiresponse.FieldError, len(violations))
+	for i, violation := range violations {
+		failedRules[i] = apiresponse.NewFieldError("", violation.Rule, violation.Format, violation.Args...)
+	}
+	apiresponse.SendPasswordPolicyError(c, failedRules)
+	return false
+}
+
+// GetClientPasswordPolicy resolves the password policy of a client by merging
+// the overrides from its configuration on top of the global policy
+func (d *Dependencies) GetClientPasswordPolicy(c *gin.Context, schemaName string) (models.PasswordPolicy, error) {
+	settings, err := db.NewClientConfigRepository(d.requestDB(c), schemaName).GetClientSettings()
+	if err != nil {
+		return d.passwordValidator.Policy(), fmt.Errorf("failed to load client settings: %w", err)
+	}
+
+	return d.passwordValidator.PolicyForClient(settings)
+}
+
+// useClientLocale makes the default locale of a client the fallback language
+// of error responses. The settings are only loaded if an error is sent.
+func (d *Dependencies) useClientLocale(c *gin.Context, schemaName string) {
+	apiresponse.SetClientLocale(c, func() string {
+		settings, err := db.NewClientConfigRepository(d.requestDB(c), schemaName).GetClientSettings()
+		if err != nil {
+			d.logger.WarnContext(c.Request.Context(), "Error loading client locale", "schema", schemaName, "error", err)
+			return ""
+		}
+		return settings.DefaultLocale
+	})
+}
+
+// GetOwnedClient fetches a client and makes sure it belongs to the user,
+// handling HTTP error responses automatically
+func (d *Dependencies) GetOwnedClient(c *gin.Context, user *models.AdminUser, clientID uint) (*models.Client, bool) {
+	client, err := d.clients(c).GetClientId(clientID)
+	if err != nil {
+		d.logError(c, "Error fetching client", err, "client_id", clientID)
+		apiresponse.SendInternalError(c, "Error fetching client")
+		return nil, false
+	}
+
+	if client == nil || client.UserID != user.ID {
+		apiresponse.SendNotFound(c, "Client not found")
+		return nil, false
+	}
+
+	traceClient(c, client.ID, client.SchemaName)
+	d.useClientLocale(c, client.SchemaName)
+
+	return client, true
+}
+
+// GetOwnedClientUser fetches a user of a client owned by the user, handling
+// HTTP error responses automatically
+func (d *Dependencies) GetOwnedClientUser(c *gin.Context, user *models.AdminUser, clientID, clientUserID uint) (*models.Client, *models.ClientUser, bool) {
+	client, ok := d.GetOwnedClient(c, user, clientID)
+	if !ok {
+		return nil, nil, false
+	}
+
+	clientUser, err := d.clientUsers(c, client.SchemaName).GetClientUserByID(clientUserID)
+	if err != nil {
+		d.logError(c, "Error fetching client user", err, "client_user_id", clientUserID)
+		apiresponse.SendInternalError(c, "Error fetching user")
+		return nil, nil, false
+	}
+
+	if clientUser == nil {
+		apiresponse.SendNotFound(c, "User not found")
+		return nil, nil, false
+	}
+
+	return client, clientUser, true
+}
+
+// pathID parses a numeric ID from the URL path, handling HTTP error responses
+// automatically
+func pathID(c *gin.Context, name string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param(name), 10, 0)
+	if err != nil || id == 0 {
+		apiresponse.SendErrorWithDetails(c, apiresponse.CodeValidation, "The request has invalid path parameters", []apiresponse.FieldError{
+			apiresponse.NewFieldError(name, "integer", "must be a positive integer"),
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// GetClientFromPath validates the user of the request and fetches the client
+// of the clientId path parameter, which must belong to them, handling HTTP
+// error responses automatically
+func (d *Dependencies) GetClientFromPath(c *gin.Context) (*models.AdminUser, *models.Client, bool) {
+	user, ok := d.ValidateUserFromContext(c)
+	if !ok {
+		return nil, nil, false
+	}
+
+	clientID, ok := pathID(c, "clientId")
+	if !ok {
+		return nil, nil, false
+	}
+
+	client, ok := d.GetOwnedClient(c, user, clientID)
+	if !ok {
+		return nil, nil, false
+	}
+
+	return user, client, true
+}
+
+// GetClientUserFromPath is GetClientFromPath for the client user of the
+// userId path parameter
+func (d *Dependencies) GetClientUserFromPath(c *gin.Context) (*models.AdminUser, *models.Client, *models.ClientUser, bool) {
+	user, ok := d.ValidateUserFromContext(c)
+	if !ok {
+		return nil, nil, nil, false
+	}
+
+	clientID, ok := pathID(c, "clientId")
+	if !ok {
+		return nil, nil, nil, false
+	}
+
+	clientUserID, ok := pathID(c, "userId")
+	if !ok {
+		return nil, nil, nil, false
+	}
+
+	client, clientUser, ok := d.GetOwnedClientUser(c, user, clientID, clientUserID)
+	if !ok {
+		return nil, nil, nil, false
+	}
+
+	return user, client, clientUser, true
+}
+
+// GetWebhookFromPath is GetClientFromPath for the webhook subscription of
+// the webhookId path parameter, which must belong to the client
+func (d *Dependencies) GetWebhookFromPath(c *gin.Context) (*models.AdminUser, *models.WebhookSubscription, bool) {
+	user, client, ok := d.GetClientFromPath(c)
+	if !ok {
+		return nil, nil, false
+	}
+
+	webhookID, ok := pathID(c, "webhookId")
+	if !ok {
+		return nil, nil, false
+	}
+
+	subscription, ok := d.getOwnedWebhook(c, user, webhookID)
+	if !ok {
+		return nil, nil, false
+	}
+
+	if subscription.ClientID != client.ID {
+		apiresponse.SendNotFound(c, "Webhook not found")
+		return nil, nil, false
+	}
+
+	return user, subscription, true
+}
@@ -0,0 +1,325 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+
+	apiresponse "github.com/Kantha2004/SimpleJWT/internal/apiResponse"
+	"github.com/Kantha2004/SimpleJWT/internal/models"
+	"github.com/Kantha2004/SimpleJWT/internal/service"
+	"github.com/Kantha2004/SimpleJWT/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// roleNamePattern keeps role names usable as they are in the roles claim
+// and in the authorization rules of the client's services
+var roleNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.:-]+$`)
+
+// ListRolesV2 godoc
+// @Summary List a client's roles
+// @Description Retrieve the roles of one of the authenticated user's clients by name
+// @Tags Client
+// @Produce json
+// @Param clientId path int true "Client ID"
+// @Success 200 {object} apiresponse.SuccessResponse{data=[]models.Role} "Successfully retrieved roles"
+// @Failure 400 {object} apiresponse.Problem "Bad request - validation error"
+// @Failure 401 {object} apiresponse.Problem "Unauthorized - invalid user"
+// @Failure 404 {object} apiresponse.Problem "Client not found"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Router /v2/clients/{clientId}/roles [get]
+// @Security BearerAuth
+func (d *Dependencies) ListRolesV2(c *gin.Context) {
+	_, client, ok := d.GetClientFromPath(c)
+	if !ok {
+		return
+	}
+
+	roles, err := d.roles(c, client.SchemaName).ListRoles()
+	if err != nil {
+		d.logError(c, "Error fetching roles", err, "client_id", client.ID)
+		apiresponse.SendInternalError(c, "Error fetching roles")
+		return
+	}
+
+	apiresponse.SendSuccess(c, http.StatusOK, roles, "Successfully retrieved roles")
+}
+
+// CreateRoleV2 godoc
+// @Summary Create a role
+// @Description Add a role to one of the authenticated user's clients. Role names may hold letters, digits and _ . : -
+// @Tags Client
+// @Accept json
+// @Produce json
+// @Param clientId path int true "Client ID"
+// @Param role body models.CreateRoleRequest true "Role creation data"
+// @Param Idempotency-Key header string false "Key that makes retries of the request safe"
+// @Success 201 {object} apiresponse.SuccessResponse{data=models.Role} "Role created successfully"
+// @Header 201 {string} Location "URL of the new role"
+// @Header 201 {string} ETag "Version of the role"
+// @Failure 400 {object} apiresponse.Problem "Bad request - validation error"
+// @Failure 401 {object} apiresponse.Problem "Unauthorized - invalid user"
+// @Failure 404 {object} apiresponse.Problem "Client not found"
+// @Failure 409 {object} apiresponse.Problem "Conflict - the client already has a role of that name"
+// @Failure 422 {object} apiresponse.Problem "Unprocessable - Idempotency-Key reused for a different request"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Router /v2/clients/{clientId}/roles [post]
+// @Security BearerAuth
+func (d *Dependencies) CreateRoleV2(c *gin.Context) {
+	var req models.CreateRoleRequest
+
+	if verified := utils.VerifyRequestModel(c, &req); !verified {
+		return
+	}
+
+	if !roleNamePattern.MatchString(req.Name) {
+		apiresponse.SendErrorWithDetails(c, apiresponse.CodeValidation, "The request has invalid fields", []apiresponse.FieldError{
+			apiresponse.NewFieldError("name", "pattern", "may only hold letters, digits and _ . : -"),
+		})
+		return
+	}
+
+	user, client, ok := d.GetClientFromPath(c)
+	if !ok {
+		return
+	}
+
+	roleRepo := d.roles(c, client.SchemaName)
+
+	if existing, err := roleRepo.GetRoleByName(req.Name); err != nil {
+		d.logError(c, "Error checking role existence", err, "client_id", client.ID)
+		apiresponse.SendInternalError(c, "Failed to create role")
+		return
+	} else if existing != nil {
+		apiresponse.SendConflict(c, "Role already exists")
+		return
+	}
+
+	role := &models.Role{Name: req.Name, Description: req.Description}
+	if err := roleRepo.CreateRole(role); err != nil {
+		d.logError(c, "Error creating role", err, "client_id", client.ID)
+		apiresponse.SendInternalError(c, "Failed to create role")
+		return
+	}
+
+	event := service.AdminAuditEvent(user, models.AuditActionRoleCreate)
+	event.TargetType = "role"
+	event.TargetID = formatID(role.ID)
+	event.ClientID = &client.ID
+	event.Reason = role.Name
+	d.RecordAudit(c, event)
+
+	c.Header("Location", fmt.Sprintf("%s/clients/%d/roles/%d", V2BasePath, client.ID, role.ID))
+	sendVersioned(c, http.StatusCreated, role, role.Version, "Role created successfully")
+}
+
+// GetRoleV2 godoc
+// @Summary Get a role
+// @Description Retrieve a role of one of the authenticated user's clients
+// @Tags Client
+// @Produce json
+// @Param clientId path int true "Client ID"
+// @Param roleId path int true "Role ID"
+// @Success 200 {object} apiresponse.SuccessResponse{data=models.Role} "Successfully retrieved role"
+// @Header 200 {string} ETag "Version of the role"
+// @Failure 400 {object} apiresponse.Problem "Bad request - validation error"
+// @Failure 401 {object} apiresponse.Problem "Unauthorized - invalid user"
+// @Failure 404 {object} apiresponse.Problem "Client or role not found"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Router /v2/clients/{clientId}/roles/{roleId} [get]
+// @Security BearerAuth
+func (d *Dependencies) GetRoleV2(c *gin.Context) {
+	_, _, role, ok := d.getRoleFromPath(c)
+	if !ok {
+		return
+	}
+
+	sendVersioned(c, http.StatusOK, role, role.Version, "Successfully retrieved role")
+}
+
+// DeleteRoleV2 godoc
+// @Summary Delete a role
+// @Description Delete a role of one of the authenticated user's clients; the users it was granted to lose it. Tokens issued before keep listing it until they expire.
+// @Tags Client
+// @Param clientId path int true "Client ID"
+// @Param roleId path int true "Role ID"
+// @Param If-Match header string true "ETag of the role"
+// @Success 204 "Role deleted successfully"
+// @Failure 400 {object} apiresponse.Problem "Bad request - validation error"
+// @Failure 401 {object} apiresponse.Problem "Unauthorized - invalid user"
+// @Failure 404 {object} apiresponse.Problem "Client or role not found"
+// @Failure 412 {object} apiresponse.Problem "Precondition failed - the role was modified since it was read"
+// @Failure 428 {object} apiresponse.Problem "Precondition required - If-Match is missing"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Router /v2/clients/{clientId}/roles/{roleId} [delete]
+// @Security BearerAuth
+func (d *Dependencies) DeleteRoleV2(c *gin.Context) {
+	user, client, role, ok := d.getRoleFromPath(c)
+	if !ok {
+		return
+	}
+
+	if !checkIfMatch(c, role.Version) {
+		return
+	}
+
+	if err := d.roles(c, client.SchemaName).DeleteRole(role); err != nil {
+		d.handleUpdateError(c, "Error deleting role", "Failed to delete role", err, "client_id", client.ID, "role_id", role.ID)
+		return
+	}
+
+	event := service.AdminAuditEvent(user, models.AuditActionRoleDelete)
+	event.TargetType = "role"
+	event.TargetID = formatID(role.ID)
+	event.ClientID = &client.ID
+	event.Reason = role.Name
+	d.RecordAudit(c, event)
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetClientUserRolesV2 godoc
+// @Summary List a ClientUser's roles
+// @Description Retrieve the roles granted to a user of one of the authenticated user's clients
+// @Tags Client
+// @Produce json
+// @Param clientId path int true "Client ID"
+// @Param userId path int true "Client user ID"
+// @Success 200 {object} apiresponse.SuccessResponse{data=[]models.Role} "Successfully retrieved roles"
+// @Header 200 {string} ETag "Version of the user, which changes with their roles"
+// @Failure 400 {object} apiresponse.Problem "Bad request - validation error"
+// @Failure 401 {object} apiresponse.Problem "Unauthorized - invalid user"
+// @Failure 404 {object} apiresponse.Problem "Client or user not found"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Router /v2/clients/{clientId}/users/{userId}/roles [get]
+// @Security BearerAuth
+func (d *Dependencies) GetClientUserRolesV2(c *gin.Context) {
+	_, client, clientUser, ok := d.GetClientUserFromPath(c)
+	if !ok {
+		return
+	}
+
+	roles, err := d.roles(c, client.SchemaName).GetUserRoles(clientUser.ID)
+	if err != nil {
+		d.logError(c, "Error fetching user roles", err, "client_id", client.ID, "client_user_id", clientUser.ID)
+		apiresponse.SendInternalError(c, "Error fetching roles")
+		return
+	}
+
+	sendVersioned(c, http.StatusOK, roles, clientUser.Version, "Successfully retrieved roles")
+}
+
+// SetClientUserRolesV2 godoc
+// @Summary Replace a ClientUser's roles
+// @Description Grant a user of one of the authenticated user's clients exactly the given roles of the client. The roles are versioned with the user, so the change increments the user's version. Tokens list the roles granted when they were issued, so the change applies from the user's next login or refresh.
+// @Tags Client
+// @Accept json
+// @Produce json
+// @Param clientId path int true "Client ID"
+// @Param userId path int true "Client user ID"
+// @Param request body models.SetClientUserRolesRequest true "Names of the roles to grant"
+// @Param If-Match header string true "ETag of the user or of their roles"
+// @Success 200 {object} apiresponse.SuccessResponse{data=[]models.Role} "Roles updated successfully"
+// @Header 200 {string} ETag "Version of the updated user"
+// @Failure 400 {object} apiresponse.Problem "Bad request - validation error or unknown role"
+// @Failure 401 {object} apiresponse.Problem "Unauthorized - invalid user"
+// @Failure 404 {object} apiresponse.Problem "Client or user not found"
+// @Failure 412 {object} apiresponse.Problem "Precondition failed - the user or their roles were modified since they were read"
+// @Failure 428 {object} apiresponse.Problem "Precondition required - If-Match is missing"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Router /v2/clients/{clientId}/users/{userId}/roles [put]
+// @Security BearerAuth
+func (d *Dependencies) SetClientUserRolesV2(c *gin.Context) {
+	var req models.SetClientUserRolesRequest
+
+	if verified := utils.VerifyRequestModel(c, &req); !verified {
+		return
+	}
+
+	user, client, clientUser, ok := d.GetClientUserFromPath(c)
+	if !ok {
+		return
+	}
+
+	if !checkIfMatch(c, clientUser.Version) {
+		return
+	}
+
+	roleRepo := d.roles(c, client.SchemaName)
+
+	names := slices.Compact(slices.Sorted(slices.Values(req.Roles)))
+	roles, err := roleRepo.GetRolesByNames(names)
+	if err != nil {
+		d.logError(c, "Error fetching roles", err, "client_id", client.ID)
+		apiresponse.SendInternalError(c, "Failed to update roles")
+		return
+	}
+
+	if len(roles) != len(names) {
+		var unknown []string
+		for _, name := range names {
+			if !slices.ContainsFunc(roles, func(role models.Role) bool { return role.Name == name }) {
+				unknown = append(unknown, name)
+			}
+		}
+		apiresponse.SendErrorWithDetails(c, apiresponse.CodeValidation, "The request has invalid fields", []apiresponse.FieldError{
+			apiresponse.NewFieldError("roles", "exists", "lists roles the client does not have: %s", strings.Join(unknown, ", ")),
+		})
+		return
+	}
+
+	// Saving the user increments its version, which fails when another
+	// request changed the user or their roles since the If-Match check
+	if err := d.clientUsers(c, client.SchemaName).UpdateClientUser(clientUser); err != nil {
+		d.handleUpdateError(c, "Error updating user roles", "Failed to update roles", err, "client_id", client.ID, "client_user_id", clientUser.ID)
+		return
+	}
+
+	roleIDs := make([]uint, len(roles))
+	for i, role := range roles {
+		roleIDs[i] = role.ID
+	}
+	if err := roleRepo.SetUserRoles(clientUser.ID, roleIDs); err != nil {
+		d.logError(c, "Error updating user roles", err, "client_id", client.ID, "client_user_id", clientUser.ID)
+		apiresponse.SendInternalError(c, "Failed to update roles")
+		return
+	}
+
+	event := service.AdminAuditEvent(user, models.AuditActionClientUserRolesUpdate)
+	event.TargetType = "client_user"
+	event.TargetID = formatID(clientUser.ID)
+	event.ClientID = &client.ID
+	event.Reason = "roles: " + strings.Join(names, ", ")
+	d.RecordAudit(c, event)
+
+	sendVersioned(c, http.StatusOK, roles, clientUser.Version, "Roles updated successfully")
+}
+
+// getRoleFromPath is GetClientFromPath for the role of the roleId path
+// parameter
+func (d *Dependencies) getRoleFromPath(c *gin.Context) (*models.AdminUser, *models.Client, *models.Role, bool) {
+	user, client, ok := d.GetClientFromPath(c)
+	if !ok {
+		return nil, nil, nil, false
+	}
+
+	roleID, ok := pathID(c, "roleId")
+	if !ok {
+		return nil, nil, nil, false
+	}
+
+	role, err := d.roles(c, client.SchemaName).GetRoleByID(roleID)
+	if err != nil {
+		d.logError(c, "Error fetching role", err, "client_id", client.ID, "role_id", roleID)
+		apiresponse.SendInternalError(c, "Error fetching roles")
+		return nil, nil, nil, false
+	}
+	if role == nil {
+		apiresponse.SendNotFound(c, "Role not found")
+		return nil, nil, nil, false
+	}
+
+	return user, client, role, true
+}
@@ -0,0 +1,522 @@
+package handlers
+
+import (
+	"net/http"
+
+	apiresponse "github.com/Kantha2004/SimpleJWT/internal/apiResponse"
+	"github.com/Kantha2004/SimpleJWT/internal/db"
+	"github.com/Kantha2004/SimpleJWT/internal/models"
+	"github.com/Kantha2004/SimpleJWT/internal/service"
+	"github.com/Kantha2004/SimpleJWT/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// GetSessions godoc
+// @Summary List my sessions
+// @Description List the active sessions of the authenticated user
+// @Tags sessions
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number, starting at 1"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param cursor query string false "Cursor of the next page, from pagination.next_cursor; takes precedence over page"
+// @Param sort query string false "Sort field, prefixed with - for descending order (default -last_seen_at)" Enums(last_seen_at, -last_seen_at, created_at, -created_at, expires_at, -expires_at)
+// @Success 200 {object} apiresponse.PaginatedResponse{data=[]models.Session} "Successfully retrieved sessions"
+// @Failure 400 {object} apiresponse.Problem "Bad request - validation error"
+// @Failure 401 {object} apiresponse.Problem "Unauthorized - invalid user"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Deprecated
+// @Router /v1/protected/getSessions [get]
+// @Security BearerAuth
+func (d *Dependencies) GetSessions(c *gin.Context) {
+	d.sendActiveSessions(c)
+}
+
+// sendActiveSessions sends the page of the user's active sessions asked for
+// by the query string, marking the one of the request
+func (d *Dependencies) sendActiveSessions(c *gin.Context) {
+	var params models.ListParams
+
+	if err := c.ShouldBindQuery(&params); err != nil {
+		apiresponse.SendValidationError(c, err)
+		return
+	}
+
+	user, ok := d.ValidateUserFromContext(c)
+	if !ok {
+		return
+	}
+
+	currentSessionID, _ := utils.GetSessionIDFromContext(c)
+
+	page, err := db.NewSessionRepository(d.requestDB(c)).ListActiveSessions(user.ID, nil, params)
+	if err != nil {
+		d.handleListError(c, "Error fetching sessions", err, "user_id", user.ID)
+		return
+	}
+
+	for i := range page.Items {
+		page.Items[i].Current = page.Items[i].SessionID == currentSessionID
+	}
+
+	sendPage(c, page, "Successfully retrieved sessions")
+}
+
+// RevokeSession godoc
+// @Summary Revoke one of my sessions
+// @Description Revoke a session of the authenticated user, signing out that device
+// @Tags sessions
+// @Accept json
+// @Produce json
+// @Param request body models.RevokeSessionRequest true "Session to revoke"
+// @Success 200 {object} apiresponse.SuccessResponse{data=models.RevokeSessionsResponse} "Session revoked successfully"
+// @Failure 400 {object} apiresponse.Problem "Bad request - validation error"
+// @Failure 401 {object} apiresponse.Problem "Unauthorized - invalid user"
+// @Failure 404 {object} apiresponse.Problem "Session not found"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Deprecated
+// @Router /v1/protected/revokeSession [post]
+// @Security BearerAuth
+func (d *Dependencies) RevokeSession(c *gin.Context) {
+	var req models.RevokeSessionRequest
+
+	if verified := utils.VerifyRequestModel(c, &req); !verified {
+		return
+	}
+
+	user, ok := d.ValidateUserFromContext(c)
+	if !ok {
+		return
+	}
+
+	revoked, ok := d.revokeSession(c, user, req.SessionID)
+	if !ok {
+		return
+	}
+
+	apiresponse.SendSuccess(c, http.StatusOK, models.RevokeSessionsResponse{Revoked: revoked}, "Session revoked successfully")
+}
+
+// revokeSession revokes one session of the user, handling HTTP error
+// responses automatically
+func (d *Dependencies) revokeSession(c *gin.Context, user *models.AdminUser, sessionID string) (int64, bool) {
+	revoked, err := db.NewSessionRepository(d.requestDB(c)).RevokeSession(user.ID, nil, sessionID)
+	if err != nil {
+		d.logError(c, "Error revoking session", err)
+		apiresponse.SendInternalError(c, "Failed to revoke session")
+		return 0, false
+	}
+
+	if revoked == 0 {
+		apiresponse.SendNotFound(c, "Session not found")
+		return 0, false
+	}
+
+	event := service.AdminAuditEvent(user, models.AuditActionSessionRevoke)
+	event.TargetType = "session"
+	event.TargetID = sessionID
+	d.RecordAudit(c, event)
+
+	return revoked, true
+}
+
+// RevokeOtherSessions godoc
+// @Summary Revoke all my other sessions
+// @Description Revoke every session of the authenticated user except the current one
+// @Tags sessions
+// @Accept json
+// @Produce json
+// @Success 200 {object} apiresponse.SuccessResponse{data=models.RevokeSessionsResponse} "Sessions revoked successfully"
+// @Failure 401 {object} apiresponse.Problem "Unauthorized - invalid user"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Deprecated
+// @Router /v1/protected/revokeOtherSessions [post]
+// @Security BearerAuth
+func (d *Dependencies) RevokeOtherSessions(c *gin.Context) {
+	user, ok := d.ValidateUserFromContext(c)
+	if !ok {
+		return
+	}
+
+	revoked, ok := d.revokeOtherSessions(c, user)
+	if !ok {
+		return
+	}
+
+	apiresponse.SendSuccess(c, http.StatusOK, models.RevokeSessionsResponse{Revoked: revoked}, "Sessions revoked successfully")
+}
+
+// revokeOtherSessions revokes every session of the user but the one of the
+// request, handling HTTP error responses automatically
+func (d *Dependencies) revokeOtherSessions(c *gin.Context, user *models.AdminUser) (int64, bool) {
+	currentSessionID, err := utils.GetSessionIDFromContext(c)
+	if err != nil {
+		apiresponse.SendValidationError(c, err)
+		return 0, false
+	}
+
+	revoked, err := db.NewSessionRepository(d.requestDB(c)).RevokeUserSessions(user.ID, nil, currentSessionID)
+	if err != nil {
+		d.logError(c, "Error revoking sessions", err, "user_id", user.ID)
+		apiresponse.SendInternalError(c, "Failed to revoke sessions")
+		return 0, false
+	}
+
+	event := service.AdminAuditEvent(user, models.AuditActionSessionRevoke)
+	event.TargetType = "user"
+	event.TargetID = formatID(user.ID)
+	event.Reason = "revoked all other sessions"
+	d.RecordAudit(c, event)
+
+	return revoked, true
+}
+
+// GetClientUserSessions godoc
+// @Summary List a client user's sessions
+// @Description List the active sessions of a user of one of the authenticated user's clients
+// @Tags sessions
+// @Accept json
+// @Produce json
+// @Param client_id query int true "Client ID"
+// @Param user_id query int true "Client user ID"
+// @Param page query int false "Page number, starting at 1"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param cursor query string false "Cursor of the next page, from pagination.next_cursor; takes precedence over page"
+// @Param sort query string false "Sort field, prefixed with - for descending order (default -last_seen_at)" Enums(last_seen_at, -last_seen_at, created_at, -created_at, expires_at, -expires_at)
+// @Success 200 {object} apiresponse.PaginatedResponse{data=[]models.Session} "Successfully retrieved sessions"
+// @Failure 400 {object} apiresponse.Problem "Bad request - validation error"
+// @Failure 401 {object} apiresponse.Problem "Unauthorized - invalid user"
+// @Failure 404 {object} apiresponse.Problem "Client not found"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Deprecated
+// @Router /v1/protected/getClientUserSessions [get]
+// @Security BearerAuth
+func (d *Dependencies) GetClientUserSessions(c *gin.Context) {
+	var req models.ClientUserSessionsRequest
+	var params models.ListParams
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		apiresponse.SendValidationError(c, err)
+		return
+	}
+	if err := c.ShouldBindQuery(&params); err != nil {
+		apiresponse.SendValidationError(c, err)
+		return
+	}
+
+	user, ok := d.ValidateUserFromContext(c)
+	if !ok {
+		return
+	}
+
+	client, ok := d.GetOwnedClient(c, user, req.ClientID)
+	if !ok {
+		return
+	}
+
+	d.sendClientUserSessions(c, client, req.UserID, params)
+}
+
+// sendClientUserSessions sends a page of the active sessions of a client user
+func (d *Dependencies) sendClientUserSessions(c *gin.Context, client *models.Client, clientUserID uint, params models.ListParams) {
+	page, err := db.NewSessionRepository(d.requestDB(c)).ListActiveSessions(clientUserID, &client.ID, params)
+	if err != nil {
+		d.handleListError(c, "Error fetching sessions", err, "client_user_id", clientUserID)
+		return
+	}
+
+	sendPage(c, page, "Successfully retrieved sessions")
+}
+
+// RevokeClientUserSessions godoc
+// @Summary Revoke a client user's sessions
+// @Description Revoke one session, or all sessions when session_id is omitted, of a user of one of the authenticated user's clients
+// @Tags sessions
+// @Accept json
+// @Produce json
+// @Param request body models.ClientUserSessionsRequest true "Client user and optional session"
+// @Success 200 {object} apiresponse.SuccessResponse{data=models.RevokeSessionsResponse} "Sessions revoked successfully"
+// @Failure 400 {object} apiresponse.Problem "Bad request - validation error"
+// @Failure 401 {object} apiresponse.Problem "Unauthorized - invalid user"
+// @Failure 404 {object} apiresponse.Problem "Client or session not found"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Deprecated
+// @Router /v1/protected/revokeClientUserSessions [post]
+// @Security BearerAuth
+func (d *Dependencies) RevokeClientUserSessions(c *gin.Context) {
+	var req models.ClientUserSessionsRequest
+
+	if verified := utils.VerifyRequestModel(c, &req); !verified {
+		return
+	}
+
+	user, ok := d.ValidateUserFromContext(c)
+	if !ok {
+		return
+	}
+
+	client, ok := d.GetOwnedClient(c, user, req.ClientID)
+	if !ok {
+		return
+	}
+
+	revoked, ok := d.revokeClientUserSessions(c, user, client, req.UserID, req.SessionID)
+	if !ok {
+		return
+	}
+
+	apiresponse.SendSuccess(c, http.StatusOK, models.RevokeSessionsResponse{Revoked: revoked}, "Sessions revoked successfully")
+}
+
+// revokeClientUserSessions revokes one session of a client user, or all of
+// them when sessionID is empty, handling HTTP error responses automatically
+func (d *Dependencies) revokeClientUserSessions(c *gin.Context, user *models.AdminUser, client *models.Client, clientUserID uint, sessionID string) (int64, bool) {
+	sessionRepo := db.NewSessionRepository(d.requestDB(c))
+
+	var revoked int64
+	var err error
+	if sessionID != "" {
+		revoked, err = sessionRepo.RevokeSession(clientUserID, &client.ID, sessionID)
+	} else {
+		revoked, err = sessionRepo.RevokeUserSessions(clientUserID, &client.ID, "")
+	}
+
+	if err != nil {
+		d.logError(c, "Error revoking sessions", err, "client_user_id", clientUserID)
+		apiresponse.SendInternalError(c, "Failed to revoke sessions")
+		return 0, false
+	}
+
+	if sessionID != "" && revoked == 0 {
+		apiresponse.SendNotFound(c, "Session not found")
+		return 0, false
+	}
+
+	event := service.AdminAuditEvent(user, models.AuditActionClientUserSessionRevoke)
+	event.TargetType = "client_user"
+	event.TargetID = formatID(clientUserID)
+	event.ClientID = &client.ID
+	if sessionID != "" {
+		event.TargetType = "session"
+		event.TargetID = sessionID
+	}
+	d.RecordAudit(c, event)
+
+	return revoked, true
+}
+
+// CreateSessionV2 godoc
+// @Summary User login
+// @Description Authenticate user with username and password, starting a session
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param credentials body models.LoginRequest true "Login credentials"
+// @Success 201 {object} apiresponse.SuccessResponse{data=models.LoginResponse} "Login successful"
+// @Failure 400 {object} apiresponse.Problem "Bad request"
+// @Failure 401 {object} apiresponse.Problem "Invalid credentials"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Router /v2/sessions [post]
+func (d *Dependencies) CreateSessionV2(c *gin.Context) {
+	var req models.LoginRequest
+
+	if verified := utils.VerifyRequestModel(c, &req); !verified {
+		return
+	}
+
+	response, ok := d.login(c, req)
+	if !ok {
+		return
+	}
+
+	apiresponse.SendSuccess(c, http.StatusCreated, response, "Login successful")
+}
+
+// CreateClientUserSessionV2 godoc
+// @Summary Client user login
+// @Description Authenticate a user of a client with username and password, starting a session
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param clientId path int true "Client ID"
+// @Param credentials body models.LoginRequest true "Login credentials"
+// @Success 201 {object} apiresponse.SuccessResponse{data=models.LoginResponse} "Login successful"
+// @Failure 400 {object} apiresponse.Problem "Bad request"
+// @Failure 401 {object} apiresponse.Problem "Invalid credentials"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Router /v2/clients/{clientId}/sessions [post]
+func (d *Dependencies) CreateClientUserSessionV2(c *gin.Context) {
+	var req models.LoginRequest
+
+	if verified := utils.VerifyRequestModel(c, &req); !verified {
+		return
+	}
+
+	clientID, ok := pathID(c, "clientId")
+	if !ok {
+		return
+	}
+
+	response, ok := d.clientUserLogin(c, clientID, req)
+	if !ok {
+		return
+	}
+
+	apiresponse.SendSuccess(c, http.StatusCreated, response, "Login successful")
+}
+
+// ListSessionsV2 godoc
+// @Summary List my sessions
+// @Description List the active sessions of the authenticated user
+// @Tags sessions
+// @Produce json
+// @Param page query int false "Page number, starting at 1"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param cursor query string false "Cursor of the next page, from pagination.next_cursor; takes precedence over page"
+// @Param sort query string false "Sort field, prefixed with - for descending order (default -last_seen_at)" Enums(last_seen_at, -last_seen_at, created_at, -created_at, expires_at, -expires_at)
+// @Success 200 {object} apiresponse.PaginatedResponse{data=[]models.Session} "Successfully retrieved sessions"
+// @Failure 400 {object} apiresponse.Problem "Bad request - validation error"
+// @Failure 401 {object} apiresponse.Problem "Unauthorized - invalid user"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Router /v2/sessions [get]
+// @Security BearerAuth
+func (d *Dependencies) ListSessionsV2(c *gin.Context) {
+	d.sendActiveSessions(c)
+}
+
+// DeleteSessionV2 godoc
+// @Summary Revoke one of my sessions
+// @Description Revoke a session of the authenticated user, signing out that device
+// @Tags sessions
+// @Param sessionId path string true "Session ID"
+// @Success 204 "Session revoked successfully"
+// @Failure 401 {object} apiresponse.Problem "Unauthorized - invalid user"
+// @Failure 404 {object} apiresponse.Problem "Session not found"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Router /v2/sessions/{sessionId} [delete]
+// @Security BearerAuth
+func (d *Dependencies) DeleteSessionV2(c *gin.Context) {
+	user, ok := d.ValidateUserFromContext(c)
+	if !ok {
+		return
+	}
+
+	if _, ok := d.revokeSession(c, user, c.Param("sessionId")); !ok {
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteOtherSessionsV2 godoc
+// @Summary Revoke all my other sessions
+// @Description Revoke every session of the authenticated user except the current one
+// @Tags sessions
+// @Produce json
+// @Success 200 {object} apiresponse.SuccessResponse{data=models.RevokeSessionsResponse} "Sessions revoked successfully"
+// @Failure 401 {object} apiresponse.Problem "Unauthorized - invalid user"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Router /v2/sessions [delete]
+// @Security BearerAuth
+func (d *Dependencies) DeleteOtherSessionsV2(c *gin.Context) {
+	user, ok := d.ValidateUserFromContext(c)
+	if !ok {
+		return
+	}
+
+	revoked, ok := d.revokeOtherSessions(c, user)
+	if !ok {
+		return
+	}
+
+	apiresponse.SendSuccess(c, http.StatusOK, models.RevokeSessionsResponse{Revoked: revoked}, "Sessions revoked successfully")
+}
+
+// ListClientUserSessionsV2 godoc
+// @Summary List a client user's sessions
+// @Description List the active sessions of a user of one of the authenticated user's clients
+// @Tags sessions
+// @Produce json
+// @Param clientId path int true "Client ID"
+// @Param userId path int true "Client user ID"
+// @Param page query int false "Page number, starting at 1"
+// @Param limit query int false "Page size (default 20, max 100)"
+// @Param cursor query string false "Cursor of the next page, from pagination.next_cursor; takes precedence over page"
+// @Param sort query string false "Sort field, prefixed with - for descending order (default -last_seen_at)" Enums(last_seen_at, -last_seen_at, created_at, -created_at, expires_at, -expires_at)
+// @Success 200 {object} apiresponse.PaginatedResponse{data=[]models.Session} "Successfully retrieved sessions"
+// @Failure 400 {object} apiresponse.Problem "Bad request - validation error"
+// @Failure 401 {object} apiresponse.Problem "Unauthorized - invalid user"
+// @Failure 404 {object} apiresponse.Problem "Client or user not found"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Router /v2/clients/{clientId}/users/{userId}/sessions [get]
+// @Security BearerAuth
+func (d *Dependencies) ListClientUserSessionsV2(c *gin.Context) {
+	var params models.ListParams
+
+	if err := c.ShouldBindQuery(&params); err != nil {
+		apiresponse.SendValidationError(c, err)
+		return
+	}
+
+	_, client, clientUser, ok := d.GetClientUserFromPath(c)
+	if !ok {
+		return
+	}
+
+	d.sendClientUserSessions(c, client, clientUser.ID, params)
+}
+
+// DeleteClientUserSessionsV2 godoc
+// @Summary Revoke a client user's sessions
+// @Description Revoke every session of a user of one of the authenticated user's clients
+// @Tags sessions
+// @Produce json
+// @Param clientId path int true "Client ID"
+// @Param userId path int true "Client user ID"
+// @Success 200 {object} apiresponse.SuccessResponse{data=models.RevokeSessionsResponse} "Sessions revoked successfully"
+// @Failure 400 {object} apiresponse.Problem "Bad request - validation error"
+// @Failure 401 {object} apiresponse.Problem "Unauthorized - invalid user"
+// @Failure 404 {object} apiresponse.Problem "Client or user not found"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Router /v2/clients/{clientId}/users/{userId}/sessions [delete]
+// @Security BearerAuth
+func (d *Dependencies) DeleteClientUserSessionsV2(c *gin.Context) {
+	user, client, clientUser, ok := d.GetClientUserFromPath(c)
+	if !ok {
+		return
+	}
+
+	revoked, ok := d.revokeClientUserSessions(c, user, client, clientUser.ID, "")
+	if !ok {
+		return
+	}
+
+	apiresponse.SendSuccess(c, http.StatusOK, models.RevokeSessionsResponse{Revoked: revoked}, "Sessions revoked successfully")
+}
+
+// DeleteClientUserSessionV2 godoc
+// @Summary Revoke a client user's session
+// @Description Revoke one session of a user of one of the authenticated user's clients
+// @Tags sessions
+// @Param clientId path int true "Client ID"
+// @Param userId path int true "Client user ID"
+// @Param sessionId path string true "Session ID"
+// @Success 204 "Session revoked successfully"
+// @Failure 400 {object} apiresponse.Problem "Bad request - validation error"
+// @Failure 401 {object} apiresponse.Problem "Unauthorized - invalid user"
+// @Failure 404 {object} apiresponse.Problem "Client, user or session not found"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Router /v2/clients/{clientId}/users/{userId}/sessions/{sessionId} [delete]
+// @Security BearerAuth
+func (d *Dependencies) DeleteClientUserSessionV2(c *gin.Context) {
+	user, client, clientUser, ok := d.GetClientUserFromPath(c)
+	if !ok {
+		return
+	}
+
+	if _, ok := d.revokeClientUserSessions(c, user, client, clientUser.ID, c.Param("sessionId")); !ok {
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -1,14 +1,14 @@
 package handlers
 
 import (
-	"log"
+	"errors"
 	"net/http"
-	"time"
 
 	apiresponse "github.com/Kantha2004/SimpleJWT/internal/apiResponse"
 	"github.com/Kantha2004/SimpleJWT/internal/auth"
 	"github.com/Kantha2004/SimpleJWT/internal/db"
 	"github.com/Kantha2004/SimpleJWT/internal/models"
+	"github.com/Kantha2004/SimpleJWT/internal/service"
 	"github.com/Kantha2004/SimpleJWT/internal/utils"
 	"github.com/gin-gonic/gin"
 )
@@ -20,11 +20,14 @@ import (
 // @Accept json
 // @Produce json
 // @Param user body models.CreateUser true "User creation data"
+// @Param Idempotency-Key header string false "Key that makes retries of the request safe"
 // @Success 201 {object} apiresponse.SuccessResponse{data=models.CreateUserResponse} "User created successfully"
-// @Failure 400 {object} apiresponse.ErrorResponse "Bad request - validation error"
-// @Failure 409 {object} apiresponse.ErrorResponse "Conflict - username or email already exists"
-// @Failure 500 {object} apiresponse.ErrorResponse "Internal server error"
-// @Router /createUser [post]
+// @Failure 400 {object} apiresponse.Problem "Bad request - validation error or password policy violation"
+// @Failure 409 {object} apiresponse.Problem "Conflict - username or email already exists"
+// @Failure 422 {object} apiresponse.Problem "Unprocessable - Idempotency-Key reused for a different request"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Deprecated
+// @Router /v1/createUser [post]
 func (d *Dependencies) CreateUser(c *gin.Context) {
 	var req models.CreateUser
 
@@ -32,34 +35,51 @@ func (d *Dependencies) CreateUser(c *gin.Context) {
 		return
 	}
 
-	userRepo := db.NewUserRepository(d.DB)
+	response, ok := d.createUser(c, req)
+	if !ok {
+		return
+	}
+
+	apiresponse.SendSuccess(c, http.StatusCreated, response, "User created successfully")
+}
+
+// createUser registers an admin user, handling HTTP error responses
+// automatically. It is shared by every API version.
+func (d *Dependencies) createUser(c *gin.Context, req models.CreateUser) (*models.CreateUserResponse, bool) {
+	userRepo := d.users(c)
 
 	// Check if username exists
 	if exists, err := userRepo.UserNameExists(req.Username); err != nil {
-		log.Printf("Error checking username existence: %v", err)
+		d.logError(c, "Error checking username existence", err)
 		apiresponse.SendInternalError(c, "Failed to validate username")
-		return
+		return nil, false
 	} else if exists {
 		apiresponse.SendConflict(c, "Username already exists")
-		return
+		return nil, false
 	}
 
 	// Check if email exists
 	if exists, err := userRepo.EmailExists(req.Email); err != nil {
-		log.Printf("Error checking email existence: %v", err)
+		d.logError(c, "Error checking email existence", err)
 		apiresponse.SendInternalError(c, "Failed to validate email")
-		return
+		return nil, false
 	} else if exists {
 		apiresponse.SendConflict(c, "Email already exists")
-		return
+		return nil, false
+	}
+
+	policy := d.passwordValidator.Policy()
+	passwordCtx := auth.PasswordContext{Username: req.Username, Email: req.Email}
+	if ok := d.ValidatePasswordPolicy(c, policy, req.Password, passwordCtx); !ok {
+		return nil, false
 	}
 
 	// Hash password
-	hashedPassword, err := auth.HashPassword(req.Password)
+	hashedPassword, err := d.passwordHasher.Hash(req.Password)
 	if err != nil {
-		log.Printf("Error hashing password: %v", err)
+		d.logError(c, "Error hashing password", err)
 		apiresponse.SendInternalError(c, "Failed to process password")
-		return
+		return nil, false
 	}
 
 	// Create user
@@ -71,19 +91,33 @@ func (d *Dependencies) CreateUser(c *gin.Context) {
 
 	userId, err := userRepo.CreateUser(user)
 	if err != nil {
-		log.Printf("Error creating user: %v", err)
+		d.logError(c, "Error creating user", err)
 		apiresponse.SendInternalError(c, "Failed to create user")
-		return
+		return nil, false
 	}
 
-	// Prepare response data
-	responseData := models.CreateUserResponse{
+	if policy.HistorySize > 0 {
+		historyRepo := db.NewPasswordHistoryRepository(d.requestDB(c))
+		if err := historyRepo.AddPasswordHash(userId, hashedPassword, policy.HistorySize); err != nil {
+			d.logError(c, "Error recording password history", err)
+		}
+	}
+
+	d.RecordAudit(c, models.AuditEvent{
+		ActorType:   models.AuditActorAdminUser,
+		ActorID:     &userId,
+		ActorName:   req.Username,
+		Action:      models.AuditActionUserCreate,
+		TargetType:  "user",
+		TargetID:    formatID(userId),
+		OwnerUserID: &userId,
+	})
+
+	return &models.CreateUserResponse{
 		UserID:   userId,
 		Username: req.Username,
 		Email:    req.Email,
-	}
-
-	apiresponse.SendSuccess(c, http.StatusCreated, responseData, "User created successfully")
+	}, true
 }
 
 // Login godoc
@@ -94,10 +128,11 @@ func (d *Dependencies) CreateUser(c *gin.Context) {
 // @Produce json
 // @Param credentials body models.LoginRequest true "Login credentials"
 // @Success 200 {object} apiresponse.SuccessResponse{data=models.LoginResponse} "Login successful"
-// @Failure 400 {object} apiresponse.ErrorResponse "Bad request"
-// @Failure 401 {object} apiresponse.ErrorResponse "Invalid credentials"
-// @Failure 500 {object} apiresponse.ErrorResponse "Internal server error"
-// @Router /login [post]
+// @Failure 400 {object} apiresponse.Problem "Bad request"
+// @Failure 401 {object} apiresponse.Problem "Invalid credentials"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Deprecated
+// @Router /v1/login [post]
 func (d *Dependencies) Login(c *gin.Context) {
 	var req models.LoginRequest
 
@@ -105,41 +140,227 @@ func (d *Dependencies) Login(c *gin.Context) {
 		return
 	}
 
-	userRepo := db.NewUserRepository(d.DB)
+	response, ok := d.login(c, req)
+	if !ok {
+		return
+	}
+
+	apiresponse.SendSuccess(c, http.StatusOK, response, "Login successful")
+}
 
-	// Get user by username
-	user, err := userRepo.GetUserByUsername(req.Username)
+// login authenticates an admin user and starts a session, handling HTTP
+// error responses automatically
+func (d *Dependencies) login(c *gin.Context, req models.LoginRequest) (*models.LoginResponse, bool) {
+	response, err := d.authService.Login(c.Request.Context(), caller(c), req.Username, req.Password)
 	if err != nil {
-		log.Printf("Error fetching user: %v", err)
+		d.sendLoginError(c, err)
+		return nil, false
+	}
+	return response, true
+}
+
+// sendLoginError answers a rejected or failed login
+func (d *Dependencies) sendLoginError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidCredentials):
+		apiresponse.SendError(c, apiresponse.CodeInvalidCredentials, "Invalid username or password")
+	case errors.Is(err, service.ErrUserDisabled):
+		apiresponse.SendError(c, apiresponse.CodeUserDisabled, "User is disabled")
+	case errors.Is(err, service.ErrClientSuspended):
+		apiresponse.SendError(c, apiresponse.CodeClientSuspended, "Client is suspended")
+	default:
+		d.logError(c, "Error logging in", err)
 		apiresponse.SendInternalError(c, "Authentication failed")
+	}
+}
+
+// ChangePassword godoc
+// @Summary Change password
+// @Description Change the password of the authenticated user
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body models.ChangePasswordRequest true "Current and new password"
+// @Success 200 {object} apiresponse.SuccessResponse "Password changed successfully"
+// @Failure 400 {object} apiresponse.Problem "Bad request - validation error or password policy violation"
+// @Failure 401 {object} apiresponse.Problem "Invalid current password"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Deprecated
+// @Router /v1/protected/changePassword [post]
+// @Security BearerAuth
+func (d *Dependencies) ChangePassword(c *gin.Context) {
+	var req models.ChangePasswordRequest
+
+	if verified := utils.VerifyRequestModel(c, &req); !verified {
+		return
+	}
+
+	user, ok := d.ValidateUserFromContext(c)
+	if !ok {
 		return
 	}
 
-	// Check user existence and password in one step for security
-	if user == nil || !auth.CheckPassword(user.PasswordHash, req.Password) {
-		apiresponse.SendUnauthorized(c, "Invalid username or password")
+	if !d.changePassword(c, user, req) {
 		return
 	}
 
-	// Generate JWT token
-	token, err := d.jwtService.CreateToken(user.ID)
+	apiresponse.SendSuccess(c, http.StatusOK, struct{}{}, "Password changed successfully")
+}
+
+// changePassword replaces the password of an admin user after checking the
+// current one, handling HTTP error responses automatically
+func (d *Dependencies) changePassword(c *gin.Context, user *models.AdminUser, req models.ChangePasswordRequest) bool {
+	if !auth.CheckPassword(user.PasswordHash, req.CurrentPassword) {
+		event := service.AdminAuditEvent(user, models.AuditActionUserPasswordChange)
+		event.Outcome = models.AuditOutcomeFailure
+		event.Reason = "invalid current password"
+		d.RecordAudit(c, event)
+
+		apiresponse.SendError(c, apiresponse.CodeInvalidCredentials, "Invalid current password")
+		return false
+	}
+
+	policy := d.passwordValidator.Policy()
+	historyRepo := db.NewPasswordHistoryRepository(d.requestDB(c))
+
+	previousHashes, err := historyRepo.GetRecentPasswordHashes(user.ID, policy.HistorySize)
 	if err != nil {
-		log.Printf("Error creating token: %v", err)
-		apiresponse.SendInternalError(c, "Authentication failed")
+		d.logError(c, "Error fetching password history", err)
+		apiresponse.SendInternalError(c, "Failed to validate password")
+		return false
+	}
+
+	passwordCtx := auth.PasswordContext{
+		Username:       user.Username,
+		Email:          user.Email,
+		PreviousHashes: append([]string{user.PasswordHash}, previousHashes...),
+	}
+	if ok := d.ValidatePasswordPolicy(c, policy, req.NewPassword, passwordCtx); !ok {
+		return false
+	}
+
+	hashedPassword, err := d.passwordHasher.Hash(req.NewPassword)
+	if err != nil {
+		d.logError(c, "Error hashing password", err)
+		apiresponse.SendInternalError(c, "Failed to process password")
+		return false
+	}
+
+	user.PasswordHash = hashedPassword
+	if err := d.users(c).UpdateUser(user); err != nil {
+		d.handleUpdateError(c, "Error updating password", "Failed to change password", err)
+		return false
+	}
+
+	if policy.HistorySize > 0 {
+		if err := historyRepo.AddPasswordHash(user.ID, hashedPassword, policy.HistorySize); err != nil {
+			d.logError(c, "Error recording password history", err)
+		}
+	}
+
+	event := service.AdminAuditEvent(user, models.AuditActionUserPasswordChange)
+	event.TargetType = "user"
+	event.TargetID = formatID(user.ID)
+	d.RecordAudit(c, event)
+
+	return true
+}
+
+// CreateUserV2 godoc
+// @Summary Create a new user
+// @Description Create a new user account in the system
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param user body models.CreateUser true "User creation data"
+// @Param Idempotency-Key header string false "Key that makes retries of the request safe"
+// @Success 201 {object} apiresponse.SuccessResponse{data=models.CreateUserResponse} "User created successfully"
+// @Header 201 {string} Location "URL of the authenticated user"
+// @Failure 400 {object} apiresponse.Problem "Bad request - validation error or password policy violation"
+// @Failure 409 {object} apiresponse.Problem "Conflict - username or email already exists"
+// @Failure 422 {object} apiresponse.Problem "Unprocessable - Idempotency-Key reused for a different request"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Router /v2/users [post]
+func (d *Dependencies) CreateUserV2(c *gin.Context) {
+	var req models.CreateUser
+
+	if verified := utils.VerifyRequestModel(c, &req); !verified {
+		return
+	}
+
+	response, ok := d.createUser(c, req)
+	if !ok {
+		return
+	}
+
+	c.Header("Location", V2BasePath+"/users/me")
+	apiresponse.SendSuccess(c, http.StatusCreated, response, "User created successfully")
+}
+
+// GetCurrentUserV2 godoc
+// @Summary Get my account
+// @Description Retrieve the account of the authenticated user
+// @Tags users
+// @Produce json
+// @Param If-None-Match header string false "ETag of a cached copy of the user"
+// @Success 200 {object} apiresponse.SuccessResponse{data=models.UserInfo} "Successfully retrieved user"
+// @Header 200 {string} ETag "Version of the user"
+// @Success 304 "The cached copy is current"
+// @Failure 401 {object} apiresponse.Problem "Unauthorized - invalid user"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Router /v2/users/me [get]
+// @Security BearerAuth
+func (d *Dependencies) GetCurrentUserV2(c *gin.Context) {
+	user, ok := d.ValidateUserFromContext(c)
+	if !ok {
+		return
+	}
+
+	response := models.UserInfo{
+		ID:       user.ID,
+		Username: user.Username,
+		Email:    user.Email,
+	}
+
+	sendVersioned(c, http.StatusOK, response, user.Version, "Successfully retrieved user")
+}
+
+// ChangePasswordV2 godoc
+// @Summary Change password
+// @Description Change the password of the authenticated user
+// @Tags users
+// @Accept json
+// @Param request body models.ChangePasswordRequest true "Current and new password"
+// @Param If-Match header string true "ETag of the user, from GET /v2/users/me"
+// @Success 204 "Password changed successfully"
+// @Header 204 {string} ETag "Version of the updated user"
+// @Failure 400 {object} apiresponse.Problem "Bad request - validation error or password policy violation"
+// @Failure 401 {object} apiresponse.Problem "Invalid current password"
+// @Failure 412 {object} apiresponse.Problem "Precondition failed - the user was modified since it was read"
+// @Failure 428 {object} apiresponse.Problem "Precondition required - If-Match is missing"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Router /v2/users/me/password [put]
+// @Security BearerAuth
+func (d *Dependencies) ChangePasswordV2(c *gin.Context) {
+	var req models.ChangePasswordRequest
+
+	if verified := utils.VerifyRequestModel(c, &req); !verified {
+		return
+	}
+
+	user, ok := d.ValidateUserFromContext(c)
+	if !ok {
+		return
+	}
+
+	if !checkIfMatch(c, user.Version) {
 		return
 	}
 
-	// Prepare response
-	expiresAt := time.Now().Add(time.Hour * 24) // Match your JWT expiration
-	responseData := models.LoginResponse{
-		Token:     token,
-		ExpiresAt: expiresAt,
-		User: models.UserInfo{
-			ID:       user.ID,
-			Username: user.Username,
-			Email:    user.Email,
-		},
+	if !d.changePassword(c, user, req) {
+		return
 	}
 
-	apiresponse.SendSuccess(c, http.StatusOK, responseData, "Login successful")
+	c.Header("ETag", etag(user.Version))
+	c.Status(http.StatusNoContent)
 }
@@ -0,0 +1,601 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	apiresponse "github.com/Kantha2004/SimpleJWT/internal/apiResponse"
+	"github.com/Kantha2004/SimpleJWT/internal/db"
+	"github.com/Kantha2004/SimpleJWT/internal/models"
+	"github.com/Kantha2004/SimpleJWT/internal/service"
+	"github.com/Kantha2004/SimpleJWT/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// PublishClientUserEvent queues a webhook event about a client user. Failures
+// are only logged so webhooks never break the request itself.
+func (d *Dependencies) PublishClientUserEvent(c *gin.Context, client *models.Client, user *models.ClientUser, eventType string) {
+	d.authService.PublishClientUserEvent(c.Request.Context(), client, user, eventType)
+}
+
+// getOwnedWebhook fetches a webhook subscription and makes sure its client
+// belongs to the user, handling HTTP error responses automatically
+func (d *Dependencies) getOwnedWebhook(c *gin.Context, user *models.AdminUser, webhookID uint) (*models.WebhookSubscription, bool) {
+	subscription, err := db.NewWebhookRepository(d.requestDB(c)).GetSubscriptionByID(webhookID)
+	if err != nil {
+		d.logError(c, "Error fetching webhook", err, "webhook_id", webhookID)
+		apiresponse.SendInternalError(c, "Error fetching webhook")
+		return nil, false
+	}
+
+	if subscription == nil {
+		apiresponse.SendNotFound(c, "Webhook not found")
+		return nil, false
+	}
+
+	client, err := d.clients(c).GetClientId(subscription.ClientID)
+	if err != nil {
+		d.logError(c, "Error fetching client", err, "client_id", subscription.ClientID)
+		apiresponse.SendInternalError(c, "Error fetching webhook")
+		return nil, false
+	}
+
+	if client == nil || client.UserID != user.ID {
+		apiresponse.SendNotFound(c, "Webhook not found")
+		return nil, false
+	}
+
+	return subscription, true
+}
+
+// CreateWebhook godoc
+// @Summary Subscribe to webhooks
+// @Description Subscribe a URL to events of one of the authenticated user's clients. Deliveries are signed with HMAC-SHA256 using the returned secret, which is only shown once.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param request body models.CreateWebhookRequest true "Webhook subscription"
+// @Param Idempotency-Key header string false "Key that makes retries of the request safe; a replayed response has no body, since it held the secret"
+// @Success 201 {object} apiresponse.SuccessResponse{data=models.CreateWebhookResponse} "Webhook created successfully"
+// @Failure 400 {object} apiresponse.Problem "Bad request - validation error"
+// @Failure 401 {object} apiresponse.Problem "Unauthorized - invalid user"
+// @Failure 404 {object} apiresponse.Problem "Client not found"
+// @Failure 422 {object} apiresponse.Problem "Unprocessable - Idempotency-Key reused for a different request"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Deprecated
+// @Router /v1/protected/createWebhook [post]
+// @Security BearerAuth
+func (d *Dependencies) CreateWebhook(c *gin.Context) {
+	var req models.CreateWebhookRequest
+
+	if verified := utils.VerifyRequestModel(c, &req); !verified {
+		return
+	}
+
+	user, ok := d.ValidateUserFromContext(c)
+	if !ok {
+		return
+	}
+
+	client, ok := d.GetOwnedClient(c, user, req.ClientID)
+	if !ok {
+		return
+	}
+
+	response, ok := d.createWebhook(c, user, client, req.WebhookSubscriptionRequest)
+	if !ok {
+		return
+	}
+
+	markSecret(c)
+	apiresponse.SendSuccess(c, http.StatusCreated, response, "Webhook created successfully")
+}
+
+// createWebhook subscribes a URL to the events of a client, handling HTTP
+// error responses automatically
+func (d *Dependencies) createWebhook(c *gin.Context, user *models.AdminUser, client *models.Client, req models.WebhookSubscriptionRequest) (*models.CreateWebhookResponse, bool) {
+	subscription := &models.WebhookSubscription{
+		ClientID:   client.ID,
+		URL:        req.URL,
+		EventTypes: req.EventTypes,
+		Active:     true,
+	}
+
+	if err := db.NewWebhookRepository(d.requestDB(c)).CreateSubscription(subscription); err != nil {
+		d.logError(c, "Error creating webhook", err)
+		apiresponse.SendInternalError(c, "Failed to create webhook")
+		return nil, false
+	}
+
+	event := service.AdminAuditEvent(user, models.AuditActionWebhookCreate)
+	event.TargetType = "webhook"
+	event.TargetID = formatID(subscription.ID)
+	event.ClientID = &client.ID
+	d.RecordAudit(c, event)
+
+	return &models.CreateWebhookResponse{
+		WebhookSubscription: *subscription,
+		Secret:              subscription.Secret,
+	}, true
+}
+
+// GetWebhooks godoc
+// @Summary List webhooks of a client
+// @Description Retrieve the webhook subscriptions of one of the authenticated user's clients
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param client_id query int true "Client ID"
+// @Success 200 {object} apiresponse.SuccessResponse{data=[]models.WebhookSubscription} "Successfully retrieved webhooks"
+// @Failure 400 {object} apiresponse.Problem "Bad request - validation error"
+// @Failure 401 {object} apiresponse.Problem "Unauthorized - invalid user"
+// @Failure 404 {object} apiresponse.Problem "Client not found"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Deprecated
+// @Router /v1/protected/getWebhooks [get]
+// @Security BearerAuth
+func (d *Dependencies) GetWebhooks(c *gin.Context) {
+	var req struct {
+		ClientID uint `form:"client_id" binding:"required"`
+	}
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		apiresponse.SendValidationError(c, err)
+		return
+	}
+
+	user, ok := d.ValidateUserFromContext(c)
+	if !ok {
+		return
+	}
+
+	client, ok := d.GetOwnedClient(c, user, req.ClientID)
+	if !ok {
+		return
+	}
+
+	subscriptions, ok := d.clientWebhooks(c, client)
+	if !ok {
+		return
+	}
+
+	apiresponse.SendSuccess(c, http.StatusOK, subscriptions, "Successfully retrieved webhooks")
+}
+
+// clientWebhooks lists the webhook subscriptions of a client, handling HTTP
+// error responses automatically
+func (d *Dependencies) clientWebhooks(c *gin.Context, client *models.Client) ([]models.WebhookSubscription, bool) {
+	subscriptions, err := db.NewWebhookRepository(d.requestDB(c)).GetSubscriptionsByClientID(client.ID)
+	if err != nil {
+		d.logError(c, "Error fetching webhooks", err, "client_id", client.ID)
+		apiresponse.SendInternalError(c, "Error fetching webhooks")
+		return nil, false
+	}
+
+	return subscriptions, true
+}
+
+// DeleteWebhook godoc
+// @Summary Delete a webhook
+// @Description Delete a webhook subscription. Pending deliveries are marked failed on their next attempt.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param request body models.WebhookIDRequest true "Webhook to delete"
+// @Success 200 {object} apiresponse.SuccessResponse "Webhook deleted successfully"
+// @Failure 400 {object} apiresponse.Problem "Bad request - validation error"
+// @Failure 401 {object} apiresponse.Problem "Unauthorized - invalid user"
+// @Failure 404 {object} apiresponse.Problem "Webhook not found"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Deprecated
+// @Router /v1/protected/deleteWebhook [post]
+// @Security BearerAuth
+func (d *Dependencies) DeleteWebhook(c *gin.Context) {
+	var req models.WebhookIDRequest
+
+	if verified := utils.VerifyRequestModel(c, &req); !verified {
+		return
+	}
+
+	user, ok := d.ValidateUserFromContext(c)
+	if !ok {
+		return
+	}
+
+	subscription, ok := d.getOwnedWebhook(c, user, req.WebhookID)
+	if !ok {
+		return
+	}
+
+	if !d.deleteWebhook(c, user, subscription) {
+		return
+	}
+
+	apiresponse.SendSuccess(c, http.StatusOK, struct{}{}, "Webhook deleted successfully")
+}
+
+// deleteWebhook deletes a webhook subscription, handling HTTP error
+// responses automatically
+func (d *Dependencies) deleteWebhook(c *gin.Context, user *models.AdminUser, subscription *models.WebhookSubscription) bool {
+	if err := db.NewWebhookRepository(d.requestDB(c)).DeleteSubscription(subscription); err != nil {
+		d.handleUpdateError(c, "Error deleting webhook", "Failed to delete webhook", err, "webhook_id", subscription.ID)
+		return false
+	}
+
+	event := service.AdminAuditEvent(user, models.AuditActionWebhookDelete)
+	event.TargetType = "webhook"
+	event.TargetID = formatID(subscription.ID)
+	event.ClientID = &subscription.ClientID
+	d.RecordAudit(c, event)
+
+	return true
+}
+
+// GetWebhookDeliveries godoc
+// @Summary List webhook deliveries
+// @Description Retrieve the delivery log of a webhook subscription with every attempt, newest deliveries first
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param webhook_id query int true "Webhook ID"
+// @Param status query string false "Only deliveries with this status" Enums(pending, succeeded, failed)
+// @Param limit query int false "Maximum number of deliveries (default 50, max 500)"
+// @Success 200 {object} apiresponse.SuccessResponse{data=[]models.WebhookDeliveryLog} "Successfully retrieved deliveries"
+// @Failure 400 {object} apiresponse.Problem "Bad request - validation error"
+// @Failure 401 {object} apiresponse.Problem "Unauthorized - invalid user"
+// @Failure 404 {object} apiresponse.Problem "Webhook not found"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Deprecated
+// @Router /v1/protected/getWebhookDeliveries [get]
+// @Security BearerAuth
+func (d *Dependencies) GetWebhookDeliveries(c *gin.Context) {
+	var req models.WebhookDeliveriesRequest
+
+	if err := c.ShouldBindQuery(&req); err != nil {
+		apiresponse.SendValidationError(c, err)
+		return
+	}
+
+	user, ok := d.ValidateUserFromContext(c)
+	if !ok {
+		return
+	}
+
+	subscription, ok := d.getOwnedWebhook(c, user, req.WebhookID)
+	if !ok {
+		return
+	}
+
+	logs, ok := d.webhookDeliveries(c, subscription, req.Status, req.Limit)
+	if !ok {
+		return
+	}
+
+	apiresponse.SendSuccess(c, http.StatusOK, logs, "Successfully retrieved deliveries")
+}
+
+// webhookDeliveries fetches the delivery log of a webhook subscription with
+// every attempt, handling HTTP error responses automatically
+func (d *Dependencies) webhookDeliveries(c *gin.Context, subscription *models.WebhookSubscription, status string, limit int) ([]models.WebhookDeliveryLog, bool) {
+	webhookRepo := db.NewWebhookRepository(d.requestDB(c))
+
+	deliveries, err := webhookRepo.GetDeliveries(subscription.ID, status, limit)
+	if err != nil {
+		d.logError(c, "Error fetching deliveries", err, "webhook_id", subscription.ID)
+		apiresponse.SendInternalError(c, "Error fetching deliveries")
+		return nil, false
+	}
+
+	deliveryIDs := make([]uint, len(deliveries))
+	for i, delivery := range deliveries {
+		deliveryIDs[i] = delivery.ID
+	}
+
+	attempts, err := webhookRepo.GetAttempts(deliveryIDs)
+	if err != nil {
+		d.logError(c, "Error fetching delivery attempts", err, "webhook_id", subscription.ID)
+		apiresponse.SendInternalError(c, "Error fetching deliveries")
+		return nil, false
+	}
+
+	attemptsByDelivery := make(map[uint][]models.WebhookDeliveryAttempt)
+	for _, attempt := range attempts {
+		attemptsByDelivery[attempt.DeliveryID] = append(attemptsByDelivery[attempt.DeliveryID], attempt)
+	}
+
+	logs := make([]models.WebhookDeliveryLog, len(deliveries))
+	for i, delivery := range deliveries {
+		logs[i] = models.WebhookDeliveryLog{
+			WebhookDelivery: delivery,
+			AttemptLog:      attemptsByDelivery[delivery.ID],
+		}
+	}
+
+	return logs, true
+}
+
+// RedeliverWebhook godoc
+// @Summary Redeliver a webhook
+// @Description Queue a delivery to be sent again right away
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param request body models.RedeliverWebhookRequest true "Delivery to send again"
+// @Success 200 {object} apiresponse.SuccessResponse{data=models.WebhookDelivery} "Delivery queued successfully"
+// @Failure 400 {object} apiresponse.Problem "Bad request - validation error"
+// @Failure 401 {object} apiresponse.Problem "Unauthorized - invalid user"
+// @Failure 404 {object} apiresponse.Problem "Delivery not found"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Deprecated
+// @Router /v1/protected/redeliverWebhook [post]
+// @Security BearerAuth
+func (d *Dependencies) RedeliverWebhook(c *gin.Context) {
+	var req models.RedeliverWebhookRequest
+
+	if verified := utils.VerifyRequestModel(c, &req); !verified {
+		return
+	}
+
+	user, ok := d.ValidateUserFromContext(c)
+	if !ok {
+		return
+	}
+
+	delivery, subscription, ok := d.getOwnedDelivery(c, user, req.DeliveryID)
+	if !ok {
+		return
+	}
+
+	if !d.redeliverWebhook(c, user, subscription, delivery) {
+		return
+	}
+
+	apiresponse.SendSuccess(c, http.StatusOK, delivery, "Delivery queued successfully")
+}
+
+// getOwnedDelivery fetches a webhook delivery and its subscription and makes
+// sure the subscription belongs to the user, handling HTTP error responses
+// automatically
+func (d *Dependencies) getOwnedDelivery(c *gin.Context, user *models.AdminUser, deliveryID uint) (*models.WebhookDelivery, *models.WebhookSubscription, bool) {
+	delivery, err := db.NewWebhookRepository(d.requestDB(c)).GetDeliveryByID(deliveryID)
+	if err != nil {
+		d.logError(c, "Error fetching delivery", err, "delivery_id", deliveryID)
+		apiresponse.SendInternalError(c, "Error fetching delivery")
+		return nil, nil, false
+	}
+
+	if delivery == nil {
+		apiresponse.SendNotFound(c, "Delivery not found")
+		return nil, nil, false
+	}
+
+	subscription, ok := d.getOwnedWebhook(c, user, delivery.SubscriptionID)
+	if !ok {
+		return nil, nil, false
+	}
+
+	return delivery, subscription, true
+}
+
+// redeliverWebhook queues a delivery to be sent again right away, handling
+// HTTP error responses automatically
+func (d *Dependencies) redeliverWebhook(c *gin.Context, user *models.AdminUser, subscription *models.WebhookSubscription, delivery *models.WebhookDelivery) bool {
+	if err := db.NewWebhookRepository(d.requestDB(c)).ResetDelivery(delivery); err != nil {
+		d.logError(c, "Error queueing delivery", err, "delivery_id", delivery.ID)
+		apiresponse.SendInternalError(c, "Failed to queue delivery")
+		return false
+	}
+
+	event := service.AdminAuditEvent(user, models.AuditActionWebhookRedeliver)
+	event.TargetType = "webhook_delivery"
+	event.TargetID = formatID(delivery.ID)
+	event.ClientID = &subscription.ClientID
+	d.RecordAudit(c, event)
+
+	return true
+}
+
+// CreateWebhookV2 godoc
+// @Summary Subscribe to webhooks
+// @Description Subscribe a URL to events of one of the authenticated user's clients. Deliveries are signed with HMAC-SHA256 using the returned secret, which is only shown once.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param clientId path int true "Client ID"
+// @Param request body models.WebhookSubscriptionRequest true "Webhook subscription"
+// @Param Idempotency-Key header string false "Key that makes retries of the request safe; a replayed response has no body, since it held the secret"
+// @Success 201 {object} apiresponse.SuccessResponse{data=models.CreateWebhookResponse} "Webhook created successfully"
+// @Failure 400 {object} apiresponse.Problem "Bad request - validation error"
+// @Failure 401 {object} apiresponse.Problem "Unauthorized - invalid user"
+// @Failure 404 {object} apiresponse.Problem "Client not found"
+// @Failure 422 {object} apiresponse.Problem "Unprocessable - Idempotency-Key reused for a different request"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Router /v2/clients/{clientId}/webhooks [post]
+// @Security BearerAuth
+func (d *Dependencies) CreateWebhookV2(c *gin.Context) {
+	var req models.WebhookSubscriptionRequest
+
+	if verified := utils.VerifyRequestModel(c, &req); !verified {
+		return
+	}
+
+	user, client, ok := d.GetClientFromPath(c)
+	if !ok {
+		return
+	}
+
+	response, ok := d.createWebhook(c, user, client, req)
+	if !ok {
+		return
+	}
+
+	c.Header("Location", fmt.Sprintf("%s/clients/%d/webhooks/%d", V2BasePath, client.ID, response.ID))
+	markSecret(c)
+	apiresponse.SendSuccess(c, http.StatusCreated, response, "Webhook created successfully")
+}
+
+// ListWebhooksV2 godoc
+// @Summary List webhooks of a client
+// @Description Retrieve the webhook subscriptions of one of the authenticated user's clients
+// @Tags webhooks
+// @Produce json
+// @Param clientId path int true "Client ID"
+// @Success 200 {object} apiresponse.SuccessResponse{data=[]models.WebhookSubscription} "Successfully retrieved webhooks"
+// @Failure 400 {object} apiresponse.Problem "Bad request - validation error"
+// @Failure 401 {object} apiresponse.Problem "Unauthorized - invalid user"
+// @Failure 404 {object} apiresponse.Problem "Client not found"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Router /v2/clients/{clientId}/webhooks [get]
+// @Security BearerAuth
+func (d *Dependencies) ListWebhooksV2(c *gin.Context) {
+	_, client, ok := d.GetClientFromPath(c)
+	if !ok {
+		return
+	}
+
+	subscriptions, ok := d.clientWebhooks(c, client)
+	if !ok {
+		return
+	}
+
+	apiresponse.SendSuccess(c, http.StatusOK, nonNil(subscriptions), "Successfully retrieved webhooks")
+}
+
+// GetWebhookV2 godoc
+// @Summary Get a webhook
+// @Description Retrieve a webhook subscription of one of the authenticated user's clients
+// @Tags webhooks
+// @Produce json
+// @Param clientId path int true "Client ID"
+// @Param webhookId path int true "Webhook ID"
+// @Param If-None-Match header string false "ETag of a cached copy of the webhook"
+// @Success 200 {object} apiresponse.SuccessResponse{data=models.WebhookSubscription} "Successfully retrieved webhook"
+// @Header 200 {string} ETag "Version of the webhook"
+// @Success 304 "The cached copy is current"
+// @Failure 400 {object} apiresponse.Problem "Bad request - validation error"
+// @Failure 401 {object} apiresponse.Problem "Unauthorized - invalid user"
+// @Failure 404 {object} apiresponse.Problem "Client or webhook not found"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Router /v2/clients/{clientId}/webhooks/{webhookId} [get]
+// @Security BearerAuth
+func (d *Dependencies) GetWebhookV2(c *gin.Context) {
+	_, subscription, ok := d.GetWebhookFromPath(c)
+	if !ok {
+		return
+	}
+
+	sendVersioned(c, http.StatusOK, subscription, subscription.Version, "Successfully retrieved webhook")
+}
+
+// DeleteWebhookV2 godoc
+// @Summary Delete a webhook
+// @Description Delete a webhook subscription. Pending deliveries are marked failed on their next attempt.
+// @Tags webhooks
+// @Param clientId path int true "Client ID"
+// @Param webhookId path int true "Webhook ID"
+// @Param If-Match header string true "ETag of the webhook"
+// @Success 204 "Webhook deleted successfully"
+// @Failure 400 {object} apiresponse.Problem "Bad request - validation error"
+// @Failure 401 {object} apiresponse.Problem "Unauthorized - invalid user"
+// @Failure 404 {object} apiresponse.Problem "Client or webhook not found"
+// @Failure 412 {object} apiresponse.Problem "Precondition failed - the webhook was modified since it was read"
+// @Failure 428 {object} apiresponse.Problem "Precondition required - If-Match is missing"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Router /v2/clients/{clientId}/webhooks/{webhookId} [delete]
+// @Security BearerAuth
+func (d *Dependencies) DeleteWebhookV2(c *gin.Context) {
+	user, subscription, ok := d.GetWebhookFromPath(c)
+	if !ok {
+		return
+	}
+
+	if !checkIfMatch(c, subscription.Version) {
+		return
+	}
+
+	if !d.deleteWebhook(c, user, subscription) {
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListWebhookDeliveriesV2 godoc
+// @Summary List webhook deliveries
+// @Description Retrieve the delivery log of a webhook subscription with every attempt, newest deliveries first
+// @Tags webhooks
+// @Produce json
+// @Param clientId path int true "Client ID"
+// @Param webhookId path int true "Webhook ID"
+// @Param status query string false "Only deliveries with this status" Enums(pending, succeeded, failed)
+// @Param limit query int false "Maximum number of deliveries (default 50, max 500)"
+// @Success 200 {object} apiresponse.SuccessResponse{data=[]models.WebhookDeliveryLog} "Successfully retrieved deliveries"
+// @Failure 400 {object} apiresponse.Problem "Bad request - validation error"
+// @Failure 401 {object} apiresponse.Problem "Unauthorized - invalid user"
+// @Failure 404 {object} apiresponse.Problem "Client or webhook not found"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Router /v2/clients/{clientId}/webhooks/{webhookId}/deliveries [get]
+// @Security BearerAuth
+func (d *Dependencies) ListWebhookDeliveriesV2(c *gin.Context) {
+	var query models.WebhookDeliveriesFilter
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		apiresponse.SendValidationError(c, err)
+		return
+	}
+
+	_, subscription, ok := d.GetWebhookFromPath(c)
+	if !ok {
+		return
+	}
+
+	logs, ok := d.webhookDeliveries(c, subscription, query.Status, query.Limit)
+	if !ok {
+		return
+	}
+
+	apiresponse.SendSuccess(c, http.StatusOK, logs, "Successfully retrieved deliveries")
+}
+
+// RedeliverWebhookV2 godoc
+// @Summary Redeliver a webhook
+// @Description Queue a delivery to be sent again right away
+// @Tags webhooks
+// @Produce json
+// @Param clientId path int true "Client ID"
+// @Param webhookId path int true "Webhook ID"
+// @Param deliveryId path int true "Delivery ID"
+// @Success 202 {object} apiresponse.SuccessResponse{data=models.WebhookDelivery} "Delivery queued successfully"
+// @Failure 400 {object} apiresponse.Problem "Bad request - validation error"
+// @Failure 401 {object} apiresponse.Problem "Unauthorized - invalid user"
+// @Failure 404 {object} apiresponse.Problem "Client, webhook or delivery not found"
+// @Failure 500 {object} apiresponse.Problem "Internal server error"
+// @Router /v2/clients/{clientId}/webhooks/{webhookId}/deliveries/{deliveryId}/attempts [post]
+// @Security BearerAuth
+func (d *Dependencies) RedeliverWebhookV2(c *gin.Context) {
+	user, subscription, ok := d.GetWebhookFromPath(c)
+	if !ok {
+		return
+	}
+
+	deliveryID, ok := pathID(c, "deliveryId")
+	if !ok {
+		return
+	}
+
+	delivery, _, ok := d.getOwnedDelivery(c, user, deliveryID)
+	if !ok {
+		return
+	}
+
+	if delivery.SubscriptionID != subscription.ID {
+		apiresponse.SendNotFound(c, "Delivery not found")
+		return
+	}
+
+	if !d.redeliverWebhook(c, user, subscription, delivery) {
+		return
+	}
+
+	apiresponse.SendSuccess(c, http.StatusAccepted, delivery, "Delivery queued successfully")
+}
@@ -0,0 +1,176 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/Kantha2004/SimpleJWT/internal/auth"
+	"github.com/Kantha2004/SimpleJWT/internal/db"
+	"github.com/gin-gonic/gin"
+)
+
+// readinessTimeout bounds all checks of one readiness probe
+const readinessTimeout = 3 * time.Second
+
+// Health status values
+const (
+	HealthStatusAlive    = "alive"
+	HealthStatusReady    = "ready"
+	HealthStatusNotReady = "not_ready"
+	HealthStatusDraining = "draining"
+	HealthStatusOK       = "ok"
+	HealthStatusFailing  = "failing"
+)
+
+// Readiness check names
+const (
+	healthCheckDatabase    = "database"
+	healthCheckMigrations  = "migrations"
+	healthCheckSigningKeys = "signing_keys"
+	healthCheckShutdown    = "shutdown"
+)
+
+// HealthCheckResult is the outcome of one readiness check
+type HealthCheckResult struct {
+	Status     string `json:"status" example:"ok"`
+	Detail     string `json:"detail,omitempty" example:"version 2"`
+	Error      string `json:"error,omitempty"`
+	DurationMS int64  `json:"duration_ms" example:"1"`
+}
+
+// HealthResponse is returned by the liveness and readiness probes
+type HealthResponse struct {
+	Status  string                       `json:"status" example:"ready"`
+	Service string                       `json:"service" example:"SimpleJWT"`
+	Checks  map[string]HealthCheckResult `json:"checks,omitempty"`
+}
+
+// checkFailure is a failed check whose message is safe to return to the
+// unauthenticated callers of the readiness probe. Any other error is logged
+// and reported as unavailable, since it may describe the database.
+type checkFailure string
+
+func (f checkFailure) Error() string {
+	return string(f)
+}
+
+// HealthChecker reports whether the server can serve traffic
+type HealthChecker struct {
+	database   *db.Database
+	jwtService *auth.JWTService
+	logger     *slog.Logger
+	draining   atomic.Bool
+}
+
+func NewHealthChecker(database *db.Database, jwtService *auth.JWTService, logger *slog.Logger) *HealthChecker {
+	return &HealthChecker{database: database, jwtService: jwtService, logger: logger}
+}
+
+// SetDraining makes the readiness probe fail so load balancers stop routing
+// new requests while in-flight ones finish
+func (h *HealthChecker) SetDraining() {
+	h.draining.Store(true)
+}
+
+// Ready runs every readiness check and reports whether all of them passed
+func (h *HealthChecker) Ready(ctx context.Context) (bool, map[string]HealthCheckResult) {
+	checks := map[string]func(context.Context) (string, error){
+		healthCheckDatabase:    h.checkDatabase,
+		healthCheckMigrations:  h.checkMigrations,
+		healthCheckSigningKeys: h.checkSigningKeys,
+	}
+
+	ready := true
+	results := make(map[string]HealthCheckResult, len(checks)+1)
+	for name, check := range checks {
+		start := time.Now()
+		detail, err := check(ctx)
+
+		result := HealthCheckResult{Status: HealthStatusOK, Detail: detail, DurationMS: time.Since(start).Milliseconds()}
+		if err != nil {
+			ready = false
+			result.Status = HealthStatusFailing
+			result.Error = h.checkError(ctx, name, err)
+		}
+		results[name] = result
+	}
+
+	if h.draining.Load() {
+		ready = false
+		results[healthCheckShutdown] = HealthCheckResult{Status: HealthStatusFailing, Error: "server is shutting down"}
+	}
+
+	return ready, results
+}
+
+// checkError returns the message reported for a failed check
+func (h *HealthChecker) checkError(ctx context.Context, name string, err error) string {
+	var failure checkFailure
+	if errors.As(err, &failure) {
+		return failure.Error()
+	}
+	h.logger.WarnContext(ctx, "Readiness check failed", "check", name, "error", err)
+	return name + ": unavailable"
+}
+
+func (h *HealthChecker) checkDatabase(ctx context.Context) (string, error) {
+	if err := h.database.Ping(ctx); err != nil {
+		return "", err
+	}
+	return h.database.Driver, nil
+}
+
+// checkMigrations only covers the control plane; client schemas are migrated
+// on startup or with simplejwtctl and a lagging schema fails its own requests.
+// It only reads the applied version: probes run often and must not run DDL.
+func (h *HealthChecker) checkMigrations(ctx context.Context) (string, error) {
+	migrator, err := h.database.WithContext(ctx).ControlPlaneMigrator()
+	if err != nil {
+		return "", err
+	}
+
+	version, err := migrator.AppliedVersion()
+	if err != nil {
+		return "", err
+	}
+	if latest := migrator.LatestVersion(); version < latest {
+		return "", checkFailure(fmt.Sprintf("version %d, migrations up to %d are pending", version, latest))
+	}
+	return fmt.Sprintf("version %d", version), nil
+}
+
+func (h *HealthChecker) checkSigningKeys(context.Context) (string, error) {
+	return h.jwtService.SigningKeyID()
+}
+
+// LivenessHandler reports that the process is running. It does not check
+// dependencies, so a database outage does not get the process restarted.
+func LivenessHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, HealthResponse{Status: HealthStatusAlive, Service: "SimpleJWT"})
+}
+
+// ReadinessHandler responds 503 with the failing checks when the server
+// cannot serve traffic, including while it is shutting down
+func (h *HealthChecker) ReadinessHandler(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+	defer cancel()
+
+	ready, checks := h.Ready(ctx)
+
+	response := HealthResponse{Status: HealthStatusReady, Service: "SimpleJWT", Checks: checks}
+	statusCode := http.StatusOK
+	if !ready {
+		response.Status = HealthStatusNotReady
+		statusCode = http.StatusServiceUnavailable
+	}
+	if h.draining.Load() {
+		response.Status = HealthStatusDraining
+	}
+
+	c.JSON(statusCode, response)
+}
@@ -0,0 +1,212 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	apiresponse "github.com/Kantha2004/SimpleJWT/internal/apiResponse"
+	"github.com/Kantha2004/SimpleJWT/internal/db"
+	"github.com/Kantha2004/SimpleJWT/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// IdempotencyKeyHeader lets a client retry a POST request without the
+// operation being performed twice
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayedHeader marks a response replayed from an earlier request
+// with the same Idempotency-Key
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+// maxIdempotencyKeyLength matches the size of the key column
+const maxIdempotencyKeyLength = 255
+
+// idempotencyPurgeInterval is how often expired keys are deleted
+const idempotencyPurgeInterval = time.Hour
+
+// IdempotencyMiddleware stores the response of POST requests carrying an
+// Idempotency-Key for the window, and replays it when the request is
+// retried. Reusing a key for a different request is rejected. Keys are
+// scoped to the authenticated user, so the middleware must come after
+// JWTMiddleware on protected routes, and to the client address elsewhere.
+// Responses marked Cache-Control: no-store hold secrets; only their status
+// and headers are stored, and replayed without a body.
+func IdempotencyMiddleware(keys *db.IdempotencyRepository, window time.Duration, logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if c.Request.Method != http.MethodPost || key == "" {
+			c.Next()
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			apiresponse.SendErrorWithDetails(c, apiresponse.CodeValidation, "The request has invalid headers", []apiresponse.FieldError{
+				apiresponse.NewFieldError(IdempotencyKeyHeader, "max", "must be at most %s characters long", strconv.Itoa(maxIdempotencyKeyLength)),
+			})
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			apiresponse.SendError(c, apiresponse.CodeMalformedRequest, "The request body could not be read")
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		repo := keys.WithContext(c.Request.Context())
+		record := &models.IdempotencyKey{
+			Scope:       idempotencyScope(c),
+			Key:         key,
+			Fingerprint: requestFingerprint(c, body),
+			ExpiresAt:   time.Now().Add(window),
+		}
+
+		reserved, err := repo.ReserveKey(record)
+		if err != nil {
+			logger.ErrorContext(c.Request.Context(), "Error reserving idempotency key", "error", err)
+			apiresponse.SendInternalError(c, "Internal server error")
+			return
+		}
+		if !reserved {
+			replayIdempotentRequest(c, repo, record, logger)
+			return
+		}
+
+		// Released unless the response is stored, including when the handler
+		// panics, so the request can be retried
+		completed := false
+		defer func() {
+			if completed {
+				return
+			}
+			if err := repo.ReleaseKey(record.ID); err != nil {
+				logger.ErrorContext(c.Request.Context(), "Error releasing idempotency key", "error", err)
+			}
+		}()
+
+		recorder := &bodyRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+		c.Writer = recorder.ResponseWriter
+
+		// Server errors are not final; the client should be able to retry
+		status := c.Writer.Status()
+		if status >= http.StatusInternalServerError {
+			return
+		}
+
+		header := c.Writer.Header()
+		record.StatusCode = status
+		record.Location = header.Get("Location")
+		record.ETag = header.Get("ETag")
+		if !strings.Contains(header.Get("Cache-Control"), "no-store") {
+			record.ContentType = header.Get("Content-Type")
+			record.ResponseBody = recorder.body.Bytes()
+		}
+		if err := repo.CompleteKey(record); err != nil {
+			logger.ErrorContext(c.Request.Context(), "Error storing idempotent response", "error", err)
+			return
+		}
+		completed = true
+	}
+}
+
+// replayIdempotentRequest answers a request whose key is already held by an
+// earlier request
+func replayIdempotentRequest(c *gin.Context, repo *db.IdempotencyRepository, record *models.IdempotencyKey, logger *slog.Logger) {
+	existing, err := repo.GetKey(record.Scope, record.Key)
+	if err != nil {
+		logger.ErrorContext(c.Request.Context(), "Error fetching idempotency key", "error", err)
+		apiresponse.SendInternalError(c, "Internal server error")
+		return
+	}
+
+	switch {
+	case existing != nil && existing.Fingerprint != record.Fingerprint:
+		apiresponse.SendError(c, apiresponse.CodeIdempotencyKeyReused, "This Idempotency-Key was already used for a different request")
+	case existing == nil || !existing.IsComplete():
+		// A missing record was released by a failed request just now
+		apiresponse.SendError(c, apiresponse.CodeIdempotencyInProgress, "A request with this Idempotency-Key is still being processed")
+	default:
+		c.Header(IdempotentReplayedHeader, "true")
+		if existing.Location != "" {
+			c.Header("Location", existing.Location)
+		}
+		if existing.ETag != "" {
+			c.Header("ETag", existing.ETag)
+		}
+		if len(existing.ResponseBody) == 0 {
+			c.AbortWithStatus(existing.StatusCode)
+			return
+		}
+		c.Data(existing.StatusCode, existing.ContentType, existing.ResponseBody)
+		c.Abort()
+	}
+}
+
+// idempotencyScope keeps the keys of every user apart; requests without a
+// user are scoped to their client address, so callers cannot replay the
+// responses of others by guessing their keys
+func idempotencyScope(c *gin.Context) string {
+	if userID, ok := c.Get("user_id"); ok {
+		if id, ok := userID.(int); ok {
+			return "user:" + strconv.Itoa(id)
+		}
+	}
+	return "address:" + c.ClientIP()
+}
+
+// requestFingerprint identifies a request by its method, path and body
+func requestFingerprint(c *gin.Context, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(c.Request.Method + " " + c.Request.URL.RequestURI() + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// bodyRecorder keeps a copy of the response body while writing it
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *bodyRecorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *bodyRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
+
+// PurgeIdempotencyKeys deletes expired idempotency keys periodically until
+// ctx is canceled
+func PurgeIdempotencyKeys(ctx context.Context, keys *db.IdempotencyRepository, logger *slog.Logger) {
+	ticker := time.NewTicker(idempotencyPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			deleted, err := keys.WithContext(ctx).DeleteExpiredKeys(now)
+			if err != nil {
+				logger.ErrorContext(ctx, "Error deleting expired idempotency keys", "error", err)
+				continue
+			}
+			if deleted > 0 {
+				logger.DebugContext(ctx, "Deleted expired idempotency keys", "count", deleted)
+			}
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package api_test
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Kantha2004/SimpleJWT/internal/api"
+	"github.com/Kantha2004/SimpleJWT/internal/config"
+	"github.com/Kantha2004/SimpleJWT/internal/db"
+	"github.com/Kantha2004/SimpleJWT/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// The middleware runs on a gin engine with a POST route whose handler the
+// test provides, and stores keys in a SQLite database in a new file for
+// every test
+
+func newIdempotentEngine(t *testing.T, handler gin.HandlerFunc) (*gin.Engine, *db.Database) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	database, err := db.NewDatabase(&config.DBConfig{
+		Driver:     config.DriverSQLite,
+		SQLitePath: filepath.Join(t.TempDir(), "simplejwt.db"),
+	})
+	if err != nil {
+		t.Fatalf("NewDatabase: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	engine := gin.New()
+	engine.Use(api.IdempotencyMiddleware(db.NewIdempotencyRepository(database), time.Hour, logger))
+	engine.POST("/things", handler)
+	engine.POST("/other", handler)
+	engine.GET("/things", handler)
+	return engine, database
+}
+
+func send(engine *gin.Engine, method, path, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(api.IdempotencyKeyHeader, key)
+	}
+	recorder := httptest.NewRecorder()
+	engine.ServeHTTP(recorder, req)
+	return recorder
+}
+
+// created answers with a new thing, numbered by the calls counted in calls
+func created(calls *atomic.Int32) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		n := calls.Add(1)
+		c.Header("Location", "/things/"+strconv.Itoa(int(n)))
+		c.Header("ETag", `"1"`)
+		c.JSON(http.StatusCreated, gin.H{"id": n})
+	}
+}
+
+func TestIdempotencyReplay(t *testing.T) {
+	var calls atomic.Int32
+	engine, _ := newIdempotentEngine(t, created(&calls))
+
+	first := send(engine, http.MethodPost, "/things", "key-1", `{"name":"a"}`)
+	if first.Code != http.StatusCreated || first.Header().Get(api.IdempotentReplayedHeader) != "" {
+		t.Fatalf("first request: got %d with headers %v", first.Code, first.Header())
+	}
+
+	replay := send(engine, http.MethodPost, "/things", "key-1", `{"name":"a"}`)
+	if replay.Code != http.StatusCreated || replay.Body.String() != first.Body.String() {
+		t.Fatalf("replay: got %d %q, want %d %q", replay.Code, replay.Body, first.Code, first.Body)
+	}
+	if replay.Header().Get(api.IdempotentReplayedHeader) != "true" ||
+		replay.Header().Get("Location") != "/things/1" ||
+		replay.Header().Get("ETag") != `"1"` ||
+		!strings.HasPrefix(replay.Header().Get("Content-Type"), "application/json") {
+		t.Fatalf("replay headers %v", replay.Header())
+	}
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("handler ran %d times for a replayed request, want 1", n)
+	}
+
+	// Other keys, requests without a key and other methods are not replayed
+	send(engine, http.MethodPost, "/things", "key-2", `{"name":"a"}`)
+	send(engine, http.MethodPost, "/things", "", `{"name":"a"}`)
+	send(engine, http.MethodGet, "/things", "key-1", "")
+	if n := calls.Load(); n != 4 {
+		t.Fatalf("handler ran %d times, want 4", n)
+	}
+}
+
+func TestIdempotencyKeyReusedForDifferentRequest(t *testing.T) {
+	var calls atomic.Int32
+	engine, _ := newIdempotentEngine(t, created(&calls))
+
+	send(engine, http.MethodPost, "/things", "key-1", `{"name":"a"}`)
+
+	tests := []struct {
+		name string
+		path string
+		body string
+	}{
+		{"different body", "/things", `{"name":"b"}`},
+		{"different path", "/other", `{"name":"a"}`},
+		{"different query", "/things?dry_run=true", `{"name":"a"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := send(engine, http.MethodPost, tt.path, "key-1", tt.body)
+			if resp.Code != http.StatusUnprocessableEntity || !strings.Contains(resp.Body.String(), "idempotency_key_reused") {
+				t.Fatalf("got %d %s, want %d", resp.Code, resp.Body, http.StatusUnprocessableEntity)
+			}
+		})
+	}
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("handler ran %d times, want 1", n)
+	}
+}
+
+func TestIdempotencyRequestInFlight(t *testing.T) {
+	entered, release := make(chan struct{}), make(chan struct{})
+	var calls atomic.Int32
+	engine, _ := newIdempotentEngine(t, func(c *gin.Context) {
+		calls.Add(1)
+		close(entered)
+		<-release
+		c.JSON(http.StatusCreated, gin.H{"id": 1})
+	})
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- send(engine, http.MethodPost, "/things", "key-1", `{}`) }()
+	<-entered
+
+	resp := send(engine, http.MethodPost, "/things", "key-1", `{}`)
+	if resp.Code != http.StatusConflict || !strings.Contains(resp.Body.String(), "idempotency_request_in_progress") {
+		t.Fatalf("request during the first: got %d %s, want %d", resp.Code, resp.Body, http.StatusConflict)
+	}
+
+	close(release)
+	if first := <-done; first.Code != http.StatusCreated {
+		t.Fatalf("first request: got %d, want %d", first.Code, http.StatusCreated)
+	}
+	if resp := send(engine, http.MethodPost, "/things", "key-1", `{}`); resp.Code != http.StatusCreated || resp.Header().Get(api.IdempotentReplayedHeader) != "true" {
+		t.Fatalf("request after the first: got %d with headers %v, want a replayed %d", resp.Code, resp.Header(), http.StatusCreated)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("handler ran %d times, want 1", n)
+	}
+}
+
+func TestIdempotencyServerErrorReleasesKey(t *testing.T) {
+	var calls atomic.Int32
+	engine, database := newIdempotentEngine(t, func(c *gin.Context) {
+		if calls.Add(1) == 1 {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "try again"})
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"id": 1})
+	})
+
+	if resp := send(engine, http.MethodPost, "/things", "key-1", `{}`); resp.Code != http.StatusServiceUnavailable {
+		t.Fatalf("first request: got %d, want %d", resp.Code, http.StatusServiceUnavailable)
+	}
+	var stored int64
+	if err := database.DB.Model(&models.IdempotencyKey{}).Count(&stored).Error; err != nil || stored != 0 {
+		t.Fatalf("%d keys stored after a server error, %v", stored, err)
+	}
+
+	resp := send(engine, http.MethodPost, "/things", "key-1", `{}`)
+	if resp.Code != http.StatusCreated || resp.Header().Get(api.IdempotentReplayedHeader) != "" {
+		t.Fatalf("retry: got %d with headers %v, want a new %d", resp.Code, resp.Header(), http.StatusCreated)
+	}
+	if n := calls.Load(); n != 2 {
+		t.Fatalf("handler ran %d times, want 2", n)
+	}
+}
+
+func TestIdempotencyNoStoreResponse(t *testing.T) {
+	var calls atomic.Int32
+	engine, database := newIdempotentEngine(t, func(c *gin.Context) {
+		calls.Add(1)
+		c.Header("Cache-Control", "no-store")
+		c.Header("Location", "/things/1")
+		c.JSON(http.StatusCreated, gin.H{"secret": "s3cr3t"})
+	})
+
+	if first := send(engine, http.MethodPost, "/things", "key-1", `{}`); first.Code != http.StatusCreated || !strings.Contains(first.Body.String(), "s3cr3t") {
+		t.Fatalf("first request: got %d %s", first.Code, first.Body)
+	}
+
+	var record models.IdempotencyKey
+	if err := database.DB.First(&record).Error; err != nil {
+		t.Fatalf("reading the stored key: %v", err)
+	}
+	if record.StatusCode != http.StatusCreated || len(record.ResponseBody) != 0 || record.ContentType != "" {
+		t.Fatalf("stored status %d, content type %q and body %q, want the status only", record.StatusCode, record.ContentType, record.ResponseBody)
+	}
+
+	replay := send(engine, http.MethodPost, "/things", "key-1", `{}`)
+	if replay.Code != http.StatusCreated || replay.Body.Len() != 0 {
+		t.Fatalf("replay: got %d %q, want %d without a body", replay.Code, replay.Body, http.StatusCreated)
+	}
+	if replay.Header().Get(api.IdempotentReplayedHeader) != "true" || replay.Header().Get("Location") != "/things/1" {
+		t.Fatalf("replay headers %v", replay.Header())
+	}
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("handler ran %d times, want 1", n)
+	}
+}
+
+func TestIdempotencyKeyTooLong(t *testing.T) {
+	var calls atomic.Int32
+	engine, _ := newIdempotentEngine(t, created(&calls))
+
+	if resp := send(engine, http.MethodPost, "/things", strings.Repeat("k", 256), `{}`); resp.Code != http.StatusBadRequest {
+		t.Fatalf("got %d, want %d", resp.Code, http.StatusBadRequest)
+	}
+	if resp := send(engine, http.MethodPost, "/things", strings.Repeat("k", 255), `{}`); resp.Code != http.StatusCreated {
+		t.Fatalf("key at the limit: got %d, want %d", resp.Code, http.StatusCreated)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("handler ran %d times, want 1", n)
+	}
+}
@@ -0,0 +1,29 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	apiresponse "github.com/Kantha2004/SimpleJWT/internal/apiResponse"
+	"github.com/gin-gonic/gin"
+)
+
+// jwksMaxAge is how long verifiers may cache the key set. A key added by a
+// rotation signs tokens at once, so verifiers fetch again on an unknown kid.
+const jwksMaxAge = 5 * time.Minute
+
+// JWKSHandler serves the public keys verifying tokens as a JSON Web Key Set
+// (RFC 7517). The set is empty when tokens are signed with the HS256 secret,
+// which cannot be published.
+func (d *Dependencies) JWKSHandler(c *gin.Context) {
+	set, err := d.JWTService.JSONWebKeySet()
+	if err != nil {
+		d.Logger.ErrorContext(c.Request.Context(), "Failed to encode signing keys", "error", err)
+		apiresponse.SendInternalError(c, "Failed to encode signing keys")
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age="+strconv.Itoa(int(jwksMaxAge.Seconds())))
+	c.JSON(http.StatusOK, set)
+}
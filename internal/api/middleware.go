@@ -1,59 +1,332 @@
 package api
 
 import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
 	"net/http"
+	"runtime/debug"
+	"strconv"
 	"strings"
+	"time"
 
+	apiresponse "github.com/Kantha2004/SimpleJWT/internal/apiResponse"
 	"github.com/Kantha2004/SimpleJWT/internal/auth"
+	"github.com/Kantha2004/SimpleJWT/internal/console"
+	"github.com/Kantha2004/SimpleJWT/internal/db"
+	"github.com/Kantha2004/SimpleJWT/internal/logging"
+	"github.com/Kantha2004/SimpleJWT/internal/metrics"
+	"github.com/Kantha2004/SimpleJWT/internal/models"
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
-func CORSMiddleware() gin.HandlerFunc {
+// sessionTouchInterval limits how often a session's last-seen time is written
+const sessionTouchInterval = time.Minute
+
+// RequestIDHeader carries the ID that ties a request to its log lines
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds the request IDs accepted from clients
+const maxRequestIDLength = 128
+
+// DeprecationMiddleware marks the responses of a deprecated API version with
+// the Deprecation and Sunset headers, and links to the version replacing it.
+// Deprecation is a structured field date (RFC 9745) while Sunset stays an
+// HTTP-date (RFC 8594).
+func DeprecationMiddleware(deprecation, sunset time.Time, successor string) gin.HandlerFunc {
+	deprecationDate := "@" + strconv.FormatInt(deprecation.Unix(), 10)
+	sunsetDate := sunset.UTC().Format(http.TimeFormat)
+	link := "<" + successor + `>; rel="successor-version"`
+
 	return func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Header("Deprecation", deprecationDate)
+		c.Header("Sunset", sunsetDate)
+		c.Header("Link", link)
+		c.Next()
+	}
+}
 
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(http.StatusOK)
-			return
+// RequestIDMiddleware reuses the X-Request-ID of the request, or generates
+// one, and echoes it in the response. The ID is added to the request context
+// so log lines and error responses carry it.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = uuid.NewString()
 		}
+
+		c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), requestID))
+		c.Header(RequestIDHeader, requestID)
 		c.Next()
 	}
 }
 
-// JWT middleware for Gin
-func JWTMiddleware(jwtService *auth.JWTService) gin.HandlerFunc {
+// validRequestID only accepts short IDs of safe characters, so a client
+// cannot forge log lines through the header
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range requestID {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// RequestLogger logs one line per request. The query string is left out as
+// it may carry credentials, and probes and scrapes are only logged at debug
+// level.
+func RequestLogger(logger *slog.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
-			c.Abort()
-			return
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		status := c.Writer.Status()
+
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		case route == "/healthz" || route == "/readyz" || route == "/metrics":
+			level = slog.LevelDebug
+		}
+
+		logger.LogAttrs(c.Request.Context(), level, "Request handled",
+			slog.String("method", c.Request.Method),
+			slog.String("path", c.Request.URL.Path),
+			slog.String("route", route),
+			slog.Int("status", status),
+			slog.Int("bytes", c.Writer.Size()),
+			slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.String("client_ip", c.ClientIP()),
+			slog.String("user_agent", c.Request.UserAgent()),
+		)
+	}
+}
+
+// RecoveryMiddleware turns a panic into a 500 response and logs it with its
+// stack trace
+func RecoveryMiddleware(logger *slog.Logger) gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, recovered any) {
+		logger.ErrorContext(c.Request.Context(), "Panic while handling request",
+			"panic", recovered,
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"stack", string(debug.Stack()),
+		)
+		apiresponse.SendInternalError(c, "Internal server error")
+	})
+}
+
+// MetricsMiddleware records the count and latency of requests per route.
+// Requests matching no route share one label, and so do their methods, so
+// scanners cannot inflate the number of series.
+func MetricsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		method, route := c.Request.Method, c.FullPath()
+		if route == "" {
+			method, route = "other", "unmatched"
+		}
+		status := strconv.Itoa(c.Writer.Status())
+
+		metrics.HTTPRequests.WithLabelValues(method, route, status).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+// tokenRejectionReason classifies a token verification error for metrics
+func tokenRejectionReason(err error) string {
+	switch {
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return metrics.TokenExpired
+	case errors.Is(err, jwt.ErrTokenSignatureInvalid):
+		return metrics.TokenBadSignature
+	case errors.Is(err, jwt.ErrTokenMalformed):
+		return metrics.TokenMalformed
+	case errors.Is(err, auth.ErrUnknownSigningKey):
+		return metrics.TokenUnknownKey
+	default:
+		return metrics.TokenInvalid
+	}
+}
+
+// TokenIdentity is who a verified token belongs to
+type TokenIdentity struct {
+	UserID uint
+	// ClientID is the client of a client user token, nil for admin users
+	ClientID  *uint
+	SessionID string
+	Claims    jwt.MapClaims
+	// Session is the session as it was before this use of the token
+	Session *models.Session
+}
+
+// IsClientUser reports whether the token belongs to a user of a client
+func (i *TokenIdentity) IsClientUser() bool {
+	return i.ClientID != nil
+}
+
+// TokenError rejects a token with the problem code of the response
+type TokenError struct {
+	Code   apiresponse.ErrorCode
+	Detail string
+}
+
+func (e *TokenError) Error() string {
+	return e.Detail
+}
+
+// rejectToken counts a rejected token and describes the rejection
+func rejectToken(reason string, code apiresponse.ErrorCode, detail string) *TokenError {
+	metrics.TokensRejected.WithLabelValues(reason).Inc()
+	return &TokenError{Code: code, Detail: detail}
+}
+
+// AuthenticateToken verifies a token and checks the session it was issued
+// for is still active. Client user tokens are only accepted when
+// allowClientUsers is set. Rejected tokens return a *TokenError; other errors
+// are failures to look the session up.
+func AuthenticateToken(ctx context.Context, jwtService *auth.JWTService, sessions *db.SessionRepository, tokenString string, allowClientUsers bool, logger *slog.Logger) (*TokenIdentity, error) {
+	claims, err := jwtService.VerifyToken(tokenString)
+	if err != nil {
+		reason := tokenRejectionReason(err)
+		if reason == metrics.TokenExpired {
+			return nil, rejectToken(reason, apiresponse.CodeTokenExpired, "Token has expired")
+		}
+		return nil, rejectToken(reason, apiresponse.CodeInvalidToken, "Token is invalid")
+	}
+
+	identity := &TokenIdentity{Claims: claims}
+
+	// Client user tokens are only valid for the client's own applications
+	if rawClientID, isClientUser := claims["client_id"]; isClientUser {
+		if !allowClientUsers {
+			return nil, rejectToken(metrics.TokenWrongAudience, apiresponse.CodeInvalidToken, "Client user tokens cannot access this resource")
+		}
+		clientID, ok := rawClientID.(float64)
+		if !ok {
+			return nil, rejectToken(metrics.TokenInvalid, apiresponse.CodeInvalidToken, "Invalid client_id in token")
 		}
+		id := uint(clientID)
+		identity.ClientID = &id
+	}
+
+	uid, ok := claims["user_id"].(float64)
+	if !ok {
+		return nil, rejectToken(metrics.TokenInvalid, apiresponse.CodeInvalidToken, "Invalid user_id in token")
+	}
+	identity.UserID = uint(uid)
+
+	identity.SessionID, _ = claims["sid"].(string)
+	if identity.SessionID == "" {
+		return nil, rejectToken(metrics.TokenMissingSession, apiresponse.CodeInvalidToken, "Token is not bound to a session")
+	}
+
+	sessionRepo := sessions.WithContext(ctx)
+	session, err := sessionRepo.GetSessionBySessionID(identity.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if session == nil || !sameClient(session.ClientID, identity.ClientID) || session.UserID != identity.UserID || !session.IsActive(now) {
+		return nil, rejectToken(metrics.TokenRevoked, apiresponse.CodeSessionRevoked, "Session has been revoked or has expired")
+	}
+
+	identity.Session = session
 
-		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-		if tokenString == authHeader {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization format"})
-			c.Abort()
+	if now.Sub(session.LastSeenAt) > sessionTouchInterval {
+		if err := sessionRepo.TouchSession(identity.SessionID, now); err != nil {
+			logger.WarnContext(ctx, "Error updating session last seen time", "error", err)
+		}
+	}
+
+	metrics.TokensVerified.Inc()
+
+	return identity, nil
+}
+
+// sameClient reports whether two optional client IDs are equal
+func sameClient(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// JWT middleware for Gin. Only admin user tokens backed by an active session are accepted.
+// Requests without an Authorization header may authenticate with the session
+// cookie of the web console instead, if they carry its CSRF token.
+func JWTMiddleware(jwtService *auth.JWTService, sessions *db.SessionRepository, logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenString, fromCookie, ok := requestToken(c)
+		if !ok {
 			return
 		}
 
-		claims, err := jwtService.VerifyToken(tokenString)
-		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token: " + err.Error()})
-			c.Abort()
+		identity, err := AuthenticateToken(c.Request.Context(), jwtService, sessions, tokenString, false, logger)
+		var tokenErr *TokenError
+		switch {
+		case errors.As(err, &tokenErr):
+			apiresponse.SendError(c, tokenErr.Code, tokenErr.Detail)
+			return
+		case err != nil:
+			logger.ErrorContext(c.Request.Context(), "Error fetching session", "error", err)
+			apiresponse.SendInternalError(c, "Failed to validate session")
 			return
 		}
 
-		// Store user info in Gin context
-		if uid, ok := claims["user_id"].(float64); ok {
-			c.Set("user_id", int(uid))
-		} else {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid user_id in token"})
+		// Browsers send the cookie with requests of any page, so only the
+		// console, which knows the CSRF token of the session, may change
+		// something with it
+		if fromCookie && !console.CheckCSRF(c.Request, identity.Session.CSRFToken) {
+			apiresponse.SendError(c, apiresponse.CodeInvalidCSRFToken, "The %s header must hold the CSRF token of the session", console.CSRFHeader)
 			return
 		}
 
+		// Store user info in Gin context
+		c.Set("user_id", int(identity.UserID))
+		c.Set("session_id", identity.SessionID)
+
 		c.Next()
 	}
 }
+
+// requestToken returns the bearer token of the Authorization header, or else
+// the token of the console session cookie, handling HTTP error responses
+// automatically. fromCookie reports which one it is.
+func requestToken(c *gin.Context) (token string, fromCookie bool, ok bool) {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		cookie, err := c.Cookie(console.SessionCookie)
+		if err != nil || cookie == "" {
+			metrics.TokensRejected.WithLabelValues(metrics.TokenMissing).Inc()
+			apiresponse.SendError(c, apiresponse.CodeMissingToken, "Authorization header required")
+			return "", false, false
+		}
+		return cookie, true, true
+	}
+
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+	if tokenString == authHeader {
+		metrics.TokensRejected.WithLabelValues(metrics.TokenMalformed).Inc()
+		apiresponse.SendError(c, apiresponse.CodeInvalidToken, "Authorization header must hold a Bearer token")
+		return "", false, false
+	}
+	return tokenString, false, true
+}
@@ -1,12 +1,14 @@
 package api
 
 import (
-	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/Kantha2004/SimpleJWT/internal/api/handlers"
 	apiresponse "github.com/Kantha2004/SimpleJWT/internal/apiResponse"
+	"github.com/Kantha2004/SimpleJWT/internal/metrics"
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
 // testHandler godoc
@@ -16,42 +18,150 @@ import (
 // @Accept       json
 // @Produce      json
 // @Success      201  {object}  map[string]interface{}  "User created successfully"
-// @Failure      401  {object}  map[string]interface{}  "Unauthorized"
-// @Router       /protected/test [get]
+// @Failure      401  {object}  apiresponse.Problem     "Unauthorized"
+// @Deprecated
+// @Router       /v1/protected/test [get]
 // @Security     BearerAuth
 func testHandler(c *gin.Context) {
-	userID, ok := c.Get("user_id")
-	if !ok {
+	if _, ok := c.Get("user_id"); !ok {
 		apiresponse.SendInternalError(c, "Unable to get userId")
+		return
 	}
-	fmt.Println(userID)
 	apiresponse.SendSuccess(c, http.StatusCreated, struct{}{}, "User created successfully")
 }
 
-func SetupGinRoutes(router *gin.Engine, deps *Dependencies, handlerDeps *handlers.Dependencies) {
+func SetupGinRoutes(router *gin.Engine, serviceName string, v1Deprecation, v1Sunset time.Time, deps *Dependencies, handlerDeps *handlers.Dependencies) {
 	// Add global middleware
-	router.Use(gin.Logger())
-	router.Use(gin.Recovery())
+	router.Use(RequestIDMiddleware())
+	router.Use(RecoveryMiddleware(deps.Logger))
+	router.Use(MetricsMiddleware())
+	router.Use(otelgin.Middleware(serviceName, otelgin.WithGinFilter(func(c *gin.Context) bool {
+		// Probes and scrapes would drown the traces of real requests
+		switch c.FullPath() {
+		case "/healthz", "/readyz", "/metrics", "/.well-known/jwks.json":
+			return false
+		}
+		return true
+	})))
+	// After the tracing middleware so request lines carry the trace ID
+	router.Use(RequestLogger(deps.Logger))
+	router.Use(deps.CORS.Middleware())
+	router.Use(deps.RateLimiter.Middleware())
+
+	// Probes live outside the versioned API so orchestrators need no base path
+	router.GET("/healthz", LivenessHandler)
+	router.GET("/readyz", deps.Health.ReadinessHandler)
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+
+	// Resource servers verify tokens with these keys, see pkg/verifier
+	router.GET("/.well-known/jwks.json", deps.JWKSHandler)
+
+	// Every error response is a problem whose type points here
+	router.GET("/api/problems", ProblemCatalogHandler)
+	router.GET("/api/problems/:code", ProblemTypeHandler)
+
+	router.HandleMethodNotAllowed = true
+	router.NoRoute(NoRouteHandler)
+	router.NoMethod(NoMethodHandler)
+
+	// v1 exposes verb-named RPC routes; it stays until its sunset date so
+	// existing integrations have time to move to v2
+	idempotency := IdempotencyMiddleware(deps.IdempotencyKeys, deps.IdempotencyWindow, deps.Logger)
 
 	v1 := router.Group("api/v1")
+	v1.Use(DeprecationMiddleware(v1Deprecation, v1Sunset, handlers.V2BasePath))
 	{
 		// GET Methods
 		v1.GET("/ping", PingHandler)
 
 		// POST Methods
-		v1.POST("/createUser", handlerDeps.CreateUser)
+		v1.POST("/createUser", idempotency, handlerDeps.CreateUser)
 		v1.POST("/login", handlerDeps.Login)
+		v1.POST("/clientUserLogin", handlerDeps.ClientUserLogin)
 	}
 
-	protected := router.Group("api/v1/protected")
-	protected.Use(JWTMiddleware(deps.JWTService))
+	protected := v1.Group("/protected")
+	protected.Use(JWTMiddleware(deps.JWTService, deps.Sessions, deps.Logger), idempotency)
 	{
 		// GET Methods
 		protected.GET("/test", testHandler)
 		protected.GET("/getAllClients", handlerDeps.GetAllClients)
+		protected.GET("/getSessions", handlerDeps.GetSessions)
+		protected.GET("/getClientUserSessions", handlerDeps.GetClientUserSessions)
+		protected.GET("/getWebhooks", handlerDeps.GetWebhooks)
+		protected.GET("/getWebhookDeliveries", handlerDeps.GetWebhookDeliveries)
 
 		// POST Methods
 		protected.POST("/createClient", handlerDeps.CreateClient)
 		protected.POST("/createClientUser", handlerDeps.CreateClientUser)
+		protected.POST("/changePassword", handlerDeps.ChangePassword)
+		protected.POST("/revokeSession", handlerDeps.RevokeSession)
+		protected.POST("/revokeOtherSessions", handlerDeps.RevokeOtherSessions)
+		protected.POST("/revokeClientUserSessions", handlerDeps.RevokeClientUserSessions)
+		protected.POST("/disableClientUser", handlerDeps.DisableClientUser)
+		protected.POST("/resetClientUserPassword", handlerDeps.ResetClientUserPassword)
+		protected.POST("/createWebhook", handlerDeps.CreateWebhook)
+		protected.POST("/deleteWebhook", handlerDeps.DeleteWebhook)
+		protected.POST("/redeliverWebhook", handlerDeps.RedeliverWebhook)
+	}
+
+	audit := v1.Group("/audit")
+	audit.Use(JWTMiddleware(deps.JWTService, deps.Sessions, deps.Logger))
+	{
+		audit.GET("", handlerDeps.GetAuditEvents)
+	}
+
+	// v2 models the same operations as resources
+	v2 := router.Group(handlers.V2BasePath)
+	{
+		v2.GET("/ping", PingHandler)
+		v2.POST("/users", idempotency, handlerDeps.CreateUserV2)
+		v2.POST("/sessions", handlerDeps.CreateSessionV2)
+		v2.POST("/clients/:clientId/sessions", handlerDeps.CreateClientUserSessionV2)
+	}
+
+	// Versioned resources are sent with an ETag, and changing them requires
+	// If-Match; sessions are not versioned and are revoked without it
+	v2Protected := v2.Group("")
+	v2Protected.Use(JWTMiddleware(deps.JWTService, deps.Sessions, deps.Logger), idempotency)
+	{
+		v2Protected.GET("/users/me", handlerDeps.GetCurrentUserV2)
+		v2Protected.PUT("/users/me/password", handlerDeps.ChangePasswordV2)
+
+		v2Protected.GET("/sessions", handlerDeps.ListSessionsV2)
+		v2Protected.DELETE("/sessions", handlerDeps.DeleteOtherSessionsV2)
+		v2Protected.DELETE("/sessions/:sessionId", handlerDeps.DeleteSessionV2)
+
+		v2Protected.GET("/clients", handlerDeps.ListClientsV2)
+		v2Protected.POST("/clients", handlerDeps.CreateClientV2)
+		v2Protected.GET("/clients/:clientId", handlerDeps.GetClientV2)
+		v2Protected.POST("/clients/:clientId/secret", handlerDeps.RotateClientSecretV2)
+		v2Protected.GET("/clients/:clientId/config", handlerDeps.GetClientConfigV2)
+		v2Protected.PUT("/clients/:clientId/config", handlerDeps.UpdateClientConfigV2)
+
+		v2Protected.GET("/clients/:clientId/roles", handlerDeps.ListRolesV2)
+		v2Protected.POST("/clients/:clientId/roles", handlerDeps.CreateRoleV2)
+		v2Protected.GET("/clients/:clientId/roles/:roleId", handlerDeps.GetRoleV2)
+		v2Protected.DELETE("/clients/:clientId/roles/:roleId", handlerDeps.DeleteRoleV2)
+
+		v2Protected.GET("/clients/:clientId/users", handlerDeps.ListClientUsersV2)
+		v2Protected.POST("/clients/:clientId/users", handlerDeps.CreateClientUserV2)
+		v2Protected.GET("/clients/:clientId/users/:userId", handlerDeps.GetClientUserV2)
+		v2Protected.PATCH("/clients/:clientId/users/:userId", handlerDeps.UpdateClientUserV2)
+		v2Protected.PUT("/clients/:clientId/users/:userId/password", handlerDeps.SetClientUserPasswordV2)
+		v2Protected.GET("/clients/:clientId/users/:userId/roles", handlerDeps.GetClientUserRolesV2)
+		v2Protected.PUT("/clients/:clientId/users/:userId/roles", handlerDeps.SetClientUserRolesV2)
+		v2Protected.GET("/clients/:clientId/users/:userId/sessions", handlerDeps.ListClientUserSessionsV2)
+		v2Protected.DELETE("/clients/:clientId/users/:userId/sessions", handlerDeps.DeleteClientUserSessionsV2)
+		v2Protected.DELETE("/clients/:clientId/users/:userId/sessions/:sessionId", handlerDeps.DeleteClientUserSessionV2)
+
+		v2Protected.GET("/clients/:clientId/webhooks", handlerDeps.ListWebhooksV2)
+		v2Protected.POST("/clients/:clientId/webhooks", handlerDeps.CreateWebhookV2)
+		v2Protected.GET("/clients/:clientId/webhooks/:webhookId", handlerDeps.GetWebhookV2)
+		v2Protected.DELETE("/clients/:clientId/webhooks/:webhookId", handlerDeps.DeleteWebhookV2)
+		v2Protected.GET("/clients/:clientId/webhooks/:webhookId/deliveries", handlerDeps.ListWebhookDeliveriesV2)
+		v2Protected.POST("/clients/:clientId/webhooks/:webhookId/deliveries/:deliveryId/attempts", handlerDeps.RedeliverWebhookV2)
+
+		v2Protected.GET("/audit-events", handlerDeps.GetAuditEvents)
 	}
 }
@@ -0,0 +1,158 @@
+package apiresponse
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/text/language"
+)
+
+// DefaultLanguage is the language messages are written in. It needs no
+// catalog: a message is its own English translation.
+const DefaultLanguage = "en"
+
+// clientLocaleKey holds the resolver of the default locale of the client a
+// request targets
+const clientLocaleKey = "apiresponse.client_locale"
+
+// Catalogs map the English text of a message to its translation, one file
+// per language named after its base language (fr.json, ...)
+//
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+var catalogs = map[string]map[string]string{}
+
+// matcher picks the supported language closest to a requested one
+var matcher language.Matcher
+
+// supported lists the languages of matcher, DefaultLanguage first
+var supported []string
+
+func init() {
+	entries, err := localeFiles.ReadDir("locales")
+	if err != nil {
+		panic(fmt.Sprintf("apiresponse: failed to read locales: %v", err))
+	}
+
+	supported = []string{DefaultLanguage}
+	for _, entry := range entries {
+		data, err := localeFiles.ReadFile(path.Join("locales", entry.Name()))
+		if err != nil {
+			panic(fmt.Sprintf("apiresponse: failed to read %s: %v", entry.Name(), err))
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Sprintf("apiresponse: invalid catalog %s: %v", entry.Name(), err))
+		}
+		lang := strings.TrimSuffix(entry.Name(), path.Ext(entry.Name()))
+		catalogs[lang] = messages
+		supported = append(supported, lang)
+	}
+
+	tags := make([]language.Tag, len(supported))
+	for i, lang := range supported {
+		tags[i] = language.Make(lang)
+	}
+	matcher = language.NewMatcher(tags)
+}
+
+// SupportedLanguages lists the languages error messages are available in
+func SupportedLanguages() []string {
+	return append([]string(nil), supported...)
+}
+
+// IsSupportedLanguage reports whether a locale matches a bundled language
+func IsSupportedLanguage(locale string) bool {
+	_, ok := matchLanguage(locale)
+	return ok
+}
+
+// SetClientLocale records how to find the default locale of the client a
+// request targets. It is only called when an error is sent, and only used
+// when the Accept-Language header names no supported language.
+func SetClientLocale(c *gin.Context, locale func() string) {
+	c.Set(clientLocaleKey, locale)
+}
+
+// matchLanguage returns the supported language of a locale (fr-CA → fr)
+func matchLanguage(locale string) (string, bool) {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return "", false
+	}
+	_, index, confidence := matcher.Match(tag)
+	if confidence == language.No {
+		return "", false
+	}
+	return supported[index], true
+}
+
+// localeChain is the ordered list of languages a message is looked up in.
+// A message missing from every catalog of the chain stays in English.
+type localeChain []string
+
+// requestLocales builds the fallback chain of a request: the languages of
+// Accept-Language by preference, then the default locale of the client, then
+// English
+func requestLocales(c *gin.Context) localeChain {
+	var chain localeChain
+	add := func(lang string) {
+		for _, existing := range chain {
+			if existing == lang {
+				return
+			}
+		}
+		chain = append(chain, lang)
+	}
+
+	tags, _, _ := language.ParseAcceptLanguage(c.GetHeader("Accept-Language"))
+	for _, tag := range tags {
+		if lang, ok := matchLanguage(tag.String()); ok {
+			add(lang)
+		}
+	}
+
+	if value, ok := c.Get(clientLocaleKey); ok {
+		if resolve, ok := value.(func() string); ok {
+			if lang, ok := matchLanguage(resolve()); ok {
+				add(lang)
+			}
+		}
+	}
+
+	add(DefaultLanguage)
+	return chain
+}
+
+// language is the language of the response, the first of the chain
+func (chain localeChain) language() string {
+	return chain[0]
+}
+
+// translate looks a message up along the chain and applies its args
+func (chain localeChain) translate(message string, args ...any) string {
+	if message == "" {
+		return message
+	}
+
+	translated := message
+	for _, lang := range chain {
+		if lang == DefaultLanguage {
+			break
+		}
+		if text, ok := catalogs[lang][message]; ok {
+			translated = text
+			break
+		}
+	}
+
+	if len(args) == 0 {
+		return translated
+	}
+	return fmt.Sprintf(translated, args...)
+}
@@ -0,0 +1,213 @@
+package apiresponse
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/Kantha2004/SimpleJWT/internal/logging"
+	"github.com/gin-gonic/gin"
+)
+
+// ProblemContentType is the media type of error responses (RFC 7807)
+const ProblemContentType = "application/problem+json"
+
+// ProblemTypeBase prefixes the code of an error to build its problem type
+// URI, where the catalog entry of the code is served
+const ProblemTypeBase = "/api/problems/"
+
+// ErrorCode identifies a kind of error. Codes are part of the API contract:
+// clients may branch on them, so existing codes are never renamed.
+type ErrorCode string
+
+const (
+	CodeValidation            ErrorCode = "validation_error"
+	CodeMalformedRequest      ErrorCode = "malformed_request"
+	CodePasswordPolicy        ErrorCode = "password_policy_violation"
+	CodeUnauthorized          ErrorCode = "unauthorized"
+	CodeMissingToken          ErrorCode = "missing_token"
+	CodeInvalidToken          ErrorCode = "invalid_token"
+	CodeTokenExpired          ErrorCode = "token_expired"
+	CodeSessionRevoked        ErrorCode = "session_revoked"
+	CodeInvalidCSRFToken      ErrorCode = "invalid_csrf_token"
+	CodeInvalidCredentials    ErrorCode = "invalid_credentials"
+	CodeUserDisabled          ErrorCode = "user_disabled"
+	CodeClientSuspended       ErrorCode = "client_suspended"
+	CodeNotFound              ErrorCode = "not_found"
+	CodeMethodNotAllowed      ErrorCode = "method_not_allowed"
+	CodeConflict              ErrorCode = "conflict"
+	CodeAlreadyExists         ErrorCode = "already_exists"
+	CodePreconditionFailed    ErrorCode = "precondition_failed"
+	CodePreconditionRequired  ErrorCode = "precondition_required"
+	CodeIdempotencyKeyReused  ErrorCode = "idempotency_key_reused"
+	CodeIdempotencyInProgress ErrorCode = "idempotency_request_in_progress"
+	CodeRateLimited           ErrorCode = "rate_limited"
+	CodeInternal              ErrorCode = "internal_error"
+)
+
+// ProblemType is an entry of the error catalog
+type ProblemType struct {
+	Code   ErrorCode `json:"code" example:"validation_error"`
+	Type   string    `json:"type" example:"/api/problems/validation_error"`
+	Title  string    `json:"title" example:"Validation failed"`
+	Status int       `json:"status" example:"400"`
+}
+
+var catalog = map[ErrorCode]ProblemType{}
+
+func register(code ErrorCode, status int, title string) {
+	catalog[code] = ProblemType{Code: code, Type: ProblemTypeBase + string(code), Title: title, Status: status}
+}
+
+func init() {
+	register(CodeValidation, http.StatusBadRequest, "Validation failed")
+	register(CodeMalformedRequest, http.StatusBadRequest, "Malformed request")
+	register(CodePasswordPolicy, http.StatusBadRequest, "Password does not satisfy the password policy")
+	register(CodeUnauthorized, http.StatusUnauthorized, "Unauthorized")
+	register(CodeMissingToken, http.StatusUnauthorized, "Missing bearer token")
+	register(CodeInvalidToken, http.StatusUnauthorized, "Invalid token")
+	register(CodeTokenExpired, http.StatusUnauthorized, "Token expired")
+	register(CodeSessionRevoked, http.StatusUnauthorized, "Session revoked or expired")
+	register(CodeInvalidCSRFToken, http.StatusForbidden, "Invalid CSRF token")
+	register(CodeInvalidCredentials, http.StatusUnauthorized, "Invalid credentials")
+	register(CodeUserDisabled, http.StatusUnauthorized, "User disabled")
+	register(CodeClientSuspended, http.StatusUnauthorized, "Client suspended")
+	register(CodeNotFound, http.StatusNotFound, "Resource not found")
+	register(CodeMethodNotAllowed, http.StatusMethodNotAllowed, "Method not allowed")
+	register(CodeConflict, http.StatusConflict, "Conflict")
+	register(CodeAlreadyExists, http.StatusConflict, "Resource already exists")
+	register(CodePreconditionFailed, http.StatusPreconditionFailed, "Precondition failed")
+	register(CodePreconditionRequired, http.StatusPreconditionRequired, "Precondition required")
+	register(CodeIdempotencyKeyReused, http.StatusUnprocessableEntity, "Idempotency key reused")
+	register(CodeIdempotencyInProgress, http.StatusConflict, "Request in progress")
+	register(CodeRateLimited, http.StatusTooManyRequests, "Too many requests")
+	register(CodeInternal, http.StatusInternalServerError, "Internal server error")
+}
+
+// Catalog lists every error code the API returns, sorted by code
+func Catalog() []ProblemType {
+	types := make([]ProblemType, 0, len(catalog))
+	for _, problemType := range catalog {
+		types = append(types, problemType)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].Code < types[j].Code })
+	return types
+}
+
+// LookupProblemType returns the catalog entry of a code
+func LookupProblemType(code ErrorCode) (ProblemType, bool) {
+	problemType, ok := catalog[code]
+	return problemType, ok
+}
+
+// Problem is the body of every error response (RFC 7807)
+type Problem struct {
+	Type   string `json:"type" example:"/api/problems/validation_error"`
+	Title  string `json:"title" example:"Validation failed"`
+	Status int    `json:"status" example:"400"`
+	// Detail explains this occurrence of the problem
+	Detail   string `json:"detail,omitempty" example:"The request has invalid fields"`
+	Instance string `json:"instance,omitempty" example:"/api/v2/users"`
+	// Code is the stable, machine-readable error code
+	Code ErrorCode `json:"code" example:"validation_error"`
+	// Errors lists the invalid fields or failed rules, if any
+	Errors []FieldError `json:"errors,omitempty"`
+	// RequestID matches the X-Request-ID response header and the server logs
+	RequestID string `json:"request_id,omitempty" example:"6f1c2a9e-3b7d-4c55-9a43-0d5f7e2b8c11"`
+
+	// detailArgs fill in Detail once it is translated
+	detailArgs []any
+}
+
+// FieldError is one reason a request was rejected
+type FieldError struct {
+	// Field is the JSON name of the invalid field, empty when the error
+	// concerns the request as a whole
+	Field   string `json:"field,omitempty" example:"email"`
+	Code    string `json:"code" example:"email"`
+	Message string `json:"message" example:"must be a valid email address"`
+
+	// args fill in Message once it is translated
+	args []any
+}
+
+// NewFieldError builds a field error whose message is a format translated
+// into the language of the response before args are applied
+func NewFieldError(field, code, format string, args ...any) FieldError {
+	return FieldError{Field: field, Code: code, Message: format, args: args}
+}
+
+// NewProblem builds the problem of a code for the request. The title and
+// detail are translated when the problem is sent, and detail is used as a
+// format when args are given.
+func NewProblem(c *gin.Context, code ErrorCode, detail string, args ...any) Problem {
+	problemType, ok := catalog[code]
+	if !ok {
+		problemType = catalog[CodeInternal]
+	}
+
+	return Problem{
+		Type:       problemType.Type,
+		Title:      problemType.Title,
+		Status:     problemType.Status,
+		Detail:     detail,
+		Instance:   c.Request.URL.Path,
+		Code:       code,
+		RequestID:  logging.RequestID(c.Request.Context()),
+		detailArgs: args,
+	}
+}
+
+// SendProblem aborts the request with a problem response in the language of
+// the request
+func SendProblem(c *gin.Context, problem Problem) {
+	locales := requestLocales(c)
+	problem.Title = locales.translate(problem.Title)
+	problem.Detail = locales.translate(problem.Detail, problem.detailArgs...)
+	for i, fieldError := range problem.Errors {
+		problem.Errors[i].Message = locales.translate(fieldError.Message, fieldError.args...)
+	}
+
+	c.Header("Content-Language", locales.language())
+	c.Writer.Header().Add("Vary", "Accept-Language")
+	// Gin keeps a content type that is already set
+	c.Header("Content-Type", ProblemContentType)
+	c.AbortWithStatusJSON(problem.Status, problem)
+}
+
+// SendError aborts the request with the problem of a code; detail is used as
+// a format when args are given
+func SendError(c *gin.Context, code ErrorCode, detail string, args ...any) {
+	SendProblem(c, NewProblem(c, code, detail, args...))
+}
+
+// SendErrorWithDetails sends a problem listing the invalid fields or failed
+// rules
+func SendErrorWithDetails(c *gin.Context, code ErrorCode, detail string, errors []FieldError) {
+	problem := NewProblem(c, code, detail)
+	problem.Errors = errors
+	SendProblem(c, problem)
+}
+
+func SendInternalError(c *gin.Context, message string) {
+	SendError(c, CodeInternal, message)
+}
+
+func SendUnauthorized(c *gin.Context, message string) {
+	SendError(c, CodeUnauthorized, message)
+}
+
+func SendNotFound(c *gin.Context, message string) {
+	SendError(c, CodeNotFound, message)
+}
+
+func SendConflict(c *gin.Context, message string) {
+	SendError(c, CodeConflict, message)
+}
+
+func SendAlreadyExistError(c *gin.Context, message string) {
+	SendError(c, CodeAlreadyExists, message)
+}
+
+func SendPasswordPolicyError(c *gin.Context, violations []FieldError) {
+	SendErrorWithDetails(c, CodePasswordPolicy, "The password was rejected by the password policy", violations)
+}
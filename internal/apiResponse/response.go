@@ -12,12 +12,6 @@ type APIResponse struct {
 	Status  bool   `json:"status" example:"true"`
 }
 
-// ErrorResponse represents error responses
-type ErrorResponse struct {
-	APIResponse
-	Error string `json:"error,omitempty" example:"validation_error"`
-}
-
 // SuccessResponse represents successful responses with data
 type SuccessResponse struct {
 	Data interface{} `json:"data"`
@@ -31,25 +25,14 @@ type PaginatedResponse struct {
 	APIResponse
 }
 
+// Pagination describes the page of a list. Page is omitted when the page was
+// fetched by cursor; NextCursor is omitted on the last page.
 type Pagination struct {
-	Page       int `json:"page" example:"1"`
-	Limit      int `json:"limit" example:"10"`
-	Total      int `json:"total" example:"100"`
-	TotalPages int `json:"totalPages" example:"10"`
-}
-
-// Constructor functions
-func NewErrorResponse(msg string, errorCode ...string) ErrorResponse {
-	response := ErrorResponse{
-		APIResponse: APIResponse{
-			Message: msg,
-			Status:  false,
-		},
-	}
-	if len(errorCode) > 0 {
-		response.Error = errorCode[0]
-	}
-	return response
+	Page       int    `json:"page,omitempty" example:"1"`
+	Limit      int    `json:"limit" example:"10"`
+	Total      int    `json:"total" example:"100"`
+	TotalPages int    `json:"total_pages" example:"10"`
+	NextCursor string `json:"next_cursor,omitempty" example:"eyJzIjoiaWQiLCJ2IjoxMCwiaWQiOjEwfQ"`
 }
 
 func NewSuccessResponse(data interface{}, msg string) SuccessResponse {
@@ -62,31 +45,21 @@ func NewSuccessResponse(data interface{}, msg string) SuccessResponse {
 	}
 }
 
-// Helper methods for common responses
-func SendError(c *gin.Context, statusCode int, message string, errorCode ...string) {
-	c.AbortWithStatusJSON(statusCode, NewErrorResponse(message, errorCode...))
-}
-
 func SendSuccess(c *gin.Context, statusCode int, data interface{}, message string) {
 	c.JSON(statusCode, NewSuccessResponse(data, message))
 }
 
-func SendValidationError(c *gin.Context, err error) {
-	SendError(c, http.StatusBadRequest, err.Error(), "validation_error")
-}
-
-func SendInternalError(c *gin.Context, message string) {
-	SendError(c, http.StatusInternalServerError, message, "internal_error")
-}
-
-func SendUnauthorized(c *gin.Context, message string) {
-	SendError(c, http.StatusUnauthorized, message, "unauthorized")
-}
-
-func SendConflict(c *gin.Context, message string) {
-	SendError(c, http.StatusConflict, message, "conflict")
+func NewPaginatedResponse(data interface{}, pagination Pagination, msg string) PaginatedResponse {
+	return PaginatedResponse{
+		Data:       data,
+		Pagination: pagination,
+		APIResponse: APIResponse{
+			Message: msg,
+			Status:  true,
+		},
+	}
 }
 
-func SendAlreadyExistError(c *gin.Context, message string) {
-	SendError(c, http.StatusConflict, message, "already_exists")
+func SendPaginated(c *gin.Context, data interface{}, pagination Pagination, message string) {
+	c.JSON(http.StatusOK, NewPaginatedResponse(data, pagination, message))
 }
@@ -0,0 +1,115 @@
+package apiresponse
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+func init() {
+	// Report fields by the name clients send them with rather than the Go
+	// field name
+	if validate, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		validate.RegisterTagNameFunc(fieldName)
+	}
+}
+
+// fieldName returns the JSON name of a field, or its query parameter name
+// for fields only bound from the query string
+func fieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form"} {
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+	return ""
+}
+
+// SendValidationError sends the problem of a request that could not be bound
+// or failed validation. Validator errors are translated into one FieldError
+// per invalid field, so their raw text never reaches the client.
+func SendValidationError(c *gin.Context, err error) {
+	var validationErrors validator.ValidationErrors
+	var syntaxError *json.SyntaxError
+	var typeError *json.UnmarshalTypeError
+	var numError *strconv.NumError
+
+	switch {
+	case errors.As(err, &validationErrors):
+		fieldErrors := make([]FieldError, len(validationErrors))
+		for i, fieldError := range validationErrors {
+			format, args := validationMessage(fieldError)
+			fieldErrors[i] = NewFieldError(fieldError.Field(), fieldError.Tag(), format, args...)
+		}
+		SendErrorWithDetails(c, CodeValidation, "The request has invalid fields", fieldErrors)
+	case errors.Is(err, io.EOF):
+		SendError(c, CodeMalformedRequest, "The request body is empty")
+	case errors.As(err, &syntaxError), errors.Is(err, io.ErrUnexpectedEOF):
+		SendError(c, CodeMalformedRequest, "The request body is not valid JSON")
+	case errors.As(err, &typeError):
+		SendErrorWithDetails(c, CodeValidation, "The request has invalid fields", []FieldError{
+			NewFieldError(typeError.Field, "type", "must be a "+jsonTypeName(typeError.Type)),
+		})
+	case errors.As(err, &numError):
+		SendError(c, CodeValidation, "%q is not a number", numError.Num)
+	default:
+		SendError(c, CodeValidation, err.Error())
+	}
+}
+
+// validationMessage describes a failed validation rule in plain words, as a
+// format and its args so it can be translated
+func validationMessage(fieldError validator.FieldError) (string, []any) {
+	param := fieldError.Param()
+	isString := fieldError.Kind() == reflect.String
+
+	switch fieldError.Tag() {
+	case "required":
+		return "is required", nil
+	case "min":
+		if isString {
+			return "must be at least %s characters long", []any{param}
+		}
+		return "must be at least %s", []any{param}
+	case "max":
+		if isString {
+			return "must be at most %s characters long", []any{param}
+		}
+		return "must be at most %s", []any{param}
+	case "email":
+		return "must be a valid email address", nil
+	case "url":
+		return "must be a valid URL", nil
+	case "startswith":
+		return "must start with %q", []any{param}
+	case "oneof":
+		return "must be one of: %s", []any{strings.ReplaceAll(param, " ", ", ")}
+	default:
+		return "is invalid", nil
+	}
+}
+
+func jsonTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Slice, reflect.Array:
+		return "list"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	case reflect.Pointer:
+		return jsonTypeName(t.Elem())
+	default:
+		return "number"
+	}
+}
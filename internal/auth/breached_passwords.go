@@ -0,0 +1,99 @@
+package auth
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// sha1PrefixLength matches the k-anonymity prefix length used by the
+// Have I Been Pwned range files
+const sha1PrefixLength = 5
+
+// BreachedPasswordList is an offline list of compromised passwords indexed by
+// the prefix of their SHA-1 hash
+type BreachedPasswordList struct {
+	ranges map[string]map[string]struct{}
+	count  int
+}
+
+// LoadBreachedPasswords reads a file of SHA-1 hashes, one per line. Lines may
+// use the "HASH:COUNT" format of the Have I Been Pwned downloads; blank lines
+// and lines starting with '#' are ignored.
+func LoadBreachedPasswords(path string) (*BreachedPasswordList, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	list := &BreachedPasswordList{ranges: make(map[string]map[string]struct{})}
+
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		hash, _, _ := strings.Cut(line, ":")
+		hash = strings.ToUpper(strings.TrimSpace(hash))
+		if len(hash) != sha1.Size*2 {
+			return nil, fmt.Errorf("%s:%d: invalid SHA-1 hash", path, lineNumber)
+		}
+		if _, err := hex.DecodeString(hash); err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid SHA-1 hash", path, lineNumber)
+		}
+
+		list.add(hash)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+func (l *BreachedPasswordList) add(hash string) {
+	prefix, suffix := hash[:sha1PrefixLength], hash[sha1PrefixLength:]
+	suffixes, ok := l.ranges[prefix]
+	if !ok {
+		suffixes = make(map[string]struct{})
+		l.ranges[prefix] = suffixes
+	}
+	if _, exists := suffixes[suffix]; !exists {
+		suffixes[suffix] = struct{}{}
+		l.count++
+	}
+}
+
+// Len returns the number of hashes in the list
+func (l *BreachedPasswordList) Len() int {
+	if l == nil {
+		return 0
+	}
+	return l.count
+}
+
+// Contains reports whether the password is in the list. A nil list contains nothing.
+func (l *BreachedPasswordList) Contains(password string) bool {
+	if l == nil {
+		return false
+	}
+
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	suffixes, ok := l.ranges[hash[:sha1PrefixLength]]
+	if !ok {
+		return false
+	}
+	_, found := suffixes[hash[sha1PrefixLength:]]
+	return found
+}
@@ -0,0 +1,107 @@
+package auth_test
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Kantha2004/SimpleJWT/internal/auth"
+)
+
+func sha1Hex(password string) string {
+	sum := sha1.Sum([]byte(password))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+// writeBreachedFile writes the lines to a new file and returns its path
+func writeBreachedFile(t *testing.T, lines ...string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0o600); err != nil {
+		t.Fatalf("writing %s: %v", path, err)
+	}
+	return path
+}
+
+func loadBreachedPasswords(t *testing.T, lines ...string) *auth.BreachedPasswordList {
+	t.Helper()
+
+	list, err := auth.LoadBreachedPasswords(writeBreachedFile(t, lines...))
+	if err != nil {
+		t.Fatalf("LoadBreachedPasswords: %v", err)
+	}
+	return list
+}
+
+func TestLoadBreachedPasswords(t *testing.T) {
+	password := sha1Hex("password")
+	// Shares the range of "password" with another suffix
+	neighbour := password[:5] + strings.Repeat("0", len(password)-5)
+
+	list := loadBreachedPasswords(t,
+		"# Have I Been Pwned download",
+		"",
+		password+":9545824",
+		strings.ToLower(sha1Hex("letmein"))+":10",
+		"  "+sha1Hex("123456")+"  ",
+		neighbour,
+		// Listed twice, counted once
+		password,
+	)
+
+	if got := list.Len(); got != 4 {
+		t.Errorf("Len = %d, want 4", got)
+	}
+
+	tests := []struct {
+		password string
+		want     bool
+	}{
+		{"password", true},
+		{"letmein", true},
+		{"123456", true},
+		{"Password", false},
+		{"correct horse battery staple", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := list.Contains(tt.password); got != tt.want {
+			t.Errorf("Contains(%q) = %v, want %v", tt.password, got, tt.want)
+		}
+	}
+}
+
+func TestLoadBreachedPasswordsErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  string
+	}{
+		{"short hash", []string{sha1Hex("a"), "ABCDEF"}, ":2: invalid SHA-1 hash"},
+		{"long hash", []string{sha1Hex("a") + "0"}, ":1: invalid SHA-1 hash"},
+		{"not hex", []string{"# comment", strings.Repeat("Z", 40)}, ":2: invalid SHA-1 hash"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeBreachedFile(t, tt.lines...)
+			if _, err := auth.LoadBreachedPasswords(path); err == nil || err.Error() != path+tt.want {
+				t.Fatalf("LoadBreachedPasswords = %v, want %s%s", err, path, tt.want)
+			}
+		})
+	}
+
+	if _, err := auth.LoadBreachedPasswords(filepath.Join(t.TempDir(), "missing.txt")); !os.IsNotExist(err) {
+		t.Fatalf("LoadBreachedPasswords of a missing file = %v, want a not-exist error", err)
+	}
+}
+
+func TestNilBreachedPasswordList(t *testing.T) {
+	var list *auth.BreachedPasswordList
+	if list.Len() != 0 || list.Contains("password") {
+		t.Fatal("a nil list is not empty")
+	}
+}
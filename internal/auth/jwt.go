@@ -2,51 +2,159 @@ package auth
 
 import (
 	"errors"
+	"fmt"
+	"sync/atomic"
 	"time"
 
+	"github.com/Kantha2004/SimpleJWT/internal/metrics"
+	"github.com/Kantha2004/SimpleJWT/internal/models"
+	"github.com/Kantha2004/SimpleJWT/pkg/verifier"
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// TokenTTL is the lifetime of issued tokens and of the sessions backing them
+const TokenTTL = time.Hour * 24
+
+// ErrUnknownSigningKey is returned for tokens whose "kid" names no loaded key
+var ErrUnknownSigningKey = errors.New("unknown signing key")
+
 type JWTService struct {
+	material atomic.Pointer[signingMaterial]
+}
+
+// signingMaterial is swapped as a whole on reload, so a token is never signed
+// or verified with a mix of old and new keys
+type signingMaterial struct {
 	secret []byte
+	keys   *KeySet
+	// issuer and audience are the "iss" and "aud" claims of new tokens;
+	// empty values are left out
+	issuer   string
+	audience string
+}
+
+// NewJWTService signs tokens with the active key of keys, or with the HS256
+// secret when keys is nil. With both configured the secret still verifies
+// tokens issued before the switch to signing keys. Tokens name issuer and
+// audience so resource servers can check who they are from and for.
+func NewJWTService(secret string, keys *KeySet, issuer, audience string) *JWTService {
+	j := &JWTService{}
+	j.Reload(secret, keys, issuer, audience)
+	return j
+}
+
+// Reload replaces the secret, signing keys, issuer and audience. Requests in
+// flight finish with the material they started with.
+func (j *JWTService) Reload(secret string, keys *KeySet, issuer, audience string) {
+	j.material.Store(&signingMaterial{secret: []byte(secret), keys: keys, issuer: issuer, audience: audience})
+}
+
+// Keys returns the signing keys, or nil when tokens are signed with the secret
+func (j *JWTService) Keys() *KeySet {
+	return j.material.Load().keys
+}
+
+// JSONWebKeySet returns the public keys verifying tokens, empty when tokens
+// are signed with the secret, which cannot be published
+func (j *JWTService) JSONWebKeySet() (verifier.JSONWebKeySet, error) {
+	set := verifier.JSONWebKeySet{Keys: []verifier.JSONWebKey{}}
+
+	keys := j.material.Load().keys
+	if keys == nil {
+		return set, nil
+	}
+	for _, key := range keys.Keys() {
+		jwk, err := verifier.NewJSONWebKey(key.ID, key.Algorithm, key.PublicKey())
+		if err != nil {
+			return verifier.JSONWebKeySet{}, fmt.Errorf("signing key %s: %w", key.ID, err)
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set, nil
 }
 
-func NewJWTService(secret string) *JWTService {
-	return &JWTService{
-		secret: []byte(secret),
+// SigningKeyID describes the key new tokens are signed with: the kid of the
+// active signing key, or HS256 when the secret is used. It returns an error
+// when neither is configured.
+func (j *JWTService) SigningKeyID() (string, error) {
+	material := j.material.Load()
+	if material.keys != nil {
+		return material.keys.Active().ID, nil
+	}
+	if len(material.secret) == 0 {
+		return "", errors.New("JWT secret is not configured")
+	}
+	return jwt.SigningMethodHS256.Alg(), nil
+}
+
+// CreateToken generates a new JWT token for an admin user session
+func (j *JWTService) CreateToken(userID uint, sessionID string) (string, error) {
+	return j.createToken(models.AuditActorAdminUser, jwt.MapClaims{
+		"user_id": userID,
+		"sid":     sessionID,
+	})
+}
+
+// CreateClientUserToken generates a new JWT token for a client user session.
+// The roles granted to the user go in the roles claim, left out when empty.
+func (j *JWTService) CreateClientUserToken(clientID, userID uint, sessionID string, roles []string) (string, error) {
+	claims := jwt.MapClaims{
+		"user_id":   userID,
+		"client_id": clientID,
+		"sid":       sessionID,
+	}
+	if len(roles) > 0 {
+		claims["roles"] = roles
 	}
+	return j.createToken(models.AuditActorClientUser, claims)
 }
 
-// CreateToken generates a new JWT token
-func (j *JWTService) CreateToken(userID uint) (string, error) {
+// createToken signs the claims and counts the token as issued to userType
+func (j *JWTService) createToken(userType string, claims jwt.MapClaims) (string, error) {
 	// Add nil checks
 	if j == nil {
 		return "", errors.New("JWT service is nil")
 	}
 
-	if len(j.secret) == 0 {
-		return "", errors.New("JWT secret is not configured")
+	material := j.material.Load()
+
+	now := time.Now()
+	claims["exp"] = now.Add(TokenTTL).Unix()
+	claims["iat"] = now.Unix()
+	if material.issuer != "" {
+		claims["iss"] = material.issuer
+	}
+	if material.audience != "" {
+		claims["aud"] = material.audience
 	}
 
-	// Create token
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id": userID,
-		"exp":     time.Now().Add(time.Hour * 24).Unix(),
-		"iat":     time.Now().Unix(),
-	})
+	var signed string
+	var err error
+	if material.keys != nil {
+		key := material.keys.Active()
+		token := jwt.NewWithClaims(jwt.GetSigningMethod(key.Algorithm), claims)
+		token.Header["kid"] = key.ID
+		signed, err = token.SignedString(key.PrivateKey)
+	} else {
+		if len(material.secret) == 0 {
+			return "", errors.New("JWT secret is not configured")
+		}
 
-	// Sign token
-	return token.SignedString(j.secret)
+		// Create and sign token
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		signed, err = token.SignedString(material.secret)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	metrics.TokensIssued.WithLabelValues(userType).Inc()
+	return signed, nil
 }
 
 func (j *JWTService) VerifyToken(tokenString string) (jwt.MapClaims, error) {
 
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, errors.New("invalid signing method")
-		}
-		return j.secret, nil
-	})
+	token, err := jwt.Parse(tokenString, j.material.Load().verificationKey)
 
 	if err != nil {
 		return nil, err
@@ -64,3 +172,31 @@ func (j *JWTService) VerifyToken(tokenString string) (jwt.MapClaims, error) {
 
 	return claims, nil
 }
+
+// verificationKey picks the key of a token: the signing key named by its
+// "kid" header, or the HS256 secret for tokens without one
+func (m *signingMaterial) verificationKey(token *jwt.Token) (interface{}, error) {
+	kid, hasKid := token.Header["kid"].(string)
+
+	if !hasKid {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok || len(m.secret) == 0 {
+			return nil, errors.New("invalid signing method")
+		}
+		return m.secret, nil
+	}
+
+	if m.keys == nil {
+		return nil, ErrUnknownSigningKey
+	}
+
+	key := m.keys.Get(kid)
+	if key == nil {
+		return nil, ErrUnknownSigningKey
+	}
+
+	if token.Method.Alg() != key.Algorithm {
+		return nil, errors.New("invalid signing method")
+	}
+
+	return key.PublicKey(), nil
+}
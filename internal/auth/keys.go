@@ -0,0 +1,207 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	signingKeyExtension = ".pem"
+	// signingKeyTimeFormat starts every key ID so IDs sort by creation time
+	signingKeyTimeFormat = "20060102T150405Z"
+)
+
+// SigningKey is an asymmetric key used to sign tokens. Its ID is sent as the
+// "kid" token header so verifiers can pick the right key after a rotation.
+type SigningKey struct {
+	ID         string
+	Algorithm  string
+	CreatedAt  time.Time
+	PrivateKey crypto.Signer
+}
+
+// PublicKey returns the key used to verify tokens signed with this key
+func (k *SigningKey) PublicKey() crypto.PublicKey {
+	return k.PrivateKey.Public()
+}
+
+// KeySet is the set of signing keys in a keys directory. The newest key
+// signs new tokens, older keys only verify tokens issued before a rotation.
+type KeySet struct {
+	keys []*SigningKey
+}
+
+// LoadSigningKeys reads every "<kid>.pem" PKCS#8 private key in dir
+func LoadSigningKeys(dir string) (*KeySet, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+signingKeyExtension))
+	if err != nil {
+		return nil, err
+	}
+
+	set := &KeySet{}
+	for _, path := range paths {
+		key, err := readSigningKey(path)
+		if err != nil {
+			return nil, err
+		}
+		set.keys = append(set.keys, key)
+	}
+
+	if len(set.keys) == 0 {
+		return nil, fmt.Errorf("no signing keys found in %s", dir)
+	}
+
+	sort.Slice(set.keys, func(i, j int) bool {
+		if !set.keys[i].CreatedAt.Equal(set.keys[j].CreatedAt) {
+			return set.keys[i].CreatedAt.Before(set.keys[j].CreatedAt)
+		}
+		return set.keys[i].ID < set.keys[j].ID
+	})
+	return set, nil
+}
+
+// Active returns the key that signs new tokens
+func (s *KeySet) Active() *SigningKey {
+	return s.keys[len(s.keys)-1]
+}
+
+// Get returns the key with the given ID, or nil
+func (s *KeySet) Get(id string) *SigningKey {
+	for _, key := range s.keys {
+		if key.ID == id {
+			return key
+		}
+	}
+	return nil
+}
+
+// Keys returns all keys, oldest first
+func (s *KeySet) Keys() []*SigningKey {
+	return s.keys
+}
+
+// GenerateSigningKey creates a new ECDSA P-256 key in dir. It becomes the
+// active key the next time the key set is loaded.
+func GenerateSigningKey(dir string) (*SigningKey, error) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	key := &SigningKey{
+		ID:         now.Format(signingKeyTimeFormat) + "-" + hex.EncodeToString(suffix),
+		Algorithm:  "ES256",
+		CreatedAt:  now.Truncate(time.Second),
+		PrivateKey: privateKey,
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(dir, key.ID+signingKeyExtension)
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if err := pem.Encode(file, &pem.Block{Type: "PRIVATE KEY", Bytes: der}); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// PruneSigningKeys deletes retired keys that can no longer verify any
+// unexpired token: keys whose successor was created more than TokenTTL ago.
+// It returns the IDs of the deleted keys.
+func PruneSigningKeys(dir string) ([]string, error) {
+	set, err := LoadSigningKeys(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var pruned []string
+	keys := set.Keys()
+	for i := 0; i < len(keys)-1; i++ {
+		retiredAt := keys[i+1].CreatedAt
+		if time.Since(retiredAt) <= TokenTTL {
+			break
+		}
+
+		if err := os.Remove(filepath.Join(dir, keys[i].ID+signingKeyExtension)); err != nil {
+			return pruned, err
+		}
+		pruned = append(pruned, keys[i].ID)
+	}
+
+	return pruned, nil
+}
+
+func readSigningKey(path string) (*SigningKey, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, fmt.Errorf("%s is not a PEM file", path)
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	key := &SigningKey{ID: strings.TrimSuffix(filepath.Base(path), signingKeyExtension)}
+
+	switch privateKey := parsed.(type) {
+	case *ecdsa.PrivateKey:
+		if privateKey.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("%s: only P-256 ECDSA keys are supported", path)
+		}
+		key.Algorithm = "ES256"
+		key.PrivateKey = privateKey
+	case *rsa.PrivateKey:
+		key.Algorithm = "RS256"
+		key.PrivateKey = privateKey
+	default:
+		return nil, errors.New(path + ": unsupported key type, use ECDSA P-256 or RSA")
+	}
+
+	// Generated key IDs start with their creation time, other keys fall back
+	// to the file modification time
+	if createdAt, err := time.Parse(signingKeyTimeFormat, strings.SplitN(key.ID, "-", 2)[0]); err == nil {
+		key.CreatedAt = createdAt
+	} else if info, err := os.Stat(path); err == nil {
+		key.CreatedAt = info.ModTime().UTC()
+	}
+
+	return key, nil
+}
@@ -1,9 +1,254 @@
 package auth
 
-import "golang.org/x/crypto/bcrypt"
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
 
-func HashPassword(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	"github.com/Kantha2004/SimpleJWT/internal/metrics"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Supported password hashing algorithms
+const (
+	AlgorithmArgon2id = "argon2id"
+	AlgorithmBcrypt   = "bcrypt"
+)
+
+// BcryptMaxPasswordBytes is the length of the longest password bcrypt hashes
+const BcryptMaxPasswordBytes = 72
+
+var ErrUnknownHashFormat = errors.New("unknown password hash format")
+
+// PasswordHasher produces self-describing password hashes in PHC string
+// format, so a hash can be verified without knowing how it was produced
+type PasswordHasher interface {
+	// Hash returns the encoded hash of the password
+	Hash(password string) (string, error)
+	// Verify reports whether the password matches the encoded hash
+	Verify(encodedHash, password string) (bool, error)
+	// NeedsRehash reports whether the encoded hash uses another algorithm or
+	// weaker parameters than the hasher is configured with
+	NeedsRehash(encodedHash string) bool
+	// MaxPasswordBytes is the length in bytes of the longest password the
+	// hasher can hash, 0 when there is no limit
+	MaxPasswordBytes() int
+}
+
+// Argon2Params are the tunable argon2id cost parameters
+type Argon2Params struct {
+	Memory      uint32 // in KiB
+	Time        uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultArgon2Params follows the OWASP recommendation for argon2id
+var DefaultArgon2Params = Argon2Params{
+	Memory:      64 * 1024,
+	Time:        3,
+	Parallelism: 2,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+// NewPasswordHasher returns the hasher for the named algorithm
+func NewPasswordHasher(algorithm string, argonParams Argon2Params, bcryptCost int) (PasswordHasher, error) {
+	switch algorithm {
+	case AlgorithmArgon2id:
+		return NewArgon2idHasher(argonParams), nil
+	case AlgorithmBcrypt:
+		return NewBcryptHasher(bcryptCost)
+	default:
+		return nil, fmt.Errorf("unsupported password hashing algorithm %q", algorithm)
+	}
+}
+
+// ReloadablePasswordHasher hashes with a hasher that is swapped on
+// configuration reloads. Hashes made before a reload still pass
+// CheckPassword, and NeedsRehash reports them so logins upgrade them.
+type ReloadablePasswordHasher struct {
+	current atomic.Pointer[hasherRef]
+}
+
+type hasherRef struct {
+	PasswordHasher
+}
+
+func NewReloadablePasswordHasher(hasher PasswordHasher) *ReloadablePasswordHasher {
+	h := &ReloadablePasswordHasher{}
+	h.Reload(hasher)
+	return h
+}
+
+// Reload replaces the hasher
+func (h *ReloadablePasswordHasher) Reload(hasher PasswordHasher) {
+	h.current.Store(&hasherRef{hasher})
+}
+
+func (h *ReloadablePasswordHasher) Hash(password string) (string, error) {
+	return h.current.Load().Hash(password)
+}
+
+func (h *ReloadablePasswordHasher) Verify(encodedHash, password string) (bool, error) {
+	return h.current.Load().Verify(encodedHash, password)
+}
+
+func (h *ReloadablePasswordHasher) NeedsRehash(encodedHash string) bool {
+	return h.current.Load().NeedsRehash(encodedHash)
+}
+
+func (h *ReloadablePasswordHasher) MaxPasswordBytes() int {
+	return h.current.Load().MaxPasswordBytes()
+}
+
+// CheckPassword verifies a password against a hash produced by any of the
+// supported algorithms
+func CheckPassword(hashedPassword, password string) bool {
+	hasher, err := hasherForHash(hashedPassword)
+	if err != nil {
+		return false
+	}
+	ok, err := hasher.Verify(hashedPassword, password)
+	return err == nil && ok
+}
+
+func hasherForHash(encodedHash string) (PasswordHasher, error) {
+	switch {
+	case strings.HasPrefix(encodedHash, "$argon2id$"):
+		return NewArgon2idHasher(DefaultArgon2Params), nil
+	case isBcryptHash(encodedHash):
+		return &BcryptHasher{cost: bcrypt.DefaultCost}, nil
+	default:
+		return nil, ErrUnknownHashFormat
+	}
+}
+
+// Argon2idHasher hashes passwords with argon2id
+type Argon2idHasher struct {
+	params Argon2Params
+}
+
+func NewArgon2idHasher(params Argon2Params) *Argon2idHasher {
+	if params.SaltLength == 0 {
+		params.SaltLength = DefaultArgon2Params.SaltLength
+	}
+	if params.KeyLength == 0 {
+		params.KeyLength = DefaultArgon2Params.KeyLength
+	}
+	return &Argon2idHasher{params: params}
+}
+
+func (h *Argon2idHasher) Hash(password string) (string, error) {
+	defer metrics.PasswordHashTimer(AlgorithmArgon2id, "hash").ObserveDuration()
+
+	salt := make([]byte, h.params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.params.Time, h.params.Memory, h.params.Parallelism, h.params.KeyLength)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		h.params.Memory,
+		h.params.Time,
+		h.params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func (h *Argon2idHasher) Verify(encodedHash, password string) (bool, error) {
+	defer metrics.PasswordHashTimer(AlgorithmArgon2id, "verify").ObserveDuration()
+
+	params, salt, key, err := decodeArgon2idHash(encodedHash)
+	if err != nil {
+		return false, err
+	}
+
+	otherKey := argon2.IDKey([]byte(password), salt, params.Time, params.Memory, params.Parallelism, params.KeyLength)
+
+	return subtle.ConstantTimeCompare(key, otherKey) == 1, nil
+}
+
+func (h *Argon2idHasher) NeedsRehash(encodedHash string) bool {
+	params, salt, _, err := decodeArgon2idHash(encodedHash)
+	if err != nil {
+		return true
+	}
+
+	return params.Memory < h.params.Memory ||
+		params.Time < h.params.Time ||
+		params.Parallelism < h.params.Parallelism ||
+		params.KeyLength < h.params.KeyLength ||
+		uint32(len(salt)) < h.params.SaltLength
+}
+
+func (h *Argon2idHasher) MaxPasswordBytes() int {
+	return 0
+}
+
+// decodeArgon2idHash parses a "$argon2id$v=19$m=..,t=..,p=..$salt$key" string
+func decodeArgon2idHash(encodedHash string) (Argon2Params, []byte, []byte, error) {
+	var params Argon2Params
+
+	parts := strings.Split(encodedHash, "$")
+	if len(parts) != 6 || parts[1] != AlgorithmArgon2id {
+		return params, nil, nil, ErrUnknownHashFormat
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return params, nil, nil, ErrUnknownHashFormat
+	}
+	if version != argon2.Version {
+		return params, nil, nil, fmt.Errorf("unsupported argon2 version %d", version)
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Time, &params.Parallelism); err != nil {
+		return params, nil, nil, ErrUnknownHashFormat
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, ErrUnknownHashFormat
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return params, nil, nil, ErrUnknownHashFormat
+	}
+
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+
+	return params, salt, key, nil
+}
+
+// BcryptHasher hashes passwords with bcrypt. Its "$2a$10$..." modular crypt
+// output is already self-describing.
+type BcryptHasher struct {
+	cost int
+}
+
+func NewBcryptHasher(cost int) (*BcryptHasher, error) {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		return nil, fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+	}
+	return &BcryptHasher{cost: cost}, nil
+}
+
+func (h *BcryptHasher) Hash(password string) (string, error) {
+	defer metrics.PasswordHashTimer(AlgorithmBcrypt, "hash").ObserveDuration()
+
+	bytes, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
 
 	if err != nil {
 		return "", err
@@ -11,7 +256,30 @@ func HashPassword(password string) (string, error) {
 	return string(bytes), nil
 }
 
-func CheckPassword(hashedPassword, password string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
-	return err == nil
+func (h *BcryptHasher) Verify(encodedHash, password string) (bool, error) {
+	defer metrics.PasswordHashTimer(AlgorithmBcrypt, "verify").ObserveDuration()
+
+	err := bcrypt.CompareHashAndPassword([]byte(encodedHash), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (h *BcryptHasher) NeedsRehash(encodedHash string) bool {
+	if !isBcryptHash(encodedHash) {
+		return true
+	}
+	cost, err := bcrypt.Cost([]byte(encodedHash))
+	return err != nil || cost < h.cost
+}
+
+func (h *BcryptHasher) MaxPasswordBytes() int {
+	return BcryptMaxPasswordBytes
+}
+
+func isBcryptHash(encodedHash string) bool {
+	return strings.HasPrefix(encodedHash, "$2a$") ||
+		strings.HasPrefix(encodedHash, "$2b$") ||
+		strings.HasPrefix(encodedHash, "$2y$")
 }
@@ -0,0 +1,216 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Kantha2004/SimpleJWT/internal/models"
+)
+
+// Password policy rule identifiers reported in PolicyViolation.Rule
+const (
+	RuleMinLength        = "min_length"
+	RuleMaxLength        = "max_length"
+	RuleUppercase        = "require_uppercase"
+	RuleLowercase        = "require_lowercase"
+	RuleDigit            = "require_digit"
+	RuleSymbol           = "require_symbol"
+	RuleMaxRepeatedChars = "max_repeated_chars"
+	RuleUserInfo         = "disallow_user_info"
+	RuleHistory          = "history"
+	RuleBreached         = "breached"
+)
+
+// minUserInfoLength is the shortest username/email part checked as a substring
+const minUserInfoLength = 3
+
+// PolicyViolation describes a single failed password rule
+type PolicyViolation struct {
+	Rule    string `json:"rule" example:"min_length"`
+	Message string `json:"message" example:"password must be at least 8 characters long"`
+
+	// Format and Args build Message, so it can be translated
+	Format string `json:"-"`
+	Args   []any  `json:"-"`
+}
+
+// PasswordContext carries the user data a password is checked against
+type PasswordContext struct {
+	Username string
+	Email    string
+	// PreviousHashes are the user's most recent password hashes, newest first
+	PreviousHashes []string
+}
+
+type PasswordValidator struct {
+	rules atomic.Pointer[passwordRules]
+}
+
+type passwordRules struct {
+	policy   models.PasswordPolicy
+	breached *BreachedPasswordList
+}
+
+func NewPasswordValidator(policy models.PasswordPolicy, breached *BreachedPasswordList) *PasswordValidator {
+	v := &PasswordValidator{}
+	v.Reload(policy, breached)
+	return v
+}
+
+// Reload replaces the global policy and the breached passwords list
+func (v *PasswordValidator) Reload(policy models.PasswordPolicy, breached *BreachedPasswordList) {
+	v.rules.Store(&passwordRules{policy: policy, breached: breached})
+}
+
+// Policy returns the global password policy
+func (v *PasswordValidator) Policy() models.PasswordPolicy {
+	return v.rules.Load().policy
+}
+
+// PolicyForClient merges the password policy overrides stored in a client's
+// settings on top of the global policy
+func (v *PasswordValidator) PolicyForClient(settings *models.ClientSettings) (models.PasswordPolicy, error) {
+	policy := v.Policy()
+	if settings == nil || len(settings.PasswordPolicy) == 0 {
+		return policy, nil
+	}
+
+	if err := json.Unmarshal(settings.PasswordPolicy, &policy); err != nil {
+		return v.Policy(), fmt.Errorf("invalid client password policy: %w", err)
+	}
+
+	return policy, nil
+}
+
+// Validate checks the password against every rule of the policy and returns
+// all the rules it fails. An empty result means the password is acceptable.
+func (v *PasswordValidator) Validate(policy models.PasswordPolicy, password string, ctx PasswordContext) []PolicyViolation {
+	var violations []PolicyViolation
+	add := func(rule, format string, args ...any) {
+		violations = append(violations, PolicyViolation{
+			Rule:    rule,
+			Message: fmt.Sprintf(format, args...),
+			Format:  format,
+			Args:    args,
+		})
+	}
+
+	length := utf8.RuneCountInString(password)
+	if policy.MinLength > 0 && length < policy.MinLength {
+		add(RuleMinLength, "password must be at least %d characters long", policy.MinLength)
+	}
+	if policy.MaxLength > 0 && length > policy.MaxLength {
+		add(RuleMaxLength, "password must be at most %d characters long", policy.MaxLength)
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+
+	if policy.RequireUppercase && !hasUpper {
+		add(RuleUppercase, "password must contain an uppercase letter")
+	}
+	if policy.RequireLowercase && !hasLower {
+		add(RuleLowercase, "password must contain a lowercase letter")
+	}
+	if policy.RequireDigit && !hasDigit {
+		add(RuleDigit, "password must contain a digit")
+	}
+	if policy.RequireSymbol && !hasSymbol {
+		add(RuleSymbol, "password must contain a symbol")
+	}
+
+	if policy.MaxRepeatedChars > 0 && longestRun(password) > policy.MaxRepeatedChars {
+		add(RuleMaxRepeatedChars, "password must not repeat the same character more than %d times in a row", policy.MaxRepeatedChars)
+	}
+
+	if policy.DisallowUserInfo && containsUserInfo(password, ctx) {
+		add(RuleUserInfo, "password must not contain the username or email")
+	}
+
+	if policy.HistorySize > 0 {
+		history := ctx.PreviousHashes
+		if len(history) > policy.HistorySize {
+			history = history[:policy.HistorySize]
+		}
+		for _, hash := range history {
+			if CheckPassword(hash, password) {
+				add(RuleHistory, "password must not match any of the last %d passwords", policy.HistorySize)
+				break
+			}
+		}
+	}
+
+	if policy.CheckBreached && v.rules.Load().breached.Contains(password) {
+		add(RuleBreached, "password has appeared in a data breach and cannot be used")
+	}
+
+	return violations
+}
+
+// HasherLimitViolation reports a password longer than the hasher can hash.
+// The max_length rule counts characters, so it cannot rule out on its own a
+// password of multibyte characters too long for bcrypt.
+func HasherLimitViolation(hasher PasswordHasher, password string) (PolicyViolation, bool) {
+	limit := hasher.MaxPasswordBytes()
+	if limit == 0 || len(password) <= limit {
+		return PolicyViolation{}, false
+	}
+
+	format := "password must be at most %d bytes long"
+	return PolicyViolation{
+		Rule:    RuleMaxLength,
+		Message: fmt.Sprintf(format, limit),
+		Format:  format,
+		Args:    []any{limit},
+	}, true
+}
+
+// longestRun returns the length of the longest run of the same character
+func longestRun(password string) int {
+	longest, current := 0, 0
+	var prev rune
+	for i, r := range password {
+		if i > 0 && r == prev {
+			current++
+		} else {
+			current = 1
+		}
+		prev = r
+		longest = max(longest, current)
+	}
+	return longest
+}
+
+// containsUserInfo reports whether the password contains the username, the
+// email address or the local part of the email, ignoring case
+func containsUserInfo(password string, ctx PasswordContext) bool {
+	lower := strings.ToLower(password)
+
+	candidates := []string{ctx.Username, ctx.Email}
+	if local, _, found := strings.Cut(ctx.Email, "@"); found {
+		candidates = append(candidates, local)
+	}
+
+	for _, candidate := range candidates {
+		candidate = strings.ToLower(strings.TrimSpace(candidate))
+		if utf8.RuneCountInString(candidate) >= minUserInfoLength && strings.Contains(lower, candidate) {
+			return true
+		}
+	}
+	return false
+}
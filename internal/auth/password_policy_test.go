@@ -0,0 +1,144 @@
+package auth_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Kantha2004/SimpleJWT/internal/auth"
+	"github.com/Kantha2004/SimpleJWT/internal/models"
+)
+
+func TestValidate(t *testing.T) {
+	// The lowest cost keeps hashing the history fast
+	hasher, err := auth.NewBcryptHasher(4)
+	if err != nil {
+		t.Fatalf("NewBcryptHasher: %v", err)
+	}
+	history := make([]string, 2)
+	for i, password := range []string{"newest-pw", "oldest-pw"} {
+		if history[i], err = hasher.Hash(password); err != nil {
+			t.Fatalf("Hash: %v", err)
+		}
+	}
+
+	breached := loadBreachedPasswords(t, sha1Hex("password1"))
+	validator := auth.NewPasswordValidator(models.PasswordPolicy{}, breached)
+	user := auth.PasswordContext{Username: "alice", Email: "a.smith@example.com", PreviousHashes: history}
+
+	tests := []struct {
+		name     string
+		policy   models.PasswordPolicy
+		password string
+		want     []string
+	}{
+		{"no rules", models.PasswordPolicy{}, "", nil},
+
+		{"one below min length", models.PasswordPolicy{MinLength: 8}, "1234567", []string{auth.RuleMinLength}},
+		{"at min length", models.PasswordPolicy{MinLength: 8}, "12345678", nil},
+		{"min length counts characters", models.PasswordPolicy{MinLength: 8}, "ééééééé", []string{auth.RuleMinLength}},
+		{"at max length", models.PasswordPolicy{MaxLength: 8}, "12345678", nil},
+		{"one above max length", models.PasswordPolicy{MaxLength: 8}, "123456789", []string{auth.RuleMaxLength}},
+		{"max length counts characters", models.PasswordPolicy{MaxLength: 8}, "éééééééé", nil},
+
+		{"missing uppercase", models.PasswordPolicy{RequireUppercase: true}, "abc", []string{auth.RuleUppercase}},
+		{"uppercase", models.PasswordPolicy{RequireUppercase: true}, "aBc", nil},
+		{"non-ASCII uppercase", models.PasswordPolicy{RequireUppercase: true}, "Éa", nil},
+		{"missing lowercase", models.PasswordPolicy{RequireLowercase: true}, "ABC", []string{auth.RuleLowercase}},
+		{"lowercase", models.PasswordPolicy{RequireLowercase: true}, "AbC", nil},
+		{"missing digit", models.PasswordPolicy{RequireDigit: true}, "abc", []string{auth.RuleDigit}},
+		{"digit", models.PasswordPolicy{RequireDigit: true}, "ab1", nil},
+		{"missing symbol", models.PasswordPolicy{RequireSymbol: true}, "abc1", []string{auth.RuleSymbol}},
+		{"punctuation is a symbol", models.PasswordPolicy{RequireSymbol: true}, "abc!", nil},
+		{"space is a symbol", models.PasswordPolicy{RequireSymbol: true}, "ab c", nil},
+
+		{"run at the limit", models.PasswordPolicy{MaxRepeatedChars: 3}, "abbbc", nil},
+		{"run over the limit", models.PasswordPolicy{MaxRepeatedChars: 3}, "abbbbc", []string{auth.RuleMaxRepeatedChars}},
+		{"run at the end", models.PasswordPolicy{MaxRepeatedChars: 3}, "abcdddd", []string{auth.RuleMaxRepeatedChars}},
+		{"run of multibyte characters", models.PasswordPolicy{MaxRepeatedChars: 2}, "aééé", []string{auth.RuleMaxRepeatedChars}},
+		{"separate runs", models.PasswordPolicy{MaxRepeatedChars: 2}, "aabaab", nil},
+
+		{"contains username", models.PasswordPolicy{DisallowUserInfo: true}, "my-ALICE-pw", []string{auth.RuleUserInfo}},
+		{"contains email", models.PasswordPolicy{DisallowUserInfo: true}, "x a.smith@example.com", []string{auth.RuleUserInfo}},
+		{"contains local part of email", models.PasswordPolicy{DisallowUserInfo: true}, "A.Smith99", []string{auth.RuleUserInfo}},
+		{"no user info", models.PasswordPolicy{DisallowUserInfo: true}, "correct horse", nil},
+
+		{"matches newest password", models.PasswordPolicy{HistorySize: 1}, "newest-pw", []string{auth.RuleHistory}},
+		{"matches password beyond history size", models.PasswordPolicy{HistorySize: 1}, "oldest-pw", nil},
+		{"matches oldest password", models.PasswordPolicy{HistorySize: 2}, "oldest-pw", []string{auth.RuleHistory}},
+
+		{"breached", models.PasswordPolicy{CheckBreached: true}, "password1", []string{auth.RuleBreached}},
+		{"not breached", models.PasswordPolicy{CheckBreached: true}, "password2", nil},
+
+		{
+			"every failing rule is reported",
+			models.PasswordPolicy{MinLength: 12, RequireUppercase: true, RequireDigit: true, RequireSymbol: true, MaxRepeatedChars: 2, DisallowUserInfo: true},
+			"aliceee",
+			[]string{auth.RuleMinLength, auth.RuleUppercase, auth.RuleDigit, auth.RuleSymbol, auth.RuleMaxRepeatedChars, auth.RuleUserInfo},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := validator.Validate(tt.policy, tt.password, user)
+
+			var got []string
+			for _, violation := range violations {
+				got = append(got, violation.Rule)
+				if violation.Message == "" {
+					t.Errorf("violation of %s has no message", violation.Rule)
+				}
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("Validate(%q) failed rules %v, want %v", tt.password, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateShortUserInfo(t *testing.T) {
+	validator := auth.NewPasswordValidator(models.PasswordPolicy{}, nil)
+	policy := models.PasswordPolicy{DisallowUserInfo: true}
+
+	// Usernames and local parts shorter than three characters would match
+	// too many passwords to be checked
+	user := auth.PasswordContext{Username: "al", Email: "al@x.io"}
+	if violations := validator.Validate(policy, "alibi-pw", user); len(violations) != 0 {
+		t.Fatalf("Validate with a short username = %+v, want none", violations)
+	}
+	user.Username = "ali"
+	if violations := validator.Validate(policy, "alibi-pw", user); len(violations) != 1 || violations[0].Rule != auth.RuleUserInfo {
+		t.Fatalf("Validate with a three character username = %+v, want %s", violations, auth.RuleUserInfo)
+	}
+}
+
+func TestHasherLimitViolation(t *testing.T) {
+	bcryptHasher, err := auth.NewBcryptHasher(4)
+	if err != nil {
+		t.Fatalf("NewBcryptHasher: %v", err)
+	}
+	argon2Hasher := auth.NewArgon2idHasher(auth.DefaultArgon2Params)
+
+	tests := []struct {
+		name     string
+		hasher   auth.PasswordHasher
+		password string
+		want     bool
+	}{
+		{"bcrypt at the limit", bcryptHasher, strings.Repeat("a", auth.BcryptMaxPasswordBytes), false},
+		{"bcrypt over the limit", bcryptHasher, strings.Repeat("a", auth.BcryptMaxPasswordBytes+1), true},
+		// 37 two-byte characters are 74 bytes, short enough for max_length
+		{"bcrypt counts bytes", bcryptHasher, strings.Repeat("é", 37), true},
+		{"argon2id has no limit", argon2Hasher, strings.Repeat("a", 1000), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violation, got := auth.HasherLimitViolation(tt.hasher, tt.password)
+			if got != tt.want {
+				t.Fatalf("HasherLimitViolation = %v, want %v", got, tt.want)
+			}
+			if got && (violation.Rule != auth.RuleMaxLength || violation.Message != "password must be at most 72 bytes long") {
+				t.Fatalf("got violation %+v", violation)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package auth_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Kantha2004/SimpleJWT/internal/auth"
+)
+
+// testArgon2Params keep hashing fast; hashes record their parameters, so
+// they verify the same as with the defaults
+var testArgon2Params = auth.Argon2Params{Memory: 1024, Time: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+func newBcryptHasher(t *testing.T, cost int) *auth.BcryptHasher {
+	t.Helper()
+
+	hasher, err := auth.NewBcryptHasher(cost)
+	if err != nil {
+		t.Fatalf("NewBcryptHasher(%d): %v", cost, err)
+	}
+	return hasher
+}
+
+func hash(t *testing.T, hasher auth.PasswordHasher, password string) string {
+	t.Helper()
+
+	encoded, err := hasher.Hash(password)
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	return encoded
+}
+
+func TestArgon2idRoundTrip(t *testing.T) {
+	hasher := auth.NewArgon2idHasher(testArgon2Params)
+
+	encoded := hash(t, hasher, "Passw0rd!")
+	if !strings.HasPrefix(encoded, "$argon2id$v=19$m=1024,t=1,p=1$") {
+		t.Fatalf("Hash = %q, want the PHC string of the parameters", encoded)
+	}
+	if parts := strings.Split(encoded, "$"); len(parts) != 6 || len(parts[4]) != 22 || len(parts[5]) != 43 {
+		t.Fatalf("Hash = %q, want a 16 byte salt and a 32 byte key in unpadded base64", encoded)
+	}
+	if again := hash(t, hasher, "Passw0rd!"); again == encoded {
+		t.Fatal("two hashes of the same password share a salt")
+	}
+
+	if ok, err := hasher.Verify(encoded, "Passw0rd!"); err != nil || !ok {
+		t.Fatalf("Verify of the password = %v, %v, want true", ok, err)
+	}
+	if ok, err := hasher.Verify(encoded, "Passw0rd?"); err != nil || ok {
+		t.Fatalf("Verify of another password = %v, %v, want false", ok, err)
+	}
+	// The parameters are read from the hash
+	if ok, err := auth.NewArgon2idHasher(auth.DefaultArgon2Params).Verify(encoded, "Passw0rd!"); err != nil || !ok {
+		t.Fatalf("Verify with other parameters = %v, %v, want true", ok, err)
+	}
+	if hasher.NeedsRehash(encoded) {
+		t.Fatal("NeedsRehash of a hash made with the same parameters")
+	}
+}
+
+func TestArgon2idRejectsMalformedHashes(t *testing.T) {
+	hasher := auth.NewArgon2idHasher(testArgon2Params)
+	parts := strings.Split(hash(t, hasher, "Passw0rd!"), "$")
+	with := func(i int, value string) string {
+		changed := append([]string(nil), parts...)
+		changed[i] = value
+		return strings.Join(changed, "$")
+	}
+
+	tests := []struct {
+		name    string
+		encoded string
+	}{
+		{"empty", ""},
+		{"missing key", strings.Join(parts[:5], "$")},
+		{"extra field", strings.Join(append(parts, "extra"), "$")},
+		{"other algorithm", with(1, "argon2i")},
+		{"missing version", with(2, "19")},
+		{"other version", with(2, "v=16")},
+		{"missing parameters", with(3, "m=1024")},
+		{"non-numeric parameters", with(3, "m=x,t=1,p=1")},
+		{"salt not base64", with(4, "not base64!")},
+		{"key not base64", with(5, "not base64!")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if ok, err := hasher.Verify(tt.encoded, "Passw0rd!"); err == nil || ok {
+				t.Fatalf("Verify(%q) = %v, %v, want an error", tt.encoded, ok, err)
+			}
+			if !hasher.NeedsRehash(tt.encoded) {
+				t.Fatalf("NeedsRehash(%q) = false, want true", tt.encoded)
+			}
+			if auth.CheckPassword(tt.encoded, "Passw0rd!") {
+				t.Fatalf("CheckPassword(%q) accepted the password", tt.encoded)
+			}
+		})
+	}
+
+	if _, err := hasher.Verify("", "Passw0rd!"); !errors.Is(err, auth.ErrUnknownHashFormat) {
+		t.Fatalf("Verify of an empty hash = %v, want %v", err, auth.ErrUnknownHashFormat)
+	}
+}
+
+func TestNeedsRehash(t *testing.T) {
+	argon2Hash := hash(t, auth.NewArgon2idHasher(testArgon2Params), "Passw0rd!")
+	bcryptHash := hash(t, newBcryptHasher(t, 5), "Passw0rd!")
+
+	stronger := func(change func(*auth.Argon2Params)) auth.PasswordHasher {
+		params := testArgon2Params
+		change(&params)
+		return auth.NewArgon2idHasher(params)
+	}
+
+	tests := []struct {
+		name    string
+		hasher  auth.PasswordHasher
+		encoded string
+		want    bool
+	}{
+		{"argon2id with the same parameters", auth.NewArgon2idHasher(testArgon2Params), argon2Hash, false},
+		{"argon2id with more memory", stronger(func(p *auth.Argon2Params) { p.Memory *= 2 }), argon2Hash, true},
+		{"argon2id with more iterations", stronger(func(p *auth.Argon2Params) { p.Time++ }), argon2Hash, true},
+		{"argon2id with more parallelism", stronger(func(p *auth.Argon2Params) { p.Parallelism++ }), argon2Hash, true},
+		{"argon2id with a longer salt", stronger(func(p *auth.Argon2Params) { p.SaltLength = 32 }), argon2Hash, true},
+		{"argon2id with a longer key", stronger(func(p *auth.Argon2Params) { p.KeyLength = 64 }), argon2Hash, true},
+		{"argon2id with less memory", stronger(func(p *auth.Argon2Params) { p.Memory /= 2 }), argon2Hash, false},
+		{"argon2id hasher and a bcrypt hash", auth.NewArgon2idHasher(testArgon2Params), bcryptHash, true},
+
+		{"bcrypt with the same cost", newBcryptHasher(t, 5), bcryptHash, false},
+		{"bcrypt with a higher cost", newBcryptHasher(t, 6), bcryptHash, true},
+		{"bcrypt with a lower cost", newBcryptHasher(t, 4), bcryptHash, false},
+		{"bcrypt hasher and an argon2id hash", newBcryptHasher(t, 5), argon2Hash, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.hasher.NeedsRehash(tt.encoded); got != tt.want {
+				t.Fatalf("NeedsRehash = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckPassword(t *testing.T) {
+	bcryptHash := hash(t, newBcryptHasher(t, 4), "Passw0rd!")
+
+	tests := []struct {
+		name     string
+		encoded  string
+		password string
+		want     bool
+	}{
+		{"argon2id", hash(t, auth.NewArgon2idHasher(testArgon2Params), "Passw0rd!"), "Passw0rd!", true},
+		{"argon2id and another password", hash(t, auth.NewArgon2idHasher(testArgon2Params), "Passw0rd!"), "passw0rd!", false},
+		{"bcrypt", bcryptHash, "Passw0rd!", true},
+		{"bcrypt and another password", bcryptHash, "passw0rd!", false},
+		// The 2b and 2y prefixes of other bcrypt implementations
+		{"bcrypt with the 2b prefix", "$2b$" + strings.TrimPrefix(bcryptHash, "$2a$"), "Passw0rd!", true},
+		{"bcrypt with the 2y prefix", "$2y$" + strings.TrimPrefix(bcryptHash, "$2a$"), "Passw0rd!", true},
+		{"unknown prefix", "$1$" + strings.TrimPrefix(bcryptHash, "$2a$"), "Passw0rd!", false},
+		{"plain text", "Passw0rd!", "Passw0rd!", false},
+		{"empty hash", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := auth.CheckPassword(tt.encoded, tt.password); got != tt.want {
+				t.Fatalf("CheckPassword(%q, %q) = %v, want %v", tt.encoded, tt.password, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReloadablePasswordHasher(t *testing.T) {
+	hasher := auth.NewReloadablePasswordHasher(newBcryptHasher(t, 4))
+	bcryptHash := hash(t, hasher, "Passw0rd!")
+	if hasher.MaxPasswordBytes() != auth.BcryptMaxPasswordBytes {
+		t.Fatalf("MaxPasswordBytes = %d, want %d", hasher.MaxPasswordBytes(), auth.BcryptMaxPasswordBytes)
+	}
+
+	hasher.Reload(auth.NewArgon2idHasher(testArgon2Params))
+	if !strings.HasPrefix(hash(t, hasher, "Passw0rd!"), "$argon2id$") {
+		t.Fatal("Hash after reloading does not use argon2id")
+	}
+	// Hashes made before the reload still verify and are upgraded on login
+	if !auth.CheckPassword(bcryptHash, "Passw0rd!") || !hasher.NeedsRehash(bcryptHash) {
+		t.Fatal("a bcrypt hash made before the reload does not verify or need a rehash")
+	}
+	if hasher.MaxPasswordBytes() != 0 {
+		t.Fatalf("MaxPasswordBytes after reloading = %d, want 0", hasher.MaxPasswordBytes())
+	}
+}
@@ -1,9 +1,20 @@
+// Package config loads the settings of the server and simplejwtctl. Every
+// setting has a default and can be set, in increasing precedence, in a YAML
+// or TOML config file, in the environment and with a command-line flag.
 package config
 
 import (
+	"fmt"
+	"io"
 	"log"
+	"log/slog"
 	"os"
 	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Kantha2004/SimpleJWT/internal/models"
+	"gopkg.in/yaml.v3"
 )
 
 type Environment string
@@ -13,60 +24,349 @@ const (
 	Production  Environment = "production"
 )
 
+// Supported database drivers
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
 type DBConfig struct {
+	// Driver selects the storage backend: postgres or sqlite
+	Driver string
+	// SQLitePath is the database file used by the sqlite driver
+	SQLitePath string
 	DBName     string
 	DBPassword string
 	DBHost     string
 	DBPort     int
 	DBUser     string
 	SSLMode    string
+	// MigrateTenantsOnStartup upgrades every client schema when the server starts
+	MigrateTenantsOnStartup bool
+}
+
+// PostgresDSN returns the keyword/value connection string of the PostgreSQL
+// settings. Values are quoted, so they may hold spaces, quotes and
+// backslashes; empty values are left out for the driver defaults to apply.
+func (c DBConfig) PostgresDSN() string {
+	var port string
+	if c.DBPort != 0 {
+		port = strconv.Itoa(c.DBPort)
+	}
+
+	var parts []string
+	for _, setting := range [][2]string{
+		{"host", c.DBHost},
+		{"port", port},
+		{"user", c.DBUser},
+		{"password", c.DBPassword},
+		{"dbname", c.DBName},
+		{"sslmode", c.SSLMode},
+	} {
+		if setting[1] != "" {
+			parts = append(parts, setting[0]+"="+quoteDSNValue(setting[1]))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// quoteDSNValue quotes a connection string value, escaping the backslashes
+// and quotes it holds
+func quoteDSNValue(value string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
+
+type PasswordHashingConfig struct {
+	Algorithm         string
+	Argon2Memory      uint32
+	Argon2Time        uint32
+	Argon2Parallelism uint8
+	BcryptCost        int
+}
+
+type WebhookConfig struct {
+	PollInterval   time.Duration
+	Timeout        time.Duration
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// AllowPrivateAddresses lets webhooks reach loopback and private
+	// networks, for development only
+	AllowPrivateAddresses bool
+}
+
+type CORSConfig struct {
+	// AllowedOrigins may read API responses from browsers; "*" allows any
+	// origin, and none are allowed when empty
+	AllowedOrigins []string
+}
+
+type RateLimitConfig struct {
+	// RequestsPerSecond is the sustained request rate allowed per client
+	// address; 0 disables rate limiting
+	RequestsPerSecond float64
+	// Burst is how many requests a client address may send at once
+	Burst int
+}
+
+// Tracing exporters
+const (
+	TracingExporterNone   = "none"
+	TracingExporterOTLP   = "otlp"
+	TracingExporterStdout = "stdout"
+)
+
+type TracingConfig struct {
+	// Exporter is none, otlp or stdout. The OTLP exporter is configured with
+	// the standard OTEL_EXPORTER_OTLP_* variables.
+	Exporter    string
+	ServiceName string
+	// SampleRatio is the fraction of new traces that are recorded; requests
+	// carrying a sampled parent trace are always recorded
+	SampleRatio float64
+}
+
+// Log formats
+const (
+	LogFormatJSON = "json"
+	LogFormatText = "text"
+)
+
+type LoggingConfig struct {
+	// Format is json or text; it defaults to json in production
+	Format string
+	Level  slog.Level
 }
 
 type Config struct {
-	JWTSecret   string
+	JWTSecret string
+	// JWTKeysDir holds the asymmetric signing keys; tokens are signed with
+	// JWTSecret when it is empty
+	JWTKeysDir string
+	// JWTIssuer and JWTAudience are the "iss" and "aud" claims of issued
+	// tokens; an empty audience is left out
+	JWTIssuer   string
+	JWTAudience string
 	DBConfig    DBConfig
 	Port        string
-	Environment Environment
+	// GRPCPort is the port of the gRPC API; set to Port both APIs share it,
+	// and empty disables the gRPC API
+	GRPCPort string
+	// ConsoleEnabled serves the admin web console at /console
+	ConsoleEnabled bool
+	// TrustedProxies are the addresses and CIDR ranges of the reverse
+	// proxies allowed to set the client address with X-Forwarded-For; none
+	// are trusted when empty
+	TrustedProxies []string
+	// DrainDelay is how long the readiness probe fails after a termination
+	// signal before the listeners close, so load balancers see the server
+	// draining and stop routing to it
+	DrainDelay time.Duration
+	// ShutdownTimeout is how long in-flight requests may take to finish
+	// after a termination signal
+	ShutdownTimeout time.Duration
+	// ReloadInterval is how often the config file and signing keys are
+	// checked for changes; 0 only reloads on SIGHUP
+	ReloadInterval time.Duration
+	// V1Deprecation and V1Sunset are announced in the Deprecation and Sunset
+	// headers of the deprecated v1 API
+	V1Deprecation time.Time
+	V1Sunset      time.Time
+	// IdempotencyWindow is how long the response of a request with an
+	// Idempotency-Key is kept for replay
+	IdempotencyWindow     time.Duration
+	Environment           Environment
+	PasswordPolicy        models.PasswordPolicy
+	BreachedPasswordsFile string
+	PasswordHashing       PasswordHashingConfig
+	CORS                  CORSConfig
+	RateLimit             RateLimitConfig
+	Webhooks              WebhookConfig
+	Tracing               TracingConfig
+	Logging               LoggingConfig
+	// File is the config file the configuration was loaded from, if any
+	File string
 }
 
-func LoadConfig() *Config {
-	jwtSecret := os.Getenv("JWT_SECRET")
-	if jwtSecret == "" {
-		log.Fatal("JWT secret is missing")
+// Default returns the configuration used when nothing is set
+func Default() *Config {
+	return &Config{
+		DBConfig: DBConfig{
+			Driver:                  DriverPostgres,
+			SQLitePath:              "simplejwt.db",
+			SSLMode:                 "disable",
+			MigrateTenantsOnStartup: true,
+		},
+		JWTIssuer:         "simplejwt",
+		Port:              "9000",
+		ConsoleEnabled:    true,
+		DrainDelay:        5 * time.Second,
+		ShutdownTimeout:   15 * time.Second,
+		V1Deprecation:     time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC),
+		V1Sunset:          time.Date(2027, time.June, 30, 0, 0, 0, 0, time.UTC),
+		IdempotencyWindow: 24 * time.Hour,
+		Environment:       Development,
+		PasswordPolicy: models.PasswordPolicy{
+			MinLength:        8,
+			MaxLength:        100,
+			DisallowUserInfo: true,
+			CheckBreached:    true,
+		},
+		PasswordHashing: PasswordHashingConfig{
+			Algorithm:         "argon2id",
+			Argon2Memory:      64 * 1024,
+			Argon2Time:        3,
+			Argon2Parallelism: 2,
+			BcryptCost:        10,
+		},
+		RateLimit: RateLimitConfig{
+			Burst: 20,
+		},
+		Webhooks: WebhookConfig{
+			PollInterval:   5 * time.Second,
+			Timeout:        10 * time.Second,
+			MaxAttempts:    8,
+			InitialBackoff: 30 * time.Second,
+			MaxBackoff:     time.Hour,
+		},
+		Tracing: TracingConfig{
+			Exporter:    TracingExporterNone,
+			ServiceName: "simplejwt",
+			SampleRatio: 1,
+		},
+		Logging: LoggingConfig{
+			Level: slog.LevelInfo,
+		},
+	}
+}
+
+// Load builds the configuration from the defaults, the config file, the
+// environment and the flags. The config file is the one given with --config,
+// or else the one named by CONFIG_FILE. The returned error lists every
+// problem found; the configuration is still returned when it only failed
+// validation, so it can be printed.
+func Load(flags *Flags) (*Config, error) {
+	cfg := Default()
+	settings := cfg.settings()
+	var problems Problems
+
+	file := os.Getenv("CONFIG_FILE")
+	if flags != nil && flags.ConfigFile != "" {
+		file = flags.ConfigFile
+	}
+	if file != "" {
+		cfg.File = file
+		values, err := readConfigFile(file)
+		if err != nil {
+			return nil, err
+		}
+		problems = append(problems, applyFile(settings, file, values)...)
 	}
 
-	DBName := os.Getenv("DB_NAME")
-	DBPassword := os.Getenv("DB_PASSWORD")
-	DBHost := os.Getenv("DB_HOST")
-	DBPortStr := os.Getenv("DB_PORT")
-	DBUser := os.Getenv("DB_USER")
+	problems = append(problems, applyEnv(settings)...)
+	if flags != nil {
+		problems = append(problems, applyFlags(settings, flags.values)...)
+	}
+
+	cfg.resolveDefaults()
+
+	// A value that failed to parse is reported once, not again as invalid
+	invalid := map[string]bool{}
+	for _, problem := range problems {
+		invalid[problem.Key] = true
+	}
+	for _, problem := range cfg.validate() {
+		if !invalid[problem.Key] {
+			problems = append(problems, problem)
+		}
+	}
 
-	if DBPortStr == "" {
-		log.Fatal("DB_PORT is missing")
+	if len(problems) > 0 {
+		return cfg, problems
 	}
-	DBPort, err := strconv.Atoi(DBPortStr)
+	return cfg, nil
+}
+
+// LoadConfig loads the configuration without command-line flags and exits
+// listing every problem when it is invalid
+func LoadConfig() *Config {
+	cfg, err := Load(nil)
 	if err != nil {
-		log.Fatal("DB_PORT must be a number")
+		log.Fatal(err)
 	}
+	return cfg
+}
 
-	return &Config{
-		JWTSecret: jwtSecret,
-		DBConfig: DBConfig{
-			DBName:     DBName,
-			DBPassword: DBPassword,
-			DBHost:     DBHost,
-			DBPort:     DBPort,
-			DBUser:     DBUser,
-			SSLMode:    getEnvWithDefault("SSL_MODE", "disable"),
-		},
-		Port:        getEnvWithDefault("PORT", "9000"),
-		Environment: Environment(getEnvWithDefault("ENV", "development")),
+// LoadDBConfig reads only the database settings, for tools that do not run
+// the server. Problems with other settings are ignored.
+func LoadDBConfig() DBConfig {
+	cfg, err := Load(nil)
+	if problems, ok := err.(Problems); ok {
+		err = problems.For("db.")
+	}
+	if err != nil {
+		log.Fatal(err)
 	}
+	return cfg.DBConfig
 }
 
-func getEnvWithDefault(key string, defaultValue string) string {
-	if val := os.Getenv(key); val != "" {
-		return val
+// Change is a setting that differs between two configurations. The values of
+// secrets are redacted.
+type Change struct {
+	Key string
+	Old any
+	New any
+}
+
+// Diff lists the settings that differ from old to new
+func Diff(old, new *Config) []Change {
+	oldSettings, newSettings := old.settings(), new.settings()
+
+	var changes []Change
+	for i, s := range newSettings {
+		oldValue, newValue := formatValue(oldSettings[i].field), formatValue(s.field)
+		if oldValue == newValue {
+			continue
+		}
+		if s.secret {
+			oldValue, newValue = redacted, redacted
+		}
+		changes = append(changes, Change{Key: s.key, Old: oldValue, New: newValue})
+	}
+	return changes
+}
+
+// resolveDefaults fills in the defaults that depend on other settings
+func (c *Config) resolveDefaults() {
+	if c.Logging.Format == "" {
+		c.Logging.Format = LogFormatText
+		if c.Environment == Production {
+			c.Logging.Format = LogFormatJSON
+		}
+	}
+}
+
+// Print writes the configuration as YAML, usable as a config file. Secrets
+// are redacted.
+func (c *Config) Print(w io.Writer) error {
+	root := map[string]any{}
+	for _, s := range c.settings() {
+		section, name := splitKey(s.key)
+		if root[section] == nil {
+			root[section] = map[string]any{}
+		}
+
+		value := formatValue(s.field)
+		if s.secret && value != "" {
+			value = redacted
+		}
+		root[section].(map[string]any)[name] = value
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(root); err != nil {
+		return fmt.Errorf("failed to encode configuration: %w", err)
 	}
-	return defaultValue
+	return encoder.Close()
 }
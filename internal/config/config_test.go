@@ -0,0 +1,292 @@
+package config_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Kantha2004/SimpleJWT/internal/config"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Every test sets the environment variables it reads with t.Setenv, which
+// restores them afterwards
+
+// setRequired sets the settings without a usable default, and clears
+// CONFIG_FILE
+func setRequired(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("JWT_SECRET", "secret")
+	t.Setenv("DB_PORT", "5432")
+}
+
+// writeFile writes content to a new file named name and returns its path
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("writing %s: %v", path, err)
+	}
+	return path
+}
+
+func parseFlags(t *testing.T, args ...string) *config.Flags {
+	t.Helper()
+
+	flags, err := config.ParseFlags("test", args)
+	if err != nil {
+		t.Fatalf("ParseFlags(%q): %v", args, err)
+	}
+	return flags
+}
+
+// problemKeys returns the keys of the problems err lists
+func problemKeys(t *testing.T, err error) []string {
+	t.Helper()
+
+	var problems config.Problems
+	if !errors.As(err, &problems) {
+		t.Fatalf("got error %v, want config.Problems", err)
+	}
+	keys := make([]string, len(problems))
+	for i, problem := range problems {
+		keys[i] = problem.Key
+	}
+	return keys
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	yamlFile := writeFile(t, "config.yaml", "server:\n  port: 9100\n  environment: production\n")
+	tomlFile := writeFile(t, "config.toml", "[server]\nport = 9101\n")
+
+	tests := []struct {
+		name  string
+		file  string
+		env   string
+		flags []string
+		want  string
+	}{
+		{"default", "", "", nil, "9000"},
+		{"file", yamlFile, "", nil, "9100"},
+		{"TOML file", tomlFile, "", nil, "9101"},
+		{"environment over file", yamlFile, "9200", nil, "9200"},
+		{"flag over environment", yamlFile, "9200", []string{"--server.port=9300"}, "9300"},
+		{"flag over file", yamlFile, "", []string{"--server.port", "9300"}, "9300"},
+		{"config flag over CONFIG_FILE", yamlFile, "", []string{"--config", tomlFile}, "9101"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRequired(t)
+			t.Setenv("CONFIG_FILE", tt.file)
+			t.Setenv("PORT", tt.env)
+
+			cfg, err := config.Load(parseFlags(t, tt.flags...))
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if cfg.Port != tt.want {
+				t.Fatalf("port = %q, want %q", cfg.Port, tt.want)
+			}
+		})
+	}
+
+	// Settings a source leaves out keep the value of the sources before it
+	setRequired(t)
+	t.Setenv("CONFIG_FILE", yamlFile)
+	t.Setenv("PORT", "9200")
+	cfg, err := config.Load(parseFlags(t, "--server.port=9300"))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Environment != config.Production || cfg.File != yamlFile || cfg.IdempotencyWindow != config.Default().IdempotencyWindow {
+		t.Fatalf("environment %q, file %q and idempotency window %s, want them from the file and the defaults", cfg.Environment, cfg.File, cfg.IdempotencyWindow)
+	}
+	// The default log format depends on the environment
+	if cfg.Logging.Format != config.LogFormatJSON {
+		t.Fatalf("log format in production = %q, want %q", cfg.Logging.Format, config.LogFormatJSON)
+	}
+}
+
+func TestLoadSecretFiles(t *testing.T) {
+	secretFile := writeFile(t, "jwt_secret", "from-file\n")
+	passwordFile := writeFile(t, "db_password", "p@ss word\r\n")
+
+	t.Run("environment", func(t *testing.T) {
+		setRequired(t)
+		// The _FILE variable takes precedence over the variable
+		t.Setenv("JWT_SECRET", "from-env")
+		t.Setenv("JWT_SECRET_FILE", secretFile)
+		t.Setenv("DB_PASSWORD_FILE", passwordFile)
+
+		cfg, err := config.Load(nil)
+		if err != nil {
+			t.Fatalf("Load: %v", err)
+		}
+		if cfg.JWTSecret != "from-file" || cfg.DBConfig.DBPassword != "p@ss word" {
+			t.Fatalf("secrets %q and %q, want them from the files without the trailing newline", cfg.JWTSecret, cfg.DBConfig.DBPassword)
+		}
+	})
+
+	t.Run("config file", func(t *testing.T) {
+		setRequired(t)
+		t.Setenv("JWT_SECRET", "")
+		t.Setenv("CONFIG_FILE", writeFile(t, "config.yaml", "jwt:\n  secret_file: "+secretFile+"\n"))
+
+		cfg, err := config.Load(nil)
+		if err != nil {
+			t.Fatalf("Load: %v", err)
+		}
+		if cfg.JWTSecret != "from-file" {
+			t.Fatalf("secret %q, want it from the file", cfg.JWTSecret)
+		}
+	})
+
+	t.Run("unreadable files", func(t *testing.T) {
+		setRequired(t)
+		t.Setenv("JWT_SECRET_FILE", writeFile(t, "empty", "\n"))
+		t.Setenv("DB_PASSWORD_FILE", filepath.Join(t.TempDir(), "missing"))
+
+		_, err := config.Load(nil)
+		if keys := problemKeys(t, err); !slices.Equal(keys, []string{"jwt.secret", "db.password"}) {
+			t.Fatalf("problems with %v, want jwt.secret and db.password", keys)
+		}
+		if !strings.Contains(err.Error(), "is empty") || !strings.Contains(err.Error(), "$DB_PASSWORD_FILE") {
+			t.Fatalf("error %q does not name the empty file and the variable", err)
+		}
+	})
+
+	t.Run("only secrets have files", func(t *testing.T) {
+		setRequired(t)
+		t.Setenv("CONFIG_FILE", writeFile(t, "config.yaml", "server:\n  port_file: /etc/port\n"))
+
+		_, err := config.Load(nil)
+		if keys := problemKeys(t, err); !slices.Equal(keys, []string{"server.port_file"}) {
+			t.Fatalf("problems with %v, want server.port_file", keys)
+		}
+	})
+}
+
+func TestLoadReportsEveryProblem(t *testing.T) {
+	setRequired(t)
+	t.Setenv("CONFIG_FILE", writeFile(t, "config.yaml", "server:\n  prot: 9000\nwebhooks:\n  max_attempts: 0\n"))
+	t.Setenv("JWT_SECRET", "")
+	t.Setenv("DB_PORT", "five")
+	t.Setenv("PORT", "70000")
+	t.Setenv("PASSWORD_MIN_LENGTH", "12")
+	t.Setenv("PASSWORD_MAX_LENGTH", "10")
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://app.example.com,app.example.com")
+
+	cfg, err := config.Load(parseFlags(t, "--rate_limit.burst=many"))
+	if cfg == nil {
+		t.Fatal("Load returned no configuration for one that only failed validation")
+	}
+
+	// Problems of the sources come first, in the order the sources are
+	// applied. A value that failed to parse is not reported again by
+	// validation, as db.port would be for being zero.
+	want := []string{
+		"server.prot",
+		"db.port",
+		"rate_limit.burst",
+		"server.port",
+		"jwt.secret",
+		"password_policy.max_length",
+		"cors.allowed_origins",
+		"webhooks.max_attempts",
+	}
+	if keys := problemKeys(t, err); !slices.Equal(keys, want) {
+		t.Fatalf("problems with\n%v\nwant\n%v", keys, want)
+	}
+	if !strings.Contains(err.Error(), `db.port ($DB_PORT): "five" is not a number`) ||
+		!strings.Contains(err.Error(), `rate_limit.burst (flag --rate_limit.burst): "many" is not a number`) {
+		t.Fatalf("error does not name the sources:\n%v", err)
+	}
+}
+
+func TestPrintRedactsSecrets(t *testing.T) {
+	setRequired(t)
+	t.Setenv("JWT_SECRET", "jwt-s3cret")
+	t.Setenv("DB_PASSWORD", "db-s3cret")
+
+	cfg, err := config.Load(nil)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := cfg.Print(&out); err != nil {
+		t.Fatalf("Print: %v", err)
+	}
+	printed := out.String()
+	if strings.Contains(printed, "s3cret") || strings.Count(printed, "[REDACTED]") != 2 {
+		t.Fatalf("printed secrets are not redacted:\n%s", printed)
+	}
+
+	// The output is a config file; loading it gives the same configuration
+	// but for the redacted secrets
+	t.Setenv("CONFIG_FILE", writeFile(t, "printed.yaml", printed))
+	t.Setenv("JWT_SECRET", "")
+	t.Setenv("DB_PASSWORD", "")
+	loaded, err := config.Load(nil)
+	if err != nil {
+		t.Fatalf("Load of the printed configuration: %v", err)
+	}
+	var changed []string
+	for _, change := range config.Diff(cfg, loaded) {
+		changed = append(changed, change.Key)
+		if change.Old != "[REDACTED]" || change.New != "[REDACTED]" {
+			t.Errorf("change of %s from %v to %v is not redacted", change.Key, change.Old, change.New)
+		}
+	}
+	if !slices.Equal(changed, []string{"jwt.secret", "db.password"}) {
+		t.Fatalf("printed configuration changed %v, want only the secrets", changed)
+	}
+}
+
+func TestPostgresDSN(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.DBConfig
+		want string
+	}{
+		{
+			"plain values",
+			config.DBConfig{DBHost: "db", DBPort: 5432, DBUser: "simplejwt", DBPassword: "secret", DBName: "auth", SSLMode: "disable"},
+			"host='db' port='5432' user='simplejwt' password='secret' dbname='auth' sslmode='disable'",
+		},
+		{
+			"quotes, backslashes and spaces",
+			config.DBConfig{DBHost: "db", DBPort: 5432, DBUser: "o'brien", DBPassword: `p\w' sslmode=disable`, DBName: "auth db", SSLMode: "disable"},
+			`host='db' port='5432' user='o\'brien' password='p\\w\' sslmode=disable' dbname='auth db' sslmode='disable'`,
+		},
+		{
+			"empty values are left out",
+			config.DBConfig{DBHost: "db", DBName: "auth", SSLMode: "disable"},
+			"host='db' dbname='auth' sslmode='disable'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dsn := tt.cfg.PostgresDSN()
+			if dsn != tt.want {
+				t.Fatalf("PostgresDSN =\n%s\nwant\n%s", dsn, tt.want)
+			}
+
+			// The driver reads back the values as they were set
+			parsed, err := pgconn.ParseConfig(dsn)
+			if err != nil {
+				t.Fatalf("ParseConfig: %v", err)
+			}
+			if parsed.Host != tt.cfg.DBHost || parsed.Database != tt.cfg.DBName ||
+				(tt.cfg.DBUser != "" && parsed.User != tt.cfg.DBUser) || parsed.Password != tt.cfg.DBPassword ||
+				(tt.cfg.DBPort != 0 && int(parsed.Port) != tt.cfg.DBPort) || parsed.TLSConfig != nil {
+				t.Fatalf("driver read host %q, port %d, user %q, password %q, database %q and TLS %v", parsed.Host, parsed.Port, parsed.User, parsed.Password, parsed.Database, parsed.TLSConfig)
+			}
+		})
+	}
+}
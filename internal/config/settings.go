@@ -0,0 +1,208 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const redacted = "[REDACTED]"
+
+// setting binds a field of Config to its config file key, environment
+// variable and flag. The flag is named after the key.
+type setting struct {
+	// key is section.name in the config file
+	key string
+	env string
+	// secret settings can be read from a file with the _FILE environment
+	// variable or the _file key, and are redacted when printed
+	secret bool
+	usage  string
+	// field points into the Config the setting was built for
+	field any
+}
+
+// settings lists every setting of the configuration. A new setting only
+// needs a field and an entry here.
+func (c *Config) settings() []setting {
+	db := &c.DBConfig
+	policy := &c.PasswordPolicy
+	hashing := &c.PasswordHashing
+
+	return []setting{
+		{key: "server.port", env: "PORT", field: &c.Port, usage: "port the HTTP server listens on"},
+		{key: "grpc.port", env: "GRPC_PORT", field: &c.GRPCPort, usage: "port the gRPC API listens on, the HTTP port to share it, empty to disable it"},
+		{key: "server.environment", env: "ENV", field: &c.Environment, usage: "development or production"},
+		{key: "server.drain_delay", env: "DRAIN_DELAY", field: &c.DrainDelay, usage: "how long the readiness probe fails on shutdown before listeners close"},
+		{key: "server.shutdown_timeout", env: "SHUTDOWN_TIMEOUT", field: &c.ShutdownTimeout, usage: "how long in-flight requests may take to finish on shutdown"},
+		{key: "server.v1_deprecation", env: "API_V1_DEPRECATION", field: &c.V1Deprecation, usage: "date the v1 API was deprecated, announced in its Deprecation header"},
+		{key: "server.v1_sunset", env: "API_V1_SUNSET", field: &c.V1Sunset, usage: "date the v1 API is removed, announced in its Sunset header"},
+		{key: "server.idempotency_window", env: "IDEMPOTENCY_WINDOW", field: &c.IdempotencyWindow, usage: "how long responses of requests with an Idempotency-Key are kept for retries"},
+		{key: "server.console_enabled", env: "CONSOLE_ENABLED", field: &c.ConsoleEnabled, usage: "serve the admin web console at /console"},
+		{key: "server.trusted_proxies", env: "TRUSTED_PROXIES", field: &c.TrustedProxies, usage: "comma-separated addresses or CIDR ranges of the reverse proxies whose X-Forwarded-For header is trusted, empty to trust none"},
+		{key: "server.reload_interval", env: "CONFIG_RELOAD_INTERVAL", field: &c.ReloadInterval, usage: "how often to check the config file and signing keys for changes, 0 to only reload on SIGHUP"},
+
+		{key: "jwt.secret", env: "JWT_SECRET", secret: true, field: &c.JWTSecret, usage: "HS256 signing secret, used when jwt.keys_dir is empty"},
+		{key: "jwt.keys_dir", env: "JWT_KEYS_DIR", field: &c.JWTKeysDir, usage: "directory of the asymmetric signing keys"},
+		{key: "jwt.issuer", env: "JWT_ISSUER", field: &c.JWTIssuer, usage: "issuer (iss claim) of tokens, checked by resource servers"},
+		{key: "jwt.audience", env: "JWT_AUDIENCE", field: &c.JWTAudience, usage: "audience (aud claim) of tokens, empty to leave it out"},
+
+		{key: "db.driver", env: "DB_DRIVER", field: &db.Driver, usage: "postgres or sqlite"},
+		{key: "db.path", env: "DB_PATH", field: &db.SQLitePath, usage: "database file of the sqlite driver"},
+		{key: "db.host", env: "DB_HOST", field: &db.DBHost, usage: "PostgreSQL host"},
+		{key: "db.port", env: "DB_PORT", field: &db.DBPort, usage: "PostgreSQL port"},
+		{key: "db.name", env: "DB_NAME", field: &db.DBName, usage: "PostgreSQL database"},
+		{key: "db.user", env: "DB_USER", field: &db.DBUser, usage: "PostgreSQL user"},
+		{key: "db.password", env: "DB_PASSWORD", secret: true, field: &db.DBPassword, usage: "PostgreSQL password"},
+		{key: "db.ssl_mode", env: "SSL_MODE", field: &db.SSLMode, usage: "PostgreSQL sslmode"},
+		{key: "db.migrate_tenants_on_startup", env: "MIGRATE_TENANTS_ON_STARTUP", field: &db.MigrateTenantsOnStartup, usage: "upgrade every client schema when the server starts"},
+
+		{key: "password_policy.min_length", env: "PASSWORD_MIN_LENGTH", field: &policy.MinLength, usage: "minimum password length"},
+		{key: "password_policy.max_length", env: "PASSWORD_MAX_LENGTH", field: &policy.MaxLength, usage: "maximum password length"},
+		{key: "password_policy.require_uppercase", env: "PASSWORD_REQUIRE_UPPERCASE", field: &policy.RequireUppercase, usage: "require an uppercase letter"},
+		{key: "password_policy.require_lowercase", env: "PASSWORD_REQUIRE_LOWERCASE", field: &policy.RequireLowercase, usage: "require a lowercase letter"},
+		{key: "password_policy.require_digit", env: "PASSWORD_REQUIRE_DIGIT", field: &policy.RequireDigit, usage: "require a digit"},
+		{key: "password_policy.require_symbol", env: "PASSWORD_REQUIRE_SYMBOL", field: &policy.RequireSymbol, usage: "require a symbol"},
+		{key: "password_policy.max_repeated_chars", env: "PASSWORD_MAX_REPEATED_CHARS", field: &policy.MaxRepeatedChars, usage: "longest allowed run of one character, 0 for no limit"},
+		{key: "password_policy.disallow_user_info", env: "PASSWORD_DISALLOW_USER_INFO", field: &policy.DisallowUserInfo, usage: "reject passwords containing the username or email"},
+		{key: "password_policy.history_size", env: "PASSWORD_HISTORY_SIZE", field: &policy.HistorySize, usage: "number of previous passwords that cannot be reused"},
+		{key: "password_policy.check_breached", env: "PASSWORD_CHECK_BREACHED", field: &policy.CheckBreached, usage: "reject passwords of the breached passwords list"},
+		{key: "password_policy.breached_passwords_file", env: "BREACHED_PASSWORDS_FILE", field: &c.BreachedPasswordsFile, usage: "file of SHA-1 hashes of breached passwords"},
+
+		{key: "password_hashing.algorithm", env: "PASSWORD_HASH_ALGORITHM", field: &hashing.Algorithm, usage: "argon2id or bcrypt"},
+		{key: "password_hashing.argon2_memory_kb", env: "ARGON2_MEMORY_KB", field: &hashing.Argon2Memory, usage: "argon2id memory in KiB"},
+		{key: "password_hashing.argon2_time", env: "ARGON2_TIME", field: &hashing.Argon2Time, usage: "argon2id iterations"},
+		{key: "password_hashing.argon2_parallelism", env: "ARGON2_PARALLELISM", field: &hashing.Argon2Parallelism, usage: "argon2id threads"},
+		{key: "password_hashing.bcrypt_cost", env: "BCRYPT_COST", field: &hashing.BcryptCost, usage: "bcrypt cost"},
+
+		{key: "cors.allowed_origins", env: "CORS_ALLOWED_ORIGINS", field: &c.CORS.AllowedOrigins, usage: "comma-separated origins browsers may call the API from, * for any, empty for none"},
+
+		{key: "rate_limit.requests_per_second", env: "RATE_LIMIT_RPS", field: &c.RateLimit.RequestsPerSecond, usage: "requests per second allowed per client address, 0 to disable rate limiting"},
+		{key: "rate_limit.burst", env: "RATE_LIMIT_BURST", field: &c.RateLimit.Burst, usage: "requests a client address may send at once"},
+
+		{key: "webhooks.poll_interval", env: "WEBHOOK_POLL_INTERVAL", field: &c.Webhooks.PollInterval, usage: "how often the delivery queue is checked"},
+		{key: "webhooks.timeout", env: "WEBHOOK_TIMEOUT", field: &c.Webhooks.Timeout, usage: "timeout of one delivery attempt"},
+		{key: "webhooks.max_attempts", env: "WEBHOOK_MAX_ATTEMPTS", field: &c.Webhooks.MaxAttempts, usage: "attempts before a delivery fails"},
+		{key: "webhooks.initial_backoff", env: "WEBHOOK_INITIAL_BACKOFF", field: &c.Webhooks.InitialBackoff, usage: "wait after the first failed attempt"},
+		{key: "webhooks.max_backoff", env: "WEBHOOK_MAX_BACKOFF", field: &c.Webhooks.MaxBackoff, usage: "longest wait between two attempts"},
+		{key: "webhooks.allow_private_addresses", env: "WEBHOOK_ALLOW_PRIVATE_ADDRESSES", field: &c.Webhooks.AllowPrivateAddresses, usage: "deliver webhooks to loopback and private network addresses, for development only"},
+
+		{key: "tracing.exporter", env: "TRACING_EXPORTER", field: &c.Tracing.Exporter, usage: "none, otlp or stdout"},
+		{key: "tracing.service_name", env: "OTEL_SERVICE_NAME", field: &c.Tracing.ServiceName, usage: "service name of the spans"},
+		{key: "tracing.sample_ratio", env: "TRACING_SAMPLE_RATIO", field: &c.Tracing.SampleRatio, usage: "fraction of new traces recorded"},
+
+		{key: "logging.format", env: "LOG_FORMAT", field: &c.Logging.Format, usage: "json or text, json by default in production"},
+		{key: "logging.level", env: "LOG_LEVEL", field: &c.Logging.Level, usage: "debug, info, warn or error"},
+	}
+}
+
+// set parses raw into the field of the setting. Strings are kept as they
+// are, since secrets may contain spaces.
+func (s setting) set(raw string) error {
+	if field, ok := s.field.(*string); ok {
+		*field = raw
+		return nil
+	}
+
+	raw = strings.TrimSpace(raw)
+	switch field := s.field.(type) {
+	case *Environment:
+		*field = Environment(raw)
+	case *int:
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", raw)
+		}
+		*field = parsed
+	case *uint32:
+		parsed, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			return fmt.Errorf("%q is not a number between 0 and %d", raw, uint32(1<<32-1))
+		}
+		*field = uint32(parsed)
+	case *uint8:
+		parsed, err := strconv.ParseUint(raw, 10, 8)
+		if err != nil {
+			return fmt.Errorf("%q is not a number between 0 and 255", raw)
+		}
+		*field = uint8(parsed)
+	case *float64:
+		parsed, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", raw)
+		}
+		*field = parsed
+	case *bool:
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("%q is not a boolean", raw)
+		}
+		*field = parsed
+	case *time.Duration:
+		parsed, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("%q is not a duration such as 30s or 5m", raw)
+		}
+		*field = parsed
+	case *time.Time:
+		parsed, err := time.Parse(time.DateOnly, raw)
+		if err != nil {
+			if parsed, err = time.Parse(time.RFC3339, raw); err != nil {
+				return fmt.Errorf("%q is not a date such as 2027-06-30", raw)
+			}
+		}
+		*field = parsed.UTC()
+	case *slog.Level:
+		if err := field.UnmarshalText([]byte(raw)); err != nil {
+			return fmt.Errorf("%q is not debug, info, warn or error", raw)
+		}
+	case *[]string:
+		// Lists are comma-separated
+		*field = nil
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				*field = append(*field, item)
+			}
+		}
+	default:
+		panic(fmt.Sprintf("config: setting %s has unsupported type %T", s.key, s.field))
+	}
+	return nil
+}
+
+// formatValue returns the value of a field as it is written in a config file
+func formatValue(field any) any {
+	switch field := field.(type) {
+	case *string:
+		return *field
+	case *Environment:
+		return string(*field)
+	case *int:
+		return *field
+	case *uint32:
+		return *field
+	case *uint8:
+		return *field
+	case *float64:
+		return *field
+	case *bool:
+		return *field
+	case *time.Duration:
+		return field.String()
+	case *time.Time:
+		return field.Format(time.DateOnly)
+	case *slog.Level:
+		return strings.ToLower(field.String())
+	case *[]string:
+		return strings.Join(*field, ",")
+	default:
+		panic(fmt.Sprintf("config: unsupported setting type %T", field))
+	}
+}
+
+func splitKey(key string) (section, name string) {
+	section, name, _ = strings.Cut(key, ".")
+	return section, name
+}
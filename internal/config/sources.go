@@ -0,0 +1,192 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Flags holds the command-line flags of the server
+type Flags struct {
+	// ConfigFile overrides CONFIG_FILE
+	ConfigFile string
+	// PrintConfig asks to print the configuration and exit
+	PrintConfig bool
+	// values holds the settings given on the command line by key
+	values map[string]string
+}
+
+// ParseFlags parses the command-line flags. Every setting has a flag named
+// after its config file key, such as --server.port.
+func ParseFlags(name string, args []string) (*Flags, error) {
+	flags := &Flags{values: map[string]string{}}
+
+	flagSet := flag.NewFlagSet(name, flag.ContinueOnError)
+	flagSet.StringVar(&flags.ConfigFile, "config", "", "YAML or TOML config file (default $CONFIG_FILE)")
+	flagSet.BoolVar(&flags.PrintConfig, "print-config", false, "print the configuration with secrets redacted and exit")
+	for _, s := range Default().settings() {
+		key := s.key
+		flagSet.Func(key, fmt.Sprintf("%s ($%s)", s.usage, s.env), func(raw string) error {
+			flags.values[key] = raw
+			return nil
+		})
+	}
+
+	if err := flagSet.Parse(args); err != nil {
+		return nil, err
+	}
+	if flagSet.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected arguments %q", strings.Join(flagSet.Args(), " "))
+	}
+	return flags, nil
+}
+
+// readConfigFile reads a YAML or TOML file, chosen by its extension, into
+// values by setting key. Sections are flattened, so a port in the server
+// section becomes server.port.
+func readConfigFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var document map[string]any
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &document)
+	case ".toml":
+		err = toml.Unmarshal(data, &document)
+	default:
+		return nil, fmt.Errorf("config file %s must end in .yaml, .yml or .toml", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	values := map[string]string{}
+	if err := flatten("", document, values); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return values, nil
+}
+
+func flatten(prefix string, document map[string]any, values map[string]string) error {
+	for name, value := range document {
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+
+		switch value := value.(type) {
+		case map[string]any:
+			if err := flatten(key, value, values); err != nil {
+				return err
+			}
+		case []any:
+			// Lists of values are read like comma-separated values
+			items := make([]string, len(value))
+			for i, item := range value {
+				if _, nested := item.(map[string]any); nested {
+					return fmt.Errorf("%s: lists may only hold values", key)
+				}
+				items[i] = fmt.Sprint(item)
+			}
+			values[key] = strings.Join(items, ",")
+		case nil:
+		case time.Time:
+			// YAML decodes unquoted dates itself
+			values[key] = value.Format(time.RFC3339)
+		default:
+			values[key] = fmt.Sprint(value)
+		}
+	}
+	return nil
+}
+
+// applyFile sets the settings found in a config file and reports keys that
+// match no setting, which are usually typos
+func applyFile(settings []setting, path string, values map[string]string) Problems {
+	var problems Problems
+	source := "config file " + path
+
+	known := map[string]bool{}
+	for _, s := range settings {
+		known[s.key] = true
+		if raw, ok := values[s.key]; ok {
+			problems = problems.add(s.key, source, s.set(raw))
+		}
+
+		if !s.secret {
+			continue
+		}
+		known[s.key+"_file"] = true
+		if secretPath, ok := values[s.key+"_file"]; ok {
+			problems = problems.add(s.key, source, setFromFile(s, secretPath))
+		}
+	}
+
+	var unknown []string
+	for key := range values {
+		if !known[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	for _, key := range unknown {
+		problems = problems.add(key, source, fmt.Errorf("unknown setting"))
+	}
+
+	return problems
+}
+
+// applyEnv sets the settings found in the environment. Empty variables are
+// treated as unset. A secret's _FILE variable takes precedence over the
+// variable itself.
+func applyEnv(settings []setting) Problems {
+	var problems Problems
+	for _, s := range settings {
+		if raw := os.Getenv(s.env); raw != "" {
+			problems = problems.add(s.key, "$"+s.env, s.set(raw))
+		}
+
+		if !s.secret {
+			continue
+		}
+		if secretPath := os.Getenv(s.env + "_FILE"); secretPath != "" {
+			problems = problems.add(s.key, "$"+s.env+"_FILE", setFromFile(s, secretPath))
+		}
+	}
+	return problems
+}
+
+func applyFlags(settings []setting, values map[string]string) Problems {
+	var problems Problems
+	for _, s := range settings {
+		if raw, ok := values[s.key]; ok {
+			problems = problems.add(s.key, "flag --"+s.key, s.set(raw))
+		}
+	}
+	return problems
+}
+
+// setFromFile sets a secret to the content of a file, such as a mounted
+// Kubernetes or Docker secret. One trailing newline is dropped.
+func setFromFile(s setting, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read secret file: %w", err)
+	}
+
+	value := strings.TrimSuffix(strings.TrimSuffix(string(data), "\n"), "\r")
+	if value == "" {
+		return fmt.Errorf("secret file %s is empty", path)
+	}
+	return s.set(value)
+}
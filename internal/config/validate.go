@@ -0,0 +1,165 @@
+package config
+
+import (
+	"fmt"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Problem is an invalid setting
+type Problem struct {
+	Key string
+	// Source is where the value came from, empty for problems found when
+	// validating the final configuration
+	Source string
+	Err    error
+}
+
+func (p *Problem) Error() string {
+	if p.Source != "" {
+		return fmt.Sprintf("%s (%s): %v", p.Key, p.Source, p.Err)
+	}
+	return fmt.Sprintf("%s: %v", p.Key, p.Err)
+}
+
+// Problems lists every problem of a configuration, so they can be fixed at
+// once
+type Problems []*Problem
+
+func (p Problems) Error() string {
+	var b strings.Builder
+	b.WriteString("invalid configuration:")
+	for _, problem := range p {
+		b.WriteString("\n  - ")
+		b.WriteString(problem.Error())
+	}
+	return b.String()
+}
+
+func (p Problems) add(key, source string, err error) Problems {
+	if err == nil {
+		return p
+	}
+	return append(p, &Problem{Key: key, Source: source, Err: err})
+}
+
+// For returns the problems of the settings whose key starts with prefix, or
+// nil when there are none
+func (p Problems) For(prefix string) error {
+	var matching Problems
+	for _, problem := range p {
+		if strings.HasPrefix(problem.Key, prefix) {
+			matching = append(matching, problem)
+		}
+	}
+	if len(matching) == 0 {
+		return nil
+	}
+	return matching
+}
+
+// validate checks the combination of settings once every source is applied
+func (c *Config) validate() Problems {
+	var problems Problems
+	check := func(key string, ok bool, format string, args ...any) {
+		if !ok {
+			problems = problems.add(key, "", fmt.Errorf(format, args...))
+		}
+	}
+
+	port, err := strconv.Atoi(c.Port)
+	check("server.port", err == nil && port > 0 && port < 65536, "%q is not a port number", c.Port)
+	if c.GRPCPort != "" {
+		grpcPort, err := strconv.Atoi(c.GRPCPort)
+		check("grpc.port", err == nil && grpcPort > 0 && grpcPort < 65536, "%q is not a port number", c.GRPCPort)
+	}
+	check("server.environment", c.Environment == Development || c.Environment == Production,
+		"must be %s or %s", Development, Production)
+	check("server.drain_delay", c.DrainDelay >= 0, "must not be negative")
+	check("server.shutdown_timeout", c.ShutdownTimeout > 0, "must be positive")
+	check("server.reload_interval", c.ReloadInterval >= 0, "must not be negative")
+	check("server.v1_sunset", !c.V1Sunset.Before(c.V1Deprecation), "must not be before server.v1_deprecation")
+	check("server.idempotency_window", c.IdempotencyWindow > 0, "must be positive")
+	for _, proxy := range c.TrustedProxies {
+		check("server.trusted_proxies", isAddressOrPrefix(proxy), "%q is not an IP address or CIDR range", proxy)
+	}
+
+	check("jwt.secret", c.JWTSecret != "" || c.JWTKeysDir != "", "either jwt.secret or jwt.keys_dir must be set")
+
+	switch c.DBConfig.Driver {
+	case DriverPostgres:
+		check("db.port", c.DBConfig.DBPort > 0 && c.DBConfig.DBPort < 65536, "must be set to a port number for the %s driver", DriverPostgres)
+	case DriverSQLite:
+		check("db.path", c.DBConfig.SQLitePath != "", "must be set for the %s driver", DriverSQLite)
+	default:
+		check("db.driver", false, "must be %s or %s", DriverPostgres, DriverSQLite)
+	}
+
+	policy := c.PasswordPolicy
+	check("password_policy.min_length", policy.MinLength > 0, "must be at least 1")
+	check("password_policy.max_length", policy.MaxLength >= policy.MinLength, "must not be less than password_policy.min_length")
+	check("password_policy.max_repeated_chars", policy.MaxRepeatedChars >= 0, "must not be negative")
+	check("password_policy.history_size", policy.HistorySize >= 0, "must not be negative")
+
+	hashing := c.PasswordHashing
+	switch hashing.Algorithm {
+	case "argon2id":
+		check("password_hashing.argon2_memory_kb", hashing.Argon2Memory >= 8*uint32(hashing.Argon2Parallelism), "must be at least 8 KiB per thread")
+		check("password_hashing.argon2_time", hashing.Argon2Time > 0, "must be at least 1")
+		check("password_hashing.argon2_parallelism", hashing.Argon2Parallelism > 0, "must be at least 1")
+	case "bcrypt":
+		check("password_hashing.bcrypt_cost", hashing.BcryptCost >= 4 && hashing.BcryptCost <= 31, "must be between 4 and 31")
+		// bcrypt cannot hash passwords longer than 72 bytes
+		check("password_policy.max_length", policy.MaxLength <= 72, "must be at most 72 with the bcrypt algorithm")
+	default:
+		check("password_hashing.algorithm", false, "must be argon2id or bcrypt")
+	}
+
+	for _, origin := range c.CORS.AllowedOrigins {
+		check("cors.allowed_origins", origin == "*" || isOrigin(origin), "%q is not an origin such as https://app.example.com", origin)
+	}
+
+	check("rate_limit.requests_per_second", c.RateLimit.RequestsPerSecond >= 0, "must not be negative")
+	check("rate_limit.burst", c.RateLimit.Burst > 0 || c.RateLimit.RequestsPerSecond == 0, "must be at least 1")
+
+	webhooks := c.Webhooks
+	check("webhooks.poll_interval", webhooks.PollInterval > 0, "must be positive")
+	check("webhooks.timeout", webhooks.Timeout > 0, "must be positive")
+	check("webhooks.max_attempts", webhooks.MaxAttempts > 0, "must be at least 1")
+	check("webhooks.initial_backoff", webhooks.InitialBackoff > 0, "must be positive")
+	check("webhooks.max_backoff", webhooks.MaxBackoff >= webhooks.InitialBackoff, "must not be less than webhooks.initial_backoff")
+
+	switch c.Tracing.Exporter {
+	case TracingExporterNone, TracingExporterOTLP, TracingExporterStdout:
+	default:
+		check("tracing.exporter", false, "must be %s, %s or %s", TracingExporterNone, TracingExporterOTLP, TracingExporterStdout)
+	}
+	check("tracing.service_name", c.Tracing.ServiceName != "", "must not be empty")
+	check("tracing.sample_ratio", c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "must be between 0 and 1")
+
+	check("logging.format", c.Logging.Format == LogFormatJSON || c.Logging.Format == LogFormatText,
+		"must be %s or %s", LogFormatJSON, LogFormatText)
+
+	return problems
+}
+
+func isAddressOrPrefix(value string) bool {
+	if _, err := netip.ParseAddr(value); err == nil {
+		return true
+	}
+	_, err := netip.ParsePrefix(value)
+	return err == nil
+}
+
+// isOrigin reports whether value is a scheme and host with nothing else, as
+// browsers send it in the Origin header
+func isOrigin(value string) bool {
+	u, err := url.Parse(value)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" &&
+		u.User == nil && u.Path == "" && u.RawQuery == "" && u.Fragment == "" && !u.ForceQuery
+}
@@ -0,0 +1,114 @@
+// Package console serves the admin web console, a single page application
+// embedded in the binary. The console signs in with a session cookie rather
+// than keeping a bearer token where scripts can read it, and calls the v2
+// API with that cookie; requests that change something must also carry the
+// CSRF token of the session.
+package console
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"embed"
+	"encoding/base64"
+	"io/fs"
+	"net/http"
+	"time"
+)
+
+const (
+	// BasePath is where the console is served
+	BasePath = "/console"
+	// SessionCookie holds the token of a console session. Scripts cannot
+	// read it, so a cross-site scripting flaw cannot leak the token.
+	SessionCookie = "simplejwt_console"
+	// CSRFHeader carries the CSRF token of the session on requests
+	// authenticated by the cookie that change something
+	CSRFHeader = "X-CSRF-Token"
+)
+
+// contentSecurityPolicy only lets the console load its own assets and call
+// its own origin, and keeps other sites from framing it
+const contentSecurityPolicy = "default-src 'self'; base-uri 'none'; form-action 'self'; frame-ancestors 'none'; object-src 'none'"
+
+//go:embed static
+var static embed.FS
+
+// Files returns the assets of the console, index.html at the root
+func Files() fs.FS {
+	files, err := fs.Sub(static, "static")
+	if err != nil {
+		panic(err)
+	}
+	return files
+}
+
+// Handler serves the assets of the console under BasePath. Assets are
+// revalidated on every load so an upgraded server is picked up at once.
+func Handler() http.Handler {
+	files := http.StripPrefix(BasePath, http.FileServerFS(Files()))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := w.Header()
+		header.Set("Content-Security-Policy", contentSecurityPolicy)
+		header.Set("X-Frame-Options", "DENY")
+		header.Set("X-Content-Type-Options", "nosniff")
+		header.Set("Referrer-Policy", "no-referrer")
+		header.Set("Cache-Control", "no-cache")
+		files.ServeHTTP(w, r)
+	})
+}
+
+// NewCSRFToken returns a random CSRF token for a new session. It is stored
+// with the session and only sent to the console in response bodies, never
+// in a cookie, so pages of other origins cannot learn it.
+func NewCSRFToken() (string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
+
+// CheckCSRF reports whether a request authenticated by the session cookie
+// may go on: safe methods always may, others must carry csrfToken, the CSRF
+// token of the session, in CSRFHeader. Sessions without a token were not
+// started by the console and may not change anything with the cookie.
+func CheckCSRF(r *http.Request, csrfToken string) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+
+	if csrfToken == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(r.Header.Get(CSRFHeader)), []byte(csrfToken)) == 1
+}
+
+// SetSessionCookie stores the token of a new session in the browser until
+// the session expires. Secure cookies are only sent over HTTPS.
+func SetSessionCookie(w http.ResponseWriter, token string, expiresAt time.Time, secure bool) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     SessionCookie,
+		Value:    token,
+		Path:     "/",
+		Expires:  expiresAt,
+		MaxAge:   int(time.Until(expiresAt).Seconds()),
+		Secure:   secure,
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
+
+// ClearSessionCookie removes the session cookie from the browser
+func ClearSessionCookie(w http.ResponseWriter, secure bool) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     SessionCookie,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		Secure:   secure,
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
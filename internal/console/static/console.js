@@ -0,0 +1,757 @@
+// SimpleJWT admin console. It signs in with a session cookie and calls the
+// v2 API with it; requests that change something carry the CSRF token of the
+// session. Pages are built with DOM calls only, never from HTML strings.
+(function () {
+  'use strict';
+
+  const API = '/api/v2';
+  const SESSION = '/console/session';
+  // Problems telling the session cookie no longer works
+  const SESSION_ENDED = ['missing_token', 'invalid_token', 'token_expired', 'session_revoked'];
+
+  const POLICY_NUMBERS = [
+    ['min_length', 'Minimum length'],
+    ['max_length', 'Maximum length'],
+    ['max_repeated_chars', 'Longest run of one character'],
+    ['history_size', 'Previous passwords that cannot be reused'],
+  ];
+  const POLICY_FLAGS = [
+    ['require_uppercase', 'Require an uppercase letter'],
+    ['require_lowercase', 'Require a lowercase letter'],
+    ['require_digit', 'Require a digit'],
+    ['require_symbol', 'Require a symbol'],
+    ['disallow_user_info', 'Reject the username or email'],
+    ['check_breached', 'Reject breached passwords'],
+  ];
+
+  const main = document.getElementById('main');
+  const topbar = document.getElementById('topbar');
+  const currentUser = document.getElementById('current-user');
+  const toastBox = document.getElementById('toast');
+
+  let session = null;
+  let toastTimer = null;
+
+  class ApiError extends Error {
+    constructor(status, problem) {
+      super(problem.detail || problem.title || 'Request failed with status ' + status);
+      this.status = status;
+      this.problem = problem;
+      // ended is set when the failure sent the console back to the login page
+      this.ended = false;
+    }
+  }
+
+  // request calls the server and returns the data, pagination and ETag of
+  // the response, or throws an ApiError holding the problem
+  async function request(method, path, options) {
+    options = options || {};
+    const headers = { Accept: 'application/json' };
+    const init = { method: method, headers: headers, credentials: 'same-origin' };
+
+    if (options.body !== undefined) {
+      headers['Content-Type'] = 'application/json';
+      init.body = JSON.stringify(options.body);
+    }
+    if (method !== 'GET' && session) {
+      headers['X-CSRF-Token'] = session.csrfToken;
+    }
+    if (options.etag) {
+      headers['If-Match'] = options.etag;
+    }
+
+    const response = await fetch(path, init);
+
+    let payload = null;
+    const text = response.status === 204 ? '' : await response.text();
+    if (text) {
+      try {
+        payload = JSON.parse(text);
+      } catch (err) {
+        payload = null;
+      }
+    }
+
+    if (!response.ok) {
+      const error = new ApiError(response.status, payload || {});
+      if (session && response.status === 401 && SESSION_ENDED.includes(error.problem.code)) {
+        error.ended = true;
+        showLogin('Your session has ended. Log in again to continue.');
+      }
+      throw error;
+    }
+
+    return {
+      data: payload ? payload.data : null,
+      pagination: payload ? payload.pagination : null,
+      etag: response.headers.get('ETag'),
+    };
+  }
+
+  function api(method, path, options) {
+    return request(method, API + path, options);
+  }
+
+  // query builds a query string of the parameters that are set
+  function query(params) {
+    const search = new URLSearchParams();
+    for (const [key, value] of Object.entries(params)) {
+      if (value !== undefined && value !== null && value !== '') {
+        search.set(key, value);
+      }
+    }
+    const encoded = search.toString();
+    return encoded ? '?' + encoded : '';
+  }
+
+  // h creates an element. Attributes starting with "on" add listeners;
+  // children are nodes or text.
+  function h(tag, attrs) {
+    const element = document.createElement(tag);
+    for (const [name, value] of Object.entries(attrs || {})) {
+      if (value === undefined || value === null || value === false) {
+        continue;
+      }
+      if (name.startsWith('on')) {
+        element.addEventListener(name.slice(2), value);
+      } else if (name === 'class') {
+        element.className = value;
+      } else {
+        element.setAttribute(name, value === true ? '' : value);
+      }
+    }
+    const children = Array.prototype.slice.call(arguments, 2).flat(Infinity);
+    for (const child of children) {
+      if (child !== undefined && child !== null && child !== false) {
+        element.append(child instanceof Node ? child : String(child));
+      }
+    }
+    return element;
+  }
+
+  function field(label, name, attrs) {
+    return h('label', null, h('span', null, label), h('input', Object.assign({ name: name }, attrs)));
+  }
+
+  function select(label, name, options, selected) {
+    return h('label', null, h('span', null, label),
+      h('select', { name: name }, options.map(([value, text]) =>
+        h('option', { value: value, selected: value === selected }, text))));
+  }
+
+  function formatDate(value) {
+    return value ? new Date(value).toLocaleString() : '—';
+  }
+
+  function badge(text, kind) {
+    return h('span', { class: kind ? 'badge ' + kind : 'badge' }, text);
+  }
+
+  function errorBox(err) {
+    const problem = err.problem || {};
+    const fieldErrors = problem.errors || [];
+    return h('div', { class: 'error', role: 'alert' },
+      err.message,
+      fieldErrors.length > 0 && h('ul', null, fieldErrors.map((e) => h('li', null, (e.field ? e.field + ': ' : '') + e.message))),
+      problem.request_id && h('div', { class: 'muted' }, 'Request ID: ' + problem.request_id));
+  }
+
+  function toast(message) {
+    toastBox.textContent = message;
+    toastBox.hidden = false;
+    clearTimeout(toastTimer);
+    toastTimer = setTimeout(() => {
+      toastBox.hidden = true;
+    }, 4000);
+  }
+
+  // run performs an action of a button, reporting failures in a toast
+  async function run(action) {
+    try {
+      await action();
+    } catch (err) {
+      if (!err.ended) {
+        toast(err.message);
+      }
+    }
+  }
+
+  // form wires the submit of a form to handler, which gets the form data.
+  // Failures are shown above the form.
+  function form(attrs, children, handler) {
+    const errors = h('div');
+    const element = h('form', attrs, children);
+    element.addEventListener('submit', async (event) => {
+      event.preventDefault();
+      errors.replaceChildren();
+      const buttons = element.querySelectorAll('button');
+      buttons.forEach((button) => {
+        button.disabled = true;
+      });
+      try {
+        await handler(new FormData(element), element);
+      } catch (err) {
+        if (!err.ended) {
+          errors.replaceChildren(errorBox(err));
+        }
+      } finally {
+        buttons.forEach((button) => {
+          button.disabled = false;
+        });
+      }
+    });
+    return h('div', null, errors, element);
+  }
+
+  // pagedTable lists the pages returned by load(cursor), following
+  // pagination.next_cursor. row returns the rows of an item.
+  function pagedTable(headers, load, row) {
+    const body = h('tbody');
+    const empty = h('p', { class: 'muted', hidden: true }, 'Nothing to show.');
+    const more = h('button', { type: 'button', class: 'secondary', hidden: true }, 'Load more');
+    let cursor = null;
+
+    async function fetchPage(reset) {
+      const page = await load(reset ? null : cursor);
+      if (reset) {
+        body.replaceChildren();
+      }
+      for (const item of page.data) {
+        body.append(...[].concat(row(item)));
+      }
+      cursor = page.pagination && page.pagination.next_cursor;
+      more.hidden = !cursor;
+      empty.hidden = body.children.length > 0;
+    }
+
+    more.addEventListener('click', () => run(() => fetchPage(false)));
+
+    return {
+      element: h('div', null,
+        h('table', null, h('thead', null, h('tr', null, headers.map((header) => h('th', null, header)))), body),
+        empty,
+        h('div', { class: 'more' }, more)),
+      reload: () => fetchPage(true),
+    };
+  }
+
+  function page(title) {
+    const root = h('div', null, h('h1', null, title));
+    main.replaceChildren(root);
+    return root;
+  }
+
+  function panel(title) {
+    return h('section', { class: 'panel' }, title && h('h2', null, title), Array.prototype.slice.call(arguments, 1));
+  }
+
+  // Clients
+
+  async function viewClients() {
+    const root = page('Clients');
+    const created = h('div');
+    let filters = {};
+
+    const list = pagedTable(['Name', 'ID', 'Status', 'Created'],
+      (cursor) => api('GET', '/clients' + query(Object.assign({ cursor: cursor }, filters))),
+      (client) => h('tr', null,
+        h('td', null, h('a', { href: '#/clients/' + client.id }, client.client_name)),
+        h('td', null, client.id),
+        h('td', null, client.suspended_at ? badge('suspended', 'danger') : badge('active', 'ok')),
+        h('td', null, formatDate(client.created_at))));
+
+    root.append(
+      panel('New client',
+        created,
+        form({ class: 'inline' }, [
+          field('Name', 'client_name', { required: true, autocomplete: 'off', pattern: '[a-z0-9_]{1,56}', title: 'Lowercase letters, digits and underscores' }),
+          h('button', { type: 'submit' }, 'Create client'),
+        ], async (data, element) => {
+          const { data: client } = await api('POST', '/clients', { body: { client_name: data.get('client_name') } });
+          element.reset();
+          created.replaceChildren(h('div', { class: 'notice' },
+            'Client ', h('a', { href: '#/clients/' + client.id }, client.client_name),
+            ' created. Its secret is shown on the client page.'));
+          await list.reload();
+        })),
+      panel(null,
+        form({ class: 'inline' }, [
+          field('Name contains', 'name', { type: 'search' }),
+          select('Status', 'suspended', [['', 'Any'], ['false', 'Active'], ['true', 'Suspended']], ''),
+          h('button', { type: 'submit', class: 'secondary' }, 'Filter'),
+        ], async (data) => {
+          filters = { name: data.get('name'), suspended: data.get('suspended') };
+          await list.reload();
+        }),
+        list.element));
+
+    await list.reload();
+  }
+
+  async function viewClient(id) {
+    const root = page('Client');
+    let { data: client, etag } = await api('GET', '/clients/' + id);
+
+    root.firstChild.textContent = client.client_name;
+    root.append(
+      h('p', null, h('a', { href: '#/clients' }, '← All clients'), ' · ',
+        h('a', { href: '#/audit' + query({ client_id: client.id }) }, 'Audit events of this client')),
+      panel('Details', h('dl', { class: 'details' },
+        h('dt', null, 'ID'), h('dd', null, client.id),
+        h('dt', null, 'Schema'), h('dd', null, h('code', null, client.schema_name)),
+        h('dt', null, 'Status'), h('dd', null, client.suspended_at ? badge('suspended since ' + formatDate(client.suspended_at), 'danger') : badge('active', 'ok')),
+        h('dt', null, 'Created'), h('dd', null, formatDate(client.created_at)))),
+      secretPanel(),
+      usersPanel(client),
+      rolesPanel(client),
+      configPanel(client));
+
+    function secretPanel() {
+      const secret = h('span', { class: 'secret' }, '••••••••••••••••');
+      let shown = false;
+
+      const toggle = h('button', { type: 'button', class: 'secondary' }, 'Show');
+      toggle.addEventListener('click', () => {
+        shown = !shown;
+        secret.textContent = shown ? client.client_secret : '••••••••••••••••';
+        toggle.textContent = shown ? 'Hide' : 'Show';
+      });
+
+      const copy = h('button', { type: 'button', class: 'secondary' }, 'Copy');
+      copy.addEventListener('click', () => run(async () => {
+        await navigator.clipboard.writeText(client.client_secret);
+        toast('Secret copied');
+      }));
+
+      const rotate = h('button', { type: 'button', class: 'danger' }, 'Rotate secret');
+      rotate.addEventListener('click', () => run(async () => {
+        if (!confirm('Rotate the secret of ' + client.client_name + '? Applications using the current secret stop working at once.')) {
+          return;
+        }
+        const result = await api('POST', '/clients/' + client.id + '/secret', { etag: etag });
+        client = result.data;
+        etag = result.etag;
+        shown = true;
+        secret.textContent = client.client_secret;
+        toggle.textContent = 'Hide';
+        toast('Secret rotated');
+      }));
+
+      return panel('Secret', h('div', { class: 'row' }, secret, toggle, copy, rotate));
+    }
+  }
+
+  function usersPanel(client) {
+    const base = '/clients/' + client.id + '/users';
+    let filters = {};
+
+    const list = pagedTable(['Username', 'Email', 'Status', 'Created', ''],
+      (cursor) => api('GET', base + query(Object.assign({ cursor: cursor }, filters))),
+      (user) => {
+        const detail = h('tr', { class: 'subrow', hidden: true });
+        const show = (content) => {
+          detail.replaceChildren(h('td', { colspan: 5 }, content));
+          detail.hidden = false;
+        };
+        const userPath = base + '/' + user.id;
+
+        const sessions = h('button', { type: 'button', class: 'secondary' }, 'Sessions');
+        sessions.addEventListener('click', () => run(async () => {
+          const table = sessionsTable(
+            (cursor) => api('GET', userPath + '/sessions' + query({ cursor: cursor })),
+            (s) => api('DELETE', userPath + '/sessions/' + encodeURIComponent(s.session_id)));
+          const revokeAll = h('button', { type: 'button', class: 'danger' }, 'Revoke all');
+          revokeAll.addEventListener('click', () => run(async () => {
+            await api('DELETE', userPath + '/sessions');
+            toast('Sessions revoked');
+            await table.reload();
+          }));
+          await table.reload();
+          show([h('div', { class: 'row' }, h('strong', null, 'Sessions of ' + user.username), revokeAll), table.element]);
+        }));
+
+        const password = h('button', { type: 'button', class: 'secondary', disabled: !!user.disabled_at }, 'Reset password');
+        password.addEventListener('click', () => show(form({ class: 'inline' }, [
+          field('New password for ' + user.username, 'new_password', { type: 'password', required: true, autocomplete: 'new-password' }),
+          h('button', { type: 'submit' }, 'Set password'),
+        ], async (data) => {
+          const { etag } = await api('GET', userPath);
+          await api('PUT', userPath + '/password', { body: { new_password: data.get('new_password') }, etag: etag });
+          detail.hidden = true;
+          toast('Password reset, the sessions of ' + user.username + ' were revoked');
+        })));
+
+        const roles = h('button', { type: 'button', class: 'secondary' }, 'Roles');
+        roles.addEventListener('click', () => run(async () => {
+          const [{ data: clientRoles }, { data: granted, etag }] = await Promise.all([
+            api('GET', '/clients/' + client.id + '/roles'),
+            api('GET', userPath + '/roles'),
+          ]);
+          if (clientRoles.length === 0) {
+            show(h('p', { class: 'muted' }, 'The client has no roles yet, add them under Roles below.'));
+            return;
+          }
+          const names = new Set(granted.map((role) => role.name));
+          show(form({ class: 'inline' }, [
+            h('strong', null, 'Roles of ' + user.username),
+            clientRoles.map((role) => h('label', { class: 'check', title: role.description || null },
+              h('input', { type: 'checkbox', name: 'roles', value: role.name, checked: names.has(role.name) }), role.name)),
+            h('button', { type: 'submit' }, 'Save roles'),
+          ], async (data) => {
+            await api('PUT', userPath + '/roles', { body: { roles: data.getAll('roles') }, etag: etag });
+            detail.hidden = true;
+            toast('Roles of ' + user.username + ' saved, they apply from the next login or refresh');
+          }));
+        }));
+
+        const disable = h('button', { type: 'button', class: 'danger', disabled: !!user.disabled_at }, 'Disable');
+        disable.addEventListener('click', () => run(async () => {
+          if (!confirm('Disable ' + user.username + '? Their sessions are revoked and they cannot be enabled again.')) {
+            return;
+          }
+          const { etag } = await api('GET', userPath);
+          await api('PATCH', userPath, { body: { disabled: true }, etag: etag });
+          toast(user.username + ' disabled');
+          await list.reload();
+        }));
+
+        return [
+          h('tr', null,
+            h('td', null, user.username),
+            h('td', null, user.email),
+            h('td', null, user.disabled_at ? badge('disabled', 'danger') : badge('active', 'ok')),
+            h('td', null, formatDate(user.created_at)),
+            h('td', { class: 'actions' }, sessions, roles, password, disable)),
+          detail,
+        ];
+      });
+
+    list.reload().catch((err) => {
+      if (!err.ended) {
+        list.element.prepend(errorBox(err));
+      }
+    });
+
+    return panel('Users',
+      form({ class: 'inline' }, [
+        field('Username', 'username', { required: true, minlength: 3, maxlength: 50, autocomplete: 'off' }),
+        field('Email', 'email', { type: 'email', required: true, autocomplete: 'off' }),
+        field('Password', 'password', { type: 'password', required: true, autocomplete: 'new-password' }),
+        h('button', { type: 'submit' }, 'Create user'),
+      ], async (data, element) => {
+        await api('POST', base, {
+          body: { username: data.get('username'), email: data.get('email'), password: data.get('password') },
+        });
+        element.reset();
+        toast('User created');
+        await list.reload();
+      }),
+      form({ class: 'inline' }, [
+        field('Username contains', 'username', { type: 'search' }),
+        field('Email contains', 'email', { type: 'search' }),
+        select('Status', 'disabled', [['', 'Any'], ['false', 'Active'], ['true', 'Disabled']], ''),
+        h('button', { type: 'submit', class: 'secondary' }, 'Filter'),
+      ], async (data) => {
+        filters = { username: data.get('username'), email: data.get('email'), disabled: data.get('disabled') };
+        await list.reload();
+      }),
+      list.element);
+  }
+
+  // rolesPanel lists the roles of a client, which are granted to its users
+  // from the Users panel
+  function rolesPanel(client) {
+    const base = '/clients/' + client.id + '/roles';
+    const body = h('tbody');
+    const empty = h('p', { class: 'muted', hidden: true }, 'Nothing to show.');
+
+    async function load() {
+      const { data: roles } = await api('GET', base);
+      body.replaceChildren(...roles.map((role) => {
+        const remove = h('button', { type: 'button', class: 'danger' }, 'Delete');
+        remove.addEventListener('click', () => run(async () => {
+          if (!confirm('Delete the role ' + role.name + '? The users it was granted to lose it.')) {
+            return;
+          }
+          const { etag } = await api('GET', base + '/' + role.id);
+          await api('DELETE', base + '/' + role.id, { etag: etag });
+          toast('Role ' + role.name + ' deleted');
+          await load();
+        }));
+        return h('tr', null,
+          h('td', null, h('code', null, role.name)),
+          h('td', null, role.description || '—'),
+          h('td', null, formatDate(role.created_at)),
+          h('td', { class: 'actions' }, remove));
+      }));
+      empty.hidden = roles.length > 0;
+    }
+
+    const table = h('div', null,
+      h('table', null, h('thead', null, h('tr', null, ['Name', 'Description', 'Created', ''].map((header) => h('th', null, header)))), body),
+      empty);
+
+    load().catch((err) => {
+      if (!err.ended) {
+        table.prepend(errorBox(err));
+      }
+    });
+
+    return panel('Roles',
+      h('p', { class: 'muted' }, 'Tokens of client users list their roles in the roles claim.'),
+      form({ class: 'inline' }, [
+        field('Name', 'name', { required: true, maxlength: 50, autocomplete: 'off', pattern: '[A-Za-z0-9_.:\\-]+', title: 'Letters, digits and _ . : -' }),
+        field('Description', 'description', { maxlength: 255, autocomplete: 'off' }),
+        h('button', { type: 'submit' }, 'Create role'),
+      ], async (data, element) => {
+        await api('POST', base, { body: { name: data.get('name'), description: data.get('description') } });
+        element.reset();
+        toast('Role created');
+        await load();
+      }),
+      table);
+  }
+
+  // configPanel edits the settings of a client. Blank policy fields inherit
+  // the global password policy of the server.
+  function configPanel(client) {
+    const path = '/clients/' + client.id + '/config';
+    const body = h('div', null, h('p', { class: 'muted' }, 'Loading…'));
+
+    async function load() {
+      const { data: settings, etag } = await api('GET', path);
+      const policy = settings.password_policy || {};
+
+      body.replaceChildren(form({ class: 'stacked' }, [
+        h('p', { class: 'muted' }, 'Password policy fields left blank use the global policy of the server.'),
+        field('Default locale of error messages', 'default_locale', { value: settings.default_locale || '', placeholder: 'e.g. fr' }),
+        POLICY_NUMBERS.map(([name, label]) =>
+          field(label, name, { type: 'number', min: 0, value: policy[name] === undefined ? '' : policy[name] })),
+        POLICY_FLAGS.map(([name, label]) =>
+          select(label, name, [['', 'Global policy'], ['true', 'Yes'], ['false', 'No']], policy[name] === undefined ? '' : String(policy[name]))),
+        h('button', { type: 'submit' }, 'Save configuration'),
+      ], async (data) => {
+        const overrides = {};
+        for (const [name] of POLICY_NUMBERS) {
+          if (data.get(name) !== '') {
+            overrides[name] = Number(data.get(name));
+          }
+        }
+        for (const [name] of POLICY_FLAGS) {
+          if (data.get(name) !== '') {
+            overrides[name] = data.get(name) === 'true';
+          }
+        }
+
+        const update = { default_locale: data.get('default_locale').trim() };
+        if (Object.keys(overrides).length > 0) {
+          update.password_policy = overrides;
+        }
+        await api('PUT', path, { body: update, etag: etag });
+        toast('Configuration saved');
+        await load();
+      }));
+    }
+
+    load().catch((err) => {
+      if (!err.ended) {
+        body.replaceChildren(errorBox(err));
+      }
+    });
+
+    return panel('Configuration', body);
+  }
+
+  // sessionsTable lists sessions with a button revoking each one
+  function sessionsTable(load, revoke) {
+    const table = pagedTable(['Started', 'Last seen', 'Expires', 'IP address', 'User agent', ''], load, (s) => {
+      const button = h('button', { type: 'button', class: 'danger' }, 'Revoke');
+      button.addEventListener('click', () => run(async () => {
+        await revoke(s);
+        toast('Session revoked');
+        await table.reload();
+      }));
+      return h('tr', null,
+        h('td', null, formatDate(s.created_at), s.current && [' ', badge('this session')]),
+        h('td', null, formatDate(s.last_seen_at)),
+        h('td', null, formatDate(s.expires_at)),
+        h('td', null, s.ip_address),
+        h('td', null, s.user_agent),
+        h('td', { class: 'actions' }, button));
+    });
+    return table;
+  }
+
+  // Audit log
+
+  async function viewAudit(search) {
+    const root = page('Audit log');
+    const initial = new URLSearchParams(search || '');
+    let filters = Object.fromEntries(initial.entries());
+
+    const list = pagedTable(['Time', 'Actor', 'Action', 'Target', 'Client', 'Outcome', 'IP address'],
+      (cursor) => api('GET', '/audit-events' + query(Object.assign({ cursor: cursor }, filters))),
+      (event) => h('tr', null,
+        h('td', null, formatDate(event.occurred_at)),
+        h('td', null, event.actor_name || '—', h('div', { class: 'muted' }, event.actor_type)),
+        h('td', null, h('code', null, event.action), event.reason && h('div', { class: 'muted' }, event.reason)),
+        h('td', null, event.target_type ? event.target_type + ' ' + event.target_id : '—'),
+        h('td', null, event.client_id ? h('a', { href: '#/clients/' + event.client_id }, event.client_id) : '—'),
+        h('td', null, badge(event.outcome, event.outcome === 'success' ? 'ok' : 'danger')),
+        h('td', null, event.ip_address)));
+
+    const toISO = (value) => (value ? new Date(value).toISOString() : '');
+
+    root.append(panel(null,
+      form({ class: 'inline' }, [
+        field('Action', 'action', { value: initial.get('action') || '', placeholder: 'e.g. client.create' }),
+        select('Actor', 'actor_type', [['', 'Any'], ['admin_user', 'Admin user'], ['client_user', 'Client user'], ['anonymous', 'Anonymous']], initial.get('actor_type') || ''),
+        field('Client ID', 'client_id', { type: 'number', min: 1, value: initial.get('client_id') || '' }),
+        field('From', 'from', { type: 'datetime-local' }),
+        field('To', 'to', { type: 'datetime-local' }),
+        h('button', { type: 'submit', class: 'secondary' }, 'Filter'),
+      ], async (data) => {
+        filters = {
+          action: data.get('action').trim(),
+          actor_type: data.get('actor_type'),
+          client_id: data.get('client_id'),
+          from: toISO(data.get('from')),
+          to: toISO(data.get('to')),
+        };
+        await list.reload();
+      }),
+      list.element));
+
+    await list.reload();
+  }
+
+  // Account
+
+  async function viewAccount() {
+    const root = page('Account');
+    let { data: user, etag } = await api('GET', '/users/me');
+
+    const sessions = sessionsTable(
+      (cursor) => api('GET', '/sessions' + query({ cursor: cursor })),
+      async (s) => {
+        await api('DELETE', '/sessions/' + encodeURIComponent(s.session_id));
+        if (s.current) {
+          showLogin('You have logged out.');
+        }
+      });
+
+    const revokeOthers = h('button', { type: 'button', class: 'danger' }, 'Revoke all other sessions');
+    revokeOthers.addEventListener('click', () => run(async () => {
+      await api('DELETE', '/sessions');
+      toast('Other sessions revoked');
+      await sessions.reload();
+    }));
+
+    root.append(
+      panel('Profile', h('dl', { class: 'details' },
+        h('dt', null, 'Username'), h('dd', null, user.username),
+        h('dt', null, 'Email'), h('dd', null, user.email))),
+      panel('Change password',
+        form({ class: 'stacked' }, [
+          field('Current password', 'current_password', { type: 'password', required: true, autocomplete: 'current-password' }),
+          field('New password', 'new_password', { type: 'password', required: true, autocomplete: 'new-password' }),
+          h('button', { type: 'submit' }, 'Change password'),
+        ], async (data, element) => {
+          const result = await api('PUT', '/users/me/password', {
+            body: { current_password: data.get('current_password'), new_password: data.get('new_password') },
+            etag: etag,
+          });
+          etag = result.etag || etag;
+          element.reset();
+          toast('Password changed');
+          await sessions.reload();
+        })),
+      panel('Sessions', h('div', { class: 'row' }, revokeOthers), sessions.element));
+
+    await sessions.reload();
+  }
+
+  // Login and routing
+
+  function showLogin(message) {
+    session = null;
+    topbar.hidden = true;
+
+    main.replaceChildren(h('div', { class: 'panel login' },
+      h('h1', null, 'SimpleJWT Console'),
+      message && h('div', { class: 'notice' }, message),
+      form({ class: 'stacked' }, [
+        field('Username', 'username', { required: true, autocomplete: 'username', autofocus: true }),
+        field('Password', 'password', { type: 'password', required: true, autocomplete: 'current-password' }),
+        h('button', { type: 'submit' }, 'Log in'),
+      ], async (data) => {
+        const { data: started } = await request('POST', SESSION, {
+          body: { username: data.get('username'), password: data.get('password') },
+        });
+        session = started;
+        start();
+      })));
+  }
+
+  const routes = [
+    [/^#\/clients$/, 'clients', viewClients],
+    [/^#\/clients\/(\d+)$/, 'clients', viewClient],
+    [/^#\/audit(?:\?(.*))?$/, 'audit', viewAudit],
+    [/^#\/account$/, 'account', viewAccount],
+  ];
+
+  async function route() {
+    if (!session) {
+      return;
+    }
+
+    const hash = location.hash || '#/clients';
+    for (const [pattern, section, view] of routes) {
+      const match = hash.match(pattern);
+      if (!match) {
+        continue;
+      }
+
+      topbar.querySelectorAll('nav a').forEach((link) => {
+        link.classList.toggle('active', link.dataset.section === section);
+      });
+      try {
+        await view(...match.slice(1));
+      } catch (err) {
+        if (!err.ended) {
+          main.replaceChildren(errorBox(err));
+        }
+      }
+      return;
+    }
+
+    location.hash = '#/clients';
+  }
+
+  function start() {
+    topbar.hidden = false;
+    currentUser.textContent = session.user.username;
+    route();
+  }
+
+  document.getElementById('logout').addEventListener('click', () => run(async () => {
+    try {
+      await request('DELETE', SESSION);
+    } finally {
+      showLogin('You have logged out.');
+    }
+  }));
+
+  window.addEventListener('hashchange', route);
+
+  request('GET', SESSION).then((result) => {
+    session = result.data;
+    start();
+  }, (err) => {
+    if (err instanceof ApiError) {
+      showLogin();
+    } else {
+      main.replaceChildren(errorBox(err));
+    }
+  });
+})();
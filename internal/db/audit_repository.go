@@ -0,0 +1,67 @@
+package db
+
+import (
+	"github.com/Kantha2004/SimpleJWT/internal/models"
+)
+
+const (
+	DEFAULT_AUDIT_QUERY_LIMIT = 100
+	MAX_AUDIT_QUERY_LIMIT     = 1000
+)
+
+var auditListSpec = ListSpec[models.AuditEvent]{
+	Sorts: []SortField[models.AuditEvent]{
+		{Name: "occurred_at", Column: "occurred_at", Value: func(e *models.AuditEvent) any { return e.OccurredAt }},
+	},
+	DefaultSort:  "-occurred_at",
+	DefaultLimit: DEFAULT_AUDIT_QUERY_LIMIT,
+	MaxLimit:     MAX_AUDIT_QUERY_LIMIT,
+	ID:           func(e *models.AuditEvent) uint { return e.ID },
+}
+
+// AuditRepository only appends and reads audit events; updates and deletes
+// are rejected by the model hooks
+type AuditRepository struct {
+	db *Database
+}
+
+func NewAuditRepository(db *Database) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+func (ar *AuditRepository) CreateAuditEvent(event *models.AuditEvent) error {
+	db, span := ar.db.startSpan("AuditRepository.CreateAuditEvent")
+	defer span.End()
+
+	return db.DB.Create(event).Error
+}
+
+// ListAuditEventsForOwner returns one page of the events visible to an
+// admin user that match the query, newest first by default
+func (ar *AuditRepository) ListAuditEventsForOwner(ownerUserID uint, query models.AuditEventQuery) (*Page[models.AuditEvent], error) {
+	db, span := ar.db.startSpan("AuditRepository.ListAuditEventsForOwner")
+	defer span.End()
+
+	tx := db.DB.Model(&models.AuditEvent{}).Where("owner_user_id = ?", ownerUserID)
+
+	if query.From != nil {
+		tx = tx.Where("occurred_at >= ?", *query.From)
+	}
+	if query.To != nil {
+		tx = tx.Where("occurred_at <= ?", *query.To)
+	}
+	if query.ActorID != nil {
+		tx = tx.Where("actor_id = ?", *query.ActorID)
+	}
+	if query.ActorType != "" {
+		tx = tx.Where("actor_type = ?", query.ActorType)
+	}
+	if query.Action != "" {
+		tx = tx.Where("action = ?", query.Action)
+	}
+	if query.ClientID != nil {
+		tx = tx.Where("client_id = ?", *query.ClientID)
+	}
+
+	return Paginate(tx, auditListSpec, query.ListParams)
+}
@@ -0,0 +1,78 @@
+package db
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/Kantha2004/SimpleJWT/internal/models"
+	"github.com/Kantha2004/SimpleJWT/internal/tracing"
+	"gorm.io/gorm"
+)
+
+type ClientConfigRepository struct {
+	db         *Database
+	schemaName string
+}
+
+func NewClientConfigRepository(db *Database, schemaName string) *ClientConfigRepository {
+	return &ClientConfigRepository{db: db, schemaName: schemaName}
+}
+
+func (ccr *ClientConfigRepository) table(db *Database) *gorm.DB {
+	return db.TableWithSchema(ccr.schemaName, CLIENT_CONFIG_TABLE)
+}
+
+// GetClientConfig returns the client's configuration row, or nil if none has been stored
+func (ccr *ClientConfigRepository) GetClientConfig() (*models.ClientConfig, error) {
+	db, span := ccr.db.startSpan("ClientConfigRepository.GetClientConfig", tracing.TenantSchemaKey.String(ccr.schemaName))
+	defer span.End()
+
+	var config models.ClientConfig
+	result := ccr.table(db).Order("id").First(&config)
+
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &config, nil
+}
+
+// GetClientSettings decodes the settings of the client's configuration.
+// A client without a configuration gets empty settings.
+func (ccr *ClientConfigRepository) GetClientSettings() (*models.ClientSettings, error) {
+	config, err := ccr.GetClientConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	var settings models.ClientSettings
+	if config == nil || len(config.Settings) == 0 {
+		return &settings, nil
+	}
+
+	if err := json.Unmarshal(config.Settings, &settings); err != nil {
+		return nil, err
+	}
+
+	return &settings, nil
+}
+
+// SaveClientConfig stores the client's configuration: a configuration read
+// with GetClientConfig is updated and its version incremented, a new one is
+// created at version 1. Returns ErrVersionConflict when the configuration
+// changed since it was read.
+func (ccr *ClientConfigRepository) SaveClientConfig(config *models.ClientConfig) error {
+	db, span := ccr.db.startSpan("ClientConfigRepository.SaveClientConfig", tracing.TenantSchemaKey.String(ccr.schemaName))
+	defer span.End()
+
+	if config.ID == 0 {
+		config.Version = 1
+		return ccr.table(db).Create(config).Error
+	}
+
+	return saveVersioned(ccr.table(db), config, &config.Version)
+}
@@ -1,35 +1,71 @@
 package db
 
 import (
+	"context"
 	"errors"
 
 	"github.com/Kantha2004/SimpleJWT/internal/models"
 	"gorm.io/gorm"
 )
 
-type ClientRepository struct {
+// ClientListSpec lists the sortable fields of clients
+var ClientListSpec = ListSpec[models.Client]{
+	Sorts: []SortField[models.Client]{
+		{Name: "id", Column: "id", Value: func(c *models.Client) any { return c.ID }},
+		{Name: "client_name", Column: "client_name", Value: func(c *models.Client) any { return c.ClientName }},
+		{Name: "created_at", Column: "created_at", Value: func(c *models.Client) any { return c.CreatedAt }},
+	},
+	DefaultSort:  "id",
+	DefaultLimit: DEFAULT_PAGE_LIMIT,
+	MaxLimit:     MAX_PAGE_LIMIT,
+	ID:           func(c *models.Client) uint { return c.ID },
+}
+
+type SQLClientRepository struct {
 	db *Database
 }
 
-func NewClientRepository(db *Database) *ClientRepository {
-	return &ClientRepository{db: db}
+func NewClientRepository(db *Database) *SQLClientRepository {
+	return &SQLClientRepository{db: db}
 }
 
-func (cr *ClientRepository) CreateClient(client *models.Client) (string, error) {
-	result := cr.db.DB.Create(client)
+func (cr *SQLClientRepository) WithContext(ctx context.Context) ClientRepository {
+	return NewClientRepository(cr.db.WithContext(ctx))
+}
+
+func (cr *SQLClientRepository) CreateClient(client *models.Client) (string, error) {
+	db, span := cr.db.startSpan("ClientRepository.CreateClient")
+	defer span.End()
+
+	result := db.DB.Create(client)
 	return client.ClientSecret, result.Error
 }
 
-func (cr *ClientRepository) GetClientId(id uint) (*models.Client, error) {
+func (cr *SQLClientRepository) GetClientId(id uint) (*models.Client, error) {
+	db, span := cr.db.startSpan("ClientRepository.GetClientId")
+	defer span.End()
+
 	var client models.Client
-	result := cr.db.DB.First(&client, id)
-	return &client, result.Error
+	result := db.DB.First(&client, id)
+
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &client, nil
 }
 
-func (cr *ClientRepository) GetClientByNameForUser(clientName string, userID uint) (*models.Client, error) {
+func (cr *SQLClientRepository) GetClientByNameForUser(clientName string, userID uint) (*models.Client, error) {
+	db, span := cr.db.startSpan("ClientRepository.GetClientByNameForUser")
+	defer span.End()
+
 	var client models.Client
 
-	result := cr.db.DB.Where("client_name = ? AND user_id = ?", clientName, userID).First(&client)
+	result := db.DB.Where("client_name = ? AND user_id = ?", clientName, userID).First(&client)
 
 	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 		return nil, nil
@@ -42,10 +78,13 @@ func (cr *ClientRepository) GetClientByNameForUser(clientName string, userID uin
 	return &client, nil
 }
 
-func (cr *ClientRepository) GetClientByUserId(userID uint) (*models.Client, error) {
+func (cr *SQLClientRepository) GetClientByUserId(userID uint) (*models.Client, error) {
+	db, span := cr.db.startSpan("ClientRepository.GetClientByUserId")
+	defer span.End()
+
 	var client models.Client
 
-	result := cr.db.DB.Where("user_id = ?", userID).First(&client)
+	result := db.DB.Where("user_id = ?", userID).First(&client)
 
 	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 		return nil, nil
@@ -58,10 +97,13 @@ func (cr *ClientRepository) GetClientByUserId(userID uint) (*models.Client, erro
 	return &client, nil
 }
 
-func (cr *ClientRepository) GetAllClientsByUserId(userID uint) ([]models.Client, error) {
+func (cr *SQLClientRepository) GetAllClientsByUserId(userID uint) ([]models.Client, error) {
+	db, span := cr.db.startSpan("ClientRepository.GetAllClientsByUserId")
+	defer span.End()
+
 	var clients []models.Client
 
-	result := cr.db.DB.Where("user_id = ?", userID).Find(&clients)
+	result := db.DB.Where("user_id = ?", userID).Find(&clients)
 
 	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 		return nil, nil
@@ -73,3 +115,48 @@ func (cr *ClientRepository) GetAllClientsByUserId(userID uint) ([]models.Client,
 
 	return clients, nil
 }
+
+// ListClientsByUserId returns one page of the clients of a user that match
+// the query
+func (cr *SQLClientRepository) ListClientsByUserId(userID uint, query models.ClientListQuery) (*Page[models.Client], error) {
+	db, span := cr.db.startSpan("ClientRepository.ListClientsByUserId")
+	defer span.End()
+
+	tx := db.DB.Model(&models.Client{}).Where("user_id = ?", userID)
+
+	if query.Name != "" {
+		tx = tx.Where("LOWER(client_name) LIKE ? ESCAPE '\\'", containsPattern(query.Name))
+	}
+	if query.Suspended != nil {
+		if *query.Suspended {
+			tx = tx.Where("suspended_at IS NOT NULL")
+		} else {
+			tx = tx.Where("suspended_at IS NULL")
+		}
+	}
+
+	return Paginate(tx, ClientListSpec, query.ListParams)
+}
+
+// GetAllClients returns the clients of every user ordered by ID
+func (cr *SQLClientRepository) GetAllClients() ([]models.Client, error) {
+	db, span := cr.db.startSpan("ClientRepository.GetAllClients")
+	defer span.End()
+
+	var clients []models.Client
+
+	result := db.DB.Order("id").Find(&clients)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return clients, nil
+}
+
+func (cr *SQLClientRepository) UpdateClient(client *models.Client) error {
+	db, span := cr.db.startSpan("ClientRepository.UpdateClient")
+	defer span.End()
+
+	return saveVersioned(db.DB, client, &client.Version)
+}
@@ -1,28 +1,61 @@
 package db
 
 import (
+	"context"
 	"errors"
 
 	"github.com/Kantha2004/SimpleJWT/internal/models"
+	"github.com/Kantha2004/SimpleJWT/internal/tracing"
 	"gorm.io/gorm"
 )
 
-type ClientUserRepository struct {
-	db *gorm.DB
+// ClientUserListSpec lists the sortable fields of client users
+var ClientUserListSpec = ListSpec[models.ClientUser]{
+	Sorts: []SortField[models.ClientUser]{
+		{Name: "id", Column: "id", Value: func(u *models.ClientUser) any { return u.ID }},
+		{Name: "username", Column: "username", Value: func(u *models.ClientUser) any { return u.Username }},
+		{Name: "email", Column: "email", Value: func(u *models.ClientUser) any { return u.Email }},
+		{Name: "created_at", Column: "created_at", Value: func(u *models.ClientUser) any { return u.CreatedAt }},
+	},
+	DefaultSort:  "id",
+	DefaultLimit: DEFAULT_PAGE_LIMIT,
+	MaxLimit:     MAX_PAGE_LIMIT,
+	ID:           func(u *models.ClientUser) uint { return u.ID },
 }
 
-func NewClientUserRepository(db *Database, schemaName string) *ClientUserRepository {
-	return &ClientUserRepository{db: db.TableWithSchema(schemaName, CLIENT_USER_TABLE)}
+type SQLClientUserRepository struct {
+	db         *Database
+	schemaName string
 }
 
-func (cur *ClientUserRepository) CreateClientUser(user *models.ClientUser) (*models.ClientUser, error) {
-	result := cur.db.Create(user)
+func NewClientUserRepository(db *Database, schemaName string) *SQLClientUserRepository {
+	return &SQLClientUserRepository{db: db, schemaName: schemaName}
+}
+
+// table starts a new query on the client's users table. A fresh statement is
+// needed per query so conditions of earlier calls do not leak into later ones.
+func (cur *SQLClientUserRepository) WithContext(ctx context.Context) ClientUserRepository {
+	return NewClientUserRepository(cur.db.WithContext(ctx), cur.schemaName)
+}
+
+func (cur *SQLClientUserRepository) table(db *Database) *gorm.DB {
+	return db.TableWithSchema(cur.schemaName, CLIENT_USER_TABLE)
+}
+
+func (cur *SQLClientUserRepository) CreateClientUser(user *models.ClientUser) (*models.ClientUser, error) {
+	db, span := cur.db.startSpan("ClientUserRepository.CreateClientUser", tracing.TenantSchemaKey.String(cur.schemaName))
+	defer span.End()
+
+	result := cur.table(db).Create(user)
 	return user, result.Error
 }
 
-func (cur *ClientUserRepository) GetClientUserByID(id uint) (*models.ClientUser, error) {
+func (cur *SQLClientUserRepository) GetClientUserByID(id uint) (*models.ClientUser, error) {
+	db, span := cur.db.startSpan("ClientUserRepository.GetClientUserByID", tracing.TenantSchemaKey.String(cur.schemaName))
+	defer span.End()
+
 	var user models.ClientUser
-	result := cur.db.Find(&user, id)
+	result := cur.table(db).First(&user, id)
 
 	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 		return nil, nil
@@ -35,15 +68,45 @@ func (cur *ClientUserRepository) GetClientUserByID(id uint) (*models.ClientUser,
 	return &user, nil
 }
 
-func (cur *ClientUserRepository) GetClientAllUser() (*[]models.ClientUser, error) {
+func (cur *SQLClientUserRepository) GetClientAllUser() (*[]models.ClientUser, error) {
+	db, span := cur.db.startSpan("ClientUserRepository.GetClientAllUser", tracing.TenantSchemaKey.String(cur.schemaName))
+	defer span.End()
+
 	var users []models.ClientUser
-	result := cur.db.Find(&users)
+	result := cur.table(db).Find(&users)
 	return &users, result.Error
 }
 
-func (cur *ClientUserRepository) GetClientUserByEmail(email string) (*models.ClientUser, error) {
+// ListClientUsers returns one page of the users that match the query
+func (cur *SQLClientUserRepository) ListClientUsers(query models.ClientUserListQuery) (*Page[models.ClientUser], error) {
+	db, span := cur.db.startSpan("ClientUserRepository.ListClientUsers", tracing.TenantSchemaKey.String(cur.schemaName))
+	defer span.End()
+
+	tx := cur.table(db)
+
+	if query.Username != "" {
+		tx = tx.Where("LOWER(username) LIKE ? ESCAPE '\\'", containsPattern(query.Username))
+	}
+	if query.Email != "" {
+		tx = tx.Where("LOWER(email) LIKE ? ESCAPE '\\'", containsPattern(query.Email))
+	}
+	if query.Disabled != nil {
+		if *query.Disabled {
+			tx = tx.Where("disabled_at IS NOT NULL")
+		} else {
+			tx = tx.Where("disabled_at IS NULL")
+		}
+	}
+
+	return Paginate(tx, ClientUserListSpec, query.ListParams)
+}
+
+func (cur *SQLClientUserRepository) GetClientUserByEmail(email string) (*models.ClientUser, error) {
+	db, span := cur.db.startSpan("ClientUserRepository.GetClientUserByEmail", tracing.TenantSchemaKey.String(cur.schemaName))
+	defer span.End()
+
 	var user models.ClientUser
-	result := cur.db.Where("email = ?", email).First(&user)
+	result := cur.table(db).Where("email = ?", email).First(&user)
 
 	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 		return nil, nil
@@ -56,9 +119,12 @@ func (cur *ClientUserRepository) GetClientUserByEmail(email string) (*models.Cli
 	return &user, nil
 }
 
-func (cur *ClientUserRepository) GetClientUserByUsername(username string) (*models.ClientUser, error) {
+func (cur *SQLClientUserRepository) GetClientUserByUsername(username string) (*models.ClientUser, error) {
+	db, span := cur.db.startSpan("ClientUserRepository.GetClientUserByUsername", tracing.TenantSchemaKey.String(cur.schemaName))
+	defer span.End()
+
 	var user models.ClientUser
-	result := cur.db.Where("username = ?", username).First(&user)
+	result := cur.table(db).Where("username = ?", username).First(&user)
 
 	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 		return nil, nil
@@ -71,14 +137,19 @@ func (cur *ClientUserRepository) GetClientUserByUsername(username string) (*mode
 	return &user, nil
 }
 
-func (cur *ClientUserRepository) UpdateClientUser(user *models.ClientUser) error {
-	result := cur.db.Save(user)
-	return result.Error
+func (cur *SQLClientUserRepository) UpdateClientUser(user *models.ClientUser) error {
+	db, span := cur.db.startSpan("ClientUserRepository.UpdateClientUser", tracing.TenantSchemaKey.String(cur.schemaName))
+	defer span.End()
+
+	return saveVersioned(cur.table(db), user, &user.Version)
 }
 
-func (cur *ClientUserRepository) ClientUserExists(username, email string) (bool, error) {
+func (cur *SQLClientUserRepository) ClientUserExists(username, email string) (bool, error) {
+	db, span := cur.db.startSpan("ClientUserRepository.ClientUserExists", tracing.TenantSchemaKey.String(cur.schemaName))
+	defer span.End()
+
 	var count int64
-	result := cur.db.Model(&models.ClientUser{}).
+	result := cur.table(db).Model(&models.ClientUser{}).
 		Where("username = ? AND email = ?", username, email).Count(&count)
 	if result.Error != nil {
 		return false, result.Error
@@ -87,9 +158,12 @@ func (cur *ClientUserRepository) ClientUserExists(username, email string) (bool,
 	return count > 0, nil
 }
 
-func (cur *ClientUserRepository) ClientUserEmailExists(email string) (bool, error) {
+func (cur *SQLClientUserRepository) ClientUserEmailExists(email string) (bool, error) {
+	db, span := cur.db.startSpan("ClientUserRepository.ClientUserEmailExists", tracing.TenantSchemaKey.String(cur.schemaName))
+	defer span.End()
+
 	var count int64
-	result := cur.db.Model(&models.ClientUser{}).Where("email = ?", email).Count(&count)
+	result := cur.table(db).Model(&models.ClientUser{}).Where("email = ?", email).Count(&count)
 
 	if result.Error != nil {
 		return false, result.Error
@@ -98,9 +172,12 @@ func (cur *ClientUserRepository) ClientUserEmailExists(email string) (bool, erro
 	return count > 0, nil
 }
 
-func (cur *ClientUserRepository) ClientUserNameExists(username string) (bool, error) {
+func (cur *SQLClientUserRepository) ClientUserNameExists(username string) (bool, error) {
+	db, span := cur.db.startSpan("ClientUserRepository.ClientUserNameExists", tracing.TenantSchemaKey.String(cur.schemaName))
+	defer span.End()
+
 	var count int64
-	result := cur.db.Model(&models.ClientUser{}).Where("username = ?", username).Count(&count)
+	result := cur.table(db).Model(&models.ClientUser{}).Where("username = ?", username).Count(&count)
 
 	if result.Error != nil {
 		return false, result.Error
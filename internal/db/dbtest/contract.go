@@ -0,0 +1,854 @@
+// Package dbtest holds the contract every repository implementation must
+// satisfy. Backends run it from their own tests with a constructor that
+// returns an empty repository, for example:
+//
+//	dbtest.RunUserRepositoryContract(t, func(t *testing.T) db.UserRepository {
+//		return memory.NewUserRepository()
+//	})
+package dbtest
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Kantha2004/SimpleJWT/internal/db"
+	"github.com/Kantha2004/SimpleJWT/internal/models"
+)
+
+// RunUserRepositoryContract checks a UserRepository. newRepo must return an
+// empty repository on every call.
+func RunUserRepositoryContract(t *testing.T, newRepo func(t *testing.T) db.UserRepository) {
+	t.Run("CreateAndGet", func(t *testing.T) {
+		repo := newRepo(t)
+
+		id, err := repo.CreateUser(newUser("alice"))
+		if err != nil {
+			t.Fatalf("CreateUser: %v", err)
+		}
+		if id == 0 {
+			t.Fatal("CreateUser returned a zero ID")
+		}
+
+		byID, err := repo.GetUserByID(id)
+		assertUser(t, "GetUserByID", byID, err, "alice")
+
+		byEmail, err := repo.GetUserByEmail("alice@example.com")
+		assertUser(t, "GetUserByEmail", byEmail, err, "alice")
+
+		byName, err := repo.GetUserByUsername("alice")
+		assertUser(t, "GetUserByUsername", byName, err, "alice")
+	})
+
+	t.Run("MissingReturnsNil", func(t *testing.T) {
+		repo := newRepo(t)
+
+		byID, err := repo.GetUserByID(404)
+		assertNoUser(t, "GetUserByID", byID, err)
+
+		byEmail, err := repo.GetUserByEmail("nobody@example.com")
+		assertNoUser(t, "GetUserByEmail", byEmail, err)
+
+		byName, err := repo.GetUserByUsername("nobody")
+		assertNoUser(t, "GetUserByUsername", byName, err)
+	})
+
+	t.Run("UniqueUsernameAndEmail", func(t *testing.T) {
+		repo := newRepo(t)
+
+		if _, err := repo.CreateUser(newUser("alice")); err != nil {
+			t.Fatalf("CreateUser: %v", err)
+		}
+
+		sameName := newUser("alice")
+		sameName.Email = "other@example.com"
+		if _, err := repo.CreateUser(sameName); err == nil {
+			t.Error("CreateUser accepted a duplicate username")
+		}
+
+		sameEmail := newUser("bob")
+		sameEmail.Email = "alice@example.com"
+		if _, err := repo.CreateUser(sameEmail); err == nil {
+			t.Error("CreateUser accepted a duplicate email")
+		}
+	})
+
+	t.Run("Exists", func(t *testing.T) {
+		repo := newRepo(t)
+
+		if _, err := repo.CreateUser(newUser("alice")); err != nil {
+			t.Fatalf("CreateUser: %v", err)
+		}
+
+		assertExists(t, "UserExists", true)(repo.UserExists("alice", "alice@example.com"))
+		assertExists(t, "UserExists with another email", false)(repo.UserExists("alice", "bob@example.com"))
+		assertExists(t, "EmailExists", true)(repo.EmailExists("alice@example.com"))
+		assertExists(t, "EmailExists for unknown email", false)(repo.EmailExists("bob@example.com"))
+		assertExists(t, "UserNameExists", true)(repo.UserNameExists("alice"))
+		assertExists(t, "UserNameExists for unknown username", false)(repo.UserNameExists("bob"))
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		repo := newRepo(t)
+
+		id, err := repo.CreateUser(newUser("alice"))
+		if err != nil {
+			t.Fatalf("CreateUser: %v", err)
+		}
+
+		user, err := repo.GetUserByID(id)
+		assertUser(t, "GetUserByID", user, err, "alice")
+
+		user.PasswordHash = "rehashed"
+		if err := repo.UpdateUser(user); err != nil {
+			t.Fatalf("UpdateUser: %v", err)
+		}
+
+		updated, err := repo.GetUserByID(id)
+		assertUser(t, "GetUserByID after update", updated, err, "alice")
+		if updated.PasswordHash != "rehashed" {
+			t.Errorf("UpdateUser did not persist the password hash, got %q", updated.PasswordHash)
+		}
+		if updated.Version != 2 || user.Version != 2 {
+			t.Errorf("UpdateUser left versions %d stored and %d in the copy, want 2", updated.Version, user.Version)
+		}
+	})
+
+	t.Run("UpdateStaleVersion", func(t *testing.T) {
+		repo := newRepo(t)
+
+		id, err := repo.CreateUser(newUser("alice"))
+		if err != nil {
+			t.Fatalf("CreateUser: %v", err)
+		}
+
+		first, err := repo.GetUserByID(id)
+		assertUser(t, "GetUserByID", first, err, "alice")
+		second, err := repo.GetUserByID(id)
+		assertUser(t, "GetUserByID", second, err, "alice")
+		if first.Version != 1 {
+			t.Errorf("CreateUser stored version %d, want 1", first.Version)
+		}
+
+		first.PasswordHash = "first"
+		if err := repo.UpdateUser(first); err != nil {
+			t.Fatalf("UpdateUser: %v", err)
+		}
+
+		second.PasswordHash = "second"
+		if err := repo.UpdateUser(second); !errors.Is(err, db.ErrVersionConflict) {
+			t.Fatalf("UpdateUser of a stale copy = %v, want ErrVersionConflict", err)
+		}
+		if second.Version != 1 {
+			t.Errorf("failed UpdateUser changed the version of the copy to %d", second.Version)
+		}
+
+		stored, err := repo.GetUserByID(id)
+		assertUser(t, "GetUserByID after conflict", stored, err, "alice")
+		if stored.PasswordHash != "first" {
+			t.Errorf("stale UpdateUser overwrote the password hash with %q", stored.PasswordHash)
+		}
+	})
+}
+
+// RunClientRepositoryContract checks a ClientRepository. newRepo must return
+// an empty repository on every call. The clients it creates belong to the
+// admin users returned by newOwner, which backends with foreign keys must
+// persist first.
+func RunClientRepositoryContract(t *testing.T, newRepo func(t *testing.T) db.ClientRepository, newOwner func(t *testing.T) uint) {
+	t.Run("CreateGeneratesSecret", func(t *testing.T) {
+		repo := newRepo(t)
+		owner := newOwner(t)
+
+		client := &models.Client{ClientName: "acme", UserID: owner, SchemaName: "acme"}
+		secret, err := repo.CreateClient(client)
+		if err != nil {
+			t.Fatalf("CreateClient: %v", err)
+		}
+		if secret == "" || secret != client.ClientSecret {
+			t.Errorf("CreateClient returned secret %q, client has %q", secret, client.ClientSecret)
+		}
+		if client.ID == 0 {
+			t.Fatal("CreateClient did not assign an ID")
+		}
+
+		stored, err := repo.GetClientId(client.ID)
+		if err != nil || stored == nil {
+			t.Fatalf("GetClientId = %v, %v", stored, err)
+		}
+		if stored.ClientName != "acme" || stored.ClientSecret != secret || stored.UserID != owner {
+			t.Errorf("GetClientId returned %+v", stored)
+		}
+	})
+
+	t.Run("UniqueClientName", func(t *testing.T) {
+		repo := newRepo(t)
+		owner, other := newOwner(t), newOwner(t)
+
+		if _, err := repo.CreateClient(&models.Client{ClientName: "acme", UserID: owner, SchemaName: "acme"}); err != nil {
+			t.Fatalf("CreateClient: %v", err)
+		}
+		if _, err := repo.CreateClient(&models.Client{ClientName: "acme", UserID: other, SchemaName: "acme_2"}); err == nil {
+			t.Error("CreateClient accepted a duplicate client name")
+		}
+	})
+
+	t.Run("MissingReturnsNil", func(t *testing.T) {
+		repo := newRepo(t)
+		owner := newOwner(t)
+
+		if client, err := repo.GetClientId(404); err != nil || client != nil {
+			t.Errorf("GetClientId = %v, %v, want nil, nil", client, err)
+		}
+		if client, err := repo.GetClientByNameForUser("acme", owner); err != nil || client != nil {
+			t.Errorf("GetClientByNameForUser = %v, %v, want nil, nil", client, err)
+		}
+		if client, err := repo.GetClientByUserId(owner); err != nil || client != nil {
+			t.Errorf("GetClientByUserId = %v, %v, want nil, nil", client, err)
+		}
+		if clients, err := repo.GetAllClientsByUserId(owner); err != nil || len(clients) != 0 {
+			t.Errorf("GetAllClientsByUserId = %v, %v, want no clients", clients, err)
+		}
+	})
+
+	t.Run("ScopedToOwner", func(t *testing.T) {
+		repo := newRepo(t)
+		owner, other := newOwner(t), newOwner(t)
+
+		for _, name := range []string{"first", "second"} {
+			if _, err := repo.CreateClient(&models.Client{ClientName: name, UserID: owner, SchemaName: name}); err != nil {
+				t.Fatalf("CreateClient: %v", err)
+			}
+		}
+		if _, err := repo.CreateClient(&models.Client{ClientName: "third", UserID: other, SchemaName: "third"}); err != nil {
+			t.Fatalf("CreateClient: %v", err)
+		}
+
+		if client, err := repo.GetClientByNameForUser("first", owner); err != nil || client == nil || client.ClientName != "first" {
+			t.Errorf("GetClientByNameForUser = %v, %v", client, err)
+		}
+		if client, err := repo.GetClientByNameForUser("third", owner); err != nil || client != nil {
+			t.Errorf("GetClientByNameForUser returned another owner's client: %v, %v", client, err)
+		}
+		if client, err := repo.GetClientByUserId(owner); err != nil || client == nil || client.ClientName != "first" {
+			t.Errorf("GetClientByUserId = %v, %v, want the first client", client, err)
+		}
+
+		clients, err := repo.GetAllClientsByUserId(owner)
+		if err != nil {
+			t.Fatalf("GetAllClientsByUserId: %v", err)
+		}
+		if len(clients) != 2 || clients[0].ClientName != "first" || clients[1].ClientName != "second" {
+			t.Errorf("GetAllClientsByUserId returned %+v", clients)
+		}
+	})
+
+	t.Run("ListPaged", func(t *testing.T) {
+		repo := newRepo(t)
+		owner, other := newOwner(t), newOwner(t)
+
+		for _, name := range []string{"delta", "alpha", "charlie", "bravo"} {
+			if _, err := repo.CreateClient(&models.Client{ClientName: name, UserID: owner, SchemaName: name}); err != nil {
+				t.Fatalf("CreateClient: %v", err)
+			}
+		}
+		if _, err := repo.CreateClient(&models.Client{ClientName: "echo", UserID: other, SchemaName: "echo"}); err != nil {
+			t.Fatalf("CreateClient: %v", err)
+		}
+
+		query := models.ClientListQuery{ListParams: models.ListParams{Limit: 3, Sort: "client_name"}}
+		first, err := repo.ListClientsByUserId(owner, query)
+		if err != nil {
+			t.Fatalf("ListClientsByUserId: %v", err)
+		}
+		assertClientNames(t, "first page", first.Items, "alpha", "bravo", "charlie")
+		if first.Total != 4 || first.NextCursor == "" {
+			t.Fatalf("first page has total %d and cursor %q, want 4 and a cursor", first.Total, first.NextCursor)
+		}
+
+		query.Cursor = first.NextCursor
+		second, err := repo.ListClientsByUserId(owner, query)
+		if err != nil {
+			t.Fatalf("ListClientsByUserId: %v", err)
+		}
+		assertClientNames(t, "second page", second.Items, "delta")
+		if second.NextCursor != "" {
+			t.Errorf("last page has cursor %q", second.NextCursor)
+		}
+
+		query = models.ClientListQuery{ListParams: models.ListParams{Page: 2, Limit: 2, Sort: "-client_name"}}
+		byNumber, err := repo.ListClientsByUserId(owner, query)
+		if err != nil {
+			t.Fatalf("ListClientsByUserId: %v", err)
+		}
+		assertClientNames(t, "page 2", byNumber.Items, "bravo", "alpha")
+
+		filtered, err := repo.ListClientsByUserId(owner, models.ClientListQuery{Name: "HAR"})
+		if err != nil {
+			t.Fatalf("ListClientsByUserId: %v", err)
+		}
+		assertClientNames(t, "name filter", filtered.Items, "charlie")
+
+		var paramErr *db.ListParamError
+		if _, err := repo.ListClientsByUserId(owner, models.ClientListQuery{ListParams: models.ListParams{Sort: "secret"}}); !errors.As(err, &paramErr) {
+			t.Errorf("ListClientsByUserId accepted an unknown sort field, got %v", err)
+		}
+	})
+
+	t.Run("CursorWalksDuplicateValues", func(t *testing.T) {
+		repo := newRepo(t)
+		owner := newOwner(t)
+
+		// Clients created in the same second share their creation time, so
+		// only the ID tells them apart
+		created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		times := []time.Time{created, created.Add(time.Second), created, created, created.Add(time.Second), created}
+		ids := make(map[time.Time][]uint)
+		for i, at := range times {
+			client := &models.Client{ClientName: fmt.Sprintf("client-%d", i), UserID: owner, SchemaName: fmt.Sprintf("client_%d", i)}
+			client.CreatedAt = at
+			if _, err := repo.CreateClient(client); err != nil {
+				t.Fatalf("CreateClient: %v", err)
+			}
+			ids[at] = append(ids[at], client.ID)
+		}
+		ascending := append(slices.Clone(ids[created]), ids[created.Add(time.Second)]...)
+		descending := slices.Clone(ascending)
+		slices.Reverse(descending)
+
+		for sort, want := range map[string][]uint{"created_at": ascending, "-created_at": descending} {
+			var got []uint
+			query := models.ClientListQuery{ListParams: models.ListParams{Limit: 2, Sort: sort}}
+			for pages := 0; ; pages++ {
+				if pages == len(times) {
+					t.Fatalf("sort %s: cursor did not reach the last page after %d pages", sort, pages)
+				}
+				page, err := repo.ListClientsByUserId(owner, query)
+				if err != nil {
+					t.Fatalf("ListClientsByUserId with sort %s: %v", sort, err)
+				}
+				if page.Total != int64(len(times)) {
+					t.Errorf("sort %s: page has total %d, want %d", sort, page.Total, len(times))
+				}
+				for _, client := range page.Items {
+					got = append(got, client.ID)
+				}
+				if page.NextCursor == "" {
+					break
+				}
+				query.Cursor = page.NextCursor
+			}
+			if !slices.Equal(got, want) {
+				t.Errorf("sort %s: walking the cursor listed clients %v, want %v", sort, got, want)
+			}
+		}
+	})
+
+	t.Run("InvalidCursor", func(t *testing.T) {
+		repo := newRepo(t)
+		owner := newOwner(t)
+
+		for _, name := range []string{"alpha", "bravo"} {
+			if _, err := repo.CreateClient(&models.Client{ClientName: name, UserID: owner, SchemaName: name}); err != nil {
+				t.Fatalf("CreateClient: %v", err)
+			}
+		}
+		first, err := repo.ListClientsByUserId(owner, models.ClientListQuery{ListParams: models.ListParams{Limit: 1, Sort: "client_name"}})
+		if err != nil {
+			t.Fatalf("ListClientsByUserId: %v", err)
+		}
+
+		encode := func(json string) string { return base64.RawURLEncoding.EncodeToString([]byte(json)) }
+		tests := []struct {
+			name   string
+			sort   string
+			cursor string
+		}{
+			{"not base64", "client_name", "not a cursor!"},
+			{"not JSON", "client_name", encode("alpha")},
+			{"of another sort", "-client_name", first.NextCursor},
+			{"without an ID", "client_name", encode(`{"s":"client_name","v":"alpha"}`)},
+			{"value of another type", "created_at", encode(`{"s":"created_at","v":42,"id":1}`)},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				query := models.ClientListQuery{ListParams: models.ListParams{Limit: 1, Sort: tt.sort, Cursor: tt.cursor}}
+				_, err := repo.ListClientsByUserId(owner, query)
+				var paramErr *db.ListParamError
+				if !errors.As(err, &paramErr) || paramErr.Param != "cursor" {
+					t.Fatalf("ListClientsByUserId with cursor %q = %v, want a cursor ListParamError", tt.cursor, err)
+				}
+			})
+		}
+	})
+
+	t.Run("UpdateAndListAll", func(t *testing.T) {
+		repo := newRepo(t)
+		owner, other := newOwner(t), newOwner(t)
+
+		client := &models.Client{ClientName: "acme", UserID: owner, SchemaName: "acme"}
+		if _, err := repo.CreateClient(client); err != nil {
+			t.Fatalf("CreateClient: %v", err)
+		}
+		if _, err := repo.CreateClient(&models.Client{ClientName: "globex", UserID: other, SchemaName: "globex"}); err != nil {
+			t.Fatalf("CreateClient: %v", err)
+		}
+
+		suspendedAt := time.Now().UTC().Truncate(time.Second)
+		client.SuspendedAt = &suspendedAt
+		if err := repo.UpdateClient(client); err != nil {
+			t.Fatalf("UpdateClient: %v", err)
+		}
+
+		stored, err := repo.GetClientId(client.ID)
+		if err != nil || stored == nil {
+			t.Fatalf("GetClientId = %v, %v", stored, err)
+		}
+		if !stored.IsSuspended() || !stored.SuspendedAt.Equal(suspendedAt) {
+			t.Errorf("UpdateClient did not persist suspended_at, got %v", stored.SuspendedAt)
+		}
+
+		clients, err := repo.GetAllClients()
+		if err != nil {
+			t.Fatalf("GetAllClients: %v", err)
+		}
+		if len(clients) != 2 || clients[0].ClientName != "acme" || clients[1].ClientName != "globex" {
+			t.Errorf("GetAllClients returned %+v", clients)
+		}
+	})
+}
+
+// RunClientUserRepositoryContract checks a ClientUserRepositoryFactory.
+// newFactory must return a factory without any users, and every schema name
+// passed to it must already exist.
+func RunClientUserRepositoryContract(t *testing.T, newFactory func(t *testing.T, schemaNames ...string) db.ClientUserRepositoryFactory) {
+	t.Run("CreateAndGet", func(t *testing.T) {
+		repo := newFactory(t, "tenant_a")("tenant_a")
+
+		created, err := repo.CreateClientUser(newUser("alice"))
+		if err != nil {
+			t.Fatalf("CreateClientUser: %v", err)
+		}
+		if created.ID == 0 {
+			t.Fatal("CreateClientUser did not assign an ID")
+		}
+
+		byID, err := repo.GetClientUserByID(created.ID)
+		assertUser(t, "GetClientUserByID", byID, err, "alice")
+
+		byEmail, err := repo.GetClientUserByEmail("alice@example.com")
+		assertUser(t, "GetClientUserByEmail", byEmail, err, "alice")
+
+		byName, err := repo.GetClientUserByUsername("alice")
+		assertUser(t, "GetClientUserByUsername", byName, err, "alice")
+	})
+
+	t.Run("MissingReturnsNil", func(t *testing.T) {
+		repo := newFactory(t, "tenant_a")("tenant_a")
+
+		byID, err := repo.GetClientUserByID(404)
+		assertNoUser(t, "GetClientUserByID", byID, err)
+
+		byEmail, err := repo.GetClientUserByEmail("nobody@example.com")
+		assertNoUser(t, "GetClientUserByEmail", byEmail, err)
+
+		byName, err := repo.GetClientUserByUsername("nobody")
+		assertNoUser(t, "GetClientUserByUsername", byName, err)
+	})
+
+	t.Run("UniqueUsernameAndEmail", func(t *testing.T) {
+		repo := newFactory(t, "tenant_a")("tenant_a")
+
+		if _, err := repo.CreateClientUser(newUser("alice")); err != nil {
+			t.Fatalf("CreateClientUser: %v", err)
+		}
+
+		sameName := newUser("alice")
+		sameName.Email = "other@example.com"
+		if _, err := repo.CreateClientUser(sameName); err == nil {
+			t.Error("CreateClientUser accepted a duplicate username")
+		}
+
+		sameEmail := newUser("bob")
+		sameEmail.Email = "alice@example.com"
+		if _, err := repo.CreateClientUser(sameEmail); err == nil {
+			t.Error("CreateClientUser accepted a duplicate email")
+		}
+	})
+
+	t.Run("Exists", func(t *testing.T) {
+		repo := newFactory(t, "tenant_a")("tenant_a")
+
+		if _, err := repo.CreateClientUser(newUser("alice")); err != nil {
+			t.Fatalf("CreateClientUser: %v", err)
+		}
+
+		assertExists(t, "ClientUserExists", true)(repo.ClientUserExists("alice", "alice@example.com"))
+		assertExists(t, "ClientUserExists with another email", false)(repo.ClientUserExists("alice", "bob@example.com"))
+		assertExists(t, "ClientUserEmailExists", true)(repo.ClientUserEmailExists("alice@example.com"))
+		assertExists(t, "ClientUserEmailExists for unknown email", false)(repo.ClientUserEmailExists("bob@example.com"))
+		assertExists(t, "ClientUserNameExists", true)(repo.ClientUserNameExists("alice"))
+		assertExists(t, "ClientUserNameExists for unknown username", false)(repo.ClientUserNameExists("bob"))
+	})
+
+	t.Run("UpdateAndList", func(t *testing.T) {
+		repo := newFactory(t, "tenant_a")("tenant_a")
+
+		for _, name := range []string{"alice", "bob"} {
+			if _, err := repo.CreateClientUser(newUser(name)); err != nil {
+				t.Fatalf("CreateClientUser: %v", err)
+			}
+		}
+
+		user, err := repo.GetClientUserByUsername("bob")
+		assertUser(t, "GetClientUserByUsername", user, err, "bob")
+
+		user.PasswordHash = "rehashed"
+		if err := repo.UpdateClientUser(user); err != nil {
+			t.Fatalf("UpdateClientUser: %v", err)
+		}
+
+		users, err := repo.GetClientAllUser()
+		if err != nil {
+			t.Fatalf("GetClientAllUser: %v", err)
+		}
+		if users == nil || len(*users) != 2 {
+			t.Fatalf("GetClientAllUser returned %v, want 2 users", users)
+		}
+		for _, u := range *users {
+			if u.Username == "bob" && u.PasswordHash != "rehashed" {
+				t.Errorf("UpdateClientUser did not persist the password hash, got %q", u.PasswordHash)
+			}
+		}
+	})
+
+	t.Run("ListPaged", func(t *testing.T) {
+		repo := newFactory(t, "tenant_a")("tenant_a")
+
+		for _, name := range []string{"alice", "bob", "carol"} {
+			if _, err := repo.CreateClientUser(newUser(name)); err != nil {
+				t.Fatalf("CreateClientUser: %v", err)
+			}
+		}
+
+		query := models.ClientUserListQuery{ListParams: models.ListParams{Limit: 2, Sort: "-username"}}
+		first, err := repo.ListClientUsers(query)
+		if err != nil {
+			t.Fatalf("ListClientUsers: %v", err)
+		}
+		if len(first.Items) != 2 || first.Items[0].Username != "carol" || first.Items[1].Username != "bob" {
+			t.Fatalf("first page returned %+v", first.Items)
+		}
+
+		query.Cursor = first.NextCursor
+		second, err := repo.ListClientUsers(query)
+		if err != nil {
+			t.Fatalf("ListClientUsers: %v", err)
+		}
+		if len(second.Items) != 1 || second.Items[0].Username != "alice" || second.NextCursor != "" {
+			t.Errorf("second page returned %+v, cursor %q", second.Items, second.NextCursor)
+		}
+
+		filtered, err := repo.ListClientUsers(models.ClientUserListQuery{Email: "BOB@"})
+		if err != nil {
+			t.Fatalf("ListClientUsers: %v", err)
+		}
+		if len(filtered.Items) != 1 || filtered.Items[0].Username != "bob" {
+			t.Errorf("email filter returned %+v", filtered.Items)
+		}
+	})
+
+	t.Run("SchemasAreIsolated", func(t *testing.T) {
+		factory := newFactory(t, "tenant_a", "tenant_b")
+		tenantA, tenantB := factory("tenant_a"), factory("tenant_b")
+
+		if _, err := tenantA.CreateClientUser(newUser("alice")); err != nil {
+			t.Fatalf("CreateClientUser: %v", err)
+		}
+
+		if user, err := tenantB.GetClientUserByUsername("alice"); err != nil || user != nil {
+			t.Errorf("user leaked into another schema: %v, %v", user, err)
+		}
+
+		// The same username may exist once per schema
+		if _, err := tenantB.CreateClientUser(newUser("alice")); err != nil {
+			t.Errorf("CreateClientUser in second schema: %v", err)
+		}
+
+		// A factory returns the same data for the same schema on every call
+		if user, err := factory("tenant_a").GetClientUserByUsername("alice"); err != nil || user == nil {
+			t.Errorf("GetClientUserByUsername through a new repository = %v, %v", user, err)
+		}
+	})
+}
+
+// RunRoleRepositoryContract checks a RoleRepositoryFactory. newFactory must
+// return a factory without any roles, and every schema name passed to it must
+// already exist. Roles are granted to the client users returned by newGrantee,
+// which backends with foreign keys must persist in the schema first.
+func RunRoleRepositoryContract(t *testing.T, newFactory func(t *testing.T, schemaNames ...string) db.RoleRepositoryFactory, newGrantee func(t *testing.T, schemaName string) uint) {
+	t.Run("CreateAndGet", func(t *testing.T) {
+		repo := newFactory(t, "tenant_a")("tenant_a")
+
+		role := &models.Role{Name: "editor", Description: "Can edit articles"}
+		if err := repo.CreateRole(role); err != nil {
+			t.Fatalf("CreateRole: %v", err)
+		}
+		if role.ID == 0 || role.Version != 1 {
+			t.Fatalf("CreateRole assigned ID %d and version %d, want an ID and version 1", role.ID, role.Version)
+		}
+
+		byID, err := repo.GetRoleByID(role.ID)
+		if err != nil || byID == nil || byID.Name != "editor" || byID.Description != "Can edit articles" || byID.Version != 1 {
+			t.Errorf("GetRoleByID = %+v, %v", byID, err)
+		}
+		byName, err := repo.GetRoleByName("editor")
+		if err != nil || byName == nil || byName.ID != role.ID {
+			t.Errorf("GetRoleByName = %+v, %v", byName, err)
+		}
+	})
+
+	t.Run("MissingReturnsNil", func(t *testing.T) {
+		repo := newFactory(t, "tenant_a")("tenant_a")
+
+		if role, err := repo.GetRoleByID(404); err != nil || role != nil {
+			t.Errorf("GetRoleByID = %v, %v, want nil, nil", role, err)
+		}
+		if role, err := repo.GetRoleByName("nobody"); err != nil || role != nil {
+			t.Errorf("GetRoleByName = %v, %v, want nil, nil", role, err)
+		}
+		if roles, err := repo.ListRoles(); err != nil || roles == nil || len(roles) != 0 {
+			t.Errorf("ListRoles = %v, %v, want an empty list", roles, err)
+		}
+	})
+
+	t.Run("UniqueName", func(t *testing.T) {
+		repo := newFactory(t, "tenant_a")("tenant_a")
+
+		if err := repo.CreateRole(&models.Role{Name: "editor"}); err != nil {
+			t.Fatalf("CreateRole: %v", err)
+		}
+		if err := repo.CreateRole(&models.Role{Name: "editor"}); err == nil {
+			t.Error("CreateRole accepted a duplicate name")
+		}
+	})
+
+	t.Run("ListByName", func(t *testing.T) {
+		repo := newFactory(t, "tenant_a")("tenant_a")
+		createRoles(t, repo, "viewer", "admin", "editor")
+
+		roles, err := repo.ListRoles()
+		if err != nil {
+			t.Fatalf("ListRoles: %v", err)
+		}
+		assertRoleNames(t, "ListRoles", roles, "admin", "editor", "viewer")
+
+		roles, err = repo.GetRolesByNames([]string{"viewer", "owner", "admin"})
+		if err != nil {
+			t.Fatalf("GetRolesByNames: %v", err)
+		}
+		assertRoleNames(t, "GetRolesByNames", roles, "admin", "viewer")
+
+		roles, err = repo.GetRolesByNames(nil)
+		if err != nil || roles == nil || len(roles) != 0 {
+			t.Errorf("GetRolesByNames without names = %v, %v, want an empty list", roles, err)
+		}
+	})
+
+	t.Run("Grants", func(t *testing.T) {
+		repo := newFactory(t, "tenant_a")("tenant_a")
+		roles := createRoles(t, repo, "viewer", "admin", "editor")
+		alice, bob := newGrantee(t, "tenant_a"), newGrantee(t, "tenant_a")
+
+		if err := repo.SetUserRoles(alice, []uint{roles["viewer"], roles["admin"]}); err != nil {
+			t.Fatalf("SetUserRoles: %v", err)
+		}
+		if err := repo.SetUserRoles(bob, []uint{roles["editor"]}); err != nil {
+			t.Fatalf("SetUserRoles: %v", err)
+		}
+
+		granted, err := repo.GetUserRoles(alice)
+		if err != nil {
+			t.Fatalf("GetUserRoles: %v", err)
+		}
+		assertRoleNames(t, "GetUserRoles", granted, "admin", "viewer")
+
+		// Setting replaces the grants of the user only
+		if err := repo.SetUserRoles(alice, []uint{roles["editor"]}); err != nil {
+			t.Fatalf("SetUserRoles: %v", err)
+		}
+		if names, err := repo.GetUserRoleNames(alice); err != nil || !slices.Equal(names, []string{"editor"}) {
+			t.Errorf("GetUserRoleNames after replacing = %v, %v, want [editor]", names, err)
+		}
+		if names, err := repo.GetUserRoleNames(bob); err != nil || !slices.Equal(names, []string{"editor"}) {
+			t.Errorf("GetUserRoleNames of another user = %v, %v, want [editor]", names, err)
+		}
+
+		if err := repo.SetUserRoles(alice, nil); err != nil {
+			t.Fatalf("SetUserRoles without roles: %v", err)
+		}
+		if names, err := repo.GetUserRoleNames(alice); err != nil || len(names) != 0 {
+			t.Errorf("GetUserRoleNames after clearing = %v, %v, want none", names, err)
+		}
+	})
+
+	t.Run("DeleteRevokesGrants", func(t *testing.T) {
+		repo := newFactory(t, "tenant_a")("tenant_a")
+		roles := createRoles(t, repo, "viewer", "editor")
+		alice := newGrantee(t, "tenant_a")
+
+		if err := repo.SetUserRoles(alice, []uint{roles["viewer"], roles["editor"]}); err != nil {
+			t.Fatalf("SetUserRoles: %v", err)
+		}
+		editor, err := repo.GetRoleByID(roles["editor"])
+		if err != nil || editor == nil {
+			t.Fatalf("GetRoleByID = %v, %v", editor, err)
+		}
+		if err := repo.DeleteRole(editor); err != nil {
+			t.Fatalf("DeleteRole: %v", err)
+		}
+
+		if role, err := repo.GetRoleByID(roles["editor"]); err != nil || role != nil {
+			t.Errorf("GetRoleByID of a deleted role = %v, %v, want nil, nil", role, err)
+		}
+		if names, err := repo.GetUserRoleNames(alice); err != nil || !slices.Equal(names, []string{"viewer"}) {
+			t.Errorf("GetUserRoleNames after deleting a granted role = %v, %v, want [viewer]", names, err)
+		}
+
+		// The name can be used again
+		if err := repo.CreateRole(&models.Role{Name: "editor"}); err != nil {
+			t.Errorf("CreateRole with the name of a deleted role: %v", err)
+		}
+	})
+
+	t.Run("DeleteStaleVersion", func(t *testing.T) {
+		repo := newFactory(t, "tenant_a")("tenant_a")
+		roles := createRoles(t, repo, "editor")
+		alice := newGrantee(t, "tenant_a")
+
+		if err := repo.SetUserRoles(alice, []uint{roles["editor"]}); err != nil {
+			t.Fatalf("SetUserRoles: %v", err)
+		}
+		role, err := repo.GetRoleByID(roles["editor"])
+		if err != nil || role == nil {
+			t.Fatalf("GetRoleByID = %v, %v", role, err)
+		}
+
+		stale := *role
+		stale.Version++
+		if err := repo.DeleteRole(&stale); !errors.Is(err, db.ErrVersionConflict) {
+			t.Fatalf("DeleteRole of a copy at another version = %v, want ErrVersionConflict", err)
+		}
+		if names, err := repo.GetUserRoleNames(alice); err != nil || !slices.Equal(names, []string{"editor"}) {
+			t.Errorf("GetUserRoleNames after a conflicting delete = %v, %v, want [editor]", names, err)
+		}
+
+		if err := repo.DeleteRole(role); err != nil {
+			t.Fatalf("DeleteRole: %v", err)
+		}
+		// The role is gone, so the copy no longer matches either
+		if err := repo.DeleteRole(role); !errors.Is(err, db.ErrVersionConflict) {
+			t.Errorf("DeleteRole of a deleted role = %v, want ErrVersionConflict", err)
+		}
+	})
+
+	t.Run("SchemasAreIsolated", func(t *testing.T) {
+		factory := newFactory(t, "tenant_a", "tenant_b")
+		tenantA, tenantB := factory("tenant_a"), factory("tenant_b")
+
+		createRoles(t, tenantA, "editor")
+		if role, err := tenantB.GetRoleByName("editor"); err != nil || role != nil {
+			t.Errorf("role leaked into another schema: %v, %v", role, err)
+		}
+
+		// The same name may exist once per schema
+		if err := tenantB.CreateRole(&models.Role{Name: "editor"}); err != nil {
+			t.Errorf("CreateRole in second schema: %v", err)
+		}
+
+		// A factory returns the same data for the same schema on every call
+		if role, err := factory("tenant_a").GetRoleByName("editor"); err != nil || role == nil {
+			t.Errorf("GetRoleByName through a new repository = %v, %v", role, err)
+		}
+	})
+}
+
+// createRoles creates roles with the given names and returns their IDs by name
+func createRoles(t *testing.T, repo db.RoleRepository, names ...string) map[string]uint {
+	t.Helper()
+
+	ids := make(map[string]uint, len(names))
+	for _, name := range names {
+		role := &models.Role{Name: name}
+		if err := repo.CreateRole(role); err != nil {
+			t.Fatalf("CreateRole(%s): %v", name, err)
+		}
+		ids[name] = role.ID
+	}
+	return ids
+}
+
+func assertRoleNames(t *testing.T, call string, roles []models.Role, names ...string) {
+	t.Helper()
+	got := make([]string, len(roles))
+	for i, role := range roles {
+		got[i] = role.Name
+	}
+	if !slices.Equal(got, names) {
+		t.Errorf("%s returned %v, want %v", call, got, names)
+	}
+}
+
+func newUser(username string) *models.AdminUser {
+	return &models.AdminUser{
+		Username:     username,
+		Email:        fmt.Sprintf("%s@example.com", username),
+		PasswordHash: "hash",
+	}
+}
+
+func assertUser(t *testing.T, call string, user *models.AdminUser, err error, username string) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("%s: %v", call, err)
+	}
+	if user == nil {
+		t.Fatalf("%s returned no user, want %q", call, username)
+	}
+	if user.Username != username {
+		t.Fatalf("%s returned %q, want %q", call, user.Username, username)
+	}
+}
+
+func assertNoUser(t *testing.T, call string, user *models.AdminUser, err error) {
+	t.Helper()
+	if err != nil || user != nil {
+		t.Errorf("%s = %v, %v, want nil, nil", call, user, err)
+	}
+}
+
+func assertClientNames(t *testing.T, call string, clients []models.Client, names ...string) {
+	t.Helper()
+	got := make([]string, len(clients))
+	for i, client := range clients {
+		got[i] = client.ClientName
+	}
+	if !slices.Equal(got, names) {
+		t.Errorf("%s returned %v, want %v", call, got, names)
+	}
+}
+
+func assertExists(t *testing.T, call string, want bool) func(bool, error) {
+	t.Helper()
+	return func(exists bool, err error) {
+		t.Helper()
+		if err != nil {
+			t.Errorf("%s: %v", call, err)
+		} else if exists != want {
+			t.Errorf("%s = %v, want %v", call, exists, want)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Kantha2004/SimpleJWT/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IdempotencyRepository struct {
+	db *Database
+}
+
+func NewIdempotencyRepository(db *Database) *IdempotencyRepository {
+	return &IdempotencyRepository{db: db}
+}
+
+// WithContext returns the repository with its queries bound to ctx
+func (ir *IdempotencyRepository) WithContext(ctx context.Context) *IdempotencyRepository {
+	return NewIdempotencyRepository(ir.db.WithContext(ctx))
+}
+
+// ReserveKey stores a key whose request is about to be handled. Returns
+// false, and stores nothing, when an unexpired record of the key exists.
+func (ir *IdempotencyRepository) ReserveKey(key *models.IdempotencyKey) (bool, error) {
+	db, span := ir.db.startSpan("IdempotencyRepository.ReserveKey")
+	defer span.End()
+
+	var reserved bool
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		// An expired record no longer holds the key
+		if err := tx.Where("scope = ? AND key = ? AND expires_at <= ?", key.Scope, key.Key, time.Now()).
+			Delete(&models.IdempotencyKey{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(key)
+		reserved = result.RowsAffected == 1
+		return result.Error
+	})
+
+	return reserved, err
+}
+
+// GetKey returns the unexpired record of a key, or nil
+func (ir *IdempotencyRepository) GetKey(scope, key string) (*models.IdempotencyKey, error) {
+	db, span := ir.db.startSpan("IdempotencyRepository.GetKey")
+	defer span.End()
+
+	var record models.IdempotencyKey
+	result := db.DB.Where("scope = ? AND key = ? AND expires_at > ?", scope, key, time.Now()).First(&record)
+
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &record, nil
+}
+
+// CompleteKey stores the response of the request of a reserved key
+func (ir *IdempotencyRepository) CompleteKey(key *models.IdempotencyKey) error {
+	db, span := ir.db.startSpan("IdempotencyRepository.CompleteKey")
+	defer span.End()
+
+	return db.DB.Model(key).Select("status_code", "content_type", "location", "etag", "response_body").Updates(key).Error
+}
+
+// ReleaseKey deletes a reserved key whose request failed, so it can be retried
+func (ir *IdempotencyRepository) ReleaseKey(id uint) error {
+	db, span := ir.db.startSpan("IdempotencyRepository.ReleaseKey")
+	defer span.End()
+
+	return db.DB.Delete(&models.IdempotencyKey{}, id).Error
+}
+
+// DeleteExpiredKeys deletes the records that expired before now and returns
+// how many were deleted
+func (ir *IdempotencyRepository) DeleteExpiredKeys(now time.Time) (int64, error) {
+	db, span := ir.db.startSpan("IdempotencyRepository.DeleteExpiredKeys")
+	defer span.End()
+
+	result := db.DB.Where("expires_at <= ?", now).Delete(&models.IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
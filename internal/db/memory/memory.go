@@ -0,0 +1,557 @@
+// Package memory provides thread-safe in-memory implementations of the
+// repository interfaces in package db, for tests and local experiments.
+// They mirror the SQL repositories, including their unique constraints and
+// the nil results returned for missing records.
+package memory
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Kantha2004/SimpleJWT/internal/db"
+	"github.com/Kantha2004/SimpleJWT/internal/models"
+)
+
+// ErrDuplicateKey is returned when a unique constraint would be violated
+var ErrDuplicateKey = errors.New("duplicate key value violates unique constraint")
+
+// users is an ID-keyed table of users with unique usernames and emails,
+// shared by the admin user and client user repositories
+type users struct {
+	mu     sync.RWMutex
+	rows   map[uint]models.AdminUser
+	nextID uint
+}
+
+func newUsers() *users {
+	return &users{rows: make(map[uint]models.AdminUser), nextID: 1}
+}
+
+func (u *users) create(user *models.AdminUser) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if user.ID != 0 {
+		if _, exists := u.rows[user.ID]; exists {
+			return ErrDuplicateKey
+		}
+	}
+	if u.conflicts(user) {
+		return ErrDuplicateKey
+	}
+
+	if user.ID == 0 {
+		user.ID = u.nextID
+	}
+	u.nextID = max(u.nextID, user.ID+1)
+
+	// Like GORM, a creation time that is already set is kept
+	now := time.Now()
+	if user.CreatedAt.IsZero() {
+		user.CreatedAt = now
+	}
+	user.UpdatedAt = now
+	user.Version = 1
+
+	u.rows[user.ID] = *user
+	return nil
+}
+
+// update replaces a stored user read at its current version and increments
+// the version, like the SQL repositories
+func (u *users) update(user *models.AdminUser) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	existing, ok := u.rows[user.ID]
+	if !ok || existing.Version != user.Version {
+		return db.ErrVersionConflict
+	}
+	if u.conflicts(user) {
+		return ErrDuplicateKey
+	}
+
+	user.CreatedAt = existing.CreatedAt
+	user.UpdatedAt = time.Now()
+	user.Version++
+
+	u.rows[user.ID] = *user
+	return nil
+}
+
+// conflicts reports whether another user already has the username or email.
+// Callers must hold the lock.
+func (u *users) conflicts(user *models.AdminUser) bool {
+	for id, row := range u.rows {
+		if id != user.ID && (row.Username == user.Username || row.Email == user.Email) {
+			return true
+		}
+	}
+	return false
+}
+
+func (u *users) get(id uint) *models.AdminUser {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	if row, ok := u.rows[id]; ok {
+		return &row
+	}
+	return nil
+}
+
+func (u *users) find(match func(models.AdminUser) bool) *models.AdminUser {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	for _, id := range u.sortedIDs() {
+		if row := u.rows[id]; match(row) {
+			return &row
+		}
+	}
+	return nil
+}
+
+func (u *users) all() []models.AdminUser {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	rows := make([]models.AdminUser, 0, len(u.rows))
+	for _, id := range u.sortedIDs() {
+		rows = append(rows, u.rows[id])
+	}
+	return rows
+}
+
+func (u *users) sortedIDs() []uint {
+	ids := make([]uint, 0, len(u.rows))
+	for id := range u.rows {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// UserRepository is an in-memory db.UserRepository
+type UserRepository struct {
+	users *users
+}
+
+func NewUserRepository() *UserRepository {
+	return &UserRepository{users: newUsers()}
+}
+
+// WithContext returns the repository itself; in-memory calls are not traced
+func (ur *UserRepository) WithContext(context.Context) db.UserRepository {
+	return ur
+}
+
+func (ur *UserRepository) CreateUser(user *models.AdminUser) (uint, error) {
+	err := ur.users.create(user)
+	return user.ID, err
+}
+
+func (ur *UserRepository) GetUserByID(id uint) (*models.AdminUser, error) {
+	return ur.users.get(id), nil
+}
+
+func (ur *UserRepository) GetUserByEmail(email string) (*models.AdminUser, error) {
+	return ur.users.find(func(u models.AdminUser) bool { return u.Email == email }), nil
+}
+
+func (ur *UserRepository) GetUserByUsername(username string) (*models.AdminUser, error) {
+	return ur.users.find(func(u models.AdminUser) bool { return u.Username == username }), nil
+}
+
+func (ur *UserRepository) UpdateUser(user *models.AdminUser) error {
+	return ur.users.update(user)
+}
+
+func (ur *UserRepository) UserExists(username, email string) (bool, error) {
+	user := ur.users.find(func(u models.AdminUser) bool { return u.Username == username && u.Email == email })
+	return user != nil, nil
+}
+
+func (ur *UserRepository) EmailExists(email string) (bool, error) {
+	user, err := ur.GetUserByEmail(email)
+	return user != nil, err
+}
+
+func (ur *UserRepository) UserNameExists(username string) (bool, error) {
+	user, err := ur.GetUserByUsername(username)
+	return user != nil, err
+}
+
+// ClientRepository is an in-memory db.ClientRepository
+type ClientRepository struct {
+	mu     sync.RWMutex
+	rows   map[uint]models.Client
+	nextID uint
+}
+
+func NewClientRepository() *ClientRepository {
+	return &ClientRepository{rows: make(map[uint]models.Client), nextID: 1}
+}
+
+func (cr *ClientRepository) WithContext(context.Context) db.ClientRepository {
+	return cr
+}
+
+func (cr *ClientRepository) CreateClient(client *models.Client) (string, error) {
+	// Generates the client secret like the GORM hook does
+	if err := client.BeforeCreate(nil); err != nil {
+		return "", err
+	}
+
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	for _, row := range cr.rows {
+		if row.ClientName == client.ClientName {
+			return client.ClientSecret, ErrDuplicateKey
+		}
+	}
+
+	client.ID = cr.nextID
+	cr.nextID++
+
+	now := time.Now()
+	if client.CreatedAt.IsZero() {
+		client.CreatedAt = now
+	}
+	client.UpdatedAt = now
+	client.Version = 1
+
+	cr.rows[client.ID] = *client
+	return client.ClientSecret, nil
+}
+
+func (cr *ClientRepository) GetClientId(id uint) (*models.Client, error) {
+	cr.mu.RLock()
+	defer cr.mu.RUnlock()
+
+	if row, ok := cr.rows[id]; ok {
+		return &row, nil
+	}
+	return nil, nil
+}
+
+func (cr *ClientRepository) GetClientByNameForUser(clientName string, userID uint) (*models.Client, error) {
+	clients := cr.filter(func(c models.Client) bool { return c.ClientName == clientName && c.UserID == userID })
+	if len(clients) == 0 {
+		return nil, nil
+	}
+	return &clients[0], nil
+}
+
+func (cr *ClientRepository) GetClientByUserId(userID uint) (*models.Client, error) {
+	clients := cr.filter(func(c models.Client) bool { return c.UserID == userID })
+	if len(clients) == 0 {
+		return nil, nil
+	}
+	return &clients[0], nil
+}
+
+func (cr *ClientRepository) GetAllClientsByUserId(userID uint) ([]models.Client, error) {
+	return cr.filter(func(c models.Client) bool { return c.UserID == userID }), nil
+}
+
+func (cr *ClientRepository) ListClientsByUserId(userID uint, query models.ClientListQuery) (*db.Page[models.Client], error) {
+	clients := cr.filter(func(c models.Client) bool {
+		return c.UserID == userID &&
+			containsFold(c.ClientName, query.Name) &&
+			(query.Suspended == nil || c.IsSuspended() == *query.Suspended)
+	})
+	return db.PaginateSlice(clients, db.ClientListSpec, query.ListParams)
+}
+
+func (cr *ClientRepository) GetAllClients() ([]models.Client, error) {
+	return cr.filter(func(models.Client) bool { return true }), nil
+}
+
+func (cr *ClientRepository) UpdateClient(client *models.Client) error {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	existing, ok := cr.rows[client.ID]
+	if !ok || existing.Version != client.Version {
+		return db.ErrVersionConflict
+	}
+	for id, row := range cr.rows {
+		if id != client.ID && row.ClientName == client.ClientName {
+			return ErrDuplicateKey
+		}
+	}
+
+	client.CreatedAt = existing.CreatedAt
+	client.UpdatedAt = time.Now()
+	client.Version++
+	cr.rows[client.ID] = *client
+	return nil
+}
+
+// filter returns the matching clients ordered by ID
+func (cr *ClientRepository) filter(match func(models.Client) bool) []models.Client {
+	cr.mu.RLock()
+	defer cr.mu.RUnlock()
+
+	clients := []models.Client{}
+	for _, row := range cr.rows {
+		if match(row) {
+			clients = append(clients, row)
+		}
+	}
+	sort.Slice(clients, func(i, j int) bool { return clients[i].ID < clients[j].ID })
+	return clients
+}
+
+// ClientUserRepository is an in-memory db.ClientUserRepository for one client schema
+type ClientUserRepository struct {
+	users *users
+}
+
+func (cur *ClientUserRepository) WithContext(context.Context) db.ClientUserRepository {
+	return cur
+}
+
+func (cur *ClientUserRepository) CreateClientUser(user *models.ClientUser) (*models.ClientUser, error) {
+	err := cur.users.create(user)
+	return user, err
+}
+
+func (cur *ClientUserRepository) GetClientUserByID(id uint) (*models.ClientUser, error) {
+	return cur.users.get(id), nil
+}
+
+func (cur *ClientUserRepository) GetClientAllUser() (*[]models.ClientUser, error) {
+	users := cur.users.all()
+	return &users, nil
+}
+
+func (cur *ClientUserRepository) ListClientUsers(query models.ClientUserListQuery) (*db.Page[models.ClientUser], error) {
+	users := slices.DeleteFunc(cur.users.all(), func(u models.ClientUser) bool {
+		return !containsFold(u.Username, query.Username) ||
+			!containsFold(u.Email, query.Email) ||
+			(query.Disabled != nil && u.IsDisabled() != *query.Disabled)
+	})
+	return db.PaginateSlice(users, db.ClientUserListSpec, query.ListParams)
+}
+
+func (cur *ClientUserRepository) GetClientUserByEmail(email string) (*models.ClientUser, error) {
+	return cur.users.find(func(u models.ClientUser) bool { return u.Email == email }), nil
+}
+
+func (cur *ClientUserRepository) GetClientUserByUsername(username string) (*models.ClientUser, error) {
+	return cur.users.find(func(u models.ClientUser) bool { return u.Username == username }), nil
+}
+
+func (cur *ClientUserRepository) UpdateClientUser(user *models.ClientUser) error {
+	return cur.users.update(user)
+}
+
+func (cur *ClientUserRepository) ClientUserExists(username, email string) (bool, error) {
+	user := cur.users.find(func(u models.ClientUser) bool { return u.Username == username && u.Email == email })
+	return user != nil, nil
+}
+
+func (cur *ClientUserRepository) ClientUserEmailExists(email string) (bool, error) {
+	user, err := cur.GetClientUserByEmail(email)
+	return user != nil, err
+}
+
+func (cur *ClientUserRepository) ClientUserNameExists(username string) (bool, error) {
+	user, err := cur.GetClientUserByUsername(username)
+	return user != nil, err
+}
+
+// containsFold reports whether s contains substr, ignoring case, like the
+// LIKE filters of the SQL repositories
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+// ClientUserRepositories keeps one in-memory user table per client schema
+type ClientUserRepositories struct {
+	mu      sync.Mutex
+	schemas map[string]*ClientUserRepository
+}
+
+func NewClientUserRepositories() *ClientUserRepositories {
+	return &ClientUserRepositories{schemas: make(map[string]*ClientUserRepository)}
+}
+
+// ForSchema returns the repository of a schema, creating it on first use
+func (r *ClientUserRepositories) ForSchema(schemaName string) db.ClientUserRepository {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	repo, ok := r.schemas[schemaName]
+	if !ok {
+		repo = &ClientUserRepository{users: newUsers()}
+		r.schemas[schemaName] = repo
+	}
+	return repo
+}
+
+// Factory adapts the repositories to handlers.Dependencies.ClientUsers
+func (r *ClientUserRepositories) Factory() db.ClientUserRepositoryFactory {
+	return r.ForSchema
+}
+
+// RoleRepository is an in-memory db.RoleRepository for one client schema
+type RoleRepository struct {
+	mu     sync.RWMutex
+	rows   map[uint]models.Role
+	grants map[uint][]uint // role IDs by user ID
+	nextID uint
+}
+
+func (rr *RoleRepository) WithContext(context.Context) db.RoleRepository {
+	return rr
+}
+
+func (rr *RoleRepository) ListRoles() ([]models.Role, error) {
+	return rr.filter(func(models.Role) bool { return true }), nil
+}
+
+func (rr *RoleRepository) GetRoleByID(id uint) (*models.Role, error) {
+	return rr.find(func(role models.Role) bool { return role.ID == id }), nil
+}
+
+func (rr *RoleRepository) GetRoleByName(name string) (*models.Role, error) {
+	return rr.find(func(role models.Role) bool { return role.Name == name }), nil
+}
+
+func (rr *RoleRepository) GetRolesByNames(names []string) ([]models.Role, error) {
+	return rr.filter(func(role models.Role) bool { return slices.Contains(names, role.Name) }), nil
+}
+
+func (rr *RoleRepository) CreateRole(role *models.Role) error {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	for _, row := range rr.rows {
+		if row.Name == role.Name {
+			return ErrDuplicateKey
+		}
+	}
+
+	role.ID = rr.nextID
+	rr.nextID++
+
+	now := time.Now()
+	role.CreatedAt = now
+	role.UpdatedAt = now
+	role.Version = 1
+
+	rr.rows[role.ID] = *role
+	return nil
+}
+
+func (rr *RoleRepository) DeleteRole(role *models.Role) error {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	existing, ok := rr.rows[role.ID]
+	if !ok || existing.Version != role.Version {
+		return db.ErrVersionConflict
+	}
+
+	delete(rr.rows, role.ID)
+	for userID, roleIDs := range rr.grants {
+		rr.grants[userID] = slices.DeleteFunc(roleIDs, func(roleID uint) bool { return roleID == role.ID })
+	}
+	return nil
+}
+
+func (rr *RoleRepository) GetUserRoles(userID uint) ([]models.Role, error) {
+	rr.mu.RLock()
+	granted := slices.Clone(rr.grants[userID])
+	rr.mu.RUnlock()
+
+	return rr.filter(func(role models.Role) bool { return slices.Contains(granted, role.ID) }), nil
+}
+
+func (rr *RoleRepository) GetUserRoleNames(userID uint) ([]string, error) {
+	roles, err := rr.GetUserRoles(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(roles))
+	for i, role := range roles {
+		names[i] = role.Name
+	}
+	return names, nil
+}
+
+func (rr *RoleRepository) SetUserRoles(userID uint, roleIDs []uint) error {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	rr.grants[userID] = slices.Clone(roleIDs)
+	return nil
+}
+
+func (rr *RoleRepository) find(match func(models.Role) bool) *models.Role {
+	roles := rr.filter(match)
+	if len(roles) == 0 {
+		return nil
+	}
+	return &roles[0]
+}
+
+// filter returns copies of the matching roles by name
+func (rr *RoleRepository) filter(match func(models.Role) bool) []models.Role {
+	rr.mu.RLock()
+	defer rr.mu.RUnlock()
+
+	roles := []models.Role{}
+	for _, row := range rr.rows {
+		if match(row) {
+			roles = append(roles, row)
+		}
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i].Name < roles[j].Name })
+	return roles
+}
+
+// RoleRepositories holds one RoleRepository per client schema
+type RoleRepositories struct {
+	mu      sync.Mutex
+	schemas map[string]*RoleRepository
+}
+
+func NewRoleRepositories() *RoleRepositories {
+	return &RoleRepositories{schemas: make(map[string]*RoleRepository)}
+}
+
+// ForSchema returns the repository of a schema, creating it on first use
+func (r *RoleRepositories) ForSchema(schemaName string) db.RoleRepository {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	repo, ok := r.schemas[schemaName]
+	if !ok {
+		repo = &RoleRepository{rows: make(map[uint]models.Role), grants: make(map[uint][]uint), nextID: 1}
+		r.schemas[schemaName] = repo
+	}
+	return repo
+}
+
+// Factory adapts the repositories to handlers.Dependencies.Roles
+func (r *RoleRepositories) Factory() db.RoleRepositoryFactory {
+	return r.ForSchema
+}
+
+var (
+	_ db.UserRepository       = (*UserRepository)(nil)
+	_ db.ClientRepository     = (*ClientRepository)(nil)
+	_ db.ClientUserRepository = (*ClientUserRepository)(nil)
+	_ db.RoleRepository       = (*RoleRepository)(nil)
+)
@@ -0,0 +1,49 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/Kantha2004/SimpleJWT/internal/db"
+	"github.com/Kantha2004/SimpleJWT/internal/db/dbtest"
+	"github.com/Kantha2004/SimpleJWT/internal/db/memory"
+)
+
+func TestUserRepository(t *testing.T) {
+	dbtest.RunUserRepositoryContract(t, func(t *testing.T) db.UserRepository {
+		return memory.NewUserRepository()
+	})
+}
+
+func TestClientRepository(t *testing.T) {
+	// The in-memory repositories have no foreign keys, any ID owns clients
+	var owners uint
+	dbtest.RunClientRepositoryContract(t,
+		func(t *testing.T) db.ClientRepository {
+			return memory.NewClientRepository()
+		},
+		func(t *testing.T) uint {
+			owners++
+			return owners
+		},
+	)
+}
+
+func TestClientUserRepository(t *testing.T) {
+	dbtest.RunClientUserRepositoryContract(t, func(t *testing.T, schemaNames ...string) db.ClientUserRepositoryFactory {
+		return memory.NewClientUserRepositories().Factory()
+	})
+}
+
+func TestRoleRepository(t *testing.T) {
+	// The in-memory repositories have no foreign keys, any ID is a user
+	var grantees uint
+	dbtest.RunRoleRepositoryContract(t,
+		func(t *testing.T, schemaNames ...string) db.RoleRepositoryFactory {
+			return memory.NewRoleRepositories().Factory()
+		},
+		func(t *testing.T, schemaName string) uint {
+			grantees++
+			return grantees
+		},
+	)
+}
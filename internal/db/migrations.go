@@ -0,0 +1,450 @@
+package db
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Kantha2004/SimpleJWT/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MigrationSet names a directory of versioned migrations
+type MigrationSet string
+
+const (
+	// ControlPlaneMigrations create the shared tables in the public schema
+	ControlPlaneMigrations MigrationSet = "control"
+	// TenantMigrations create the tables inside every client schema
+	TenantMigrations MigrationSet = "tenant"
+)
+
+// SCHEMA_MIGRATIONS_TABLE records the applied versions, once for the control
+// plane and once inside every client schema
+const SCHEMA_MIGRATIONS_TABLE = "schema_migrations"
+
+//go:embed migrations
+var migrationFiles embed.FS
+
+// migrationFileName matches "0001_initial.up.sql" and "0001_initial.down.sql"
+var migrationFileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// tablePlaceholder matches the {{table "x"}} and {{name "x"}} placeholders of
+// tenant migrations
+var tablePlaceholder = regexp.MustCompile(`\{\{\s*(table|name)\s+"([a-z_]+)"\s*\}\}`)
+
+// Migration is a numbered pair of up and down SQL scripts. Tenant migrations
+// refer to tables inside the client schema with placeholders.
+type Migration struct {
+	Version uint
+	Name    string
+	up      string
+	down    string
+}
+
+// loadMigrations returns the migrations of a set for a driver ordered by version
+func loadMigrations(set MigrationSet, driver string) ([]Migration, error) {
+	dir := path.Join("migrations", string(set), driver)
+
+	entries, err := fs.ReadDir(migrationFiles, dir)
+	if err != nil {
+		return nil, fmt.Errorf("no %s migrations for driver %s: %w", set, driver, err)
+	}
+
+	byVersion := make(map[uint]*Migration)
+	for _, entry := range entries {
+		match := migrationFileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %s", entry.Name())
+		}
+
+		version, err := strconv.ParseUint(match[1], 10, 32)
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("invalid migration version in %s", entry.Name())
+		}
+
+		content, err := fs.ReadFile(migrationFiles, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[uint(version)]
+		if !ok {
+			migration = &Migration{Version: uint(version), Name: match[2]}
+			byVersion[uint(version)] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, migration.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			migration.up = string(content)
+		} else {
+			migration.down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.up == "" || migration.down == "" {
+			return nil, fmt.Errorf("migration %d_%s needs both an up and a down script", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+// appliedMigration is a row of the schema_migrations table
+type appliedMigration struct {
+	Version   uint
+	Name      string
+	AppliedAt time.Time
+}
+
+// Migrator applies one migration set to the control plane or to a single
+// client schema
+type Migrator struct {
+	db         *Database
+	set        MigrationSet
+	schema     string
+	migrations []Migration
+}
+
+// ControlPlaneMigrator returns the migrator of the shared tables
+func (d *Database) ControlPlaneMigrator() (*Migrator, error) {
+	return d.newMigrator(ControlPlaneMigrations, "")
+}
+
+// TenantMigrator returns the migrator of a client schema
+func (d *Database) TenantMigrator(schemaName string) (*Migrator, error) {
+	return d.newMigrator(TenantMigrations, schemaName)
+}
+
+func (d *Database) newMigrator(set MigrationSet, schema string) (*Migrator, error) {
+	if schema != "" {
+		if err := ValidateIdentifier(schema); err != nil {
+			return nil, err
+		}
+	}
+
+	migrations, err := loadMigrations(set, d.Driver)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: d, set: set, schema: schema, migrations: migrations}, nil
+}
+
+// Migrations returns every known migration ordered by version
+func (m *Migrator) Migrations() []Migration {
+	return m.migrations
+}
+
+// Up applies every migration that has not been applied yet and returns how
+// many were applied. Each migration runs in its own transaction.
+func (m *Migrator) Up() (int, error) {
+	if err := m.ensureVersionTable(); err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	for _, migration := range m.migrations {
+		ran, err := m.apply(migration, true)
+		if err != nil {
+			return applied, err
+		}
+		if ran {
+			applied++
+		}
+	}
+
+	return applied, nil
+}
+
+// Down reverts the given number of most recently applied migrations and
+// returns how many were reverted
+func (m *Migrator) Down(steps int) (int, error) {
+	if err := m.ensureVersionTable(); err != nil {
+		return 0, err
+	}
+
+	versions, err := m.appliedVersions()
+	if err != nil {
+		return 0, err
+	}
+
+	reverted := 0
+	for i := len(m.migrations) - 1; i >= 0 && reverted < steps; i-- {
+		migration := m.migrations[i]
+		if !versions[migration.Version] {
+			continue
+		}
+
+		ran, err := m.apply(migration, false)
+		if err != nil {
+			return reverted, err
+		}
+		if ran {
+			reverted++
+		}
+	}
+
+	return reverted, nil
+}
+
+// Version returns the highest applied version, or 0 when nothing was applied
+func (m *Migrator) Version() (uint, error) {
+	if err := m.ensureVersionTable(); err != nil {
+		return 0, err
+	}
+	return m.AppliedVersion()
+}
+
+// AppliedVersion is Version without creating the version table first: it
+// only reads, so probes can call it often, and fails when the table does not
+// exist
+func (m *Migrator) AppliedVersion() (uint, error) {
+	var version uint
+	err := m.versionTable(m.db.DB).Select("COALESCE(MAX(version), 0)").Scan(&version).Error
+	return version, err
+}
+
+// LatestVersion returns the version of the newest known migration, or 0
+// when the set is empty
+func (m *Migrator) LatestVersion() uint {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Pending returns the migrations that have not been applied yet
+func (m *Migrator) Pending() ([]Migration, error) {
+	if err := m.ensureVersionTable(); err != nil {
+		return nil, err
+	}
+
+	versions, err := m.appliedVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, migration := range m.migrations {
+		if !versions[migration.Version] {
+			pending = append(pending, migration)
+		}
+	}
+	return pending, nil
+}
+
+// apply runs one direction of a migration and records it in the version
+// table. The applied state is checked again inside the transaction, after
+// taking a lock on PostgreSQL, so concurrent instances run it only once.
+func (m *Migrator) apply(migration Migration, up bool) (bool, error) {
+	script := migration.down
+	if up {
+		script = migration.up
+	}
+
+	statements, err := m.render(migration, script)
+	if err != nil {
+		return false, err
+	}
+
+	database, span := m.db.startSpan("Migrator.Apply",
+		attribute.String("simplejwt.migration.set", string(m.set)),
+		attribute.Int("simplejwt.migration.version", int(migration.Version)),
+		attribute.Bool("simplejwt.migration.up", up),
+		tracing.TenantSchemaKey.String(m.schema),
+	)
+	defer span.End()
+
+	ran := false
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if !m.db.IsSQLite() {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", m.lockKey()).Error; err != nil {
+				return err
+			}
+		}
+
+		var count int64
+		if err := m.versionTable(tx).Where("version = ?", migration.Version).Count(&count).Error; err != nil {
+			return err
+		}
+		if (count > 0) == up {
+			return nil
+		}
+
+		if err := tx.Exec(statements).Error; err != nil {
+			return err
+		}
+
+		ran = true
+		if up {
+			return m.versionTable(tx).Create(&appliedMigration{
+				Version:   migration.Version,
+				Name:      migration.Name,
+				AppliedAt: time.Now().UTC(),
+			}).Error
+		}
+		return m.versionTable(tx).Where("version = ?", migration.Version).Delete(&appliedMigration{}).Error
+	})
+	if err != nil {
+		direction := "down"
+		if up {
+			direction = "up"
+		}
+		return false, fmt.Errorf("migration %d_%s %s failed: %w", migration.Version, migration.Name, direction, err)
+	}
+
+	return ran, nil
+}
+
+// render expands the placeholders of a migration script. {{table "x"}}
+// becomes the quoted name of the table in the client schema, and {{name "x"}}
+// the same name with the schema joined by an underscore, to build index and
+// constraint names. The schema name was validated, so it can only hold
+// lowercase letters, digits and underscores.
+func (m *Migrator) render(migration Migration, script string) (string, error) {
+	statements := tablePlaceholder.ReplaceAllStringFunc(script, func(placeholder string) string {
+		match := tablePlaceholder.FindStringSubmatch(placeholder)
+		if match[1] == "table" {
+			return m.db.QuoteIdentifier(m.tableName(match[2]))
+		}
+		return strings.ReplaceAll(m.tableName(match[2]), ".", "_")
+	})
+
+	if strings.Contains(statements, "{{") {
+		return "", fmt.Errorf("invalid migration %d_%s: unknown placeholder", migration.Version, migration.Name)
+	}
+	return statements, nil
+}
+
+func (m *Migrator) tableName(table string) string {
+	if m.schema == "" {
+		return table
+	}
+	return m.db.QualifiedTableName(m.schema, table)
+}
+
+func (m *Migrator) versionTable(tx *gorm.DB) *gorm.DB {
+	return tx.Table(m.tableName(SCHEMA_MIGRATIONS_TABLE))
+}
+
+func (m *Migrator) ensureVersionTable() error {
+	return m.db.DB.Exec(
+		"CREATE TABLE IF NOT EXISTS ? (version bigint PRIMARY KEY, name varchar(255) NOT NULL, applied_at timestamp NOT NULL)",
+		clause.Table{Name: m.tableName(SCHEMA_MIGRATIONS_TABLE)},
+	).Error
+}
+
+func (m *Migrator) appliedVersions() (map[uint]bool, error) {
+	var versions []uint
+	if err := m.versionTable(m.db.DB).Pluck("version", &versions).Error; err != nil {
+		return nil, err
+	}
+
+	applied := make(map[uint]bool, len(versions))
+	for _, version := range versions {
+		applied[version] = true
+	}
+	return applied, nil
+}
+
+func (m *Migrator) lockKey() string {
+	return fmt.Sprintf("%s:%s:%s", SCHEMA_MIGRATIONS_TABLE, m.set, m.schema)
+}
+
+// TenantMigrationResult reports the migration of one client schema
+type TenantMigrationResult struct {
+	Schema      string
+	FromVersion uint
+	ToVersion   uint
+	Applied     int
+	Err         error
+}
+
+// MigrateTenant applies the pending tenant migrations to one client schema
+func (d *Database) MigrateTenant(schemaName string) error {
+	database, span := d.startSpan("Database.MigrateTenant", tracing.TenantSchemaKey.String(schemaName))
+	defer span.End()
+
+	migrator, err := database.TenantMigrator(schemaName)
+	if err != nil {
+		return err
+	}
+	_, err = migrator.Up()
+	return err
+}
+
+// TenantSchemas returns the schema names of all clients
+func (d *Database) TenantSchemas() ([]string, error) {
+	var schemaNames []string
+	err := d.DB.Table("clients").Distinct().Order("schema_name").Pluck("schema_name", &schemaNames).Error
+	return schemaNames, err
+}
+
+// MigrateAllTenants upgrades every client schema. A failing schema is
+// reported in its result and does not stop the others from being migrated.
+func (d *Database) MigrateAllTenants() ([]TenantMigrationResult, error) {
+	schemaNames, err := d.TenantSchemas()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]TenantMigrationResult, 0, len(schemaNames))
+	failed := 0
+	for i, schemaName := range schemaNames {
+		result := d.migrateTenant(schemaName)
+		results = append(results, result)
+
+		if result.Err != nil {
+			failed++
+			slog.Error("Tenant migration failed", "error", result.Err, "schema", schemaName, "version", result.ToVersion, "position", i+1, "total", len(schemaNames))
+		} else if result.Applied > 0 {
+			slog.Info("Tenant migrated", "schema", schemaName, "from_version", result.FromVersion, "to_version", result.ToVersion, "position", i+1, "total", len(schemaNames))
+		}
+	}
+
+	slog.Info("Tenant migrations finished", "schemas", len(schemaNames), "failed", failed)
+	return results, nil
+}
+
+func (d *Database) migrateTenant(schemaName string) TenantMigrationResult {
+	result := TenantMigrationResult{Schema: schemaName}
+
+	if err := d.CreateClientSchema(schemaName); err != nil {
+		result.Err = err
+		return result
+	}
+
+	migrator, err := d.TenantMigrator(schemaName)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	if result.FromVersion, err = migrator.Version(); err != nil {
+		result.Err = err
+		return result
+	}
+
+	result.Applied, result.Err = migrator.Up()
+
+	// Report the version actually reached, also after a failure
+	if version, err := migrator.Version(); err == nil {
+		result.ToVersion = version
+	}
+
+	return result
+}
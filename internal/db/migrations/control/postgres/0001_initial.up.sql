@@ -0,0 +1,133 @@
+-- Control-plane tables. IF NOT EXISTS lets databases created by the former
+-- AutoMigrate setup adopt this migration without changes.
+
+CREATE TABLE IF NOT EXISTS admin_users (
+    id bigserial PRIMARY KEY,
+    username varchar(50) NOT NULL,
+    email varchar(100) NOT NULL,
+    password_hash varchar(255) NOT NULL,
+    disabled_at timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    CONSTRAINT uni_admin_users_username UNIQUE (username),
+    CONSTRAINT uni_admin_users_email UNIQUE (email)
+);
+CREATE INDEX IF NOT EXISTS idx_admin_users_deleted_at ON admin_users (deleted_at);
+
+CREATE TABLE IF NOT EXISTS clients (
+    client_name text NOT NULL,
+    client_secret text NOT NULL,
+    user_id bigint NOT NULL,
+    schema_name text NOT NULL,
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    CONSTRAINT fk_clients_user FOREIGN KEY (user_id) REFERENCES admin_users (id) ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT uni_clients_client_name UNIQUE (client_name)
+);
+CREATE INDEX IF NOT EXISTS idx_clients_deleted_at ON clients (deleted_at);
+
+CREATE TABLE IF NOT EXISTS password_histories (
+    user_id bigint NOT NULL,
+    password_hash varchar(255) NOT NULL,
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_password_histories_deleted_at ON password_histories (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_password_histories_user_id ON password_histories (user_id);
+
+CREATE TABLE IF NOT EXISTS sessions (
+    session_id varchar(36) NOT NULL,
+    user_id bigint NOT NULL,
+    client_id bigint,
+    family_id varchar(36) NOT NULL,
+    user_agent varchar(512),
+    ip_address varchar(64),
+    last_seen_at timestamptz,
+    expires_at timestamptz,
+    revoked_at timestamptz,
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_sessions_deleted_at ON sessions (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_sessions_family_id ON sessions (family_id);
+CREATE INDEX IF NOT EXISTS idx_sessions_client_id ON sessions (client_id);
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions (user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sessions_session_id ON sessions (session_id);
+
+CREATE TABLE IF NOT EXISTS audit_events (
+    id bigserial PRIMARY KEY,
+    occurred_at timestamptz NOT NULL,
+    actor_type varchar(20) NOT NULL,
+    actor_id bigint,
+    actor_name varchar(100),
+    action varchar(50) NOT NULL,
+    target_type varchar(50),
+    target_id varchar(100),
+    client_id bigint,
+    owner_user_id bigint,
+    ip_address varchar(64),
+    user_agent varchar(512),
+    outcome varchar(10) NOT NULL,
+    reason varchar(255)
+);
+CREATE INDEX IF NOT EXISTS idx_audit_events_owner_user_id ON audit_events (owner_user_id);
+CREATE INDEX IF NOT EXISTS idx_audit_events_client_id ON audit_events (client_id);
+CREATE INDEX IF NOT EXISTS idx_audit_events_action ON audit_events (action);
+CREATE INDEX IF NOT EXISTS idx_audit_events_occurred_at ON audit_events (occurred_at);
+
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    client_id bigint NOT NULL,
+    url varchar(2048) NOT NULL,
+    secret varchar(128) NOT NULL,
+    event_types jsonb,
+    active boolean NOT NULL DEFAULT true,
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    CONSTRAINT fk_webhook_subscriptions_client FOREIGN KEY (client_id) REFERENCES clients (id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_deleted_at ON webhook_subscriptions (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_client_id ON webhook_subscriptions (client_id);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    subscription_id bigint NOT NULL,
+    event_id varchar(36) NOT NULL,
+    event_type varchar(50) NOT NULL,
+    payload jsonb,
+    status varchar(20) NOT NULL,
+    attempts bigint NOT NULL DEFAULT 0,
+    next_attempt_at timestamptz NOT NULL,
+    last_attempt_at timestamptz,
+    delivered_at timestamptz,
+    last_status_code bigint,
+    last_error varchar(1024),
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_deleted_at ON webhook_deliveries (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_next_attempt_at ON webhook_deliveries (next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_status ON webhook_deliveries (status);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_event_id ON webhook_deliveries (event_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription_id ON webhook_deliveries (subscription_id);
+
+CREATE TABLE IF NOT EXISTS webhook_delivery_attempts (
+    id bigserial PRIMARY KEY,
+    delivery_id bigint NOT NULL,
+    attempt bigint NOT NULL,
+    attempted_at timestamptz NOT NULL,
+    status_code bigint,
+    duration_ms bigint,
+    error varchar(1024),
+    response_body varchar(1024)
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_delivery_attempts_delivery_id ON webhook_delivery_attempts (delivery_id);
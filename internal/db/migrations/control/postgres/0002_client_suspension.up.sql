@@ -0,0 +1 @@
+ALTER TABLE clients ADD COLUMN suspended_at timestamptz;
@@ -0,0 +1,14 @@
+CREATE TABLE idempotency_keys (
+    id bigserial PRIMARY KEY,
+    scope varchar(64) NOT NULL,
+    key varchar(255) NOT NULL,
+    fingerprint varchar(64) NOT NULL,
+    status_code bigint,
+    content_type varchar(255),
+    location varchar(2048),
+    response_body bytea,
+    created_at timestamptz,
+    expires_at timestamptz NOT NULL
+);
+CREATE UNIQUE INDEX idx_idempotency_keys_scope_key ON idempotency_keys (scope, key);
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
@@ -0,0 +1,3 @@
+ALTER TABLE webhook_subscriptions DROP COLUMN version;
+ALTER TABLE clients DROP COLUMN version;
+ALTER TABLE admin_users DROP COLUMN version;
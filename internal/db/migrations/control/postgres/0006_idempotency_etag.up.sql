@@ -0,0 +1,4 @@
+-- Stored responses may hold client and webhook secrets, which are no longer
+-- kept; the keys expire within a day anyway
+DELETE FROM idempotency_keys;
+ALTER TABLE idempotency_keys ADD COLUMN etag varchar(255);
@@ -0,0 +1,133 @@
+-- Control-plane tables. IF NOT EXISTS lets databases created by the former
+-- AutoMigrate setup adopt this migration without changes.
+
+CREATE TABLE IF NOT EXISTS admin_users (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    username text NOT NULL,
+    email text NOT NULL,
+    password_hash text NOT NULL,
+    disabled_at datetime,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    CONSTRAINT uni_admin_users_username UNIQUE (username),
+    CONSTRAINT uni_admin_users_email UNIQUE (email)
+);
+CREATE INDEX IF NOT EXISTS idx_admin_users_deleted_at ON admin_users (deleted_at);
+
+CREATE TABLE IF NOT EXISTS clients (
+    client_name text NOT NULL,
+    client_secret text NOT NULL,
+    user_id integer NOT NULL,
+    schema_name text NOT NULL,
+    id integer PRIMARY KEY AUTOINCREMENT,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    CONSTRAINT fk_clients_user FOREIGN KEY (user_id) REFERENCES admin_users (id) ON DELETE CASCADE ON UPDATE CASCADE,
+    CONSTRAINT uni_clients_client_name UNIQUE (client_name)
+);
+CREATE INDEX IF NOT EXISTS idx_clients_deleted_at ON clients (deleted_at);
+
+CREATE TABLE IF NOT EXISTS password_histories (
+    user_id integer NOT NULL,
+    password_hash text NOT NULL,
+    id integer PRIMARY KEY AUTOINCREMENT,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime
+);
+CREATE INDEX IF NOT EXISTS idx_password_histories_deleted_at ON password_histories (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_password_histories_user_id ON password_histories (user_id);
+
+CREATE TABLE IF NOT EXISTS sessions (
+    session_id text NOT NULL,
+    user_id integer NOT NULL,
+    client_id integer,
+    family_id text NOT NULL,
+    user_agent text,
+    ip_address text,
+    last_seen_at datetime,
+    expires_at datetime,
+    revoked_at datetime,
+    id integer PRIMARY KEY AUTOINCREMENT,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime
+);
+CREATE INDEX IF NOT EXISTS idx_sessions_deleted_at ON sessions (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_sessions_family_id ON sessions (family_id);
+CREATE INDEX IF NOT EXISTS idx_sessions_client_id ON sessions (client_id);
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions (user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sessions_session_id ON sessions (session_id);
+
+CREATE TABLE IF NOT EXISTS audit_events (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    occurred_at datetime NOT NULL,
+    actor_type text NOT NULL,
+    actor_id integer,
+    actor_name text,
+    action text NOT NULL,
+    target_type text,
+    target_id text,
+    client_id integer,
+    owner_user_id integer,
+    ip_address text,
+    user_agent text,
+    outcome text NOT NULL,
+    reason text
+);
+CREATE INDEX IF NOT EXISTS idx_audit_events_owner_user_id ON audit_events (owner_user_id);
+CREATE INDEX IF NOT EXISTS idx_audit_events_client_id ON audit_events (client_id);
+CREATE INDEX IF NOT EXISTS idx_audit_events_action ON audit_events (action);
+CREATE INDEX IF NOT EXISTS idx_audit_events_occurred_at ON audit_events (occurred_at);
+
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    client_id integer NOT NULL,
+    url text NOT NULL,
+    secret text NOT NULL,
+    event_types JSON,
+    active numeric NOT NULL DEFAULT true,
+    id integer PRIMARY KEY AUTOINCREMENT,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    CONSTRAINT fk_webhook_subscriptions_client FOREIGN KEY (client_id) REFERENCES clients (id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_deleted_at ON webhook_subscriptions (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_client_id ON webhook_subscriptions (client_id);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    subscription_id integer NOT NULL,
+    event_id text NOT NULL,
+    event_type text NOT NULL,
+    payload JSON,
+    status text NOT NULL,
+    attempts integer NOT NULL DEFAULT 0,
+    next_attempt_at datetime NOT NULL,
+    last_attempt_at datetime,
+    delivered_at datetime,
+    last_status_code integer,
+    last_error text,
+    id integer PRIMARY KEY AUTOINCREMENT,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_deleted_at ON webhook_deliveries (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_next_attempt_at ON webhook_deliveries (next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_status ON webhook_deliveries (status);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_event_id ON webhook_deliveries (event_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription_id ON webhook_deliveries (subscription_id);
+
+CREATE TABLE IF NOT EXISTS webhook_delivery_attempts (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    delivery_id integer NOT NULL,
+    attempt integer NOT NULL,
+    attempted_at datetime NOT NULL,
+    status_code integer,
+    duration_ms integer,
+    error text,
+    response_body text
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_delivery_attempts_delivery_id ON webhook_delivery_attempts (delivery_id);
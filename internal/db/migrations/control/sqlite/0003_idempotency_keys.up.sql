@@ -0,0 +1,14 @@
+CREATE TABLE idempotency_keys (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    scope text NOT NULL,
+    key text NOT NULL,
+    fingerprint text NOT NULL,
+    status_code integer,
+    content_type text,
+    location text,
+    response_body blob,
+    created_at datetime,
+    expires_at datetime NOT NULL
+);
+CREATE UNIQUE INDEX idx_idempotency_keys_scope_key ON idempotency_keys (scope, key);
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys (expires_at);
@@ -0,0 +1,4 @@
+-- Versions let updates detect that a resource changed since it was read
+ALTER TABLE admin_users ADD COLUMN version integer NOT NULL DEFAULT 1;
+ALTER TABLE clients ADD COLUMN version integer NOT NULL DEFAULT 1;
+ALTER TABLE webhook_subscriptions ADD COLUMN version integer NOT NULL DEFAULT 1;
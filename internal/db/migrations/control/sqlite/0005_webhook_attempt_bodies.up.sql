@@ -0,0 +1,2 @@
+-- Response bodies of receivers are no longer kept
+ALTER TABLE webhook_delivery_attempts DROP COLUMN response_body;
@@ -0,0 +1,2 @@
+-- Console sessions get a random CSRF token; other sessions have none
+ALTER TABLE sessions ADD COLUMN csrf_token varchar(64);
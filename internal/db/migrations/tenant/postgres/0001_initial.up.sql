@@ -0,0 +1,38 @@
+-- Per-client tables. {{table "x"}} expands to the table inside the client
+-- schema and {{name "x"}} to the same name usable inside identifiers.
+-- IF NOT EXISTS lets schemas created by the former AutoMigrate setup adopt
+-- this migration without changes.
+
+CREATE TABLE IF NOT EXISTS {{table "users"}} (
+    id bigserial PRIMARY KEY,
+    username varchar(50) NOT NULL,
+    email varchar(100) NOT NULL,
+    password_hash varchar(255) NOT NULL,
+    disabled_at timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    CONSTRAINT uni_{{name "users"}}_username UNIQUE (username),
+    CONSTRAINT uni_{{name "users"}}_email UNIQUE (email)
+);
+CREATE INDEX IF NOT EXISTS idx_{{name "users"}}_deleted_at ON {{table "users"}} (deleted_at);
+
+CREATE TABLE IF NOT EXISTS {{table "configs"}} (
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    settings jsonb
+);
+CREATE INDEX IF NOT EXISTS idx_{{name "configs"}}_deleted_at ON {{table "configs"}} (deleted_at);
+
+CREATE TABLE IF NOT EXISTS {{table "password_histories"}} (
+    user_id bigint NOT NULL,
+    password_hash varchar(255) NOT NULL,
+    id bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_{{name "password_histories"}}_deleted_at ON {{table "password_histories"}} (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_{{name "password_histories"}}_user_id ON {{table "password_histories"}} (user_id);
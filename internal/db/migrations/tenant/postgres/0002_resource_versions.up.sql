@@ -0,0 +1,3 @@
+-- Versions let updates detect that a resource changed since it was read
+ALTER TABLE {{table "users"}} ADD COLUMN version bigint NOT NULL DEFAULT 1;
+ALTER TABLE {{table "configs"}} ADD COLUMN version bigint NOT NULL DEFAULT 1;
@@ -0,0 +1,19 @@
+-- Roles the client defines for its users. The roles of a user are listed in
+-- the roles claim of their tokens.
+CREATE TABLE {{table "roles"}} (
+    id bigserial PRIMARY KEY,
+    name varchar(50) NOT NULL,
+    description varchar(255) NOT NULL DEFAULT '',
+    created_at timestamptz,
+    updated_at timestamptz,
+    CONSTRAINT uni_{{name "roles"}}_name UNIQUE (name)
+);
+
+CREATE TABLE {{table "user_roles"}} (
+    user_id bigint NOT NULL,
+    role_id bigint NOT NULL,
+    PRIMARY KEY (user_id, role_id),
+    CONSTRAINT fk_{{name "user_roles"}}_user FOREIGN KEY (user_id) REFERENCES {{table "users"}} (id) ON DELETE CASCADE,
+    CONSTRAINT fk_{{name "user_roles"}}_role FOREIGN KEY (role_id) REFERENCES {{table "roles"}} (id) ON DELETE CASCADE
+);
+CREATE INDEX idx_{{name "user_roles"}}_role_id ON {{table "user_roles"}} (role_id);
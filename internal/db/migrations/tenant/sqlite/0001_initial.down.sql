@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS {{table "password_histories"}};
+DROP TABLE IF EXISTS {{table "configs"}};
+DROP TABLE IF EXISTS {{table "users"}};
@@ -0,0 +1,38 @@
+-- Per-client tables. {{table "x"}} expands to the table inside the client
+-- schema and {{name "x"}} to the same name usable inside identifiers.
+-- IF NOT EXISTS lets schemas created by the former AutoMigrate setup adopt
+-- this migration without changes.
+
+CREATE TABLE IF NOT EXISTS {{table "users"}} (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    username text NOT NULL,
+    email text NOT NULL,
+    password_hash text NOT NULL,
+    disabled_at datetime,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    CONSTRAINT uni_{{name "users"}}_username UNIQUE (username),
+    CONSTRAINT uni_{{name "users"}}_email UNIQUE (email)
+);
+CREATE INDEX IF NOT EXISTS idx_{{name "users"}}_deleted_at ON {{table "users"}} (deleted_at);
+
+CREATE TABLE IF NOT EXISTS {{table "configs"}} (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    settings JSON
+);
+CREATE INDEX IF NOT EXISTS idx_{{name "configs"}}_deleted_at ON {{table "configs"}} (deleted_at);
+
+CREATE TABLE IF NOT EXISTS {{table "password_histories"}} (
+    user_id integer NOT NULL,
+    password_hash text NOT NULL,
+    id integer PRIMARY KEY AUTOINCREMENT,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime
+);
+CREATE INDEX IF NOT EXISTS idx_{{name "password_histories"}}_deleted_at ON {{table "password_histories"}} (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_{{name "password_histories"}}_user_id ON {{table "password_histories"}} (user_id);
@@ -0,0 +1,2 @@
+ALTER TABLE {{table "configs"}} DROP COLUMN version;
+ALTER TABLE {{table "users"}} DROP COLUMN version;
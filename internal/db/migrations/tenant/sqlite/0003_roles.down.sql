@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS {{table "user_roles"}};
+DROP TABLE IF EXISTS {{table "roles"}};
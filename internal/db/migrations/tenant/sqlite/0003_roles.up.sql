@@ -0,0 +1,19 @@
+-- Roles the client defines for its users. The roles of a user are listed in
+-- the roles claim of their tokens.
+CREATE TABLE {{table "roles"}} (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    name text NOT NULL,
+    description text NOT NULL DEFAULT '',
+    created_at datetime,
+    updated_at datetime,
+    CONSTRAINT uni_{{name "roles"}}_name UNIQUE (name)
+);
+
+CREATE TABLE {{table "user_roles"}} (
+    user_id integer NOT NULL,
+    role_id integer NOT NULL,
+    PRIMARY KEY (user_id, role_id),
+    CONSTRAINT fk_{{name "user_roles"}}_user FOREIGN KEY (user_id) REFERENCES {{table "users"}} (id) ON DELETE CASCADE,
+    CONSTRAINT fk_{{name "user_roles"}}_role FOREIGN KEY (role_id) REFERENCES {{table "roles"}} (id) ON DELETE CASCADE
+);
+CREATE INDEX idx_{{name "user_roles"}}_role_id ON {{table "user_roles"}} (role_id);
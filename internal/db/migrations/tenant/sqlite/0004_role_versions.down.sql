@@ -0,0 +1 @@
+ALTER TABLE {{table "roles"}} DROP COLUMN version;
@@ -0,0 +1,2 @@
+-- Versions let updates detect that a resource changed since it was read
+ALTER TABLE {{table "roles"}} ADD COLUMN version integer NOT NULL DEFAULT 1;
@@ -0,0 +1,301 @@
+package db
+
+import (
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Kantha2004/SimpleJWT/internal/models"
+	"gorm.io/gorm"
+)
+
+const (
+	DEFAULT_PAGE_LIMIT = 20
+	MAX_PAGE_LIMIT     = 100
+)
+
+// ListParamError reports a list parameter that cannot be used. Format and
+// Args describe the problem in the words of a validation message.
+type ListParamError struct {
+	Param  string
+	Code   string
+	Format string
+	Args   []any
+}
+
+func (e *ListParamError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Param, fmt.Sprintf(e.Format, e.Args...))
+}
+
+// SortField is a field a list can be ordered by
+type SortField[T any] struct {
+	// Name is the value of the sort parameter
+	Name   string
+	Column string
+	// Value returns the value of the column, which cursors are built from
+	Value func(*T) any
+}
+
+// ListSpec describes how a list is ordered and paged. Rows are always
+// ordered by ID after the sort field, so cursors point to a single row.
+type ListSpec[T any] struct {
+	Sorts []SortField[T]
+	// DefaultSort is used when the sort parameter is empty
+	DefaultSort  string
+	DefaultLimit int
+	MaxLimit     int
+	ID           func(*T) uint
+}
+
+// Page is one page of a list
+type Page[T any] struct {
+	Items []T
+	// Page is the page number, zero when the page was fetched by cursor
+	Page  int
+	Limit int
+	// Total counts the rows of the list across every page
+	Total int64
+	// NextCursor fetches the following page; empty on the last page
+	NextCursor string
+}
+
+// cursor is the position of the last row of a page, encoded as base64 JSON
+type cursor struct {
+	Sort  string          `json:"s"`
+	Value json.RawMessage `json:"v"`
+	ID    uint            `json:"id"`
+}
+
+// listing is a list request resolved against its spec
+type listing[T any] struct {
+	spec   ListSpec[T]
+	sort   string
+	field  SortField[T]
+	desc   bool
+	limit  int
+	page   int
+	after  any
+	lastID uint
+	cursor bool
+}
+
+func (spec ListSpec[T]) resolve(params models.ListParams) (*listing[T], error) {
+	l := &listing[T]{spec: spec, sort: params.Sort, limit: params.Limit, page: params.Page}
+
+	if l.sort == "" {
+		l.sort = spec.DefaultSort
+	}
+	name := strings.TrimPrefix(l.sort, "-")
+	l.desc = name != l.sort
+
+	index := slices.IndexFunc(spec.Sorts, func(field SortField[T]) bool { return field.Name == name })
+	if index < 0 {
+		names := make([]string, len(spec.Sorts))
+		for i, field := range spec.Sorts {
+			names[i] = field.Name
+		}
+		return nil, &ListParamError{Param: "sort", Code: "oneof", Format: "must be one of: %s", Args: []any{strings.Join(names, ", ")}}
+	}
+	l.field = spec.Sorts[index]
+
+	if l.limit == 0 {
+		l.limit = spec.DefaultLimit
+	}
+	if l.limit > spec.MaxLimit {
+		return nil, &ListParamError{Param: "limit", Code: "max", Format: "must be at most %s", Args: []any{fmt.Sprint(spec.MaxLimit)}}
+	}
+	if l.page == 0 {
+		l.page = 1
+	}
+
+	if params.Cursor != "" {
+		if err := l.decodeCursor(params.Cursor); err != nil {
+			return nil, &ListParamError{Param: "cursor", Code: "cursor", Format: "is invalid"}
+		}
+		l.cursor = true
+		l.page = 0
+	}
+
+	return l, nil
+}
+
+func (l *listing[T]) decodeCursor(encoded string) error {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return err
+	}
+
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return err
+	}
+	// A cursor only makes sense in the order it was built for
+	if c.Sort != l.sort || c.ID == 0 {
+		return fmt.Errorf("cursor of sort %q used with sort %q", c.Sort, l.sort)
+	}
+
+	// Decode the value into the type of the column, so it compares like one
+	var zero T
+	value := reflect.New(reflect.TypeOf(l.field.Value(&zero)))
+	if err := json.Unmarshal(c.Value, value.Interface()); err != nil {
+		return err
+	}
+
+	l.after = value.Elem().Interface()
+	l.lastID = c.ID
+	return nil
+}
+
+func (l *listing[T]) encodeCursor(item *T) (string, error) {
+	value, err := json.Marshal(l.field.Value(item))
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(cursor{Sort: l.sort, Value: value, ID: l.spec.ID(item)})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// offset is the number of rows before the page, zero when paged by cursor
+func (l *listing[T]) offset() int {
+	if l.cursor {
+		return 0
+	}
+	return (l.page - 1) * l.limit
+}
+
+// newPage builds the page from up to limit+1 rows; the extra row tells there
+// is a next page
+func (l *listing[T]) newPage(rows []T, total int64) (*Page[T], error) {
+	page := &Page[T]{Items: rows, Page: l.page, Limit: l.limit, Total: total}
+	if len(rows) > l.limit {
+		page.Items = rows[:l.limit]
+		next, err := l.encodeCursor(&page.Items[l.limit-1])
+		if err != nil {
+			return nil, err
+		}
+		page.NextCursor = next
+	}
+	return page, nil
+}
+
+// Paginate fetches one page of the rows matched by tx. Invalid parameters
+// are reported as a *ListParamError.
+func Paginate[T any](tx *gorm.DB, spec ListSpec[T], params models.ListParams) (*Page[T], error) {
+	l, err := spec.resolve(params)
+	if err != nil {
+		return nil, err
+	}
+
+	// Lets the query be run twice, for the count and for the rows
+	tx = tx.Session(&gorm.Session{})
+
+	var total int64
+	if err := tx.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	direction, operator := "ASC", ">"
+	if l.desc {
+		direction, operator = "DESC", "<"
+	}
+
+	query := tx.Order(fmt.Sprintf("%s %s, id %s", l.field.Column, direction, direction))
+	if l.cursor {
+		query = query.Where(
+			fmt.Sprintf("(%[1]s %[2]s ? OR (%[1]s = ? AND id %[2]s ?))", l.field.Column, operator),
+			l.after, l.after, l.lastID,
+		)
+	}
+
+	var rows []T
+	if err := query.Offset(l.offset()).Limit(l.limit + 1).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	return l.newPage(rows, total)
+}
+
+// PaginateSlice pages rows held in memory exactly like Paginate pages a query
+func PaginateSlice[T any](rows []T, spec ListSpec[T], params models.ListParams) (*Page[T], error) {
+	l, err := spec.resolve(params)
+	if err != nil {
+		return nil, err
+	}
+
+	// The first error of a comparison fails the listing once sorting is done
+	var compareErr error
+	compare := func(a any, aID uint, b any, bID uint) int {
+		order, err := compareValues(a, b)
+		if err != nil && compareErr == nil {
+			compareErr = err
+		}
+		if order == 0 {
+			order = cmp.Compare(aID, bID)
+		}
+		if l.desc {
+			return -order
+		}
+		return order
+	}
+
+	sorted := slices.Clone(rows)
+	slices.SortFunc(sorted, func(a, b T) int {
+		return compare(l.field.Value(&a), spec.ID(&a), l.field.Value(&b), spec.ID(&b))
+	})
+
+	total := int64(len(sorted))
+	if l.cursor {
+		sorted = slices.DeleteFunc(sorted, func(row T) bool {
+			return compare(l.field.Value(&row), spec.ID(&row), l.after, l.lastID) <= 0
+		})
+	}
+	if compareErr != nil {
+		return nil, fmt.Errorf("sorting by %s: %w", l.field.Name, compareErr)
+	}
+
+	start := min(l.offset(), len(sorted))
+	end := min(start+l.limit+1, len(sorted))
+	return l.newPage(sorted[start:end], total)
+}
+
+// containsPattern is the LIKE pattern of the lowercase values containing s
+func containsPattern(s string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(strings.ToLower(s))
+	return "%" + escaped + "%"
+}
+
+// compareValues orders two values of a sort field. Values of other types,
+// or of two different types, cannot be ordered.
+func compareValues(a, b any) (int, error) {
+	switch a := a.(type) {
+	case time.Time:
+		return compareAs(a, b, time.Time.Compare)
+	case string:
+		return compareAs(a, b, strings.Compare)
+	case uint:
+		return compareAs(a, b, cmp.Compare[uint])
+	case int:
+		return compareAs(a, b, cmp.Compare[int])
+	case int64:
+		return compareAs(a, b, cmp.Compare[int64])
+	default:
+		return 0, fmt.Errorf("cannot sort by values of type %T", a)
+	}
+}
+
+// compareAs orders a and b with compare when b has the type of a
+func compareAs[V any](a V, b any, compare func(V, V) int) (int, error) {
+	other, ok := b.(V)
+	if !ok {
+		return 0, fmt.Errorf("cannot compare %T with %T", a, b)
+	}
+	return compare(a, other), nil
+}
@@ -0,0 +1,33 @@
+package db_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Kantha2004/SimpleJWT/internal/db"
+	"github.com/Kantha2004/SimpleJWT/internal/models"
+)
+
+type scored struct {
+	ID    uint
+	Score float64
+}
+
+func TestPaginateSliceUnsortableValues(t *testing.T) {
+	spec := db.ListSpec[scored]{
+		Sorts: []db.SortField[scored]{
+			{Name: "score", Column: "score", Value: func(s *scored) any { return s.Score }},
+		},
+		DefaultSort:  "score",
+		DefaultLimit: 10,
+		MaxLimit:     10,
+		ID:           func(s *scored) uint { return s.ID },
+	}
+	rows := []scored{{ID: 1, Score: 0.5}, {ID: 2, Score: 0.25}}
+
+	page, err := db.PaginateSlice(rows, spec, models.ListParams{})
+	var paramErr *db.ListParamError
+	if err == nil || errors.As(err, &paramErr) {
+		t.Fatalf("PaginateSlice by float values = %+v, %v, want an error of the server", page, err)
+	}
+}
@@ -0,0 +1,75 @@
+package db
+
+import (
+	"github.com/Kantha2004/SimpleJWT/internal/models"
+	"gorm.io/gorm"
+)
+
+const ADMIN_PASSWORD_HISTORY_TABLE = "password_histories"
+
+type PasswordHistoryRepository struct {
+	db    *Database
+	table string
+}
+
+// NewPasswordHistoryRepository returns the password history of admin users
+func NewPasswordHistoryRepository(db *Database) *PasswordHistoryRepository {
+	return &PasswordHistoryRepository{db: db, table: ADMIN_PASSWORD_HISTORY_TABLE}
+}
+
+// NewClientPasswordHistoryRepository returns the password history of a client's users
+func NewClientPasswordHistoryRepository(db *Database, schemaName string) *PasswordHistoryRepository {
+	return &PasswordHistoryRepository{
+		db:    db,
+		table: db.QualifiedTableName(schemaName, CLIENT_PASSWORD_HISTORY_TABLE),
+	}
+}
+
+// AddPasswordHash records a password hash for the user and prunes entries
+// beyond the most recent keep hashes. A keep of zero or less keeps everything.
+func (phr *PasswordHistoryRepository) AddPasswordHash(userID uint, passwordHash string, keep int) error {
+	db, span := phr.db.startSpan("PasswordHistoryRepository.AddPasswordHash")
+	defer span.End()
+
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		entry := &models.PasswordHistory{UserID: userID, PasswordHash: passwordHash}
+		if err := tx.Table(phr.table).Create(entry).Error; err != nil {
+			return err
+		}
+
+		if keep <= 0 {
+			return nil
+		}
+
+		var staleIDs []uint
+		err := tx.Table(phr.table).
+			Where("user_id = ?", userID).
+			Order("id DESC").
+			Offset(keep).
+			Pluck("id", &staleIDs).Error
+		if err != nil || len(staleIDs) == 0 {
+			return err
+		}
+
+		return tx.Table(phr.table).Unscoped().Delete(&models.PasswordHistory{}, staleIDs).Error
+	})
+}
+
+// GetRecentPasswordHashes returns up to limit password hashes of the user, newest first
+func (phr *PasswordHistoryRepository) GetRecentPasswordHashes(userID uint, limit int) ([]string, error) {
+	db, span := phr.db.startSpan("PasswordHistoryRepository.GetRecentPasswordHashes")
+	defer span.End()
+
+	if limit <= 0 {
+		return nil, nil
+	}
+
+	var hashes []string
+	result := db.DB.Table(phr.table).
+		Where("user_id = ?", userID).
+		Order("id DESC").
+		Limit(limit).
+		Pluck("password_hash", &hashes)
+
+	return hashes, result.Error
+}
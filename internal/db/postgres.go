@@ -1,70 +1,133 @@
 package db
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"time"
 
 	"github.com/Kantha2004/SimpleJWT/internal/config"
-	"github.com/Kantha2004/SimpleJWT/internal/models"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
 
 type Database struct {
-	DB *gorm.DB
+	DB     *gorm.DB
+	Driver string
 }
 
-// NewDatabase connects to PostgreSQL
+// NewDatabase connects to the database and applies the pending migrations
 func NewDatabase(cfg *config.DBConfig) (*Database, error) {
-	config := &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+	database, err := Connect(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := database.migrate(cfg.MigrateTenantsOnStartup); err != nil {
+		return nil, err
 	}
 
-	// PostgreSQL DSN
+	return database, nil
+}
+
+// Connect connects to PostgreSQL, or to SQLite when the driver is sqlite,
+// without running migrations
+func Connect(cfg *config.DBConfig) (*Database, error) {
+	gormConfig := &gorm.Config{
+		Logger: newQueryLogger(),
+	}
 
-	dsn := fmt.Sprintf(
-		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
-		cfg.DBHost,
-		cfg.DBPort,
-		cfg.DBUser,
-		cfg.DBPassword,
-		cfg.DBName,
-		cfg.SSLMode,
-	)
+	var dialector gorm.Dialector
+	switch cfg.Driver {
+	case "", config.DriverPostgres:
+		dialector = postgres.Open(cfg.PostgresDSN())
+	case config.DriverSQLite:
+		dialector = openSQLite(cfg.SQLitePath)
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", cfg.Driver)
+	}
 
-	db, err := gorm.Open(postgres.Open(dsn), config)
+	db, err := gorm.Open(dialector, gormConfig)
 	if err != nil {
 		return nil, err
 	}
 
-	database := &Database{DB: db}
-
-	if err := database.migrate(); err != nil {
+	// Every query gets a span, a child of the repository call running it
+	if err := db.Use(queryTracing{}); err != nil {
 		return nil, err
 	}
 
+	database := &Database{DB: db, Driver: db.Dialector.Name()}
+
+	if database.IsSQLite() {
+		if err := configureSQLite(db); err != nil {
+			return nil, err
+		}
+	}
+
 	return database, nil
 }
 
+// newQueryLogger writes GORM's logs through the default slog logger. Every
+// statement is only logged when the debug level is enabled, and never with
+// its bound values, which would include password hashes.
+func newQueryLogger() logger.Interface {
+	level := logger.Warn
+	if slog.Default().Enabled(context.Background(), slog.LevelDebug) {
+		level = logger.Info
+	}
+
+	return logger.NewSlogLogger(slog.Default(), logger.Config{
+		LogLevel:                  level,
+		SlowThreshold:             200 * time.Millisecond,
+		ParameterizedQueries:      true,
+		IgnoreRecordNotFoundError: true,
+	})
+}
+
 func (d *Database) GetDB() *gorm.DB {
 	return d.DB
 }
 
-func (d *Database) migrate() error {
-	err := d.DB.AutoMigrate(
-		&models.AdminUser{},
-		&models.Client{},
-	)
+// migrate applies the control-plane migrations and, when enabled, upgrades
+// every client schema. Failing client schemas are logged but do not stop the
+// server from starting.
+func (d *Database) migrate(migrateTenants bool) error {
+	migrator, err := d.ControlPlaneMigrator()
+	if err != nil {
+		return err
+	}
 
+	applied, err := migrator.Up()
 	if err != nil {
 		return err
 	}
+	slog.Info("Database migration completed", "driver", d.Driver, "applied", applied)
+
+	if migrateTenants {
+		if _, err := d.MigrateAllTenants(); err != nil {
+			return fmt.Errorf("failed to list client schemas: %w", err)
+		}
+	}
 
-	log.Println("PostgreSQL database migration completed successfully")
 	return nil
 }
 
+// Ping checks that the database is reachable
+func (d *Database) Ping(ctx context.Context) error {
+	sqlDB, err := d.DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// WithContext returns a copy of the database whose queries are bound to ctx
+func (d *Database) WithContext(ctx context.Context) *Database {
+	return &Database{DB: d.DB.WithContext(ctx), Driver: d.Driver}
+}
+
 func (d *Database) Close() error {
 	sqlDB, err := d.DB.DB()
 	if err != nil {
@@ -73,6 +136,20 @@ func (d *Database) Close() error {
 	return sqlDB.Close()
 }
 
+// QualifiedTableName returns the name of a table in a client schema. SQLite
+// has no schemas, so client tables are emulated with a schema name prefix.
+func (d *Database) QualifiedTableName(schema, table string) string {
+	if d.IsSQLite() {
+		return sqliteTableName(schema, table)
+	}
+	return fmt.Sprintf("%s.%s", schema, table)
+}
+
 func (d *Database) TableWithSchema(schema, table string) *gorm.DB {
-	return d.DB.Table(fmt.Sprintf("%s.%s", schema, table))
+	return d.DB.Table(d.QualifiedTableName(schema, table))
+}
+
+// IsSQLite reports whether the database uses the sqlite driver
+func (d *Database) IsSQLite() bool {
+	return d.Driver == config.DriverSQLite
 }
@@ -0,0 +1,106 @@
+package db
+
+import (
+	"context"
+
+	"github.com/Kantha2004/SimpleJWT/internal/models"
+)
+
+// UserRepository stores admin users
+type UserRepository interface {
+	// WithContext returns the repository bound to a request context, so its
+	// calls join the request's trace
+	WithContext(ctx context.Context) UserRepository
+	CreateUser(user *models.AdminUser) (uint, error)
+	GetUserByID(id uint) (*models.AdminUser, error)
+	GetUserByEmail(email string) (*models.AdminUser, error)
+	GetUserByUsername(username string) (*models.AdminUser, error)
+	// UpdateUser saves the user and increments its version. Returns
+	// ErrVersionConflict when the user changed since it was read.
+	UpdateUser(user *models.AdminUser) error
+	UserExists(username, email string) (bool, error)
+	EmailExists(email string) (bool, error)
+	UserNameExists(username string) (bool, error)
+}
+
+// ClientRepository stores the clients of admin users
+type ClientRepository interface {
+	WithContext(ctx context.Context) ClientRepository
+	// CreateClient stores the client and returns its generated secret
+	CreateClient(client *models.Client) (string, error)
+	GetClientId(id uint) (*models.Client, error)
+	GetClientByNameForUser(clientName string, userID uint) (*models.Client, error)
+	GetClientByUserId(userID uint) (*models.Client, error)
+	GetAllClientsByUserId(userID uint) ([]models.Client, error)
+	ListClientsByUserId(userID uint, query models.ClientListQuery) (*Page[models.Client], error)
+	GetAllClients() ([]models.Client, error)
+	// UpdateClient saves the client and increments its version. Returns
+	// ErrVersionConflict when the client changed since it was read.
+	UpdateClient(client *models.Client) error
+}
+
+// ClientUserRepository stores the users of one client
+type ClientUserRepository interface {
+	WithContext(ctx context.Context) ClientUserRepository
+	CreateClientUser(user *models.ClientUser) (*models.ClientUser, error)
+	GetClientUserByID(id uint) (*models.ClientUser, error)
+	GetClientAllUser() (*[]models.ClientUser, error)
+	ListClientUsers(query models.ClientUserListQuery) (*Page[models.ClientUser], error)
+	GetClientUserByEmail(email string) (*models.ClientUser, error)
+	GetClientUserByUsername(username string) (*models.ClientUser, error)
+	// UpdateClientUser saves the user and increments its version. Returns
+	// ErrVersionConflict when the user changed since it was read.
+	UpdateClientUser(user *models.ClientUser) error
+	ClientUserExists(username, email string) (bool, error)
+	ClientUserEmailExists(email string) (bool, error)
+	ClientUserNameExists(username string) (bool, error)
+}
+
+// ClientUserRepositoryFactory returns the user repository of a client schema
+type ClientUserRepositoryFactory func(schemaName string) ClientUserRepository
+
+// NewClientUserRepositoryFactory returns a factory of SQL client user repositories
+func NewClientUserRepositoryFactory(db *Database) ClientUserRepositoryFactory {
+	return func(schemaName string) ClientUserRepository {
+		return NewClientUserRepository(db, schemaName)
+	}
+}
+
+// RoleRepository stores the roles of one client and the users they are
+// granted to. Roles are listed by name.
+type RoleRepository interface {
+	WithContext(ctx context.Context) RoleRepository
+	ListRoles() ([]models.Role, error)
+	GetRoleByID(id uint) (*models.Role, error)
+	GetRoleByName(name string) (*models.Role, error)
+	// GetRolesByNames returns the roles with the given names; names the
+	// client has no role of are left out
+	GetRolesByNames(names []string) ([]models.Role, error)
+	CreateRole(role *models.Role) error
+	// DeleteRole deletes a role; the users it was granted to lose it.
+	// Returns ErrVersionConflict when the role changed since it was read.
+	DeleteRole(role *models.Role) error
+	GetUserRoles(userID uint) ([]models.Role, error)
+	// GetUserRoleNames returns the names of the roles granted to a user, as
+	// listed in the roles claim of their tokens
+	GetUserRoleNames(userID uint) ([]string, error)
+	// SetUserRoles replaces the roles granted to a user
+	SetUserRoles(userID uint, roleIDs []uint) error
+}
+
+// RoleRepositoryFactory returns the role repository of a client schema
+type RoleRepositoryFactory func(schemaName string) RoleRepository
+
+// NewRoleRepositoryFactory returns a factory of SQL role repositories
+func NewRoleRepositoryFactory(db *Database) RoleRepositoryFactory {
+	return func(schemaName string) RoleRepository {
+		return NewRoleRepository(db, schemaName)
+	}
+}
+
+var (
+	_ UserRepository       = (*SQLUserRepository)(nil)
+	_ ClientRepository     = (*SQLClientRepository)(nil)
+	_ ClientUserRepository = (*SQLClientUserRepository)(nil)
+	_ RoleRepository       = (*SQLRoleRepository)(nil)
+)
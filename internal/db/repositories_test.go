@@ -0,0 +1,106 @@
+package db_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/Kantha2004/SimpleJWT/internal/config"
+	"github.com/Kantha2004/SimpleJWT/internal/db"
+	"github.com/Kantha2004/SimpleJWT/internal/db/dbtest"
+	"github.com/Kantha2004/SimpleJWT/internal/models"
+)
+
+// The SQL repositories run the contract on SQLite, migrated like a server
+// database, in a new file for every test
+
+func newSQLiteDatabase(t *testing.T) *db.Database {
+	t.Helper()
+
+	database, err := db.NewDatabase(&config.DBConfig{
+		Driver:     config.DriverSQLite,
+		SQLitePath: filepath.Join(t.TempDir(), "simplejwt.db"),
+	})
+	if err != nil {
+		t.Fatalf("NewDatabase: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+	return database
+}
+
+func TestSQLUserRepository(t *testing.T) {
+	dbtest.RunUserRepositoryContract(t, func(t *testing.T) db.UserRepository {
+		return db.NewUserRepository(newSQLiteDatabase(t))
+	})
+}
+
+func TestSQLClientRepository(t *testing.T) {
+	var database *db.Database
+	var owners int
+	dbtest.RunClientRepositoryContract(t,
+		func(t *testing.T) db.ClientRepository {
+			database = newSQLiteDatabase(t)
+			return db.NewClientRepository(database)
+		},
+		// Clients reference their owner, who must be stored first
+		func(t *testing.T) uint {
+			owners++
+			id, err := db.NewUserRepository(database).CreateUser(&models.AdminUser{
+				Username:     fmt.Sprintf("owner%d", owners),
+				Email:        fmt.Sprintf("owner%d@example.com", owners),
+				PasswordHash: "hash",
+			})
+			if err != nil {
+				t.Fatalf("CreateUser: %v", err)
+			}
+			return id
+		},
+	)
+}
+
+func TestSQLClientUserRepository(t *testing.T) {
+	dbtest.RunClientUserRepositoryContract(t, func(t *testing.T, schemaNames ...string) db.ClientUserRepositoryFactory {
+		return db.NewClientUserRepositoryFactory(newTenantDatabase(t, schemaNames...))
+	})
+}
+
+func TestSQLRoleRepository(t *testing.T) {
+	var database *db.Database
+	var grantees int
+	dbtest.RunRoleRepositoryContract(t,
+		func(t *testing.T, schemaNames ...string) db.RoleRepositoryFactory {
+			database = newTenantDatabase(t, schemaNames...)
+			return db.NewRoleRepositoryFactory(database)
+		},
+		// Grants reference their user, who must be stored first
+		func(t *testing.T, schemaName string) uint {
+			grantees++
+			user, err := db.NewClientUserRepository(database, schemaName).CreateClientUser(&models.ClientUser{
+				Username:     fmt.Sprintf("user%d", grantees),
+				Email:        fmt.Sprintf("user%d@example.com", grantees),
+				PasswordHash: "hash",
+			})
+			if err != nil {
+				t.Fatalf("CreateClientUser: %v", err)
+			}
+			return user.ID
+		},
+	)
+}
+
+// newTenantDatabase returns a database with the given client schemas created
+// and migrated
+func newTenantDatabase(t *testing.T, schemaNames ...string) *db.Database {
+	t.Helper()
+
+	database := newSQLiteDatabase(t)
+	for _, schemaName := range schemaNames {
+		if err := database.CreateClientSchema(schemaName); err != nil {
+			t.Fatalf("CreateClientSchema(%s): %v", schemaName, err)
+		}
+		if err := database.MigrateTenant(schemaName); err != nil {
+			t.Fatalf("MigrateTenant(%s): %v", schemaName, err)
+		}
+	}
+	return database
+}
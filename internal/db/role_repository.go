@@ -0,0 +1,143 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Kantha2004/SimpleJWT/internal/models"
+	"github.com/Kantha2004/SimpleJWT/internal/tracing"
+	"gorm.io/gorm"
+)
+
+type SQLRoleRepository struct {
+	db             *Database
+	schemaName     string
+	rolesTable     string
+	userRolesTable string
+}
+
+func NewRoleRepository(db *Database, schemaName string) *SQLRoleRepository {
+	return &SQLRoleRepository{
+		db:             db,
+		schemaName:     schemaName,
+		rolesTable:     db.QualifiedTableName(schemaName, CLIENT_ROLE_TABLE),
+		userRolesTable: db.QualifiedTableName(schemaName, CLIENT_USER_ROLE_TABLE),
+	}
+}
+
+func (rr *SQLRoleRepository) WithContext(ctx context.Context) RoleRepository {
+	return NewRoleRepository(rr.db.WithContext(ctx), rr.schemaName)
+}
+
+func (rr *SQLRoleRepository) ListRoles() ([]models.Role, error) {
+	db, span := rr.db.startSpan("RoleRepository.ListRoles", tracing.TenantSchemaKey.String(rr.schemaName))
+	defer span.End()
+
+	roles := []models.Role{}
+	result := db.DB.Table(rr.rolesTable).Order("name").Find(&roles)
+	return roles, result.Error
+}
+
+func (rr *SQLRoleRepository) GetRoleByID(id uint) (*models.Role, error) {
+	return rr.getRole("RoleRepository.GetRoleByID", "id = ?", id)
+}
+
+func (rr *SQLRoleRepository) GetRoleByName(name string) (*models.Role, error) {
+	return rr.getRole("RoleRepository.GetRoleByName", "name = ?", name)
+}
+
+func (rr *SQLRoleRepository) getRole(spanName, condition string, value any) (*models.Role, error) {
+	db, span := rr.db.startSpan(spanName, tracing.TenantSchemaKey.String(rr.schemaName))
+	defer span.End()
+
+	var role models.Role
+	result := db.DB.Table(rr.rolesTable).Where(condition, value).First(&role)
+
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &role, nil
+}
+
+func (rr *SQLRoleRepository) GetRolesByNames(names []string) ([]models.Role, error) {
+	db, span := rr.db.startSpan("RoleRepository.GetRolesByNames", tracing.TenantSchemaKey.String(rr.schemaName))
+	defer span.End()
+
+	roles := []models.Role{}
+	if len(names) == 0 {
+		return roles, nil
+	}
+	result := db.DB.Table(rr.rolesTable).Where("name IN ?", names).Order("name").Find(&roles)
+	return roles, result.Error
+}
+
+func (rr *SQLRoleRepository) CreateRole(role *models.Role) error {
+	db, span := rr.db.startSpan("RoleRepository.CreateRole", tracing.TenantSchemaKey.String(rr.schemaName))
+	defer span.End()
+
+	return db.DB.Table(rr.rolesTable).Create(role).Error
+}
+
+func (rr *SQLRoleRepository) DeleteRole(role *models.Role) error {
+	db, span := rr.db.startSpan("RoleRepository.DeleteRole", tracing.TenantSchemaKey.String(rr.schemaName))
+	defer span.End()
+
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := deleteVersioned(tx.Table(rr.rolesTable), role, role.Version); err != nil {
+			return err
+		}
+		return tx.Table(rr.userRolesTable).Where("role_id = ?", role.ID).Delete(&models.UserRole{}).Error
+	})
+}
+
+func (rr *SQLRoleRepository) GetUserRoles(userID uint) ([]models.Role, error) {
+	db, span := rr.db.startSpan("RoleRepository.GetUserRoles", tracing.TenantSchemaKey.String(rr.schemaName))
+	defer span.End()
+
+	roles := []models.Role{}
+	result := db.DB.Table(rr.rolesTable).
+		Select(rr.rolesTable+".*").
+		Joins("JOIN "+rr.userRolesTable+" ON "+rr.userRolesTable+".role_id = "+rr.rolesTable+".id").
+		Where(rr.userRolesTable+".user_id = ?", userID).
+		Order("name").
+		Find(&roles)
+	return roles, result.Error
+}
+
+func (rr *SQLRoleRepository) GetUserRoleNames(userID uint) ([]string, error) {
+	roles, err := rr.GetUserRoles(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(roles))
+	for i, role := range roles {
+		names[i] = role.Name
+	}
+	return names, nil
+}
+
+func (rr *SQLRoleRepository) SetUserRoles(userID uint, roleIDs []uint) error {
+	db, span := rr.db.startSpan("RoleRepository.SetUserRoles", tracing.TenantSchemaKey.String(rr.schemaName))
+	defer span.End()
+
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Table(rr.userRolesTable).Where("user_id = ?", userID).Delete(&models.UserRole{}).Error; err != nil {
+			return err
+		}
+		if len(roleIDs) == 0 {
+			return nil
+		}
+
+		grants := make([]models.UserRole, len(roleIDs))
+		for i, roleID := range roleIDs {
+			grants[i] = models.UserRole{UserID: userID, RoleID: roleID}
+		}
+		return tx.Table(rr.userRolesTable).Create(&grants).Error
+	})
+}
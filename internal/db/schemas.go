@@ -1,32 +1,76 @@
 package db
 
 import (
+	"errors"
 	"fmt"
+	"regexp"
+	"strings"
 
-	"github.com/Kantha2004/SimpleJWT/internal/models"
+	"github.com/Kantha2004/SimpleJWT/internal/tracing"
+	"gorm.io/gorm/clause"
 )
 
 const (
-	CLIENT_USER_TABLE   = "users"
-	CLIENT_CONFIG_TABLE = "configs"
+	CLIENT_USER_TABLE             = "users"
+	CLIENT_CONFIG_TABLE           = "configs"
+	CLIENT_PASSWORD_HISTORY_TABLE = "password_histories"
+	CLIENT_ROLE_TABLE             = "roles"
+	CLIENT_USER_ROLE_TABLE        = "user_roles"
 )
 
-// Create a schema for the client
-func (db *Database) CreateClientSchema(schemaName string) error {
-	return db.DB.Exec(fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s", schemaName)).Error
-}
+// clientSchemaSuffix ends the schema name of every client
+const clientSchemaSuffix = "_client"
 
-func (db *Database) MigrateClientTables(schemaName string) error {
-	userTable := fmt.Sprintf("%s.%s", schemaName, CLIENT_USER_TABLE)
-	configTable := fmt.Sprintf("%s.%s", schemaName, CLIENT_CONFIG_TABLE)
+// identifierPattern matches the schema names that may reach DDL: lowercase,
+// so PostgreSQL does not fold them, and no longer than its 63 byte limit
+var identifierPattern = regexp.MustCompile(`^[a-z0-9_]{1,63}$`)
 
-	if err := db.DB.Table(userTable).AutoMigrate(&models.ClientUser{}); err != nil {
-		return err
+// MaxClientNameLength is the longest client name that still gives a valid
+// schema name
+const MaxClientNameLength = 63 - len(clientSchemaSuffix)
+
+// ErrInvalidIdentifier is returned for a schema name that is not made of 1
+// to 63 lowercase letters, digits and underscores
+var ErrInvalidIdentifier = errors.New("invalid identifier")
+
+// ValidateIdentifier checks a schema name before it is used in SQL
+func ValidateIdentifier(name string) error {
+	if !identifierPattern.MatchString(name) {
+		return fmt.Errorf("%w %q", ErrInvalidIdentifier, name)
+	}
+	return nil
+}
+
+// ClientSchemaName returns the schema of a new client. Client names are
+// unique, and so are the schema names derived from them.
+func ClientSchemaName(clientName string) (string, error) {
+	schemaName := clientName + clientSchemaSuffix
+	if err := ValidateIdentifier(schemaName); err != nil {
+		return "", err
 	}
+	return schemaName, nil
+}
+
+// QuoteIdentifier quotes a table or schema name the way the driver does.
+// The parts of a qualified name are quoted separately.
+func (d *Database) QuoteIdentifier(name string) string {
+	var b strings.Builder
+	d.DB.Dialector.QuoteTo(&b, name)
+	return b.String()
+}
 
-	if err := db.DB.Table(configTable).AutoMigrate(&models.ClientConfig{}); err != nil {
+// Create a schema for the client. On SQLite client tables only carry the
+// schema name as a prefix, so there is nothing to create.
+func (db *Database) CreateClientSchema(schemaName string) error {
+	if err := ValidateIdentifier(schemaName); err != nil {
 		return err
 	}
+	if db.IsSQLite() {
+		return nil
+	}
 
-	return nil
+	tx, span := db.startSpan("Database.CreateClientSchema", tracing.TenantSchemaKey.String(schemaName))
+	defer span.End()
+
+	return tx.DB.Exec("CREATE SCHEMA IF NOT EXISTS ?", clause.Table{Name: schemaName}).Error
 }
@@ -0,0 +1,146 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Kantha2004/SimpleJWT/internal/models"
+	"gorm.io/gorm"
+)
+
+var sessionListSpec = ListSpec[models.Session]{
+	Sorts: []SortField[models.Session]{
+		{Name: "last_seen_at", Column: "last_seen_at", Value: func(s *models.Session) any { return s.LastSeenAt }},
+		{Name: "created_at", Column: "created_at", Value: func(s *models.Session) any { return s.CreatedAt }},
+		{Name: "expires_at", Column: "expires_at", Value: func(s *models.Session) any { return s.ExpiresAt }},
+	},
+	DefaultSort:  "-last_seen_at",
+	DefaultLimit: DEFAULT_PAGE_LIMIT,
+	MaxLimit:     MAX_PAGE_LIMIT,
+	ID:           func(s *models.Session) uint { return s.ID },
+}
+
+type SessionRepository struct {
+	db *Database
+}
+
+func NewSessionRepository(db *Database) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+// forOwner scopes a query to the sessions of an admin user (nil clientID) or
+// of a client user
+func forOwner(userID uint, clientID *uint) func(*gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		if clientID == nil {
+			return tx.Where("user_id = ? AND client_id IS NULL", userID)
+		}
+		return tx.Where("user_id = ? AND client_id = ?", userID, *clientID)
+	}
+}
+
+// WithContext returns the repository with its queries bound to ctx
+func (sr *SessionRepository) WithContext(ctx context.Context) *SessionRepository {
+	return NewSessionRepository(sr.db.WithContext(ctx))
+}
+
+func (sr *SessionRepository) CreateSession(session *models.Session) error {
+	db, span := sr.db.startSpan("SessionRepository.CreateSession")
+	defer span.End()
+
+	return db.DB.Create(session).Error
+}
+
+func (sr *SessionRepository) GetSessionBySessionID(sessionID string) (*models.Session, error) {
+	db, span := sr.db.startSpan("SessionRepository.GetSessionBySessionID")
+	defer span.End()
+
+	var session models.Session
+	result := db.DB.Where("session_id = ?", sessionID).First(&session)
+
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &session, nil
+}
+
+// ListActiveSessions returns one page of the sessions of a user that are
+// neither revoked nor expired, most recently used first by default
+func (sr *SessionRepository) ListActiveSessions(userID uint, clientID *uint, params models.ListParams) (*Page[models.Session], error) {
+	db, span := sr.db.startSpan("SessionRepository.ListActiveSessions")
+	defer span.End()
+
+	tx := db.DB.Model(&models.Session{}).
+		Scopes(forOwner(userID, clientID)).
+		Where("revoked_at IS NULL AND expires_at > ?", time.Now())
+
+	return Paginate(tx, sessionListSpec, params)
+}
+
+// TouchSession updates the last time the session was used
+func (sr *SessionRepository) TouchSession(sessionID string, lastSeenAt time.Time) error {
+	db, span := sr.db.startSpan("SessionRepository.TouchSession")
+	defer span.End()
+
+	return db.DB.Model(&models.Session{}).
+		Where("session_id = ?", sessionID).
+		Update("last_seen_at", lastSeenAt).Error
+}
+
+// SetCSRFToken stores the CSRF token of a console session
+func (sr *SessionRepository) SetCSRFToken(sessionID, csrfToken string) error {
+	db, span := sr.db.startSpan("SessionRepository.SetCSRFToken")
+	defer span.End()
+
+	return db.DB.Model(&models.Session{}).
+		Where("session_id = ?", sessionID).
+		Update("csrf_token", csrfToken).Error
+}
+
+// RevokeSession revokes one session of a user. Returns the number of
+// sessions revoked, which is zero when it does not belong to the user.
+func (sr *SessionRepository) RevokeSession(userID uint, clientID *uint, sessionID string) (int64, error) {
+	db, span := sr.db.startSpan("SessionRepository.RevokeSession")
+	defer span.End()
+
+	result := db.DB.Model(&models.Session{}).
+		Scopes(forOwner(userID, clientID)).
+		Where("session_id = ? AND revoked_at IS NULL", sessionID).
+		Update("revoked_at", time.Now())
+
+	return result.RowsAffected, result.Error
+}
+
+// RevokeUserSessions revokes every active session of a user except exceptSessionID
+func (sr *SessionRepository) RevokeUserSessions(userID uint, clientID *uint, exceptSessionID string) (int64, error) {
+	db, span := sr.db.startSpan("SessionRepository.RevokeUserSessions")
+	defer span.End()
+
+	query := db.DB.Model(&models.Session{}).
+		Scopes(forOwner(userID, clientID)).
+		Where("revoked_at IS NULL")
+
+	if exceptSessionID != "" {
+		query = query.Where("session_id <> ?", exceptSessionID)
+	}
+
+	result := query.Update("revoked_at", time.Now())
+	return result.RowsAffected, result.Error
+}
+
+// RevokeClientSessions revokes every active session of the users of a client
+func (sr *SessionRepository) RevokeClientSessions(clientID uint) (int64, error) {
+	db, span := sr.db.startSpan("SessionRepository.RevokeClientSessions")
+	defer span.End()
+
+	result := db.DB.Model(&models.Session{}).
+		Where("client_id = ? AND revoked_at IS NULL", clientID).
+		Update("revoked_at", time.Now())
+	return result.RowsAffected, result.Error
+}
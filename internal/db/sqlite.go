@@ -0,0 +1,40 @@
+package db
+
+import (
+	"fmt"
+	"net/url"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// sqliteSchemaSeparator joins a client schema name and a table name into the
+// name of an emulated per-client table, e.g. "acme_client__users"
+const sqliteSchemaSeparator = "__"
+
+// openSQLite opens the database file with foreign keys enforced, WAL
+// journaling and a busy timeout so the webhook worker and request handlers
+// can write concurrently
+func openSQLite(path string) gorm.Dialector {
+	params := url.Values{}
+	params.Add("_foreign_keys", "on")
+	params.Add("_journal_mode", "WAL")
+	params.Add("_busy_timeout", "5000")
+
+	return sqlite.Open(fmt.Sprintf("file:%s?%s", path, params.Encode()))
+}
+
+// configureSQLite limits the pool to a single connection since SQLite
+// serializes writers anyway and concurrent writers only cause lock errors
+func configureSQLite(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	sqlDB.SetMaxOpenConns(1)
+	return nil
+}
+
+func sqliteTableName(schema, table string) string {
+	return schema + sqliteSchemaSeparator + table
+}
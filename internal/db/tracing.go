@@ -0,0 +1,98 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Kantha2004/SimpleJWT/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+var tracer = tracing.Tracer("internal/db")
+
+// startSpan starts the span of a repository call as a child of the context
+// the database is bound to. Queries run on the returned database become
+// children of the span. Calls outside a trace, such as background polling,
+// are not traced so they do not each start a new trace.
+func (d *Database) startSpan(name string, attrs ...attribute.KeyValue) (*Database, trace.Span) {
+	ctx := d.context()
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return d, trace.SpanFromContext(ctx)
+	}
+
+	ctx, span := tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+	return d.WithContext(ctx), span
+}
+
+// context returns the context the database is bound to with WithContext
+func (d *Database) context() context.Context {
+	if d.DB.Statement != nil && d.DB.Statement.Context != nil {
+		return d.DB.Statement.Context
+	}
+	return context.Background()
+}
+
+// querySpanKey stores the span of a running query on its statement
+const querySpanKey = "simplejwt:query_span"
+
+// queryTracing is a GORM plugin that records a span for every query run
+// within a trace. The
+// SQL is recorded without its parameters, which hold password hashes and
+// secrets.
+type queryTracing struct{}
+
+func (queryTracing) Name() string {
+	return "simplejwt:tracing"
+}
+
+func (queryTracing) Initialize(db *gorm.DB) error {
+	callback := db.Callback()
+	return errors.Join(
+		callback.Create().Before("gorm:create").Register("simplejwt:before_create", startQuerySpan("create")),
+		callback.Create().After("gorm:create").Register("simplejwt:after_create", endQuerySpan),
+		callback.Query().Before("gorm:query").Register("simplejwt:before_query", startQuerySpan("query")),
+		callback.Query().After("gorm:query").Register("simplejwt:after_query", endQuerySpan),
+		callback.Update().Before("gorm:update").Register("simplejwt:before_update", startQuerySpan("update")),
+		callback.Update().After("gorm:update").Register("simplejwt:after_update", endQuerySpan),
+		callback.Delete().Before("gorm:delete").Register("simplejwt:before_delete", startQuerySpan("delete")),
+		callback.Delete().After("gorm:delete").Register("simplejwt:after_delete", endQuerySpan),
+		callback.Row().Before("gorm:row").Register("simplejwt:before_row", startQuerySpan("row")),
+		callback.Row().After("gorm:row").Register("simplejwt:after_row", endQuerySpan),
+		callback.Raw().Before("gorm:raw").Register("simplejwt:before_raw", startQuerySpan("raw")),
+		callback.Raw().After("gorm:raw").Register("simplejwt:after_raw", endQuerySpan),
+	)
+}
+
+func startQuerySpan(operation string) func(*gorm.DB) {
+	return func(tx *gorm.DB) {
+		ctx := tx.Statement.Context
+		if ctx == nil || !trace.SpanContextFromContext(ctx).IsValid() {
+			return
+		}
+		_, span := tracer.Start(ctx, "db."+operation, trace.WithSpanKind(trace.SpanKindClient))
+		tx.InstanceSet(querySpanKey, span)
+	}
+}
+
+func endQuerySpan(tx *gorm.DB) {
+	value, ok := tx.InstanceGet(querySpanKey)
+	if !ok {
+		return
+	}
+	span := value.(trace.Span)
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("db.system.name", tx.Dialector.Name()),
+		attribute.String("db.collection.name", tx.Statement.Table),
+		attribute.String("db.query.text", tx.Statement.SQL.String()),
+		attribute.Int64("db.response.returned_rows", tx.RowsAffected),
+	)
+	if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(tx.Error)
+		span.SetStatus(codes.Error, tx.Error.Error())
+	}
+}
@@ -1,28 +1,39 @@
 package db
 
 import (
+	"context"
 	"errors"
 
 	"github.com/Kantha2004/SimpleJWT/internal/models"
 	"gorm.io/gorm"
 )
 
-type UserRepository struct {
+type SQLUserRepository struct {
 	db *Database
 }
 
-func NewUserRepository(db *Database) *UserRepository {
-	return &UserRepository{db: db}
+func NewUserRepository(db *Database) *SQLUserRepository {
+	return &SQLUserRepository{db: db}
 }
 
-func (ur *UserRepository) CreateUser(user *models.AdminUser) (uint, error) {
-	result := ur.db.DB.Create(user)
+func (ur *SQLUserRepository) WithContext(ctx context.Context) UserRepository {
+	return NewUserRepository(ur.db.WithContext(ctx))
+}
+
+func (ur *SQLUserRepository) CreateUser(user *models.AdminUser) (uint, error) {
+	db, span := ur.db.startSpan("UserRepository.CreateUser")
+	defer span.End()
+
+	result := db.DB.Create(user)
 	return user.ID, result.Error
 }
 
-func (ur *UserRepository) GetUserByID(id uint) (*models.AdminUser, error) {
+func (ur *SQLUserRepository) GetUserByID(id uint) (*models.AdminUser, error) {
+	db, span := ur.db.startSpan("UserRepository.GetUserByID")
+	defer span.End()
+
 	var user models.AdminUser
-	result := ur.db.DB.First(&user, id)
+	result := db.DB.First(&user, id)
 
 	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 		return nil, nil
@@ -35,9 +46,12 @@ func (ur *UserRepository) GetUserByID(id uint) (*models.AdminUser, error) {
 	return &user, nil
 }
 
-func (ur *UserRepository) GetUserByEmail(email string) (*models.AdminUser, error) {
+func (ur *SQLUserRepository) GetUserByEmail(email string) (*models.AdminUser, error) {
+	db, span := ur.db.startSpan("UserRepository.GetUserByEmail")
+	defer span.End()
+
 	var user models.AdminUser
-	result := ur.db.DB.Where("email = ?", email).First(&user)
+	result := db.DB.Where("email = ?", email).First(&user)
 
 	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 		return nil, nil
@@ -50,9 +64,12 @@ func (ur *UserRepository) GetUserByEmail(email string) (*models.AdminUser, error
 	return &user, nil
 }
 
-func (ur *UserRepository) GetUserByUsername(username string) (*models.AdminUser, error) {
+func (ur *SQLUserRepository) GetUserByUsername(username string) (*models.AdminUser, error) {
+	db, span := ur.db.startSpan("UserRepository.GetUserByUsername")
+	defer span.End()
+
 	var user models.AdminUser
-	result := ur.db.DB.Where("username = ?", username).First(&user)
+	result := db.DB.Where("username = ?", username).First(&user)
 
 	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 		return nil, nil
@@ -65,14 +82,19 @@ func (ur *UserRepository) GetUserByUsername(username string) (*models.AdminUser,
 	return &user, nil
 }
 
-func (ur *UserRepository) UpdateUser(user *models.AdminUser) error {
-	result := ur.db.DB.Save(user)
-	return result.Error
+func (ur *SQLUserRepository) UpdateUser(user *models.AdminUser) error {
+	db, span := ur.db.startSpan("UserRepository.UpdateUser")
+	defer span.End()
+
+	return saveVersioned(db.DB, user, &user.Version)
 }
 
-func (ur *UserRepository) UserExists(username, email string) (bool, error) {
+func (ur *SQLUserRepository) UserExists(username, email string) (bool, error) {
+	db, span := ur.db.startSpan("UserRepository.UserExists")
+	defer span.End()
+
 	var count int64
-	result := ur.db.DB.Model(&models.AdminUser{}).
+	result := db.DB.Model(&models.AdminUser{}).
 		Where("username = ? AND email = ?", username, email).Count(&count)
 	if result.Error != nil {
 		return false, result.Error
@@ -81,9 +103,12 @@ func (ur *UserRepository) UserExists(username, email string) (bool, error) {
 	return count > 0, nil
 }
 
-func (ur *UserRepository) EmailExists(email string) (bool, error) {
+func (ur *SQLUserRepository) EmailExists(email string) (bool, error) {
+	db, span := ur.db.startSpan("UserRepository.EmailExists")
+	defer span.End()
+
 	var count int64
-	result := ur.db.DB.Model(&models.AdminUser{}).Where("email = ?", email).Count(&count)
+	result := db.DB.Model(&models.AdminUser{}).Where("email = ?", email).Count(&count)
 
 	if result.Error != nil {
 		return false, result.Error
@@ -92,9 +117,12 @@ func (ur *UserRepository) EmailExists(email string) (bool, error) {
 	return count > 0, nil
 }
 
-func (ur *UserRepository) UserNameExists(username string) (bool, error) {
+func (ur *SQLUserRepository) UserNameExists(username string) (bool, error) {
+	db, span := ur.db.startSpan("UserRepository.UserNameExists")
+	defer span.End()
+
 	var count int64
-	result := ur.db.DB.Model(&models.AdminUser{}).Where("username = ?", username).Count(&count)
+	result := db.DB.Model(&models.AdminUser{}).Where("username = ?", username).Count(&count)
 
 	if result.Error != nil {
 		return false, result.Error
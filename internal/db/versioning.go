@@ -0,0 +1,41 @@
+package db
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// ErrVersionConflict is returned when a resource is updated or deleted from a
+// copy that is no longer current: another request changed or deleted it since
+// the copy was read
+var ErrVersionConflict = errors.New("resource was modified since it was read")
+
+// saveVersioned writes every column of model, a row read at *version, and
+// increments the version. Nothing is written when the stored row is at
+// another version.
+func saveVersioned(tx *gorm.DB, model any, version *uint) error {
+	read := *version
+	*version = read + 1
+
+	result := tx.Select("*").Omit("created_at").Where("version = ?", read).Updates(model)
+	if result.Error == nil && result.RowsAffected == 0 {
+		result.Error = ErrVersionConflict
+	}
+	if result.Error != nil {
+		*version = read
+		return result.Error
+	}
+
+	return nil
+}
+
+// deleteVersioned deletes model, a row read at version. Nothing is deleted
+// when the stored row is at another version.
+func deleteVersioned(tx *gorm.DB, model any, version uint) error {
+	result := tx.Where("version = ?", version).Delete(model)
+	if result.Error == nil && result.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+	return result.Error
+}
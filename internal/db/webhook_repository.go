@@ -0,0 +1,199 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Kantha2004/SimpleJWT/internal/models"
+	"gorm.io/gorm"
+)
+
+const DEFAULT_WEBHOOK_DELIVERY_LIMIT = 50
+
+type WebhookRepository struct {
+	db *Database
+}
+
+func NewWebhookRepository(db *Database) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+// WithContext returns the repository with its queries bound to ctx
+func (wr *WebhookRepository) WithContext(ctx context.Context) *WebhookRepository {
+	return NewWebhookRepository(wr.db.WithContext(ctx))
+}
+
+func (wr *WebhookRepository) CreateSubscription(subscription *models.WebhookSubscription) error {
+	db, span := wr.db.startSpan("WebhookRepository.CreateSubscription")
+	defer span.End()
+
+	return db.DB.Create(subscription).Error
+}
+
+func (wr *WebhookRepository) GetSubscriptionByID(id uint) (*models.WebhookSubscription, error) {
+	db, span := wr.db.startSpan("WebhookRepository.GetSubscriptionByID")
+	defer span.End()
+
+	var subscription models.WebhookSubscription
+	result := db.DB.First(&subscription, id)
+
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &subscription, nil
+}
+
+func (wr *WebhookRepository) GetSubscriptionsByClientID(clientID uint) ([]models.WebhookSubscription, error) {
+	db, span := wr.db.startSpan("WebhookRepository.GetSubscriptionsByClientID")
+	defer span.End()
+
+	var subscriptions []models.WebhookSubscription
+	result := db.DB.Where("client_id = ?", clientID).Order("id").Find(&subscriptions)
+	return subscriptions, result.Error
+}
+
+// GetActiveSubscriptions returns the active subscriptions of a client
+func (wr *WebhookRepository) GetActiveSubscriptions(clientID uint) ([]models.WebhookSubscription, error) {
+	db, span := wr.db.startSpan("WebhookRepository.GetActiveSubscriptions")
+	defer span.End()
+
+	var subscriptions []models.WebhookSubscription
+	result := db.DB.Where("client_id = ? AND active = ?", clientID, true).Find(&subscriptions)
+	return subscriptions, result.Error
+}
+
+// DeleteSubscription deletes the subscription. Returns ErrVersionConflict when
+// it changed since it was read.
+func (wr *WebhookRepository) DeleteSubscription(subscription *models.WebhookSubscription) error {
+	db, span := wr.db.startSpan("WebhookRepository.DeleteSubscription")
+	defer span.End()
+
+	return deleteVersioned(db.DB, subscription, subscription.Version)
+}
+
+func (wr *WebhookRepository) CreateDeliveries(deliveries []models.WebhookDelivery) error {
+	db, span := wr.db.startSpan("WebhookRepository.CreateDeliveries")
+	defer span.End()
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return db.DB.Create(&deliveries).Error
+}
+
+func (wr *WebhookRepository) GetDeliveryByID(id uint) (*models.WebhookDelivery, error) {
+	db, span := wr.db.startSpan("WebhookRepository.GetDeliveryByID")
+	defer span.End()
+
+	var delivery models.WebhookDelivery
+	result := db.DB.First(&delivery, id)
+
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &delivery, nil
+}
+
+// GetDeliveries returns the deliveries of a subscription, newest first
+func (wr *WebhookRepository) GetDeliveries(subscriptionID uint, status string, limit int) ([]models.WebhookDelivery, error) {
+	db, span := wr.db.startSpan("WebhookRepository.GetDeliveries")
+	defer span.End()
+
+	var deliveries []models.WebhookDelivery
+
+	tx := db.DB.Where("subscription_id = ?", subscriptionID)
+	if status != "" {
+		tx = tx.Where("status = ?", status)
+	}
+	if limit <= 0 {
+		limit = DEFAULT_WEBHOOK_DELIVERY_LIMIT
+	}
+
+	result := tx.Order("id DESC").Limit(limit).Find(&deliveries)
+	return deliveries, result.Error
+}
+
+// GetAttempts returns the attempt log of the given deliveries, oldest first
+func (wr *WebhookRepository) GetAttempts(deliveryIDs []uint) ([]models.WebhookDeliveryAttempt, error) {
+	db, span := wr.db.startSpan("WebhookRepository.GetAttempts")
+	defer span.End()
+
+	var attempts []models.WebhookDeliveryAttempt
+	if len(deliveryIDs) == 0 {
+		return attempts, nil
+	}
+
+	result := db.DB.Where("delivery_id IN ?", deliveryIDs).Order("delivery_id, attempt").Find(&attempts)
+	return attempts, result.Error
+}
+
+// GetDueDeliveries returns pending deliveries whose next attempt is due
+func (wr *WebhookRepository) GetDueDeliveries(now time.Time, limit int) ([]models.WebhookDelivery, error) {
+	db, span := wr.db.startSpan("WebhookRepository.GetDueDeliveries")
+	defer span.End()
+
+	var deliveries []models.WebhookDelivery
+	result := db.DB.
+		Where("status = ? AND next_attempt_at <= ?", models.WebhookDeliveryPending, now).
+		Order("next_attempt_at").
+		Limit(limit).
+		Find(&deliveries)
+	return deliveries, result.Error
+}
+
+// ClaimDelivery leases a due delivery to the caller by pushing its next
+// attempt time to leaseUntil. It returns false when another worker claimed
+// it first. If the worker dies, the delivery becomes due again after the lease.
+func (wr *WebhookRepository) ClaimDelivery(delivery *models.WebhookDelivery, leaseUntil time.Time) (bool, error) {
+	db, span := wr.db.startSpan("WebhookRepository.ClaimDelivery")
+	defer span.End()
+
+	result := db.DB.Model(&models.WebhookDelivery{}).
+		Where("id = ? AND status = ? AND next_attempt_at = ?", delivery.ID, models.WebhookDeliveryPending, delivery.NextAttemptAt).
+		Update("next_attempt_at", leaseUntil)
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// RecordAttempt stores the outcome of an attempt together with the delivery's new state
+func (wr *WebhookRepository) RecordAttempt(delivery *models.WebhookDelivery, attempt *models.WebhookDeliveryAttempt) error {
+	db, span := wr.db.startSpan("WebhookRepository.RecordAttempt")
+	defer span.End()
+
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(attempt).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(delivery).Select(
+			"status", "attempts", "next_attempt_at", "last_attempt_at",
+			"delivered_at", "last_status_code", "last_error",
+		).Updates(delivery).Error
+	})
+}
+
+// ResetDelivery queues a delivery to be sent again right away
+func (wr *WebhookRepository) ResetDelivery(delivery *models.WebhookDelivery) error {
+	db, span := wr.db.startSpan("WebhookRepository.ResetDelivery")
+	defer span.End()
+
+	delivery.Status = models.WebhookDeliveryPending
+	delivery.NextAttemptAt = time.Now()
+
+	return db.DB.Model(delivery).Select("status", "next_attempt_at").Updates(delivery).Error
+}
@@ -0,0 +1,86 @@
+package grpcapi
+
+import (
+	"context"
+	"log/slog"
+	"runtime/debug"
+	"time"
+
+	"github.com/Kantha2004/SimpleJWT/internal/api"
+	apiresponse "github.com/Kantha2004/SimpleJWT/internal/apiResponse"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// RequestLogger logs one line per call, at warn level for errors caused by
+// the caller and at error level for failures of the server
+func RequestLogger(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		code := status.Code(err)
+		level := slog.LevelInfo
+		switch code {
+		case codes.OK:
+		case codes.Internal, codes.Unknown, codes.Unavailable, codes.DataLoss:
+			level = slog.LevelError
+		default:
+			level = slog.LevelWarn
+		}
+
+		from := caller(ctx)
+		logger.LogAttrs(ctx, level, "Call handled",
+			slog.String("method", info.FullMethod),
+			slog.String("code", code.String()),
+			slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.String("client_ip", from.IP),
+			slog.String("user_agent", from.UserAgent),
+		)
+		return resp, err
+	}
+}
+
+// RecoveryInterceptor turns a panic into an internal error and logs it with
+// its stack trace
+func RecoveryInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				logger.ErrorContext(ctx, "Panic while handling call",
+					"panic", recovered,
+					"method", info.FullMethod,
+					"stack", string(debug.Stack()),
+				)
+				err = statusError(apiresponse.CodeInternal, "Internal server error")
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// RateLimitInterceptor rejects the calls of caller addresses over the limit,
+// with a RetryInfo detail saying when to retry. Sharing the limiter of the
+// HTTP API gives a caller one bucket for both APIs. It must run after the
+// caller address is resolved.
+func RateLimitInterceptor(limiter *api.RateLimiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		allowed, wait := limiter.Allow(caller(ctx).IP)
+		if allowed {
+			return handler(ctx, req)
+		}
+
+		st := status.New(grpcCode(apiresponse.CodeRateLimited), "Too many requests, retry later")
+		withDetails, err := st.WithDetails(
+			&errdetails.ErrorInfo{Reason: string(apiresponse.CodeRateLimited), Domain: errorDomain},
+			&errdetails.RetryInfo{RetryDelay: durationpb.New(wait)},
+		)
+		if err != nil {
+			return nil, st.Err()
+		}
+		return nil, withDetails.Err()
+	}
+}
@@ -0,0 +1,51 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+
+	apiresponse "github.com/Kantha2004/SimpleJWT/internal/apiResponse"
+	"github.com/Kantha2004/SimpleJWT/internal/models"
+	"github.com/Kantha2004/SimpleJWT/internal/service"
+	"github.com/Kantha2004/SimpleJWT/pkg/authv1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Login logs an admin user, or a user of a client, in
+func (s *Server) Login(ctx context.Context, req *authv1.LoginRequest) (*authv1.LoginResponse, error) {
+	if req.GetUsername() == "" || req.GetPassword() == "" {
+		return nil, statusError(apiresponse.CodeValidation, "Username and password are required")
+	}
+
+	var response *models.LoginResponse
+	var err error
+	if req.GetClientId() != 0 {
+		response, _, err = s.authService.ClientUserLogin(ctx, caller(ctx), uint(req.GetClientId()), req.GetUsername(), req.GetPassword())
+	} else {
+		response, err = s.authService.Login(ctx, caller(ctx), req.GetUsername(), req.GetPassword())
+	}
+	if err != nil {
+		return nil, s.loginError(ctx, err)
+	}
+
+	return &authv1.LoginResponse{
+		Token:     response.Token,
+		SessionId: response.SessionID,
+		ExpiresAt: timestamppb.New(response.ExpiresAt),
+		User:      &authv1.User{Id: uint64(response.User.ID), Username: response.User.Username, Email: response.User.Email},
+	}, nil
+}
+
+// loginError maps a rejected or failed login to its status
+func (s *Server) loginError(ctx context.Context, err error) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidCredentials):
+		return statusError(apiresponse.CodeInvalidCredentials, "Invalid username or password")
+	case errors.Is(err, service.ErrUserDisabled):
+		return statusError(apiresponse.CodeUserDisabled, "User is disabled")
+	case errors.Is(err, service.ErrClientSuspended):
+		return statusError(apiresponse.CodeClientSuspended, "Client is suspended")
+	default:
+		return s.internalError(ctx, "Authentication failed", err)
+	}
+}
@@ -0,0 +1,231 @@
+// Package grpcapi serves the gRPC API defined in proto/simplejwt/auth/v1.
+// It issues and verifies the same tokens and sessions as the HTTP API.
+package grpcapi
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/Kantha2004/SimpleJWT/internal/api"
+	apiresponse "github.com/Kantha2004/SimpleJWT/internal/apiResponse"
+	"github.com/Kantha2004/SimpleJWT/internal/auth"
+	"github.com/Kantha2004/SimpleJWT/internal/db"
+	"github.com/Kantha2004/SimpleJWT/internal/models"
+	"github.com/Kantha2004/SimpleJWT/internal/service"
+	"github.com/Kantha2004/SimpleJWT/pkg/authv1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// errorDomain is the domain of the ErrorInfo details of errors
+const errorDomain = "simplejwt"
+
+type Server struct {
+	authv1.UnimplementedAuthServiceServer
+
+	users       db.UserRepository
+	clients     db.ClientRepository
+	clientUsers db.ClientUserRepositoryFactory
+	sessions    *db.SessionRepository
+	jwtService  *auth.JWTService
+	authService *service.AuthService
+	logger      *slog.Logger
+	// trustedProxies may set the caller address with x-forwarded-for
+	trustedProxies []netip.Prefix
+}
+
+// NewServer builds the API on the repositories and login service of the HTTP
+// API, so both serve the same accounts and sessions
+func NewServer(users db.UserRepository, clients db.ClientRepository, clientUsers db.ClientUserRepositoryFactory, sessions *db.SessionRepository, jwtService *auth.JWTService, authService *service.AuthService, logger *slog.Logger) *Server {
+	return &Server{
+		users:       users,
+		clients:     clients,
+		clientUsers: clientUsers,
+		sessions:    sessions,
+		jwtService:  jwtService,
+		authService: authService,
+		logger:      logger,
+	}
+}
+
+// SetTrustedProxies sets the addresses and CIDR ranges of the proxies whose
+// x-forwarded-for metadata gives the caller address, like the HTTP router.
+// No proxy is trusted by default.
+func (s *Server) SetTrustedProxies(proxies []string) error {
+	prefixes := make([]netip.Prefix, 0, len(proxies))
+	for _, proxy := range proxies {
+		if addr, err := netip.ParseAddr(proxy); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(proxy)
+		if err != nil {
+			return fmt.Errorf("invalid trusted proxy %q", proxy)
+		}
+		prefixes = append(prefixes, prefix)
+	}
+	s.trustedProxies = prefixes
+	return nil
+}
+
+// NewGRPCServer builds a gRPC server serving the API, with panic recovery,
+// request logging and the per-address rate limit of the HTTP API
+func NewGRPCServer(server *Server, rateLimiter *api.RateLimiter, logger *slog.Logger) *grpc.Server {
+	grpcServer := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		server.callerIPInterceptor,
+		RequestLogger(logger),
+		RateLimitInterceptor(rateLimiter),
+		RecoveryInterceptor(logger),
+	))
+	authv1.RegisterAuthServiceServer(grpcServer, server)
+	return grpcServer
+}
+
+// statusError builds the status of an error of the API. The code of the HTTP
+// API is carried in an ErrorInfo detail so clients can branch on it.
+func statusError(code apiresponse.ErrorCode, detail string) error {
+	st := status.New(grpcCode(code), detail)
+	withInfo, err := st.WithDetails(&errdetails.ErrorInfo{Reason: string(code), Domain: errorDomain})
+	if err != nil {
+		return st.Err()
+	}
+	return withInfo.Err()
+}
+
+// grpcCode maps an error code to the gRPC code closest to its HTTP status
+func grpcCode(code apiresponse.ErrorCode) codes.Code {
+	problemType, _ := apiresponse.LookupProblemType(code)
+	switch problemType.Status {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		if code == apiresponse.CodeAlreadyExists {
+			return codes.AlreadyExists
+		}
+		return codes.Aborted
+	case http.StatusPreconditionFailed, http.StatusPreconditionRequired:
+		return codes.FailedPrecondition
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	default:
+		return codes.Internal
+	}
+}
+
+// internalError logs a failure and hides its cause from the caller
+func (s *Server) internalError(ctx context.Context, msg string, err error, args ...any) error {
+	s.logger.ErrorContext(ctx, msg, append([]any{"error", err}, args...)...)
+	return statusError(apiresponse.CodeInternal, msg)
+}
+
+// callerIPKey holds the caller address resolved by callerIPInterceptor
+type callerIPKey struct{}
+
+// callerIPInterceptor resolves the IP address of the caller once per call
+func (s *Server) callerIPInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	return handler(context.WithValue(ctx, callerIPKey{}, s.callerIP(ctx)), req)
+}
+
+// callerIP returns the address of the peer, or the one it forwarded the call
+// for when it is a trusted proxy. Like the HTTP router, x-forwarded-for is
+// read from the right and the first address that is not a trusted proxy is
+// the caller.
+func (s *Server) callerIP(ctx context.Context) string {
+	peerIP := peerAddress(ctx)
+	addr, err := netip.ParseAddr(peerIP)
+	if err != nil || !s.isTrustedProxy(addr) {
+		return peerIP
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	var hops []string
+	for _, value := range md.Get("x-forwarded-for") {
+		hops = append(hops, strings.Split(value, ",")...)
+	}
+
+	ip := peerIP
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			return peerIP
+		}
+		ip = hop.Unmap().String()
+		if !s.isTrustedProxy(hop) {
+			break
+		}
+	}
+	return ip
+}
+
+func (s *Server) isTrustedProxy(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, prefix := range s.trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// peerAddress returns the IP address of the connection of the call
+func peerAddress(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	ip := p.Addr.String()
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	return ip
+}
+
+// caller returns the IP address and user agent of the caller
+func caller(ctx context.Context) service.Caller {
+	ip, _ := ctx.Value(callerIPKey{}).(string)
+	if ip == "" {
+		ip = peerAddress(ctx)
+	}
+	var userAgent string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("user-agent"); len(values) > 0 {
+			userAgent = values[0]
+		}
+	}
+	return service.Caller{IP: ip, UserAgent: userAgent}
+}
+
+// bearerToken returns the token of the "authorization" metadata
+func bearerToken(ctx context.Context) (string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return "", statusError(apiresponse.CodeMissingToken, "Authorization metadata required")
+	}
+	token, found := strings.CutPrefix(values[0], "Bearer ")
+	if !found || token == "" {
+		return "", statusError(apiresponse.CodeInvalidToken, "Authorization metadata must hold a Bearer token")
+	}
+	return token, nil
+}
+
+// recordAudit appends an event to the audit log like the HTTP handlers do.
+// Failures are only logged.
+func (s *Server) recordAudit(ctx context.Context, event models.AuditEvent) {
+	s.authService.RecordAudit(ctx, caller(ctx), event)
+}
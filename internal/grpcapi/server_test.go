@@ -0,0 +1,347 @@
+package grpcapi_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/Kantha2004/SimpleJWT/internal/api"
+	apiresponse "github.com/Kantha2004/SimpleJWT/internal/apiResponse"
+	"github.com/Kantha2004/SimpleJWT/internal/auth"
+	"github.com/Kantha2004/SimpleJWT/internal/config"
+	"github.com/Kantha2004/SimpleJWT/internal/db"
+	"github.com/Kantha2004/SimpleJWT/internal/db/memory"
+	"github.com/Kantha2004/SimpleJWT/internal/grpcapi"
+	"github.com/Kantha2004/SimpleJWT/internal/models"
+	"github.com/Kantha2004/SimpleJWT/internal/service"
+	"github.com/Kantha2004/SimpleJWT/pkg/authv1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// The server runs over an in-memory connection on the in-memory
+// repositories; sessions and the audit log are stored in SQLite, in a new
+// file for every test. Every call comes from the same address.
+
+const testPassword = "Passw0rd!x"
+
+type fixture struct {
+	client      authv1.AuthServiceClient
+	users       *memory.UserRepository
+	clients     *memory.ClientRepository
+	clientUsers *memory.ClientUserRepositories
+	hasher      auth.PasswordHasher
+}
+
+// newFixture serves the API with a limit of burst calls per address, which
+// refills too slowly to matter during a test; 0 disables the limit
+func newFixture(t *testing.T, burst int) *fixture {
+	t.Helper()
+
+	database, err := db.NewDatabase(&config.DBConfig{
+		Driver:     config.DriverSQLite,
+		SQLitePath: filepath.Join(t.TempDir(), "simplejwt.db"),
+	})
+	if err != nil {
+		t.Fatalf("NewDatabase: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	// The lowest cost keeps logins fast
+	hasher, err := auth.NewBcryptHasher(4)
+	if err != nil {
+		t.Fatalf("NewBcryptHasher: %v", err)
+	}
+
+	f := &fixture{
+		users:       memory.NewUserRepository(),
+		clients:     memory.NewClientRepository(),
+		clientUsers: memory.NewClientUserRepositories(),
+		hasher:      hasher,
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	jwtService := auth.NewJWTService("grpc-test-secret", nil, "simplejwt", "")
+	sessions := db.NewSessionRepository(database)
+	authService := service.NewAuthService(database, f.users, f.clients, f.clientUsers.Factory(), memory.NewRoleRepositories().Factory(), sessions, jwtService, hasher, nil, logger)
+
+	requestsPerSecond := 0.001
+	if burst == 0 {
+		requestsPerSecond = 0
+	}
+	server := grpcapi.NewServer(f.users, f.clients, f.clientUsers.Factory(), sessions, jwtService, authService, logger)
+	grpcServer := grpcapi.NewGRPCServer(server, api.NewRateLimiter(requestsPerSecond, burst), logger)
+
+	listener := bufconn.Listen(1 << 20)
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	f.client = authv1.NewAuthServiceClient(conn)
+	return f
+}
+
+func (f *fixture) newUser(t *testing.T, username string) *models.AdminUser {
+	t.Helper()
+
+	hash, err := f.hasher.Hash(testPassword)
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	user := &models.AdminUser{Username: username, Email: username + "@example.com", PasswordHash: hash}
+	if _, err := f.users.CreateUser(user); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	return user
+}
+
+// newClientUser creates a client of owner with one user named username
+func (f *fixture) newClientUser(t *testing.T, owner *models.AdminUser, username string) *models.Client {
+	t.Helper()
+
+	client := &models.Client{ClientName: owner.Username + "-app", UserID: owner.ID, SchemaName: owner.Username + "_app"}
+	if _, err := f.clients.CreateClient(client); err != nil {
+		t.Fatalf("CreateClient: %v", err)
+	}
+	hash, err := f.hasher.Hash(testPassword)
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	user := &models.ClientUser{Username: username, Email: username + "@example.com", PasswordHash: hash}
+	if _, err := f.clientUsers.ForSchema(client.SchemaName).CreateClientUser(user); err != nil {
+		t.Fatalf("CreateClientUser: %v", err)
+	}
+	return client
+}
+
+func (f *fixture) login(t *testing.T, clientID uint, username string) *authv1.LoginResponse {
+	t.Helper()
+
+	resp, err := f.client.Login(context.Background(), &authv1.LoginRequest{ClientId: uint64(clientID), Username: username, Password: testPassword})
+	if err != nil {
+		t.Fatalf("Login of %s: %v", username, err)
+	}
+	return resp
+}
+
+// withToken authorizes a call with token
+func withToken(token string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+// checkStatus fails unless err has the gRPC code and the error code of the
+// API
+func checkStatus(t *testing.T, err error, code codes.Code, reason apiresponse.ErrorCode) *status.Status {
+	t.Helper()
+
+	st := status.Convert(err)
+	if st.Code() != code {
+		t.Fatalf("got status %v, want %v", st, code)
+	}
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok && info.Reason == string(reason) {
+			return st
+		}
+	}
+	t.Fatalf("status %v has no ErrorInfo with reason %s", st, reason)
+	return nil
+}
+
+func TestLogin(t *testing.T) {
+	f := newFixture(t, 0)
+	alice := f.newUser(t, "alice")
+	client := f.newClientUser(t, alice, "bob")
+
+	tests := []struct {
+		name     string
+		clientID uint
+		username string
+		password string
+		wantUser string
+		code     codes.Code
+		reason   apiresponse.ErrorCode
+	}{
+		{"admin user", 0, "alice", testPassword, "alice", codes.OK, ""},
+		{"client user", client.ID, "bob", testPassword, "bob", codes.OK, ""},
+		{"wrong password", 0, "alice", "wrong password", "", codes.Unauthenticated, apiresponse.CodeInvalidCredentials},
+		{"unknown user", 0, "carol", testPassword, "", codes.Unauthenticated, apiresponse.CodeInvalidCredentials},
+		{"client user without the client", 0, "bob", testPassword, "", codes.Unauthenticated, apiresponse.CodeInvalidCredentials},
+		{"admin user as a client user", client.ID, "alice", testPassword, "", codes.Unauthenticated, apiresponse.CodeInvalidCredentials},
+		{"missing password", 0, "alice", "", "", codes.InvalidArgument, apiresponse.CodeValidation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := f.client.Login(context.Background(), &authv1.LoginRequest{ClientId: uint64(tt.clientID), Username: tt.username, Password: tt.password})
+			if tt.code != codes.OK {
+				checkStatus(t, err, tt.code, tt.reason)
+				return
+			}
+			if err != nil {
+				t.Fatalf("Login: %v", err)
+			}
+			if resp.GetToken() == "" || resp.GetSessionId() == "" || resp.GetUser().GetUsername() != tt.wantUser {
+				t.Fatalf("got response %v, want a token and session of %s", resp, tt.wantUser)
+			}
+		})
+	}
+}
+
+func TestLoginRateLimited(t *testing.T) {
+	f := newFixture(t, 2)
+	f.newUser(t, "alice")
+
+	// Rejected logins take tokens too, so passwords cannot be guessed faster
+	for range 2 {
+		_, err := f.client.Login(context.Background(), &authv1.LoginRequest{Username: "alice", Password: "wrong password"})
+		checkStatus(t, err, codes.Unauthenticated, apiresponse.CodeInvalidCredentials)
+	}
+
+	_, err := f.client.Login(context.Background(), &authv1.LoginRequest{Username: "alice", Password: testPassword})
+	st := checkStatus(t, err, codes.ResourceExhausted, apiresponse.CodeRateLimited)
+	var retry *errdetails.RetryInfo
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok {
+			retry = info
+		}
+	}
+	if retry == nil || retry.GetRetryDelay().AsDuration() <= 0 {
+		t.Fatalf("status %v has no RetryInfo with a delay", st)
+	}
+
+	// The limit is per address, not per method
+	_, err = f.client.GetJWKS(context.Background(), &authv1.GetJWKSRequest{})
+	checkStatus(t, err, codes.ResourceExhausted, apiresponse.CodeRateLimited)
+}
+
+func TestRefreshToken(t *testing.T) {
+	f := newFixture(t, 0)
+	alice := f.newUser(t, "alice")
+	client := f.newClientUser(t, alice, "bob")
+
+	for _, login := range []*authv1.LoginResponse{f.login(t, 0, "alice"), f.login(t, client.ID, "bob")} {
+		t.Run(login.GetUser().GetUsername(), func(t *testing.T) {
+			refreshed, err := f.client.RefreshToken(context.Background(), &authv1.RefreshTokenRequest{Token: login.GetToken()})
+			if err != nil {
+				t.Fatalf("RefreshToken: %v", err)
+			}
+			if refreshed.GetToken() == login.GetToken() || refreshed.GetSessionId() == login.GetSessionId() {
+				t.Fatalf("refresh kept the token or session %s", login.GetSessionId())
+			}
+
+			verified, err := f.client.VerifyToken(context.Background(), &authv1.VerifyTokenRequest{Token: refreshed.GetToken()})
+			if err != nil || !verified.GetValid() || verified.GetClaims().GetUserId() != login.GetUser().GetId() {
+				t.Fatalf("VerifyToken of the new token = %v, %v", verified, err)
+			}
+
+			// A token is refreshed once
+			_, err = f.client.RefreshToken(context.Background(), &authv1.RefreshTokenRequest{Token: login.GetToken()})
+			checkStatus(t, err, codes.Unauthenticated, apiresponse.CodeSessionRevoked)
+		})
+	}
+
+	_, err := f.client.RefreshToken(context.Background(), &authv1.RefreshTokenRequest{Token: "not-a-token"})
+	checkStatus(t, err, codes.Unauthenticated, apiresponse.CodeInvalidToken)
+}
+
+func TestVerifyToken(t *testing.T) {
+	f := newFixture(t, 0)
+	alice := f.newUser(t, "alice")
+	client := f.newClientUser(t, alice, "bob")
+
+	admin := f.login(t, 0, "alice")
+	clientUser := f.login(t, client.ID, "bob")
+	revoked := f.login(t, 0, "alice")
+	if _, err := f.client.RefreshToken(context.Background(), &authv1.RefreshTokenRequest{Token: revoked.GetToken()}); err != nil {
+		t.Fatalf("RefreshToken: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		token    string
+		wantCode apiresponse.ErrorCode
+		clientID uint
+	}{
+		{"admin user", admin.GetToken(), "", 0},
+		{"client user", clientUser.GetToken(), "", client.ID},
+		{"revoked session", revoked.GetToken(), apiresponse.CodeSessionRevoked, 0},
+		{"malformed", "not-a-token", apiresponse.CodeInvalidToken, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Invalid tokens are an answer, not an error of the call
+			resp, err := f.client.VerifyToken(context.Background(), &authv1.VerifyTokenRequest{Token: tt.token})
+			if err != nil {
+				t.Fatalf("VerifyToken: %v", err)
+			}
+			if tt.wantCode != "" {
+				if resp.GetValid() || resp.GetErrorCode() != string(tt.wantCode) || resp.GetClaims() != nil {
+					t.Fatalf("got %v, want an invalid token with code %s", resp, tt.wantCode)
+				}
+				return
+			}
+			if !resp.GetValid() || resp.GetClaims().GetClientId() != uint64(tt.clientID) || resp.GetClaims().GetIssuer() != "simplejwt" {
+				t.Fatalf("got %v, want a valid token of client %d", resp, tt.clientID)
+			}
+		})
+	}
+}
+
+func TestIntrospectOwnership(t *testing.T) {
+	f := newFixture(t, 0)
+	alice := f.newUser(t, "alice")
+	f.newUser(t, "mallory")
+	client := f.newClientUser(t, alice, "bob")
+
+	aliceToken := f.login(t, 0, "alice").GetToken()
+	malloryToken := f.login(t, 0, "mallory").GetToken()
+	bobToken := f.login(t, client.ID, "bob").GetToken()
+
+	tests := []struct {
+		name       string
+		caller     string
+		token      string
+		wantActive bool
+		wantType   string
+	}{
+		{"own token", aliceToken, aliceToken, true, models.AuditActorAdminUser},
+		{"user of an own client", aliceToken, bobToken, true, models.AuditActorClientUser},
+		{"another admin user", malloryToken, aliceToken, false, ""},
+		{"user of another admin's client", malloryToken, bobToken, false, ""},
+		{"invalid token", aliceToken, "not-a-token", false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := f.client.Introspect(withToken(tt.caller), &authv1.IntrospectRequest{Token: tt.token})
+			if err != nil {
+				t.Fatalf("Introspect: %v", err)
+			}
+			if resp.GetActive() != tt.wantActive || resp.GetUserType() != tt.wantType {
+				t.Fatalf("got %v, want active %v and user type %q", resp, tt.wantActive, tt.wantType)
+			}
+			// Inactive answers do not describe the token
+			if !tt.wantActive && (resp.GetClaims() != nil || resp.GetUsername() != "") {
+				t.Fatalf("inactive answer %v describes the token", resp)
+			}
+		})
+	}
+
+	// Only admin users introspect
+	_, err := f.client.Introspect(context.Background(), &authv1.IntrospectRequest{Token: bobToken})
+	checkStatus(t, err, codes.Unauthenticated, apiresponse.CodeMissingToken)
+	_, err = f.client.Introspect(withToken(bobToken), &authv1.IntrospectRequest{Token: bobToken})
+	checkStatus(t, err, codes.Unauthenticated, apiresponse.CodeInvalidToken)
+}
@@ -0,0 +1,235 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Kantha2004/SimpleJWT/internal/api"
+	apiresponse "github.com/Kantha2004/SimpleJWT/internal/apiResponse"
+	"github.com/Kantha2004/SimpleJWT/internal/models"
+	"github.com/Kantha2004/SimpleJWT/internal/service"
+	"github.com/Kantha2004/SimpleJWT/pkg/authv1"
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// authenticate verifies a token and its session like the HTTP middleware.
+// Rejected tokens return their error code.
+func (s *Server) authenticate(ctx context.Context, token string, allowClientUsers bool) (*api.TokenIdentity, apiresponse.ErrorCode, error) {
+	identity, err := api.AuthenticateToken(ctx, s.jwtService, s.sessions, token, allowClientUsers, s.logger)
+	var tokenErr *api.TokenError
+	switch {
+	case errors.As(err, &tokenErr):
+		return nil, tokenErr.Code, statusError(tokenErr.Code, tokenErr.Detail)
+	case err != nil:
+		return nil, apiresponse.CodeInternal, s.internalError(ctx, "Failed to validate session", err)
+	}
+	return identity, "", nil
+}
+
+// RefreshToken rotates a token: the session of the token is revoked and a
+// new session of the same family is started. A token can only be refreshed
+// once, so concurrent refreshes of a leaked token cannot both succeed.
+func (s *Server) RefreshToken(ctx context.Context, req *authv1.RefreshTokenRequest) (*authv1.RefreshTokenResponse, error) {
+	identity, _, err := s.authenticate(ctx, req.GetToken(), true)
+	if err != nil {
+		return nil, err
+	}
+
+	event, client, err := s.refreshAuditEvent(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	revoked, err := s.sessions.WithContext(ctx).RevokeSession(identity.UserID, identity.ClientID, identity.SessionID)
+	if err != nil {
+		return nil, s.internalError(ctx, "Failed to refresh token", err)
+	}
+	if revoked == 0 {
+		return nil, statusError(apiresponse.CodeSessionRevoked, "Session has been revoked or has expired")
+	}
+
+	session, err := s.authService.StartSession(ctx, caller(ctx), identity.UserID, identity.ClientID, identity.Session.FamilyID)
+	if err != nil {
+		return nil, s.internalError(ctx, "Failed to refresh token", err)
+	}
+
+	var token string
+	if identity.IsClientUser() {
+		token, err = s.authService.ClientUserToken(ctx, client, identity.UserID, session.SessionID)
+	} else {
+		token, err = s.jwtService.CreateToken(identity.UserID, session.SessionID)
+	}
+	if err != nil {
+		return nil, s.internalError(ctx, "Failed to refresh token", err)
+	}
+
+	event.TargetType = "session"
+	event.TargetID = session.SessionID
+	event.Reason = "refreshed session " + identity.SessionID
+	s.recordAudit(ctx, event)
+
+	return &authv1.RefreshTokenResponse{
+		Token:     token,
+		SessionId: session.SessionID,
+		ExpiresAt: timestamppb.New(session.ExpiresAt),
+	}, nil
+}
+
+// refreshAuditEvent starts the audit event of a refresh and returns the
+// client of a client user. Disabled users and users of suspended clients
+// cannot refresh, even if their sessions were not revoked.
+func (s *Server) refreshAuditEvent(ctx context.Context, identity *api.TokenIdentity) (models.AuditEvent, *models.Client, error) {
+	if !identity.IsClientUser() {
+		user, err := s.users.WithContext(ctx).GetUserByID(identity.UserID)
+		if err != nil {
+			return models.AuditEvent{}, nil, s.internalError(ctx, "Failed to refresh token", err)
+		}
+		if user == nil || user.IsDisabled() {
+			return models.AuditEvent{}, nil, statusError(apiresponse.CodeUserDisabled, "User is disabled")
+		}
+		return service.AdminAuditEvent(user, models.AuditActionSessionRefresh), nil, nil
+	}
+
+	client, err := s.clients.WithContext(ctx).GetClientId(*identity.ClientID)
+	if err != nil {
+		return models.AuditEvent{}, nil, s.internalError(ctx, "Failed to refresh token", err)
+	}
+	if client == nil || client.IsSuspended() {
+		return models.AuditEvent{}, nil, statusError(apiresponse.CodeClientSuspended, "Client is suspended")
+	}
+
+	user, err := s.clientUsers(client.SchemaName).WithContext(ctx).GetClientUserByID(identity.UserID)
+	if err != nil {
+		return models.AuditEvent{}, nil, s.internalError(ctx, "Failed to refresh token", err)
+	}
+	if user == nil || user.IsDisabled() {
+		return models.AuditEvent{}, nil, statusError(apiresponse.CodeUserDisabled, "User is disabled")
+	}
+	return service.ClientUserAuditEvent(client, user, models.AuditActionClientUserSessionRefresh), client, nil
+}
+
+// VerifyToken reports whether a token is valid. Invalid tokens are not an
+// error of the call.
+func (s *Server) VerifyToken(ctx context.Context, req *authv1.VerifyTokenRequest) (*authv1.VerifyTokenResponse, error) {
+	identity, code, err := s.authenticate(ctx, req.GetToken(), true)
+	if err != nil {
+		if code == apiresponse.CodeInternal {
+			return nil, err
+		}
+		return &authv1.VerifyTokenResponse{Valid: false, ErrorCode: string(code)}, nil
+	}
+
+	return &authv1.VerifyTokenResponse{Valid: true, Claims: tokenClaims(identity)}, nil
+}
+
+// Introspect describes a token of the calling admin user or of a user of
+// their clients
+func (s *Server) Introspect(ctx context.Context, req *authv1.IntrospectRequest) (*authv1.IntrospectResponse, error) {
+	callerToken, err := bearerToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	caller, _, err := s.authenticate(ctx, callerToken, false)
+	if err != nil {
+		return nil, err
+	}
+
+	identity, code, err := s.authenticate(ctx, req.GetToken(), true)
+	if err != nil {
+		if code == apiresponse.CodeInternal {
+			return nil, err
+		}
+		return &authv1.IntrospectResponse{Active: false}, nil
+	}
+
+	resp := &authv1.IntrospectResponse{
+		Active:            true,
+		Claims:            tokenClaims(identity),
+		SessionLastSeenAt: timestamppb.New(identity.Session.LastSeenAt),
+	}
+
+	if !identity.IsClientUser() {
+		if identity.UserID != caller.UserID {
+			return &authv1.IntrospectResponse{Active: false}, nil
+		}
+		user, err := s.users.WithContext(ctx).GetUserByID(identity.UserID)
+		if err != nil {
+			return nil, s.internalError(ctx, "Failed to introspect token", err)
+		}
+		if user == nil {
+			return &authv1.IntrospectResponse{Active: false}, nil
+		}
+		resp.Username = user.Username
+		resp.UserType = models.AuditActorAdminUser
+		return resp, nil
+	}
+
+	client, err := s.clients.WithContext(ctx).GetClientId(*identity.ClientID)
+	if err != nil {
+		return nil, s.internalError(ctx, "Failed to introspect token", err)
+	}
+	if client == nil || client.UserID != caller.UserID {
+		return &authv1.IntrospectResponse{Active: false}, nil
+	}
+	user, err := s.clientUsers(client.SchemaName).WithContext(ctx).GetClientUserByID(identity.UserID)
+	if err != nil {
+		return nil, s.internalError(ctx, "Failed to introspect token", err)
+	}
+	if user == nil {
+		return &authv1.IntrospectResponse{Active: false}, nil
+	}
+	resp.Username = user.Username
+	resp.UserType = models.AuditActorClientUser
+	return resp, nil
+}
+
+// GetJWKS returns the public keys verifying tokens
+func (s *Server) GetJWKS(ctx context.Context, _ *authv1.GetJWKSRequest) (*authv1.GetJWKSResponse, error) {
+	set, err := s.jwtService.JSONWebKeySet()
+	if err != nil {
+		return nil, s.internalError(ctx, "Failed to encode signing keys", err)
+	}
+
+	resp := &authv1.GetJWKSResponse{Keys: make([]*authv1.JSONWebKey, 0, len(set.Keys))}
+	for _, key := range set.Keys {
+		resp.Keys = append(resp.Keys, &authv1.JSONWebKey{
+			Kty: key.KeyType,
+			Kid: key.KeyID,
+			Alg: key.Algorithm,
+			Use: key.Use,
+			Crv: key.Curve,
+			X:   key.X,
+			Y:   key.Y,
+			N:   key.N,
+			E:   key.E,
+		})
+	}
+	return resp, nil
+}
+
+// tokenClaims converts the claims of a verified token
+func tokenClaims(identity *api.TokenIdentity) *authv1.Claims {
+	claims := &authv1.Claims{
+		UserId:    uint64(identity.UserID),
+		SessionId: identity.SessionID,
+	}
+	if identity.ClientID != nil {
+		claims.ClientId = uint64(*identity.ClientID)
+	}
+
+	claims.Issuer, _ = identity.Claims.GetIssuer()
+	claims.Audience, _ = identity.Claims.GetAudience()
+	claims.IssuedAt = timestamp(identity.Claims.GetIssuedAt())
+	claims.ExpiresAt = timestamp(identity.Claims.GetExpirationTime())
+
+	return claims
+}
+
+// timestamp converts a time claim, nil when the claim is missing
+func timestamp(date *jwt.NumericDate, err error) *timestamppb.Timestamp {
+	if err != nil || date == nil {
+		return nil
+	}
+	return timestamppb.New(date.Time)
+}
@@ -0,0 +1,125 @@
+// Package metrics holds the Prometheus collectors of the service. They are
+// registered on Registry, which the server exposes on /metrics.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "simplejwt"
+
+// Login outcomes
+const (
+	LoginSuccess            = "success"
+	LoginInvalidCredentials = "invalid_credentials"
+	LoginUserDisabled       = "user_disabled"
+	LoginClientSuspended    = "client_suspended"
+	LoginUnknownClient      = "unknown_client"
+	LoginError              = "error"
+)
+
+// UnknownTenant is the tenant label of client user logins whose client does
+// not resolve. Only IDs of existing clients become labels, so callers cannot
+// create series by naming clients.
+const UnknownTenant = "unknown"
+
+// Token rejection reasons
+const (
+	TokenExpired        = "expired"
+	TokenBadSignature   = "bad_signature"
+	TokenMalformed      = "malformed"
+	TokenUnknownKey     = "unknown_key"
+	TokenRevoked        = "revoked"
+	TokenWrongAudience  = "wrong_audience"
+	TokenMissingSession = "missing_session"
+	TokenMissing        = "missing"
+	TokenInvalid        = "invalid"
+)
+
+// Registry holds every collector of the service along with the Go runtime
+// and process collectors
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+var (
+	HTTPRequests = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route and status code.",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method, route and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	LoginAttempts = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "login_attempts_total",
+		Help:      "Login attempts by user type, tenant (client ID, empty for admin users, \"unknown\" when the client does not exist) and outcome.",
+	}, []string{"user_type", "tenant", "outcome"})
+
+	TokensIssued = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tokens_issued_total",
+		Help:      "Tokens issued by user type.",
+	}, []string{"user_type"})
+
+	TokensVerified = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tokens_verified_total",
+		Help:      "Tokens accepted by the authentication middleware.",
+	})
+
+	TokensRejected = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tokens_rejected_total",
+		Help:      "Tokens rejected by the authentication middleware by reason.",
+	}, []string{"reason"})
+
+	// Password hashing is deliberately slow, so the buckets reach further
+	// than the HTTP ones
+	PasswordHashDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "password_hash_duration_seconds",
+		Help:      "Time spent hashing and verifying passwords by algorithm and operation.",
+		Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"algorithm", "operation"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// RegisterDBStats exposes the connection pool statistics of a database
+func RegisterDBStats(db *sql.DB, name string) error {
+	return Registry.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+// Handler serves the metrics of Registry in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// ObserveLogin counts a login attempt
+func ObserveLogin(userType, tenant, outcome string) {
+	LoginAttempts.WithLabelValues(userType, tenant, outcome).Inc()
+}
+
+// PasswordHashTimer starts timing a password hash operation; call
+// ObserveDuration on the result when it is done
+func PasswordHashTimer(algorithm, operation string) *prometheus.Timer {
+	return prometheus.NewTimer(PasswordHashDuration.WithLabelValues(algorithm, operation))
+}
@@ -4,17 +4,25 @@ import "time"
 
 // AdminUser represents a user in the system
 type AdminUser struct {
-	ID           uint   `json:"id" gorm:"primaryKey" example:"1"`
-	Username     string `json:"username" gorm:"unique;not null;size:50" example:"john_doe"`
-	Email        string `json:"email" gorm:"unique;not null;size:100" example:"john@example.com"`
-	PasswordHash string `json:"-" gorm:"not null;size:255"`
+	ID           uint       `json:"id" gorm:"primaryKey" example:"1"`
+	Username     string     `json:"username" gorm:"unique;not null;size:50" example:"john_doe"`
+	Email        string     `json:"email" gorm:"unique;not null;size:100" example:"john@example.com"`
+	PasswordHash string     `json:"-" gorm:"not null;size:255"`
+	DisabledAt   *time.Time `json:"disabled_at,omitempty"`
+	// Version is incremented by every update; the ETag of the user
+	Version uint `json:"version" gorm:"not null;default:1" example:"1"`
 	TableModel
 }
 
+// IsDisabled reports whether the user has been disabled and can no longer log in
+func (u *AdminUser) IsDisabled() bool {
+	return u.DisabledAt != nil
+}
+
 // CreateUser represents the request payload for user creation
 type CreateUser struct {
 	Username string `json:"username" binding:"required,min=3,max=50" example:"john_doe"`
-	Password string `json:"password" binding:"required,min=8,max=100" example:"password123"`
+	Password string `json:"password" binding:"required" example:"password123"`
 	Email    string `json:"email" binding:"required,email,max=100" example:"john@example.com"`
 }
 
@@ -28,9 +36,21 @@ type LoginRequest struct {
 type LoginResponse struct {
 	Token     string    `json:"token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
 	ExpiresAt time.Time `json:"expiresAt" example:"2023-01-02T00:00:00Z"`
+	SessionID string    `json:"sessionId" example:"3f1c8f9e-7c1e-4a8e-9a4e-1b2c3d4e5f60"`
 	User      UserInfo  `json:"user"`
 }
 
+// ConsoleSession describes the session of the web console. Its token is
+// kept in a cookie scripts cannot read, so it is not part of the response.
+type ConsoleSession struct {
+	ExpiresAt time.Time `json:"expiresAt" example:"2023-01-02T00:00:00Z"`
+	SessionID string    `json:"sessionId" example:"3f1c8f9e-7c1e-4a8e-9a4e-1b2c3d4e5f60"`
+	// CSRFToken must be sent in the X-CSRF-Token header of requests that
+	// change something
+	CSRFToken string   `json:"csrfToken" example:"mO3a9yJc1V2kq0k5s9dYxkq5T2sWq7bC8nKf3uQH1xE"`
+	User      UserInfo `json:"user"`
+}
+
 // UserInfo represents public user information
 type UserInfo struct {
 	ID       uint   `json:"id" example:"1"`
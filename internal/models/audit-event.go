@@ -0,0 +1,89 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Audit actions
+const (
+	AuditActionUserCreate               = "user.create"
+	AuditActionUserLogin                = "user.login"
+	AuditActionUserPasswordChange       = "user.password_change"
+	AuditActionSessionRevoke            = "session.revoke"
+	AuditActionSessionRefresh           = "session.refresh"
+	AuditActionClientCreate             = "client.create"
+	AuditActionClientSuspend            = "client.suspend"
+	AuditActionClientResume             = "client.resume"
+	AuditActionClientSecretRotate       = "client.secret_rotate"
+	AuditActionClientConfigUpdate       = "client.config_update"
+	AuditActionClientUserCreate         = "client_user.create"
+	AuditActionClientUserLogin          = "client_user.login"
+	AuditActionClientUserSessionRevoke  = "client_user.session_revoke"
+	AuditActionClientUserSessionRefresh = "client_user.session_refresh"
+	AuditActionClientUserDisable        = "client_user.disable"
+	AuditActionClientUserPasswordReset  = "client_user.password_reset"
+	AuditActionClientUserRolesUpdate    = "client_user.roles_update"
+	AuditActionRoleCreate               = "role.create"
+	AuditActionRoleDelete               = "role.delete"
+	AuditActionWebhookCreate            = "webhook.create"
+	AuditActionWebhookDelete            = "webhook.delete"
+	AuditActionWebhookRedeliver         = "webhook.redeliver"
+)
+
+// Audit actor types
+const (
+	AuditActorAdminUser  = "admin_user"
+	AuditActorClientUser = "client_user"
+	AuditActorAnonymous  = "anonymous"
+	// AuditActorOperator is someone running simplejwtctl against the database
+	AuditActorOperator = "operator"
+)
+
+// Audit outcomes
+const (
+	AuditOutcomeSuccess = "success"
+	AuditOutcomeFailure = "failure"
+)
+
+var ErrAuditEventImmutable = errors.New("audit events are append-only")
+
+// AuditEvent is an append-only record of a security-relevant event
+type AuditEvent struct {
+	ID         uint      `json:"id" gorm:"primaryKey" example:"1"`
+	OccurredAt time.Time `json:"occurred_at" gorm:"not null;index" example:"2023-01-01T00:00:00Z"`
+	ActorType  string    `json:"actor_type" gorm:"not null;size:20" example:"admin_user"`
+	ActorID    *uint     `json:"actor_id,omitempty" example:"1"`
+	ActorName  string    `json:"actor_name,omitempty" gorm:"size:100" example:"john_doe"`
+	Action     string    `json:"action" gorm:"not null;size:50;index" example:"client.create"`
+	TargetType string    `json:"target_type,omitempty" gorm:"size:50" example:"client"`
+	TargetID   string    `json:"target_id,omitempty" gorm:"size:100" example:"1"`
+	ClientID   *uint     `json:"client_id,omitempty" gorm:"index" example:"1"`
+	// OwnerUserID is the admin user the event is visible to
+	OwnerUserID *uint  `json:"-" gorm:"index"`
+	IPAddress   string `json:"ip_address" gorm:"size:64" example:"203.0.113.7"`
+	UserAgent   string `json:"user_agent" gorm:"size:512" example:"Mozilla/5.0"`
+	Outcome     string `json:"outcome" gorm:"not null;size:10" example:"success"`
+	Reason      string `json:"reason,omitempty" gorm:"size:255" example:"invalid password"`
+}
+
+func (e *AuditEvent) BeforeUpdate(tx *gorm.DB) error {
+	return ErrAuditEventImmutable
+}
+
+func (e *AuditEvent) BeforeDelete(tx *gorm.DB) error {
+	return ErrAuditEventImmutable
+}
+
+// AuditEventQuery represents the filters of an audit log query
+type AuditEventQuery struct {
+	From      *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00" example:"2023-01-01T00:00:00Z"`
+	To        *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00" example:"2023-01-31T23:59:59Z"`
+	ActorID   *uint      `form:"actor_id" example:"1"`
+	ActorType string     `form:"actor_type" binding:"omitempty,oneof=admin_user client_user anonymous" example:"admin_user"`
+	Action    string     `form:"action" example:"client.create"`
+	ClientID  *uint      `form:"client_id" example:"1"`
+	ListParams
+}
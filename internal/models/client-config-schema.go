@@ -1,6 +1,8 @@
 package models
 
 import (
+	"encoding/json"
+
 	"gorm.io/datatypes"
 )
 
@@ -8,4 +10,30 @@ import (
 type ClientConfig struct {
 	TableModel
 	Settings datatypes.JSON `json:"settings" gorm:"type:jsonb"`
+	// Version is incremented by every update; the ETag of the configuration
+	Version uint `json:"version" gorm:"not null;default:1"`
+}
+
+// ClientSettings is the decoded form of ClientConfig.Settings
+type ClientSettings struct {
+	// PasswordPolicy holds per-client overrides of the global password policy.
+	// Only the fields present in the JSON are overridden.
+	PasswordPolicy json.RawMessage `json:"password_policy,omitempty" swaggertype:"object"`
+	// DefaultLocale is the language of error messages for requests whose
+	// Accept-Language header names no supported language (e.g. "fr")
+	DefaultLocale string `json:"default_locale,omitempty" example:"fr"`
+}
+
+// PasswordPolicy describes the rules a password must satisfy
+type PasswordPolicy struct {
+	MinLength        int  `json:"min_length" example:"8"`
+	MaxLength        int  `json:"max_length" example:"100"`
+	RequireUppercase bool `json:"require_uppercase" example:"true"`
+	RequireLowercase bool `json:"require_lowercase" example:"true"`
+	RequireDigit     bool `json:"require_digit" example:"true"`
+	RequireSymbol    bool `json:"require_symbol" example:"false"`
+	MaxRepeatedChars int  `json:"max_repeated_chars" example:"3"`
+	DisallowUserInfo bool `json:"disallow_user_info" example:"true"`
+	HistorySize      int  `json:"history_size" example:"5"`
+	CheckBreached    bool `json:"check_breached" example:"true"`
 }
@@ -8,3 +8,34 @@ type CreateClientUser struct {
 	CreateUser
 	ClientID uint `json:"client_id" binding:"required" example:"0"`
 }
+
+// ClientUserRequest identifies a user of a client
+type ClientUserRequest struct {
+	ClientID uint `json:"client_id" binding:"required" example:"1"`
+	UserID   uint `json:"user_id" binding:"required" example:"1"`
+}
+
+// NewPasswordRequest represents the request payload for an admin setting a
+// new password for a client user given by the URL of the request
+type NewPasswordRequest struct {
+	NewPassword string `json:"new_password" binding:"required" example:"N3w-Passw0rd!"`
+}
+
+// ResetClientUserPasswordRequest represents the request payload for an admin
+// setting a new password for a client user
+type ResetClientUserPasswordRequest struct {
+	ClientUserRequest
+	NewPasswordRequest
+}
+
+// UpdateClientUserRequest represents the changes an admin can make to a
+// client user. Only disabling is supported; disabled users stay disabled.
+type UpdateClientUserRequest struct {
+	Disabled *bool `json:"disabled" binding:"required" example:"true"`
+}
+
+// ClientUserLoginRequest represents the request payload for a client user login
+type ClientUserLoginRequest struct {
+	LoginRequest
+	ClientID uint `json:"client_id" binding:"required" example:"1"`
+}
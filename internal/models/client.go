@@ -3,6 +3,7 @@ package models
 import (
 	"crypto/rand"
 	"encoding/hex"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -13,9 +14,28 @@ type Client struct {
 	UserID       uint      `json:"user_id" gorm:"not null"`
 	User         AdminUser `json:"-" gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
 	SchemaName   string    `json:"schema_name" gorm:"not null"`
+	// SuspendedAt is set while the client is suspended; its users cannot log in
+	SuspendedAt *time.Time `json:"suspended_at,omitempty"`
+	// Version is incremented by every update; the ETag of the client
+	Version uint `json:"version" gorm:"not null;default:1" example:"1"`
 	TableModel
 }
 
+func (c *Client) IsSuspended() bool {
+	return c.SuspendedAt != nil
+}
+
+// RotateSecret replaces the secret of the client with a new one; the old
+// secret stops working once the client is saved
+func (c *Client) RotateSecret() error {
+	secret, err := generateUniqueClientSecret()
+	if err != nil {
+		return err
+	}
+	c.ClientSecret = secret
+	return nil
+}
+
 func generateUniqueClientSecret() (string, error) {
 	bytes := make([]byte, 32)
 	if _, err := rand.Read(bytes); err != nil {
@@ -36,7 +56,9 @@ func (c *Client) BeforeCreate(tx *gorm.DB) error {
 }
 
 type CreateClient struct {
-	ClientName string `json:"client_name" binding:"required"`
+	// ClientName names the schema of the client, so it may only hold
+	// lowercase letters, digits and underscores
+	ClientName string `json:"client_name" binding:"required" example:"acme"`
 }
 
 type CreateClientReponse struct {
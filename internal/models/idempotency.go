@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// IdempotencyKey stores the response of a request sent with an
+// Idempotency-Key header, so a retry gets the same response instead of
+// repeating the operation. The key is unique per caller (Scope).
+type IdempotencyKey struct {
+	ID    uint   `gorm:"primaryKey"`
+	Scope string `gorm:"not null;size:64;uniqueIndex:idx_idempotency_keys_scope_key"`
+	Key   string `gorm:"not null;size:255;uniqueIndex:idx_idempotency_keys_scope_key"`
+	// Fingerprint is the SHA-256 of the method, path and body of the request
+	Fingerprint string `gorm:"not null;size:64"`
+	// StatusCode is zero while the first request is still being handled
+	StatusCode  int
+	ContentType string `gorm:"size:255"`
+	Location    string `gorm:"size:2048"`
+	ETag        string `gorm:"column:etag;size:255"`
+	// ResponseBody is empty for responses holding a secret
+	ResponseBody []byte
+	CreatedAt    time.Time
+	ExpiresAt    time.Time `gorm:"not null;index"`
+}
+
+// IsComplete reports whether the response of the request has been stored
+func (k *IdempotencyKey) IsComplete() bool {
+	return k.StatusCode != 0
+}
@@ -0,0 +1,29 @@
+package models
+
+// ListParams are the paging and ordering query parameters of list endpoints.
+// A list is paged either by number (page) or by the opaque cursor returned
+// with the previous page, which takes precedence over page.
+type ListParams struct {
+	Page   int    `form:"page" binding:"omitempty,min=1" example:"1"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1" example:"20"`
+	Cursor string `form:"cursor" example:"eyJzIjoiaWQiLCJ2IjoyMCwiaWQiOjIwfQ"`
+	// Sort is a sortable field of the list, prefixed with - for descending order
+	Sort string `form:"sort" example:"-created_at"`
+}
+
+// ClientListQuery represents the filters of a client list
+type ClientListQuery struct {
+	ListParams
+	// Name only keeps clients whose name contains it, ignoring case
+	Name      string `form:"name" example:"acme"`
+	Suspended *bool  `form:"suspended" example:"false"`
+}
+
+// ClientUserListQuery represents the filters of a client user list
+type ClientUserListQuery struct {
+	ListParams
+	// Username and Email only keep users whose value contains them, ignoring case
+	Username string `form:"username" example:"john"`
+	Email    string `form:"email" example:"@example.com"`
+	Disabled *bool  `form:"disabled" example:"false"`
+}
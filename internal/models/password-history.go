@@ -0,0 +1,14 @@
+package models
+
+// PasswordHistory stores previous password hashes of a user
+type PasswordHistory struct {
+	UserID       uint   `json:"user_id" gorm:"not null;index"`
+	PasswordHash string `json:"-" gorm:"not null;size:255"`
+	TableModel
+}
+
+// ChangePasswordRequest represents the request payload for changing a password
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required" example:"password123"`
+	NewPassword     string `json:"new_password" binding:"required" example:"N3w-Passw0rd!"`
+}
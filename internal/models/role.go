@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// Role is a role a client defines for its users. The roles of a client user
+// are listed in the roles claim of their tokens, for the client's services to
+// authorize requests with.
+type Role struct {
+	ID          uint      `json:"id" gorm:"primaryKey" example:"1"`
+	Name        string    `json:"name" gorm:"not null;size:50" example:"editor"`
+	Description string    `json:"description" gorm:"not null;size:255" example:"Can edit articles"`
+	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+	Version     uint      `json:"version" gorm:"not null;default:1" example:"1"`
+}
+
+// UserRole grants a role to a client user
+type UserRole struct {
+	UserID uint `gorm:"primaryKey"`
+	RoleID uint `gorm:"primaryKey"`
+}
+
+// CreateRoleRequest represents the request payload for role creation
+type CreateRoleRequest struct {
+	Name        string `json:"name" binding:"required,max=50" example:"editor"`
+	Description string `json:"description" binding:"max=255" example:"Can edit articles"`
+}
+
+// SetClientUserRolesRequest represents the request payload replacing the
+// roles of a client user; an empty list removes them all
+type SetClientUserRolesRequest struct {
+	Roles []string `json:"roles" binding:"required,max=50,dive,required" example:"editor"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// Session records a login of an admin user or, when ClientID is set, of a client user
+type Session struct {
+	SessionID  string     `json:"session_id" gorm:"uniqueIndex;not null;size:36" example:"3f1c8f9e-7c1e-4a8e-9a4e-1b2c3d4e5f60"`
+	UserID     uint       `json:"user_id" gorm:"not null;index" example:"1"`
+	ClientID   *uint      `json:"client_id,omitempty" gorm:"index" example:"1"`
+	FamilyID   string     `json:"family_id" gorm:"not null;size:36;index" example:"9b2d7c4a-1f3e-4d5c-8b6a-7e8f9a0b1c2d"`
+	UserAgent  string     `json:"user_agent" gorm:"size:512" example:"Mozilla/5.0"`
+	IPAddress  string     `json:"ip_address" gorm:"size:64" example:"203.0.113.7"`
+	LastSeenAt time.Time  `json:"last_seen_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	// CSRFToken must accompany requests of a console session that change
+	// something; sessions not started by the console have none
+	CSRFToken string `json:"-" gorm:"column:csrf_token;size:64"`
+	Current   bool   `json:"current" gorm:"-"`
+	TableModel
+}
+
+// IsActive reports whether the session is neither revoked nor expired
+func (s *Session) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// RevokeSessionRequest represents the request payload for revoking one session
+type RevokeSessionRequest struct {
+	SessionID string `json:"session_id" binding:"required" example:"3f1c8f9e-7c1e-4a8e-9a4e-1b2c3d4e5f60"`
+}
+
+// ClientUserSessionsRequest identifies a client user whose sessions are managed by an admin
+type ClientUserSessionsRequest struct {
+	ClientID uint `json:"client_id" form:"client_id" binding:"required" example:"1"`
+	UserID   uint `json:"user_id" form:"user_id" binding:"required" example:"1"`
+	// SessionID limits a revocation to a single session; empty revokes all of them
+	SessionID string `json:"session_id,omitempty" form:"session_id" example:"3f1c8f9e-7c1e-4a8e-9a4e-1b2c3d4e5f60"`
+}
+
+// RevokeSessionsResponse reports how many sessions were revoked
+type RevokeSessionsResponse struct {
+	Revoked int64 `json:"revoked" example:"2"`
+}
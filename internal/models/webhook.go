@@ -0,0 +1,159 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// Webhook event types
+const (
+	WebhookEventClientUserCreated         = "client_user.created"
+	WebhookEventClientUserLoggedIn        = "client_user.logged_in"
+	WebhookEventClientUserPasswordChanged = "client_user.password_changed"
+	WebhookEventClientUserDisabled        = "client_user.disabled"
+)
+
+// WebhookEventTypes lists every event a subscription can filter on
+var WebhookEventTypes = []string{
+	WebhookEventClientUserCreated,
+	WebhookEventClientUserLoggedIn,
+	WebhookEventClientUserPasswordChanged,
+	WebhookEventClientUserDisabled,
+}
+
+// Webhook delivery statuses
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// WebhookSubscription sends the client's events of the given types to a URL
+type WebhookSubscription struct {
+	ClientID   uint                        `json:"client_id" gorm:"not null;index" example:"1"`
+	Client     Client                      `json:"-" gorm:"foreignKey:ClientID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+	URL        string                      `json:"url" gorm:"not null;size:2048" example:"https://example.com/hooks/simplejwt"`
+	Secret     string                      `json:"-" gorm:"not null;size:128"`
+	EventTypes datatypes.JSONSlice[string] `json:"event_types" gorm:"type:jsonb" swaggertype:"array,string" example:"client_user.created"`
+	Active     bool                        `json:"active" gorm:"not null;default:true" example:"true"`
+	// Version is incremented by every update; the ETag of the subscription
+	Version uint `json:"version" gorm:"not null;default:1" example:"1"`
+	TableModel
+}
+
+// Subscribes reports whether the subscription wants events of the given type.
+// A subscription without event types receives every event.
+func (s *WebhookSubscription) Subscribes(eventType string) bool {
+	if len(s.EventTypes) == 0 {
+		return true
+	}
+	for _, t := range s.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery is a queued event for one subscription
+type WebhookDelivery struct {
+	SubscriptionID uint           `json:"subscription_id" gorm:"not null;index" example:"1"`
+	EventID        string         `json:"event_id" gorm:"not null;size:36;index" example:"0f8e6c1a-2b3d-4e5f-8a9b-0c1d2e3f4a5b"`
+	EventType      string         `json:"event_type" gorm:"not null;size:50" example:"client_user.created"`
+	Payload        datatypes.JSON `json:"payload" gorm:"type:jsonb" swaggertype:"object"`
+	Status         string         `json:"status" gorm:"not null;size:20;index" example:"pending"`
+	Attempts       int            `json:"attempts" gorm:"not null;default:0" example:"1"`
+	NextAttemptAt  time.Time      `json:"next_attempt_at" gorm:"not null;index"`
+	LastAttemptAt  *time.Time     `json:"last_attempt_at,omitempty"`
+	DeliveredAt    *time.Time     `json:"delivered_at,omitempty"`
+	LastStatusCode int            `json:"last_status_code,omitempty" example:"200"`
+	LastError      string         `json:"last_error,omitempty" gorm:"size:1024"`
+	TableModel
+}
+
+// WebhookDeliveryAttempt logs one HTTP attempt of a delivery
+type WebhookDeliveryAttempt struct {
+	ID          uint      `json:"id" gorm:"primaryKey" example:"1"`
+	DeliveryID  uint      `json:"delivery_id" gorm:"not null;index" example:"1"`
+	Attempt     int       `json:"attempt" gorm:"not null" example:"1"`
+	AttemptedAt time.Time `json:"attempted_at" gorm:"not null"`
+	StatusCode  int       `json:"status_code,omitempty" example:"500"`
+	DurationMs  int64     `json:"duration_ms" example:"120"`
+	Error       string    `json:"error,omitempty" gorm:"size:1024"`
+}
+
+// WebhookEvent is the JSON body posted to subscribers
+type WebhookEvent struct {
+	ID        string      `json:"id" example:"0f8e6c1a-2b3d-4e5f-8a9b-0c1d2e3f4a5b"`
+	Type      string      `json:"type" example:"client_user.created"`
+	CreatedAt time.Time   `json:"created_at" example:"2023-01-01T00:00:00Z"`
+	ClientID  uint        `json:"client_id" example:"1"`
+	Data      interface{} `json:"data"`
+}
+
+// ClientUserEventData is the data of client user webhook events
+type ClientUserEventData struct {
+	UserID   uint   `json:"user_id" example:"1"`
+	Username string `json:"username" example:"john_doe"`
+	Email    string `json:"email" example:"john@example.com"`
+}
+
+// WebhookSubscriptionRequest describes the subscription of a client's
+// webhook; the client is given by the URL of the request
+type WebhookSubscriptionRequest struct {
+	URL        string   `json:"url" binding:"required,url,startswith=http" example:"https://example.com/hooks/simplejwt"`
+	EventTypes []string `json:"event_types" binding:"omitempty,dive,oneof=client_user.created client_user.logged_in client_user.password_changed client_user.disabled" example:"client_user.created"`
+}
+
+// CreateWebhookRequest represents the request payload for subscribing to webhooks
+type CreateWebhookRequest struct {
+	ClientID uint `json:"client_id" binding:"required" example:"1"`
+	WebhookSubscriptionRequest
+}
+
+// CreateWebhookResponse returns the signing secret, which is only shown once
+type CreateWebhookResponse struct {
+	WebhookSubscription
+	Secret string `json:"secret" example:"4f9c0b1e..."`
+}
+
+// WebhookIDRequest identifies a webhook subscription
+type WebhookIDRequest struct {
+	WebhookID uint `json:"webhook_id" form:"webhook_id" binding:"required" example:"1"`
+}
+
+// WebhookDeliveriesFilter represents the filters of a delivery log query
+type WebhookDeliveriesFilter struct {
+	Status string `form:"status" binding:"omitempty,oneof=pending succeeded failed" example:"failed"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=500" example:"50"`
+}
+
+// WebhookDeliveriesRequest represents a delivery log query
+type WebhookDeliveriesRequest struct {
+	WebhookID uint `form:"webhook_id" binding:"required" example:"1"`
+	WebhookDeliveriesFilter
+}
+
+// WebhookDeliveryLog is a delivery with its attempts
+type WebhookDeliveryLog struct {
+	WebhookDelivery
+	AttemptLog []WebhookDeliveryAttempt `json:"attempt_log"`
+}
+
+// RedeliverWebhookRequest identifies a delivery to send again
+type RedeliverWebhookRequest struct {
+	DeliveryID uint `json:"delivery_id" binding:"required" example:"1"`
+}
+
+func (s *WebhookSubscription) BeforeCreate(tx *gorm.DB) error {
+	if s.Secret == "" {
+		secret, err := generateUniqueClientSecret()
+		if err != nil {
+			return err
+		}
+		s.Secret = secret
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Kantha2004/SimpleJWT/internal/db"
+	"github.com/Kantha2004/SimpleJWT/internal/models"
+)
+
+// RecordAudit appends an event to the audit log, filling in the caller
+// details. Failures are only logged so auditing never breaks the request
+// itself.
+func (s *AuthService) RecordAudit(ctx context.Context, caller Caller, event models.AuditEvent) {
+	event.OccurredAt = time.Now()
+	event.IPAddress = caller.IP
+	event.UserAgent = truncate(caller.UserAgent, 512)
+	event.Reason = truncate(event.Reason, 255)
+
+	if event.ActorType == "" {
+		event.ActorType = models.AuditActorAnonymous
+	}
+	if event.Outcome == "" {
+		event.Outcome = models.AuditOutcomeSuccess
+	}
+
+	if err := db.NewAuditRepository(s.db.WithContext(ctx)).CreateAuditEvent(&event); err != nil {
+		s.logger.ErrorContext(ctx, "Error recording audit event", "error", err, "action", event.Action)
+	}
+}
+
+// AdminAuditEvent starts an audit event performed by an admin user on their own resources
+func AdminAuditEvent(user *models.AdminUser, action string) models.AuditEvent {
+	return models.AuditEvent{
+		ActorType:   models.AuditActorAdminUser,
+		ActorID:     &user.ID,
+		ActorName:   user.Username,
+		Action:      action,
+		OwnerUserID: &user.ID,
+	}
+}
+
+// ClientUserAuditEvent starts an audit event performed by a client user, or
+// by an unknown user of the client when user is nil
+func ClientUserAuditEvent(client *models.Client, user *models.ClientUser, action string) models.AuditEvent {
+	event := models.AuditEvent{
+		ActorType:   models.AuditActorAnonymous,
+		Action:      action,
+		ClientID:    &client.ID,
+		OwnerUserID: &client.UserID,
+	}
+	if user != nil {
+		event.ActorType = models.AuditActorClientUser
+		event.ActorID = &user.ID
+		event.ActorName = user.Username
+	}
+	return event
+}
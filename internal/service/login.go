@@ -0,0 +1,237 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/Kantha2004/SimpleJWT/internal/auth"
+	"github.com/Kantha2004/SimpleJWT/internal/metrics"
+	"github.com/Kantha2004/SimpleJWT/internal/models"
+	"github.com/Kantha2004/SimpleJWT/internal/tracing"
+)
+
+// Rejected logins return one of these errors; any other error is a failure
+// of the server whose cause should not reach the caller
+var (
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	ErrUserDisabled       = errors.New("user is disabled")
+	ErrClientSuspended    = errors.New("client is suspended")
+)
+
+// Login authenticates an admin user and starts a session
+func (s *AuthService) Login(ctx context.Context, caller Caller, username, password string) (*models.LoginResponse, error) {
+	userRepo := s.users.WithContext(ctx)
+
+	user, err := userRepo.GetUserByUsername(username)
+	if err != nil {
+		metrics.ObserveLogin(models.AuditActorAdminUser, "", metrics.LoginError)
+		return nil, fmt.Errorf("fetching user: %w", err)
+	}
+
+	// Unknown users are checked against a dummy hash, so they take as long
+	// to reject as wrong passwords and response times do not tell which
+	// usernames exist
+	if user == nil {
+		s.checkDummyPassword(ctx, password)
+	}
+	if user == nil || !auth.CheckPassword(user.PasswordHash, password) {
+		event := models.AuditEvent{
+			ActorName: username,
+			Action:    models.AuditActionUserLogin,
+			Outcome:   models.AuditOutcomeFailure,
+			Reason:    "invalid username or password",
+		}
+		if user != nil {
+			event = AdminAuditEvent(user, models.AuditActionUserLogin)
+			event.Outcome = models.AuditOutcomeFailure
+			event.Reason = "invalid password"
+		}
+		s.RecordAudit(ctx, caller, event)
+		metrics.ObserveLogin(models.AuditActorAdminUser, "", metrics.LoginInvalidCredentials)
+		return nil, ErrInvalidCredentials
+	}
+
+	if user.IsDisabled() {
+		event := AdminAuditEvent(user, models.AuditActionUserLogin)
+		event.Outcome = models.AuditOutcomeFailure
+		event.Reason = "user is disabled"
+		s.RecordAudit(ctx, caller, event)
+		metrics.ObserveLogin(models.AuditActorAdminUser, "", metrics.LoginUserDisabled)
+		return nil, ErrUserDisabled
+	}
+
+	// Upgrade hashes produced with an older algorithm or weaker parameters
+	if s.passwordHasher.NeedsRehash(user.PasswordHash) {
+		s.rehashPassword(ctx, user, password, userRepo.UpdateUser)
+	}
+
+	session, err := s.StartSession(ctx, caller, user.ID, nil, "")
+	if err != nil {
+		metrics.ObserveLogin(models.AuditActorAdminUser, "", metrics.LoginError)
+		return nil, fmt.Errorf("creating session: %w", err)
+	}
+
+	token, err := s.jwtService.CreateToken(user.ID, session.SessionID)
+	if err != nil {
+		metrics.ObserveLogin(models.AuditActorAdminUser, "", metrics.LoginError)
+		return nil, fmt.Errorf("creating token: %w", err)
+	}
+
+	event := AdminAuditEvent(user, models.AuditActionUserLogin)
+	event.TargetType = "session"
+	event.TargetID = session.SessionID
+	s.RecordAudit(ctx, caller, event)
+	metrics.ObserveLogin(models.AuditActorAdminUser, "", metrics.LoginSuccess)
+
+	return loginResponse(token, session, user), nil
+}
+
+// ClientUserLogin authenticates a user of a client and starts a session. The
+// client is returned once it is found, even when the login is rejected, so
+// callers can answer in the client's locale. Unknown clients are rejected
+// like wrong passwords.
+func (s *AuthService) ClientUserLogin(ctx context.Context, caller Caller, clientID uint, username, password string) (*models.LoginResponse, *models.Client, error) {
+	client, err := s.clients.WithContext(ctx).GetClientId(clientID)
+	if err != nil {
+		metrics.ObserveLogin(models.AuditActorClientUser, metrics.UnknownTenant, metrics.LoginError)
+		return nil, nil, fmt.Errorf("fetching client: %w", err)
+	}
+	if client == nil {
+		s.checkDummyPassword(ctx, password)
+		metrics.ObserveLogin(models.AuditActorClientUser, metrics.UnknownTenant, metrics.LoginUnknownClient)
+		return nil, nil, ErrInvalidCredentials
+	}
+
+	tenant := strconv.FormatUint(uint64(client.ID), 10)
+	tracing.SetClient(ctx, client.ID, client.SchemaName)
+
+	clientUserRepo := s.clientUsers(client.SchemaName).WithContext(ctx)
+
+	user, err := clientUserRepo.GetClientUserByUsername(username)
+	if err != nil {
+		metrics.ObserveLogin(models.AuditActorClientUser, tenant, metrics.LoginError)
+		return nil, client, fmt.Errorf("fetching client user: %w", err)
+	}
+
+	// Unknown users are checked against a dummy hash like in Login
+	if user == nil {
+		s.checkDummyPassword(ctx, password)
+	}
+	if user == nil || !auth.CheckPassword(user.PasswordHash, password) {
+		event := ClientUserAuditEvent(client, user, models.AuditActionClientUserLogin)
+		event.ActorName = username
+		event.Outcome = models.AuditOutcomeFailure
+		event.Reason = "invalid username or password"
+		s.RecordAudit(ctx, caller, event)
+		metrics.ObserveLogin(models.AuditActorClientUser, tenant, metrics.LoginInvalidCredentials)
+		return nil, client, ErrInvalidCredentials
+	}
+
+	if user.IsDisabled() {
+		event := ClientUserAuditEvent(client, user, models.AuditActionClientUserLogin)
+		event.Outcome = models.AuditOutcomeFailure
+		event.Reason = "user is disabled"
+		s.RecordAudit(ctx, caller, event)
+		metrics.ObserveLogin(models.AuditActorClientUser, tenant, metrics.LoginUserDisabled)
+		return nil, client, ErrUserDisabled
+	}
+
+	if client.IsSuspended() {
+		event := ClientUserAuditEvent(client, user, models.AuditActionClientUserLogin)
+		event.Outcome = models.AuditOutcomeFailure
+		event.Reason = "client is suspended"
+		s.RecordAudit(ctx, caller, event)
+		metrics.ObserveLogin(models.AuditActorClientUser, tenant, metrics.LoginClientSuspended)
+		return nil, client, ErrClientSuspended
+	}
+
+	if s.passwordHasher.NeedsRehash(user.PasswordHash) {
+		s.rehashPassword(ctx, user, password, clientUserRepo.UpdateClientUser)
+	}
+
+	session, err := s.StartSession(ctx, caller, user.ID, &client.ID, "")
+	if err != nil {
+		metrics.ObserveLogin(models.AuditActorClientUser, tenant, metrics.LoginError)
+		return nil, client, fmt.Errorf("creating session: %w", err)
+	}
+
+	token, err := s.ClientUserToken(ctx, client, user.ID, session.SessionID)
+	if err != nil {
+		metrics.ObserveLogin(models.AuditActorClientUser, tenant, metrics.LoginError)
+		return nil, client, err
+	}
+
+	event := ClientUserAuditEvent(client, user, models.AuditActionClientUserLogin)
+	event.TargetType = "session"
+	event.TargetID = session.SessionID
+	s.RecordAudit(ctx, caller, event)
+	s.PublishClientUserEvent(ctx, client, user, models.WebhookEventClientUserLoggedIn)
+	metrics.ObserveLogin(models.AuditActorClientUser, tenant, metrics.LoginSuccess)
+
+	return loginResponse(token, session, user), client, nil
+}
+
+// ClientUserToken signs the token of a session of a client user, listing the
+// roles the user is granted now
+func (s *AuthService) ClientUserToken(ctx context.Context, client *models.Client, userID uint, sessionID string) (string, error) {
+	roles, err := s.roles(client.SchemaName).WithContext(ctx).GetUserRoleNames(userID)
+	if err != nil {
+		return "", fmt.Errorf("fetching roles: %w", err)
+	}
+
+	token, err := s.jwtService.CreateClientUserToken(client.ID, userID, sessionID, roles)
+	if err != nil {
+		return "", fmt.Errorf("creating token: %w", err)
+	}
+	return token, nil
+}
+
+// dummyPassword is hashed into the dummy hash; no login checks it
+const dummyPassword = "simplejwt-dummy-password"
+
+// checkDummyPassword verifies a password against a hash made by the current
+// hasher, spending the time a login of an existing user would. The hash is
+// made again when the hasher is reloaded with stronger parameters.
+func (s *AuthService) checkDummyPassword(ctx context.Context, password string) {
+	hash := s.dummyHash.Load()
+	if hash == nil || s.passwordHasher.NeedsRehash(*hash) {
+		encoded, err := s.passwordHasher.Hash(dummyPassword)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Error hashing dummy password", "error", err)
+			return
+		}
+		hash = &encoded
+		s.dummyHash.Store(hash)
+	}
+	auth.CheckPassword(*hash, password)
+}
+
+// rehashPassword replaces the stored hash with one produced by the current
+// hasher. Failures are only logged since the login itself already succeeded.
+func (s *AuthService) rehashPassword(ctx context.Context, user *models.AdminUser, password string, update func(*models.AdminUser) error) {
+	hashedPassword, err := s.passwordHasher.Hash(password)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Error rehashing password", "error", err, "user_id", user.ID)
+		return
+	}
+
+	user.PasswordHash = hashedPassword
+	if err := update(user); err != nil {
+		s.logger.ErrorContext(ctx, "Error storing rehashed password", "error", err, "user_id", user.ID)
+	}
+}
+
+func loginResponse(token string, session *models.Session, user *models.AdminUser) *models.LoginResponse {
+	return &models.LoginResponse{
+		Token:     token,
+		ExpiresAt: session.ExpiresAt,
+		SessionID: session.SessionID,
+		User: models.UserInfo{
+			ID:       user.ID,
+			Username: user.Username,
+			Email:    user.Email,
+		},
+	}
+}
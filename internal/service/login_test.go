@@ -0,0 +1,300 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Kantha2004/SimpleJWT/internal/auth"
+	"github.com/Kantha2004/SimpleJWT/internal/config"
+	"github.com/Kantha2004/SimpleJWT/internal/db"
+	"github.com/Kantha2004/SimpleJWT/internal/db/memory"
+	"github.com/Kantha2004/SimpleJWT/internal/models"
+	"github.com/Kantha2004/SimpleJWT/internal/service"
+)
+
+// The service runs on the in-memory repositories it is given; sessions and
+// the audit log are stored in SQLite, in a new file for every test
+
+const testPassword = "Passw0rd!x"
+
+type fixture struct {
+	service     *service.AuthService
+	database    *db.Database
+	users       *memory.UserRepository
+	clients     *memory.ClientRepository
+	clientUsers *memory.ClientUserRepositories
+	roles       *memory.RoleRepositories
+	hasher      auth.PasswordHasher
+	jwtService  *auth.JWTService
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+
+	database, err := db.NewDatabase(&config.DBConfig{
+		Driver:     config.DriverSQLite,
+		SQLitePath: filepath.Join(t.TempDir(), "simplejwt.db"),
+	})
+	if err != nil {
+		t.Fatalf("NewDatabase: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	// The lowest cost keeps logins fast
+	hasher, err := auth.NewBcryptHasher(4)
+	if err != nil {
+		t.Fatalf("NewBcryptHasher: %v", err)
+	}
+
+	f := &fixture{
+		database:    database,
+		users:       memory.NewUserRepository(),
+		clients:     memory.NewClientRepository(),
+		clientUsers: memory.NewClientUserRepositories(),
+		roles:       memory.NewRoleRepositories(),
+		hasher:      hasher,
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	jwtService := auth.NewJWTService("service-test-secret", nil, "simplejwt", "")
+	f.jwtService = jwtService
+	f.service = service.NewAuthService(database, f.users, f.clients, f.clientUsers.Factory(), f.roles.Factory(), db.NewSessionRepository(database), jwtService, hasher, nil, logger)
+	return f
+}
+
+func (f *fixture) newUser(t *testing.T, username string) *models.AdminUser {
+	t.Helper()
+
+	hash, err := f.hasher.Hash(testPassword)
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	return &models.AdminUser{Username: username, Email: username + "@example.com", PasswordHash: hash}
+}
+
+func (f *fixture) auditOutcomes(t *testing.T, action string) []string {
+	t.Helper()
+
+	var outcomes []string
+	err := f.database.DB.Model(&models.AuditEvent{}).Where("action = ?", action).Order("id").Pluck("outcome", &outcomes).Error
+	if err != nil {
+		t.Fatalf("reading audit events: %v", err)
+	}
+	return outcomes
+}
+
+func TestLogin(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	caller := service.Caller{IP: "192.0.2.1", UserAgent: "test"}
+
+	user := f.newUser(t, "alice")
+	if _, err := f.users.CreateUser(user); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	disabled := f.newUser(t, "bob")
+	disabledAt := time.Now()
+	disabled.DisabledAt = &disabledAt
+	if _, err := f.users.CreateUser(disabled); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		username string
+		password string
+		want     error
+	}{
+		{"valid credentials", "alice", testPassword, nil},
+		{"wrong password", "alice", "wrong password", service.ErrInvalidCredentials},
+		{"unknown user", "carol", testPassword, service.ErrInvalidCredentials},
+		{"disabled user", "bob", testPassword, service.ErrUserDisabled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := f.service.Login(ctx, caller, tt.username, tt.password)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Login: got %v, want %v", err, tt.want)
+			}
+			if tt.want != nil {
+				return
+			}
+
+			if response.Token == "" || response.User.ID != user.ID {
+				t.Fatalf("got response %+v for user %d", response, user.ID)
+			}
+			session, err := db.NewSessionRepository(f.database).GetSessionBySessionID(response.SessionID)
+			if err != nil || session == nil || session.UserID != user.ID || session.IPAddress != caller.IP {
+				t.Fatalf("stored session %+v, %v", session, err)
+			}
+		})
+	}
+
+	got := f.auditOutcomes(t, models.AuditActionUserLogin)
+	want := []string{models.AuditOutcomeSuccess, models.AuditOutcomeFailure, models.AuditOutcomeFailure, models.AuditOutcomeFailure}
+	if len(got) != len(want) {
+		t.Fatalf("audited login outcomes %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("audited login outcomes %v, want %v", got, want)
+		}
+	}
+}
+
+func TestClientUserLoginRejections(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	client := &models.Client{ClientName: "acme", UserID: 1, SchemaName: "acme_client"}
+	if _, err := f.clients.CreateClient(client); err != nil {
+		t.Fatalf("CreateClient: %v", err)
+	}
+	if _, err := f.clientUsers.ForSchema(client.SchemaName).CreateClientUser(f.newUser(t, "bob")); err != nil {
+		t.Fatalf("CreateClientUser: %v", err)
+	}
+
+	if _, got, err := f.service.ClientUserLogin(ctx, service.Caller{}, client.ID+1, "bob", testPassword); !errors.Is(err, service.ErrInvalidCredentials) || got != nil {
+		t.Fatalf("login to an unknown client: got %v and client %v, want %v", err, got, service.ErrInvalidCredentials)
+	}
+	if _, got, err := f.service.ClientUserLogin(ctx, service.Caller{}, client.ID, "bob", "wrong password"); !errors.Is(err, service.ErrInvalidCredentials) || got == nil || got.ID != client.ID {
+		t.Fatalf("login with a wrong password: got %v and client %v, want %v and the client", err, got, service.ErrInvalidCredentials)
+	}
+
+	suspendedAt := time.Now()
+	client.SuspendedAt = &suspendedAt
+	if err := f.clients.UpdateClient(client); err != nil {
+		t.Fatalf("UpdateClient: %v", err)
+	}
+	if _, _, err := f.service.ClientUserLogin(ctx, service.Caller{}, client.ID, "bob", testPassword); !errors.Is(err, service.ErrClientSuspended) {
+		t.Fatalf("login to a suspended client: got %v, want %v", err, service.ErrClientSuspended)
+	}
+}
+
+func TestClientUserLoginListsRoles(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	client := &models.Client{ClientName: "acme", UserID: 1, SchemaName: "acme_client"}
+	if _, err := f.clients.CreateClient(client); err != nil {
+		t.Fatalf("CreateClient: %v", err)
+	}
+	user, err := f.clientUsers.ForSchema(client.SchemaName).CreateClientUser(f.newUser(t, "bob"))
+	if err != nil {
+		t.Fatalf("CreateClientUser: %v", err)
+	}
+
+	roles := f.roles.ForSchema(client.SchemaName)
+	var granted []uint
+	for _, name := range []string{"viewer", "editor"} {
+		role := &models.Role{Name: name}
+		if err := roles.CreateRole(role); err != nil {
+			t.Fatalf("CreateRole: %v", err)
+		}
+		granted = append(granted, role.ID)
+	}
+	if err := roles.SetUserRoles(user.ID, granted); err != nil {
+		t.Fatalf("SetUserRoles: %v", err)
+	}
+
+	response, got, err := f.service.ClientUserLogin(ctx, service.Caller{}, client.ID, "bob", testPassword)
+	if err != nil || got == nil || got.ID != client.ID {
+		t.Fatalf("ClientUserLogin = %v, client %v", err, got)
+	}
+
+	claims, err := f.jwtService.VerifyToken(response.Token)
+	if err != nil {
+		t.Fatalf("VerifyToken: %v", err)
+	}
+	if fmt.Sprint(claims["roles"]) != "[editor viewer]" || claims["client_id"] != float64(client.ID) {
+		t.Fatalf("token has roles %v and client_id %v, want [editor viewer] and %d", claims["roles"], claims["client_id"], client.ID)
+	}
+}
+
+// countingHasher counts the hashes made, which logins only make for the
+// dummy hash and for rehashing
+type countingHasher struct {
+	auth.PasswordHasher
+	hashes atomic.Int32
+}
+
+func (h *countingHasher) Hash(password string) (string, error) {
+	h.hashes.Add(1)
+	return h.PasswordHasher.Hash(password)
+}
+
+func TestUnknownUsersCheckDummyHash(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	reloadable := auth.NewReloadablePasswordHasher(f.hasher)
+	hasher := &countingHasher{PasswordHasher: reloadable}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	svc := service.NewAuthService(f.database, f.users, f.clients, f.clientUsers.Factory(), f.roles.Factory(), db.NewSessionRepository(f.database), f.jwtService, hasher, nil, logger)
+
+	if _, err := f.users.CreateUser(f.newUser(t, "alice")); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	client := &models.Client{ClientName: "acme", UserID: 1, SchemaName: "acme_client"}
+	if _, err := f.clients.CreateClient(client); err != nil {
+		t.Fatalf("CreateClient: %v", err)
+	}
+
+	adminLogin := func(username string) func() error {
+		return func() error {
+			_, err := svc.Login(ctx, service.Caller{}, username, testPassword)
+			return err
+		}
+	}
+	clientUserLogin := func(clientID uint, username string) func() error {
+		return func() error {
+			_, _, err := svc.ClientUserLogin(ctx, service.Caller{}, clientID, username, testPassword)
+			return err
+		}
+	}
+
+	// The dummy hash is made by the first rejected login of an unknown user
+	// and checked by the following ones
+	tests := []struct {
+		name       string
+		login      func() error
+		wantHashes int32
+	}{
+		{"unknown admin user", adminLogin("carol"), 1},
+		{"another unknown admin user", adminLogin("dave"), 1},
+		{"unknown client user", clientUserLogin(client.ID, "carol"), 1},
+		{"unknown client", clientUserLogin(client.ID+1, "carol"), 1},
+		{"known user", func() error {
+			_, err := svc.Login(ctx, service.Caller{}, "alice", "wrong password")
+			return err
+		}, 1},
+	}
+	for _, tt := range tests {
+		if err := tt.login(); !errors.Is(err, service.ErrInvalidCredentials) {
+			t.Fatalf("%s: got %v, want %v", tt.name, err, service.ErrInvalidCredentials)
+		}
+		if n := hasher.hashes.Load(); n != tt.wantHashes {
+			t.Fatalf("%s: %d hashes made, want %d", tt.name, n, tt.wantHashes)
+		}
+	}
+
+	// A stronger hasher makes verifying take longer, so the dummy hash is
+	// made again
+	stronger, err := auth.NewBcryptHasher(5)
+	if err != nil {
+		t.Fatalf("NewBcryptHasher: %v", err)
+	}
+	reloadable.Reload(stronger)
+	if err := adminLogin("carol")(); !errors.Is(err, service.ErrInvalidCredentials) {
+		t.Fatalf("login after the reload: got %v, want %v", err, service.ErrInvalidCredentials)
+	}
+	if n := hasher.hashes.Load(); n != 2 {
+		t.Fatalf("%d hashes made after the reload, want 2", n)
+	}
+}
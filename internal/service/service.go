@@ -0,0 +1,60 @@
+// Package service holds the account logic shared by the HTTP and gRPC APIs:
+// logins, sessions, audit events and the webhook events they raise. It knows
+// nothing of either transport; callers describe themselves with a Caller and
+// map the errors to their own responses.
+package service
+
+import (
+	"log/slog"
+	"sync/atomic"
+
+	"github.com/Kantha2004/SimpleJWT/internal/auth"
+	"github.com/Kantha2004/SimpleJWT/internal/db"
+	"github.com/Kantha2004/SimpleJWT/internal/webhooks"
+)
+
+// Caller is who made a request, as recorded in sessions and audit events
+type Caller struct {
+	IP        string
+	UserAgent string
+}
+
+type AuthService struct {
+	db             *db.Database
+	users          db.UserRepository
+	clients        db.ClientRepository
+	clientUsers    db.ClientUserRepositoryFactory
+	roles          db.RoleRepositoryFactory
+	sessions       *db.SessionRepository
+	jwtService     *auth.JWTService
+	passwordHasher auth.PasswordHasher
+	webhooks       *webhooks.Dispatcher
+	logger         *slog.Logger
+	// dummyHash is checked for logins of unknown users, see
+	// checkDummyPassword
+	dummyHash atomic.Pointer[string]
+}
+
+// NewAuthService builds the service on the repositories the APIs share. The
+// database stores the audit log.
+func NewAuthService(database *db.Database, users db.UserRepository, clients db.ClientRepository, clientUsers db.ClientUserRepositoryFactory, roles db.RoleRepositoryFactory, sessions *db.SessionRepository, jwtService *auth.JWTService, passwordHasher auth.PasswordHasher, webhookDispatcher *webhooks.Dispatcher, logger *slog.Logger) *AuthService {
+	return &AuthService{
+		db:             database,
+		users:          users,
+		clients:        clients,
+		clientUsers:    clientUsers,
+		roles:          roles,
+		sessions:       sessions,
+		jwtService:     jwtService,
+		passwordHasher: passwordHasher,
+		webhooks:       webhookDispatcher,
+		logger:         logger,
+	}
+}
+
+func truncate(value string, maxLength int) string {
+	if len(value) <= maxLength {
+		return value
+	}
+	return value[:maxLength]
+}
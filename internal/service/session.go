@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Kantha2004/SimpleJWT/internal/auth"
+	"github.com/Kantha2004/SimpleJWT/internal/models"
+	"github.com/google/uuid"
+)
+
+// StartSession stores a new session of a login or refresh. clientID is nil
+// for admin users. familyID ties a refreshed session to the login it
+// descends from; empty starts a family.
+func (s *AuthService) StartSession(ctx context.Context, caller Caller, userID uint, clientID *uint, familyID string) (*models.Session, error) {
+	if familyID == "" {
+		familyID = uuid.NewString()
+	}
+
+	now := time.Now()
+	session := &models.Session{
+		SessionID:  uuid.NewString(),
+		UserID:     userID,
+		ClientID:   clientID,
+		FamilyID:   familyID,
+		UserAgent:  truncate(caller.UserAgent, 512),
+		IPAddress:  caller.IP,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(auth.TokenTTL),
+	}
+
+	if err := s.sessions.WithContext(ctx).CreateSession(session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// PublishClientUserEvent queues a webhook event about a client user.
+// Failures are only logged so webhooks never break the request itself.
+func (s *AuthService) PublishClientUserEvent(ctx context.Context, client *models.Client, user *models.ClientUser, eventType string) {
+	if s.webhooks == nil {
+		return
+	}
+
+	data := models.ClientUserEventData{
+		UserID:   user.ID,
+		Username: user.Username,
+		Email:    user.Email,
+	}
+	if err := s.webhooks.Publish(ctx, client.ID, eventType, data); err != nil {
+		s.logger.ErrorContext(ctx, "Error publishing webhook event", "error", err, "event_type", eventType, "client_id", client.ID)
+	}
+}
@@ -0,0 +1,84 @@
+// Package tracing sets up the OpenTelemetry tracer provider and W3C trace
+// context propagation, and defines the span attributes shared by the
+// instrumented packages.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/Kantha2004/SimpleJWT/internal/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Span attribute keys for the tenant a span works on
+const (
+	ClientIDKey     = attribute.Key("simplejwt.client.id")
+	TenantSchemaKey = attribute.Key("simplejwt.tenant.schema")
+)
+
+// Setup installs the global tracer provider and propagator and returns a
+// function that flushes pending spans on shutdown. With the none exporter
+// the global no-op provider stays in place, but incoming trace context is
+// still propagated.
+func Setup(ctx context.Context, cfg config.TracingConfig) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch cfg.Exporter {
+	case config.TracingExporterOTLP:
+		exporter, err = otlptracehttp.New(ctx)
+	case config.TracingExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout), stdouttrace.WithPrettyPrint())
+	default:
+		return func(context.Context) error { return nil }, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s trace exporter: %w", cfg.Exporter, err)
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithHost(),
+		resource.WithAttributes(semconv.ServiceName(cfg.ServiceName)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// Tracer returns a tracer of the global provider for an instrumented package
+func Tracer(name string) trace.Tracer {
+	return otel.Tracer("github.com/Kantha2004/SimpleJWT/" + name)
+}
+
+// SetClient records the client a request works on, on the current span
+func SetClient(ctx context.Context, clientID uint, schemaName string) {
+	trace.SpanFromContext(ctx).SetAttributes(
+		ClientIDKey.Int64(int64(clientID)),
+		TenantSchemaKey.String(schemaName),
+	)
+}
@@ -29,3 +29,13 @@ func GetUserIDFromContext(c *gin.Context) (uint, error) {
 
 	return uint(userID), nil
 }
+
+// GetSessionIDFromContext extracts the session ID of the authenticated token from context
+func GetSessionIDFromContext(c *gin.Context) (string, error) {
+	sessionID := c.GetString("session_id")
+	if sessionID == "" {
+		return "", errors.New("unable to find sessionID from the request")
+	}
+
+	return sessionID, nil
+}
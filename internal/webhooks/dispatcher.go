@@ -0,0 +1,262 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Kantha2004/SimpleJWT/internal/db"
+	"github.com/Kantha2004/SimpleJWT/internal/models"
+	"github.com/Kantha2004/SimpleJWT/internal/tracing"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Headers sent with every delivery
+const (
+	HeaderEvent     = "X-SimpleJWT-Event"
+	HeaderDelivery  = "X-SimpleJWT-Delivery"
+	HeaderTimestamp = "X-SimpleJWT-Timestamp"
+	HeaderSignature = "X-SimpleJWT-Signature"
+)
+
+var tracer = tracing.Tracer("internal/webhooks")
+
+const (
+	maxLoggedErrorLength = 1024
+	deliveryBatchSize    = 50
+)
+
+type Config struct {
+	// PollInterval is how often the queue is checked for due deliveries
+	PollInterval time.Duration
+	// Timeout bounds a single HTTP attempt
+	Timeout time.Duration
+	// MaxAttempts is the number of attempts before a delivery is marked failed
+	MaxAttempts int
+	// InitialBackoff is the wait after the first failure; it doubles on every retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between two attempts
+	MaxBackoff time.Duration
+	// AllowPrivateAddresses lets webhooks reach loopback and private
+	// networks, for development only
+	AllowPrivateAddresses bool
+}
+
+// Dispatcher queues events for webhook subscribers in the database and
+// delivers them in the background with exponential backoff
+type Dispatcher struct {
+	repo   *db.WebhookRepository
+	client *http.Client
+	config Config
+	logger *slog.Logger
+}
+
+func NewDispatcher(database *db.Database, config Config, logger *slog.Logger) *Dispatcher {
+	return &Dispatcher{
+		repo:   db.NewWebhookRepository(database),
+		client: newHTTPClient(config.Timeout, config.AllowPrivateAddresses),
+		config: config,
+		logger: logger,
+	}
+}
+
+// Publish queues an event for every active subscription of the client that
+// listens to its type. The event is durable once Publish returns.
+func (d *Dispatcher) Publish(ctx context.Context, clientID uint, eventType string, data interface{}) error {
+	repo := d.repo.WithContext(ctx)
+	subscriptions, err := repo.GetActiveSubscriptions(clientID)
+	if err != nil {
+		return fmt.Errorf("failed to load webhook subscriptions: %w", err)
+	}
+
+	event := models.WebhookEvent{
+		ID:        uuid.NewString(),
+		Type:      eventType,
+		CreatedAt: time.Now().UTC(),
+		ClientID:  clientID,
+		Data:      data,
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook event: %w", err)
+	}
+
+	var deliveries []models.WebhookDelivery
+	for _, subscription := range subscriptions {
+		if !subscription.Subscribes(eventType) {
+			continue
+		}
+		deliveries = append(deliveries, models.WebhookDelivery{
+			SubscriptionID: subscription.ID,
+			EventID:        event.ID,
+			EventType:      eventType,
+			Payload:        payload,
+			Status:         models.WebhookDeliveryPending,
+			NextAttemptAt:  event.CreatedAt,
+		})
+	}
+
+	return repo.CreateDeliveries(deliveries)
+}
+
+// Run delivers due webhooks until the context is cancelled
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		d.deliverDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *Dispatcher) deliverDue(ctx context.Context) {
+	deliveries, err := d.repo.GetDueDeliveries(time.Now(), deliveryBatchSize)
+	if err != nil {
+		d.logger.ErrorContext(ctx, "Error fetching due webhook deliveries", "error", err)
+		return
+	}
+
+	for i := range deliveries {
+		if ctx.Err() != nil {
+			return
+		}
+
+		delivery := &deliveries[i]
+
+		// Lease the delivery for longer than an attempt can take
+		claimed, err := d.repo.ClaimDelivery(delivery, time.Now().Add(2*d.config.Timeout))
+		if err != nil {
+			d.logger.ErrorContext(ctx, "Error claiming webhook delivery", "error", err, "delivery_id", delivery.ID)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		d.attempt(ctx, delivery)
+	}
+}
+
+// attempt delivers one webhook in a trace of its own, which the receiver can
+// continue from the traceparent header
+func (d *Dispatcher) attempt(ctx context.Context, delivery *models.WebhookDelivery) {
+	ctx, span := tracer.Start(ctx, "webhooks.Deliver", trace.WithAttributes(
+		attribute.Int64("simplejwt.webhook.delivery.id", int64(delivery.ID)),
+		attribute.String("simplejwt.webhook.event", delivery.EventType),
+	))
+	defer span.End()
+
+	repo := d.repo.WithContext(ctx)
+	subscription, err := repo.GetSubscriptionByID(delivery.SubscriptionID)
+	if err != nil {
+		d.logger.ErrorContext(ctx, "Error fetching webhook subscription", "error", err, "webhook_id", delivery.SubscriptionID)
+		return
+	}
+
+	now := time.Now()
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+
+	attempt := &models.WebhookDeliveryAttempt{
+		DeliveryID:  delivery.ID,
+		Attempt:     delivery.Attempts,
+		AttemptedAt: now,
+	}
+
+	if subscription == nil || !subscription.Active {
+		attempt.Error = "subscription was deleted or deactivated"
+	} else {
+		attempt.StatusCode, err = d.send(ctx, subscription, delivery)
+		if err != nil {
+			attempt.Error = truncate(err.Error(), maxLoggedErrorLength)
+			span.SetStatus(codes.Error, attempt.Error)
+		}
+	}
+	attempt.DurationMs = time.Since(now).Milliseconds()
+
+	delivery.LastStatusCode = attempt.StatusCode
+	delivery.LastError = attempt.Error
+
+	switch {
+	case attempt.Error == "":
+		delivery.Status = models.WebhookDeliverySucceeded
+		delivery.DeliveredAt = &now
+	case subscription == nil || !subscription.Active || delivery.Attempts >= d.config.MaxAttempts:
+		delivery.Status = models.WebhookDeliveryFailed
+	default:
+		delivery.NextAttemptAt = now.Add(d.backoff(delivery.Attempts))
+	}
+
+	if err := repo.RecordAttempt(delivery, attempt); err != nil {
+		d.logger.ErrorContext(ctx, "Error recording webhook delivery attempt", "error", err, "delivery_id", delivery.ID)
+	}
+}
+
+// send posts the payload and treats any non-2xx response, redirects
+// included, as a failure. Only the status of the response is kept: its body
+// could disclose what a receiver on an internal network answered.
+func (d *Dispatcher) send(ctx context.Context, subscription *models.WebhookSubscription, delivery *models.WebhookDelivery) (int, error) {
+	timestamp := time.Now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "SimpleJWT-Webhooks/1.0")
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderDelivery, delivery.EventID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(subscription.Secret, timestamp, delivery.Payload))
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// backoff returns the wait before the next attempt: InitialBackoff doubled
+// for every failed attempt so far, capped at MaxBackoff
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	wait := d.config.InitialBackoff
+	for i := 1; i < attempts; i++ {
+		wait *= 2
+		if wait >= d.config.MaxBackoff {
+			return d.config.MaxBackoff
+		}
+	}
+	return wait
+}
+
+func truncate(value string, maxLength int) string {
+	if len(value) <= maxLength {
+		return value
+	}
+	return value[:maxLength]
+}
@@ -0,0 +1,7 @@
+package webhooks
+
+// Exported for the tests of package webhooks_test
+var (
+	IsPublicAddress = isPublicAddress
+	NewHTTPClient   = newHTTPClient
+)
@@ -0,0 +1,48 @@
+package webhooks_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Kantha2004/SimpleJWT/internal/webhooks"
+)
+
+func TestSign(t *testing.T) {
+	// Receivers compute the signature themselves, so its format is pinned
+	const want = "sha256=2309b3241c934edd598182cd8af8663e23a4ed93bae9e076fbd3e8df8202253b"
+	if got := webhooks.Sign("whsec_test", 1700000000, []byte(`{"type":"user.created"}`)); got != want {
+		t.Fatalf("Sign = %s, want %s", got, want)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	const secret, timestamp = "whsec_test", int64(1700000000)
+	payload := []byte(`{"type":"user.created"}`)
+	signature := webhooks.Sign(secret, timestamp, payload)
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp int64
+		payload   string
+		signature string
+		want      bool
+	}{
+		{"round trip", secret, timestamp, string(payload), signature, true},
+		{"other secret", "whsec_other", timestamp, string(payload), signature, false},
+		{"other timestamp", secret, timestamp + 1, string(payload), signature, false},
+		{"other payload", secret, timestamp, `{"type":"user.deleted"}`, signature, false},
+		{"missing prefix", secret, timestamp, string(payload), strings.TrimPrefix(signature, "sha256="), false},
+		{"other prefix", secret, timestamp, string(payload), "sha1=" + strings.TrimPrefix(signature, "sha256="), false},
+		{"uppercase hex", secret, timestamp, string(payload), "sha256=" + strings.ToUpper(strings.TrimPrefix(signature, "sha256=")), false},
+		{"truncated", secret, timestamp, string(payload), signature[:len(signature)-2], false},
+		{"empty", secret, timestamp, string(payload), "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := webhooks.Verify(tt.secret, tt.timestamp, []byte(tt.payload), tt.signature); got != tt.want {
+				t.Fatalf("Verify = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
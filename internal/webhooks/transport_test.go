@@ -0,0 +1,88 @@
+package webhooks_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/Kantha2004/SimpleJWT/internal/webhooks"
+)
+
+func TestIsPublicAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		want    bool
+	}{
+		{"8.8.8.8", true},
+		{"172.32.0.1", true},
+		{"2606:4700:4700::1111", true},
+		{"::ffff:8.8.8.8", true},
+
+		// Loopback
+		{"127.0.0.1", false},
+		{"127.255.255.254", false},
+		{"::1", false},
+		// RFC 1918 and unique local
+		{"10.0.0.1", false},
+		{"172.16.0.1", false},
+		{"172.31.255.255", false},
+		{"192.168.1.1", false},
+		{"fd00::1", false},
+		// Link-local, where cloud metadata services listen
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		// IPv4-mapped IPv6 addresses are checked as IPv4
+		{"::ffff:127.0.0.1", false},
+		{"::ffff:10.0.0.1", false},
+		{"::ffff:169.254.169.254", false},
+		// IPv6 forms that reach IPv4 addresses through a translator
+		{"::ffff:0:7f00:1", false},
+		{"64:ff9b::7f00:1", false},
+		{"2002:7f00:1::", false},
+		// Unspecified, multicast and reserved
+		{"0.0.0.0", false},
+		{"::", false},
+		{"0.1.2.3", false},
+		{"224.0.0.1", false},
+		{"ff02::1", false},
+		{"100.64.0.1", false},
+		{"255.255.255.255", false},
+		{"2001:db8::1", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			if got := webhooks.IsPublicAddress(netip.MustParseAddr(tt.address)); got != tt.want {
+				t.Fatalf("isPublicAddress(%s) = %v, want %v", tt.address, got, tt.want)
+			}
+		})
+	}
+
+	if webhooks.IsPublicAddress(netip.Addr{}) {
+		t.Fatal("isPublicAddress of the zero address = true, want false")
+	}
+}
+
+func TestHTTPClientRefusesPrivateAddresses(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+
+	// The receiver listens on loopback
+	_, err := webhooks.NewHTTPClient(time.Second, false).Post(receiver.URL, "application/json", nil)
+	if !errors.Is(err, webhooks.ErrForbiddenAddress) {
+		t.Fatalf("delivery to %s: got %v, want %v", receiver.URL, err, webhooks.ErrForbiddenAddress)
+	}
+
+	resp, err := webhooks.NewHTTPClient(time.Second, true).Post(receiver.URL, "application/json", nil)
+	if err != nil {
+		t.Fatalf("delivery with private addresses allowed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("got status %d, want %d", resp.StatusCode, http.StatusNoContent)
+	}
+}
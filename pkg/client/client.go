@@ -0,0 +1,381 @@
+// Package client is a Go client of the SimpleJWT management API (v2).
+//
+// The client logs in with the configured credentials when a call first
+// needs a token, and logs in again shortly before the token expires or when
+// the server no longer accepts it, so callers never handle tokens:
+//
+//	api, err := client.New(client.Config{
+//		BaseURL:  "https://auth.example.com",
+//		Username: "admin",
+//		Password: os.Getenv("SIMPLEJWT_PASSWORD"),
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	acme, err := api.CreateClient(ctx, "acme")
+//
+// Calls return an *Error for error responses of the API; IsCode tells them
+// apart by their stable code.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// apiBasePath is where the v2 API is mounted on the server
+const apiBasePath = "/api/v2"
+
+const (
+	defaultTimeout       = 30 * time.Second
+	defaultRefreshBefore = time.Minute
+	userAgent            = "simplejwt-go-client"
+	// maxErrorBodyLength bounds how much of an error response is read
+	maxErrorBodyLength = 1 << 20
+)
+
+type Config struct {
+	// BaseURL is the root of the server, e.g. https://auth.example.com
+	BaseURL string
+	// HTTPClient sends the requests; defaults to a client with a 30s timeout
+	HTTPClient *http.Client
+	// Username and Password log the client in when a call needs a token.
+	// Login sets them too.
+	Username string
+	Password string
+	// Token is a bearer token used as is instead of logging in. It is not
+	// renewed, so calls fail once it expires.
+	Token string
+	// RefreshBefore is how long before its expiry a token is replaced by a
+	// new login; defaults to one minute
+	RefreshBefore time.Duration
+	// AcceptLanguage asks for the messages of errors in a language, e.g. "fr"
+	AcceptLanguage string
+}
+
+// Client calls the API. It is safe for concurrent use.
+type Client struct {
+	baseURL string
+	http    *http.Client
+	config  Config
+
+	// mu guards the login state below; it is held during a login so
+	// concurrent calls wait for a single new token
+	mu       sync.Mutex
+	session  *Session
+	username string
+	password string
+}
+
+func New(config Config) (*Client, error) {
+	base, err := url.Parse(config.BaseURL)
+	if err != nil || base.Scheme == "" || base.Host == "" {
+		return nil, fmt.Errorf("simplejwt: invalid base URL %q", config.BaseURL)
+	}
+
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: defaultTimeout}
+	}
+	if config.RefreshBefore <= 0 {
+		config.RefreshBefore = defaultRefreshBefore
+	}
+
+	return &Client{
+		baseURL:  strings.TrimSuffix(base.String(), "/") + apiBasePath,
+		http:     config.HTTPClient,
+		config:   config,
+		username: config.Username,
+		password: config.Password,
+	}, nil
+}
+
+// request is a call of the API
+type request struct {
+	method string
+	path   string
+	query  url.Values
+	body   any
+	header http.Header
+	// public requests are sent without a token
+	public bool
+}
+
+// response holds what a successful call returns besides its data
+type response struct {
+	header     http.Header
+	pagination pagination
+}
+
+// envelope is the body of successful responses
+type envelope struct {
+	Data       json.RawMessage `json:"data"`
+	Pagination *pagination     `json:"pagination"`
+}
+
+// call sends a request and decodes the data of the response into data,
+// unless data is nil or the response has no body
+func (c *Client) call(ctx context.Context, req request, data any) (*response, error) {
+	var body []byte
+	if req.body != nil {
+		encoded, err := json.Marshal(req.body)
+		if err != nil {
+			return nil, fmt.Errorf("simplejwt: failed to encode request: %w", err)
+		}
+		body = encoded
+	}
+
+	httpResp, err := c.send(ctx, req, body, false)
+	if err != nil {
+		return nil, err
+	}
+	// A login may fix a token the server no longer accepts; once
+	if httpResp.StatusCode == http.StatusUnauthorized && !req.public && c.canLogin() {
+		apiErr := decodeError(httpResp)
+		switch apiErr.Code {
+		case CodeTokenExpired, CodeSessionRevoked, CodeInvalidToken:
+			httpResp, err = c.send(ctx, req, body, true)
+			if err != nil {
+				return nil, err
+			}
+		default:
+			return nil, apiErr
+		}
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode >= http.StatusBadRequest {
+		return nil, decodeError(httpResp)
+	}
+
+	resp := &response{header: httpResp.Header}
+	if data == nil || httpResp.StatusCode == http.StatusNoContent {
+		return resp, nil
+	}
+
+	var decoded envelope
+	if err := json.NewDecoder(httpResp.Body).Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("simplejwt: failed to decode response: %w", err)
+	}
+	if err := json.Unmarshal(decoded.Data, data); err != nil {
+		return nil, fmt.Errorf("simplejwt: failed to decode response data: %w", err)
+	}
+	if decoded.Pagination != nil {
+		resp.pagination = *decoded.Pagination
+	}
+
+	return resp, nil
+}
+
+// send makes one HTTP attempt of a request. renew replaces the token by a
+// new login first.
+func (c *Client) send(ctx context.Context, req request, body []byte, renew bool) (*http.Response, error) {
+	endpoint := c.baseURL + req.path
+	if len(req.query) > 0 {
+		endpoint += "?" + req.query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.method, endpoint, reader)
+	if err != nil {
+		return nil, fmt.Errorf("simplejwt: failed to build request: %w", err)
+	}
+
+	for name, values := range req.header {
+		httpReq.Header[name] = values
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	httpReq.Header.Set("User-Agent", userAgent)
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	if c.config.AcceptLanguage != "" {
+		httpReq.Header.Set("Accept-Language", c.config.AcceptLanguage)
+	}
+
+	if !req.public {
+		token, err := c.token(ctx, renew)
+		if err != nil {
+			return nil, err
+		}
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	httpResp, err := c.http.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("simplejwt: %s %s: %w", req.method, req.path, err)
+	}
+	return httpResp, nil
+}
+
+// decodeError reads the problem details of an error response and closes it
+func decodeError(httpResp *http.Response) *Error {
+	defer httpResp.Body.Close()
+
+	data, _ := io.ReadAll(io.LimitReader(httpResp.Body, maxErrorBodyLength))
+	apiErr := &Error{}
+	if err := json.Unmarshal(data, apiErr); err != nil {
+		// Not a problem, e.g. the error page of a proxy
+		apiErr = &Error{Title: http.StatusText(httpResp.StatusCode), Detail: strings.TrimSpace(string(data))}
+	}
+	apiErr.StatusCode = httpResp.StatusCode
+
+	return apiErr
+}
+
+// canLogin reports whether the client has credentials to log in with
+func (c *Client) canLogin() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.username != ""
+}
+
+// token returns the bearer token of the next call, logging in when there is
+// no token yet, when it is about to expire, or when renew is set
+func (c *Client) token(ctx context.Context, renew bool) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.session != nil && !renew && time.Until(c.session.ExpiresAt) > c.config.RefreshBefore {
+		return c.session.Token, nil
+	}
+
+	if c.username == "" {
+		switch {
+		case c.session != nil:
+			// Nothing to log in with; the server decides whether it still works
+			return c.session.Token, nil
+		case c.config.Token != "":
+			return c.config.Token, nil
+		default:
+			return "", ErrNotAuthenticated
+		}
+	}
+
+	session, err := c.login(ctx, c.username, c.password)
+	if err != nil {
+		return "", err
+	}
+	c.session = session
+
+	return session.Token, nil
+}
+
+// login creates a session without changing the login state
+func (c *Client) login(ctx context.Context, username, password string) (*Session, error) {
+	var session Session
+	_, err := c.call(ctx, request{
+		method: http.MethodPost,
+		path:   "/sessions",
+		body:   map[string]string{"username": username, "password": password},
+		public: true,
+	}, &session)
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// Login logs in as an admin user. Later calls use the session, and the
+// credentials are kept to log in again when it expires.
+func (c *Client) Login(ctx context.Context, username, password string) (*Session, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	session, err := c.login(ctx, username, password)
+	if err != nil {
+		return nil, err
+	}
+
+	c.session = session
+	c.username = username
+	c.password = password
+
+	return session, nil
+}
+
+// Logout revokes the session of the client and forgets its credentials
+func (c *Client) Logout(ctx context.Context) error {
+	c.mu.Lock()
+	session := c.session
+	c.mu.Unlock()
+
+	if session == nil {
+		return nil
+	}
+
+	if _, err := c.call(ctx, request{method: http.MethodDelete, path: "/sessions/" + url.PathEscape(session.SessionID)}, nil); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.session = nil
+	c.username = ""
+	c.password = ""
+
+	return nil
+}
+
+// Session returns the current session, or nil before the first login
+func (c *Client) Session() *Session {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.session == nil {
+		return nil
+	}
+	session := *c.session
+	return &session
+}
+
+// ifMatch is the If-Match header of a change based on a version; version
+// zero changes the resource whatever its version
+func ifMatch(version uint) http.Header {
+	tag := "*"
+	if version != 0 {
+		tag = `"` + strconv.FormatUint(uint64(version), 10) + `"`
+	}
+	return http.Header{"If-Match": {tag}}
+}
+
+// parseETag returns the version of an ETag header, zero when there is none
+func parseETag(header string) uint {
+	tag := strings.Trim(strings.TrimPrefix(header, "W/"), `"`)
+	version, err := strconv.ParseUint(tag, 10, 0)
+	if err != nil {
+		return 0
+	}
+	return uint(version)
+}
+
+// newPage builds a page from the items and pagination of a list response
+func newPage[T any](items []T, resp *response) *Page[T] {
+	if items == nil {
+		items = []T{}
+	}
+	return &Page[T]{
+		Items:      items,
+		Page:       resp.pagination.Page,
+		Limit:      resp.pagination.Limit,
+		Total:      resp.pagination.Total,
+		TotalPages: resp.pagination.TotalPages,
+		NextCursor: resp.pagination.NextCursor,
+	}
+}
+
+// pathID formats an ID for a URL path
+func pathID(id uint) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
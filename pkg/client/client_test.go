@@ -0,0 +1,525 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Kantha2004/SimpleJWT/internal/api"
+	"github.com/Kantha2004/SimpleJWT/internal/api/handlers"
+	"github.com/Kantha2004/SimpleJWT/internal/auth"
+	"github.com/Kantha2004/SimpleJWT/internal/config"
+	"github.com/Kantha2004/SimpleJWT/internal/db"
+	"github.com/Kantha2004/SimpleJWT/internal/service"
+	"github.com/Kantha2004/SimpleJWT/internal/webhooks"
+	"github.com/Kantha2004/SimpleJWT/pkg/client"
+	"github.com/Kantha2004/SimpleJWT/pkg/verifier"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// The client runs against the routes of the server, on a SQLite database in
+// a new file for every test
+
+const (
+	testSecret   = "client-test-secret"
+	testPassword = "Passw0rd!x"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	database, err := db.NewDatabase(&config.DBConfig{
+		Driver:     config.DriverSQLite,
+		SQLitePath: filepath.Join(t.TempDir(), "simplejwt.db"),
+	})
+	if err != nil {
+		t.Fatalf("NewDatabase: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	jwtService := auth.NewJWTService(testSecret, nil, "simplejwt", "")
+
+	policy := config.Default().PasswordPolicy
+	policy.CheckBreached = false
+	passwordValidator := auth.NewPasswordValidator(policy, nil)
+
+	// The lowest cost keeps logins fast
+	passwordHasher, err := auth.NewBcryptHasher(4)
+	if err != nil {
+		t.Fatalf("NewBcryptHasher: %v", err)
+	}
+
+	dispatcher := webhooks.NewDispatcher(database, webhooks.Config{}, logger)
+	users := db.NewUserRepository(database)
+	clients := db.NewClientRepository(database)
+	clientUsers := db.NewClientUserRepositoryFactory(database)
+	roles := db.NewRoleRepositoryFactory(database)
+	sessions := db.NewSessionRepository(database)
+	authService := service.NewAuthService(database, users, clients, clientUsers, roles, sessions, jwtService, passwordHasher, dispatcher, logger)
+
+	deps := api.NewDependencies(jwtService, sessions, db.NewIdempotencyRepository(database), time.Hour, api.NewHealthChecker(database, jwtService, logger), logger)
+	handlerDeps := handlers.NewDependencies(database, users, clients, clientUsers, roles, jwtService, passwordValidator, passwordHasher, authService, logger)
+
+	router := gin.New()
+	defaults := config.Default()
+	api.SetupGinRoutes(router, "simplejwt-test", defaults.V1Deprecation, defaults.V1Sunset, deps, handlerDeps)
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newLoggedInClient signs up an admin user and returns a client holding
+// their credentials
+func newLoggedInClient(t *testing.T, server *httptest.Server, username string) *client.Client {
+	t.Helper()
+
+	api := newClient(t, client.Config{BaseURL: server.URL})
+	_, err := api.CreateUser(context.Background(), client.CreateUserRequest{
+		Username: username,
+		Email:    username + "@example.com",
+		Password: testPassword,
+	})
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+
+	return newClient(t, client.Config{BaseURL: server.URL, Username: username, Password: testPassword})
+}
+
+func newClient(t *testing.T, config client.Config) *client.Client {
+	t.Helper()
+
+	api, err := client.New(config)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return api
+}
+
+func TestLogin(t *testing.T) {
+	server := newTestServer(t)
+	ctx := context.Background()
+	newLoggedInClient(t, server, "alice")
+
+	api := newClient(t, client.Config{BaseURL: server.URL})
+	if _, err := api.CurrentUser(ctx); !errors.Is(err, client.ErrNotAuthenticated) {
+		t.Fatalf("CurrentUser before login: got %v, want ErrNotAuthenticated", err)
+	}
+
+	_, err := api.Login(ctx, "alice", "wrong password")
+	if !client.IsCode(err, client.CodeInvalidCredentials) {
+		t.Fatalf("Login with a wrong password: got %v, want %s", err, client.CodeInvalidCredentials)
+	}
+	if api.Session() != nil {
+		t.Fatal("a failed login set the session")
+	}
+
+	session, err := api.Login(ctx, "alice", testPassword)
+	if err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	if session.Token == "" || session.SessionID == "" || session.User.Username != "alice" {
+		t.Fatalf("Login returned %+v", session)
+	}
+
+	user, err := api.CurrentUser(ctx)
+	if err != nil {
+		t.Fatalf("CurrentUser: %v", err)
+	}
+	if user.Username != "alice" || user.Version == 0 {
+		t.Fatalf("CurrentUser returned %+v", user)
+	}
+	if api.Session().SessionID != session.SessionID {
+		t.Fatal("CurrentUser logged in again with a valid session")
+	}
+
+	if err := api.Logout(ctx); err != nil {
+		t.Fatalf("Logout: %v", err)
+	}
+	if _, err := api.CurrentUser(ctx); !errors.Is(err, client.ErrNotAuthenticated) {
+		t.Fatalf("CurrentUser after logout: got %v, want ErrNotAuthenticated", err)
+	}
+}
+
+func TestLoginWithConfiguredCredentials(t *testing.T) {
+	server := newTestServer(t)
+	api := newLoggedInClient(t, server, "alice")
+
+	if api.Session() != nil {
+		t.Fatal("the client logged in before a call needed a token")
+	}
+	if _, err := api.CurrentUser(context.Background()); err != nil {
+		t.Fatalf("CurrentUser: %v", err)
+	}
+	if api.Session() == nil {
+		t.Fatal("CurrentUser did not log in")
+	}
+}
+
+// expiringTransport sends the next request with an expired token, as if the
+// token of the client had expired on the server's clock
+type expiringTransport struct {
+	mu     sync.Mutex
+	expire bool
+	sent   []string
+}
+
+func (e *expiringTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	e.mu.Lock()
+	if e.expire && req.Header.Get("Authorization") != "" {
+		e.expire = false
+		req = req.Clone(req.Context())
+		req.Header.Set("Authorization", "Bearer "+expiredToken())
+	}
+	e.sent = append(e.sent, req.Method+" "+req.URL.Path)
+	e.mu.Unlock()
+
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func expiredToken() string {
+	past := time.Now().Add(-2 * time.Hour)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": 1,
+		"sid":     "expired",
+		"iss":     "simplejwt",
+		"iat":     past.Unix(),
+		"exp":     past.Add(time.Hour).Unix(),
+	})
+	signed, err := token.SignedString([]byte(testSecret))
+	if err != nil {
+		panic(err)
+	}
+	return signed
+}
+
+func TestReloginOnExpiredToken(t *testing.T) {
+	server := newTestServer(t)
+	ctx := context.Background()
+	newLoggedInClient(t, server, "alice")
+
+	transport := &expiringTransport{}
+	api := newClient(t, client.Config{
+		BaseURL:    server.URL,
+		HTTPClient: &http.Client{Transport: transport},
+		Username:   "alice",
+		Password:   testPassword,
+	})
+
+	if _, err := api.CurrentUser(ctx); err != nil {
+		t.Fatalf("CurrentUser: %v", err)
+	}
+	first := api.Session().SessionID
+
+	transport.mu.Lock()
+	transport.expire = true
+	transport.sent = nil
+	transport.mu.Unlock()
+
+	if _, err := api.CurrentUser(ctx); err != nil {
+		t.Fatalf("CurrentUser with an expired token: %v", err)
+	}
+	if api.Session().SessionID == first {
+		t.Fatal("the client did not log in again after token_expired")
+	}
+
+	want := []string{"GET /api/v2/users/me", "POST /api/v2/sessions", "GET /api/v2/users/me"}
+	if fmt.Sprint(transport.sent) != fmt.Sprint(want) {
+		t.Fatalf("sent %v, want %v", transport.sent, want)
+	}
+}
+
+func TestReloginOnRevokedSession(t *testing.T) {
+	server := newTestServer(t)
+	ctx := context.Background()
+	api := newLoggedInClient(t, server, "alice")
+
+	if _, err := api.CurrentUser(ctx); err != nil {
+		t.Fatalf("CurrentUser: %v", err)
+	}
+	revoked := api.Session()
+
+	// Revoke the session behind the client's back
+	req, err := http.NewRequest(http.MethodDelete, server.URL+"/api/v2/sessions/"+revoked.SessionID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+revoked.Token)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("revoking the session: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		t.Fatalf("revoking the session: status %d", resp.StatusCode)
+	}
+
+	if _, err := api.CurrentUser(ctx); err != nil {
+		t.Fatalf("CurrentUser with a revoked session: %v", err)
+	}
+	if api.Session().SessionID == revoked.SessionID {
+		t.Fatal("the client did not log in again after session_revoked")
+	}
+
+	// Without credentials the error reaches the caller
+	tokenOnly := newClient(t, client.Config{BaseURL: server.URL, Token: revoked.Token})
+	if _, err := tokenOnly.CurrentUser(ctx); !client.IsCode(err, client.CodeSessionRevoked) {
+		t.Fatalf("CurrentUser with a revoked token: got %v, want %s", err, client.CodeSessionRevoked)
+	}
+}
+
+func TestIfMatch(t *testing.T) {
+	server := newTestServer(t)
+	ctx := context.Background()
+	api := newLoggedInClient(t, server, "alice")
+
+	acme, err := api.CreateClient(ctx, "acme")
+	if err != nil {
+		t.Fatalf("CreateClient: %v", err)
+	}
+
+	settings, version, err := api.GetClientConfig(ctx, acme.ID)
+	if err != nil {
+		t.Fatalf("GetClientConfig: %v", err)
+	}
+
+	settings.DefaultLocale = "fr"
+	updated, err := api.UpdateClientConfig(ctx, acme.ID, version, *settings)
+	if err != nil {
+		t.Fatalf("UpdateClientConfig: %v", err)
+	}
+	if updated == version {
+		t.Fatalf("UpdateClientConfig kept version %d", version)
+	}
+
+	settings, current, err := api.GetClientConfig(ctx, acme.ID)
+	if err != nil {
+		t.Fatalf("GetClientConfig: %v", err)
+	}
+	if current != updated || settings.DefaultLocale != "fr" {
+		t.Fatalf("GetClientConfig returned %+v at version %d, want fr at version %d", settings, current, updated)
+	}
+
+	settings.DefaultLocale = "en"
+	if _, err := api.UpdateClientConfig(ctx, acme.ID, current, *settings); err != nil {
+		t.Fatalf("UpdateClientConfig: %v", err)
+	}
+
+	// The settings changed since they were read at current
+	_, err = api.UpdateClientConfig(ctx, acme.ID, current, *settings)
+	if !client.IsCode(err, client.CodePreconditionFailed) {
+		t.Fatalf("UpdateClientConfig with a stale version: got %v, want %s", err, client.CodePreconditionFailed)
+	}
+
+	rotated, err := api.RotateClientSecret(ctx, acme.ID, acme.Version)
+	if err != nil {
+		t.Fatalf("RotateClientSecret: %v", err)
+	}
+	if rotated.ClientSecret == "" || rotated.ClientSecret == acme.ClientSecret || rotated.Version == acme.Version {
+		t.Fatalf("RotateClientSecret returned %+v", rotated)
+	}
+	if _, err := api.RotateClientSecret(ctx, acme.ID, acme.Version); !client.IsCode(err, client.CodePreconditionFailed) {
+		t.Fatalf("RotateClientSecret with a stale version: got %v, want %s", err, client.CodePreconditionFailed)
+	}
+
+	user, err := api.CurrentUser(ctx)
+	if err != nil {
+		t.Fatalf("CurrentUser: %v", err)
+	}
+	newVersion, err := api.ChangePassword(ctx, user.Version, testPassword, "N3w-Passw0rd!")
+	if err != nil {
+		t.Fatalf("ChangePassword: %v", err)
+	}
+	if newVersion == user.Version {
+		t.Fatalf("ChangePassword kept version %d", user.Version)
+	}
+	if _, err := api.ChangePassword(ctx, user.Version, "N3w-Passw0rd!", testPassword); !client.IsCode(err, client.CodePreconditionFailed) {
+		t.Fatalf("ChangePassword with a stale version: got %v, want %s", err, client.CodePreconditionFailed)
+	}
+}
+
+func TestCursorPagination(t *testing.T) {
+	server := newTestServer(t)
+	ctx := context.Background()
+	api := newLoggedInClient(t, server, "alice")
+
+	var want []string
+	for i := range 5 {
+		name := fmt.Sprintf("client_%d", i)
+		if _, err := api.CreateClient(ctx, name); err != nil {
+			t.Fatalf("CreateClient: %v", err)
+		}
+		want = append(want, name)
+	}
+
+	var got []string
+	options := client.ClientListOptions{ListOptions: client.ListOptions{Limit: 2, Sort: "client_name"}}
+	for pages := 0; ; pages++ {
+		if pages == len(want) {
+			t.Fatalf("pagination did not end, got %v", got)
+		}
+
+		page, err := api.ListClients(ctx, options)
+		if err != nil {
+			t.Fatalf("ListClients: %v", err)
+		}
+		if len(page.Items) > 2 {
+			t.Fatalf("page of %d items with limit 2", len(page.Items))
+		}
+		for _, item := range page.Items {
+			got = append(got, item.ClientName)
+		}
+
+		if page.NextCursor == "" {
+			break
+		}
+		options.Cursor = page.NextCursor
+	}
+
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("listed %v, want %v", got, want)
+	}
+}
+
+func TestProblemResponses(t *testing.T) {
+	server := newTestServer(t)
+	ctx := context.Background()
+	api := newLoggedInClient(t, server, "alice")
+
+	_, err := api.CreateClient(ctx, "Not A Valid Name")
+	if !client.IsCode(err, client.CodeValidation) {
+		t.Fatalf("CreateClient with an invalid name: got %v, want %s", err, client.CodeValidation)
+	}
+
+	var apiErr *client.Error
+	if !errors.As(fmt.Errorf("wrapped: %w", err), &apiErr) {
+		t.Fatalf("%v is not an *Error", err)
+	}
+	if apiErr.StatusCode != http.StatusBadRequest || apiErr.RequestID == "" {
+		t.Fatalf("got status %d and request ID %q", apiErr.StatusCode, apiErr.RequestID)
+	}
+	if len(apiErr.Errors) != 1 || apiErr.Errors[0].Field != "client_name" {
+		t.Fatalf("got field errors %+v, want one for client_name", apiErr.Errors)
+	}
+	if client.IsCode(err, client.CodeNotFound) {
+		t.Fatal("IsCode matched another code")
+	}
+
+	if _, err := api.GetClient(ctx, 999); !client.IsCode(err, client.CodeNotFound) {
+		t.Fatalf("GetClient of a missing client: got %v, want %s", err, client.CodeNotFound)
+	}
+	if client.IsCode(errors.New("not an API error"), client.CodeNotFound) {
+		t.Fatal("IsCode matched an error that is not an *Error")
+	}
+
+	// Messages are translated, codes are not
+	french := newClient(t, client.Config{BaseURL: server.URL, Username: "alice", Password: testPassword, AcceptLanguage: "fr"})
+	_, err = french.Login(ctx, "alice", "wrong password")
+	if !client.IsCode(err, client.CodeInvalidCredentials) || !errors.As(err, &apiErr) || apiErr.Title == "Invalid credentials" {
+		t.Fatalf("Login in French: got %v", err)
+	}
+}
+
+func TestClientUserRoles(t *testing.T) {
+	server := newTestServer(t)
+	ctx := context.Background()
+	api := newLoggedInClient(t, server, "alice")
+
+	tenant, err := api.CreateClient(ctx, "acme")
+	if err != nil {
+		t.Fatalf("CreateClient: %v", err)
+	}
+	user, err := api.CreateClientUser(ctx, tenant.ID, client.CreateUserRequest{Username: "bob", Email: "bob@example.com", Password: testPassword})
+	if err != nil {
+		t.Fatalf("CreateClientUser: %v", err)
+	}
+
+	editor, err := api.CreateRole(ctx, tenant.ID, "editor", "Can edit articles")
+	if err != nil {
+		t.Fatalf("CreateRole: %v", err)
+	}
+	if _, err := api.CreateRole(ctx, tenant.ID, "viewer", ""); err != nil {
+		t.Fatalf("CreateRole: %v", err)
+	}
+	if _, err := api.CreateRole(ctx, tenant.ID, "editor", ""); !client.IsCode(err, client.CodeConflict) {
+		t.Fatalf("CreateRole of an existing name: got %v, want %s", err, client.CodeConflict)
+	}
+	if _, err := api.CreateRole(ctx, tenant.ID, "two words", ""); !client.IsCode(err, client.CodeValidation) {
+		t.Fatalf("CreateRole with an invalid name: got %v, want %s", err, client.CodeValidation)
+	}
+
+	if _, _, err := api.SetClientUserRoles(ctx, tenant.ID, user.ID, user.Version, []string{"editor", "admin"}); !client.IsCode(err, client.CodeValidation) {
+		t.Fatalf("SetClientUserRoles with an unknown role: got %v, want %s", err, client.CodeValidation)
+	}
+	roles, version, err := api.SetClientUserRoles(ctx, tenant.ID, user.ID, user.Version, []string{"viewer", "editor", "viewer"})
+	if err != nil {
+		t.Fatalf("SetClientUserRoles: %v", err)
+	}
+	if len(roles) != 2 || roles[0].Name != "editor" || roles[1].Name != "viewer" || version != user.Version+1 {
+		t.Fatalf("granted %+v at version %d, want editor and viewer at version %d", roles, version, user.Version+1)
+	}
+	// The roles are versioned with the user
+	if _, _, err := api.SetClientUserRoles(ctx, tenant.ID, user.ID, user.Version, nil); !client.IsCode(err, client.CodePreconditionFailed) {
+		t.Fatalf("SetClientUserRoles with a stale version: got %v, want %s", err, client.CodePreconditionFailed)
+	}
+
+	if got := loginRoles(t, api, tenant.ID); fmt.Sprint(got) != "[editor viewer]" {
+		t.Fatalf("token lists roles %v, want [editor viewer]", got)
+	}
+
+	// Deleting a role takes it from the users it was granted to
+	if err := api.DeleteRole(ctx, tenant.ID, editor.ID, editor.Version+1); !client.IsCode(err, client.CodePreconditionFailed) {
+		t.Fatalf("DeleteRole with a stale version: got %v, want %s", err, client.CodePreconditionFailed)
+	}
+	if err := api.DeleteRole(ctx, tenant.ID, editor.ID, editor.Version); err != nil {
+		t.Fatalf("DeleteRole: %v", err)
+	}
+	if _, err := api.GetRole(ctx, tenant.ID, editor.ID); !client.IsCode(err, client.CodeNotFound) {
+		t.Fatalf("GetRole of a deleted role: got %v, want %s", err, client.CodeNotFound)
+	}
+	roles, current, err := api.ClientUserRoles(ctx, tenant.ID, user.ID)
+	if err != nil {
+		t.Fatalf("ClientUserRoles: %v", err)
+	}
+	if len(roles) != 1 || roles[0].Name != "viewer" || current != version {
+		t.Fatalf("user has roles %+v at version %d after deleting editor, want viewer at version %d", roles, current, version)
+	}
+
+	if _, _, err := api.SetClientUserRoles(ctx, tenant.ID, user.ID, current, nil); err != nil {
+		t.Fatalf("SetClientUserRoles with no roles: %v", err)
+	}
+	if got := loginRoles(t, api, tenant.ID); got != nil {
+		t.Fatalf("token lists roles %v, want none", got)
+	}
+}
+
+// loginRoles logs bob in to the client and returns the roles of the token
+func loginRoles(t *testing.T, api *client.Client, clientID uint) []string {
+	t.Helper()
+
+	session, err := api.ClientUserLogin(context.Background(), clientID, "bob", testPassword)
+	if err != nil {
+		t.Fatalf("ClientUserLogin: %v", err)
+	}
+
+	var claims verifier.Claims
+	_, err = jwt.ParseWithClaims(session.Token, &claims, func(*jwt.Token) (any, error) {
+		return []byte(testSecret), nil
+	})
+	if err != nil {
+		t.Fatalf("parsing token: %v", err)
+	}
+	return claims.Roles
+}
@@ -0,0 +1,99 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+func clientUsersPath(clientID uint) string {
+	return "/clients/" + pathID(clientID) + "/users"
+}
+
+func clientUserPath(clientID, userID uint) string {
+	return clientUsersPath(clientID) + "/" + pathID(userID)
+}
+
+// CreateClientUser adds a user to a client of the logged in user
+func (c *Client) CreateClientUser(ctx context.Context, clientID uint, user CreateUserRequest) (*ClientUser, error) {
+	var created ClientUser
+	_, err := c.call(ctx, request{method: http.MethodPost, path: clientUsersPath(clientID), body: user}, &created)
+	if err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// ListClientUsers returns a page of the users of a client
+func (c *Client) ListClientUsers(ctx context.Context, clientID uint, options ClientUserListOptions) (*Page[ClientUser], error) {
+	var users []ClientUser
+	resp, err := c.call(ctx, request{method: http.MethodGet, path: clientUsersPath(clientID), query: options.values()}, &users)
+	if err != nil {
+		return nil, err
+	}
+	return newPage(users, resp), nil
+}
+
+// GetClientUser returns a user of a client
+func (c *Client) GetClientUser(ctx context.Context, clientID, userID uint) (*ClientUser, error) {
+	var user ClientUser
+	_, err := c.call(ctx, request{method: http.MethodGet, path: clientUserPath(clientID, userID)}, &user)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// DisableClientUser disables a user of a client read at version (zero for
+// any version) and revokes their sessions. Disabled users cannot be enabled
+// again.
+func (c *Client) DisableClientUser(ctx context.Context, clientID, userID, version uint) (*ClientUser, error) {
+	var user ClientUser
+	_, err := c.call(ctx, request{
+		method: http.MethodPatch,
+		path:   clientUserPath(clientID, userID),
+		body:   map[string]bool{"disabled": true},
+		header: ifMatch(version),
+	}, &user)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// SetClientUserPassword sets a new password for a user of a client read at
+// version (zero for any version) and revokes their sessions. Returns the new
+// version of the user.
+func (c *Client) SetClientUserPassword(ctx context.Context, clientID, userID, version uint, newPassword string) (uint, error) {
+	resp, err := c.call(ctx, request{
+		method: http.MethodPut,
+		path:   clientUserPath(clientID, userID) + "/password",
+		body:   map[string]string{"new_password": newPassword},
+		header: ifMatch(version),
+	}, nil)
+	if err != nil {
+		return 0, err
+	}
+	return parseETag(resp.header.Get("ETag")), nil
+}
+
+// RevokeClientUserSessions revokes every session of a user of a client
+func (c *Client) RevokeClientUserSessions(ctx context.Context, clientID, userID uint) error {
+	_, err := c.call(ctx, request{method: http.MethodDelete, path: clientUserPath(clientID, userID) + "/sessions"}, nil)
+	return err
+}
+
+// ClientUserLogin logs a user of a client in and returns their session. It
+// does not change the session of the client.
+func (c *Client) ClientUserLogin(ctx context.Context, clientID uint, username, password string) (*Session, error) {
+	var session Session
+	_, err := c.call(ctx, request{
+		method: http.MethodPost,
+		path:   "/clients/" + pathID(clientID) + "/sessions",
+		body:   map[string]string{"username": username, "password": password},
+		public: true,
+	}, &session)
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
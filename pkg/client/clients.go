@@ -0,0 +1,83 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// CreateClient creates a client of the logged in user. The returned client
+// holds its secret, which later calls do not return.
+func (c *Client) CreateClient(ctx context.Context, name string) (*TenantClient, error) {
+	var client TenantClient
+	_, err := c.call(ctx, request{
+		method: http.MethodPost,
+		path:   "/clients",
+		body:   map[string]string{"client_name": name},
+	}, &client)
+	if err != nil {
+		return nil, err
+	}
+	return &client, nil
+}
+
+// ListClients returns a page of the clients of the logged in user
+func (c *Client) ListClients(ctx context.Context, options ClientListOptions) (*Page[TenantClient], error) {
+	var clients []TenantClient
+	resp, err := c.call(ctx, request{method: http.MethodGet, path: "/clients", query: options.values()}, &clients)
+	if err != nil {
+		return nil, err
+	}
+	return newPage(clients, resp), nil
+}
+
+// GetClient returns a client of the logged in user
+func (c *Client) GetClient(ctx context.Context, clientID uint) (*TenantClient, error) {
+	var client TenantClient
+	_, err := c.call(ctx, request{method: http.MethodGet, path: "/clients/" + pathID(clientID)}, &client)
+	if err != nil {
+		return nil, err
+	}
+	return &client, nil
+}
+
+// RotateClientSecret replaces the secret of a client read at version (zero
+// for any version). The returned client holds the new secret; the old one
+// stops working at once.
+func (c *Client) RotateClientSecret(ctx context.Context, clientID, version uint) (*TenantClient, error) {
+	var client TenantClient
+	_, err := c.call(ctx, request{
+		method: http.MethodPost,
+		path:   "/clients/" + pathID(clientID) + "/secret",
+		header: ifMatch(version),
+	}, &client)
+	if err != nil {
+		return nil, err
+	}
+	return &client, nil
+}
+
+// GetClientConfig returns the settings of a client and their version, zero
+// for a client that was never configured
+func (c *Client) GetClientConfig(ctx context.Context, clientID uint) (*ClientSettings, uint, error) {
+	var settings ClientSettings
+	resp, err := c.call(ctx, request{method: http.MethodGet, path: "/clients/" + pathID(clientID) + "/config"}, &settings)
+	if err != nil {
+		return nil, 0, err
+	}
+	return &settings, parseETag(resp.header.Get("ETag")), nil
+}
+
+// UpdateClientConfig replaces the settings of a client read at version (zero
+// for any version). Returns the new version of the settings.
+func (c *Client) UpdateClientConfig(ctx context.Context, clientID, version uint, settings ClientSettings) (uint, error) {
+	resp, err := c.call(ctx, request{
+		method: http.MethodPut,
+		path:   "/clients/" + pathID(clientID) + "/config",
+		body:   settings,
+		header: ifMatch(version),
+	}, nil)
+	if err != nil {
+		return 0, err
+	}
+	return parseETag(resp.header.Get("ETag")), nil
+}
@@ -0,0 +1,82 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrorCode is the stable, machine-readable code of an API error
+type ErrorCode string
+
+// Error codes returned by the API; /api/problems lists them with their
+// HTTP status
+const (
+	CodeValidation            ErrorCode = "validation_error"
+	CodeMalformedRequest      ErrorCode = "malformed_request"
+	CodePasswordPolicy        ErrorCode = "password_policy_violation"
+	CodeUnauthorized          ErrorCode = "unauthorized"
+	CodeMissingToken          ErrorCode = "missing_token"
+	CodeInvalidToken          ErrorCode = "invalid_token"
+	CodeTokenExpired          ErrorCode = "token_expired"
+	CodeSessionRevoked        ErrorCode = "session_revoked"
+	CodeInvalidCSRFToken      ErrorCode = "invalid_csrf_token"
+	CodeInvalidCredentials    ErrorCode = "invalid_credentials"
+	CodeUserDisabled          ErrorCode = "user_disabled"
+	CodeClientSuspended       ErrorCode = "client_suspended"
+	CodeNotFound              ErrorCode = "not_found"
+	CodeMethodNotAllowed      ErrorCode = "method_not_allowed"
+	CodeConflict              ErrorCode = "conflict"
+	CodeAlreadyExists         ErrorCode = "already_exists"
+	CodePreconditionFailed    ErrorCode = "precondition_failed"
+	CodePreconditionRequired  ErrorCode = "precondition_required"
+	CodeIdempotencyKeyReused  ErrorCode = "idempotency_key_reused"
+	CodeIdempotencyInProgress ErrorCode = "idempotency_request_in_progress"
+	CodeRateLimited           ErrorCode = "rate_limited"
+	CodeInternal              ErrorCode = "internal_error"
+)
+
+// ErrNotAuthenticated is returned by calls needing a token when the client
+// has neither a token nor credentials to log in with
+var ErrNotAuthenticated = errors.New("simplejwt: not authenticated, call Login or configure credentials")
+
+// Error is an error response of the API, decoded from its problem details
+// (RFC 7807)
+type Error struct {
+	// StatusCode is the HTTP status of the response
+	StatusCode int       `json:"status"`
+	Type       string    `json:"type"`
+	Title      string    `json:"title"`
+	Detail     string    `json:"detail"`
+	Instance   string    `json:"instance"`
+	Code       ErrorCode `json:"code"`
+	// Errors lists the invalid fields or failed rules, if any
+	Errors []FieldError `json:"errors"`
+	// RequestID matches the server logs of the request
+	RequestID string `json:"request_id"`
+}
+
+// FieldError is one reason a request was rejected
+type FieldError struct {
+	// Field is the JSON name of the invalid field, empty when the error
+	// concerns the request as a whole
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	message := e.Detail
+	if message == "" {
+		message = e.Title
+	}
+	if e.Code == "" {
+		return fmt.Sprintf("simplejwt: %d: %s", e.StatusCode, message)
+	}
+	return fmt.Sprintf("simplejwt: %d %s: %s", e.StatusCode, e.Code, message)
+}
+
+// IsCode reports whether err is an API error with the given code
+func IsCode(err error, code ErrorCode) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.Code == code
+}
@@ -0,0 +1,88 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+func rolesPath(clientID uint) string {
+	return "/clients/" + pathID(clientID) + "/roles"
+}
+
+// ListRoles returns the roles of a client by name
+func (c *Client) ListRoles(ctx context.Context, clientID uint) ([]Role, error) {
+	var roles []Role
+	_, err := c.call(ctx, request{method: http.MethodGet, path: rolesPath(clientID)}, &roles)
+	if err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// CreateRole adds a role to a client of the logged in user
+func (c *Client) CreateRole(ctx context.Context, clientID uint, name, description string) (*Role, error) {
+	var role Role
+	_, err := c.call(ctx, request{
+		method: http.MethodPost,
+		path:   rolesPath(clientID),
+		body:   map[string]string{"name": name, "description": description},
+	}, &role)
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// GetRole returns a role of a client
+func (c *Client) GetRole(ctx context.Context, clientID, roleID uint) (*Role, error) {
+	var role Role
+	_, err := c.call(ctx, request{method: http.MethodGet, path: rolesPath(clientID) + "/" + pathID(roleID)}, &role)
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// DeleteRole deletes a role of a client read at version (zero for any
+// version); the users it was granted to lose it
+func (c *Client) DeleteRole(ctx context.Context, clientID, roleID, version uint) error {
+	_, err := c.call(ctx, request{
+		method: http.MethodDelete,
+		path:   rolesPath(clientID) + "/" + pathID(roleID),
+		header: ifMatch(version),
+	}, nil)
+	return err
+}
+
+// ClientUserRoles returns the roles granted to a user of a client and the
+// version of the user, which changes with their roles
+func (c *Client) ClientUserRoles(ctx context.Context, clientID, userID uint) ([]Role, uint, error) {
+	var roles []Role
+	resp, err := c.call(ctx, request{method: http.MethodGet, path: clientUserPath(clientID, userID) + "/roles"}, &roles)
+	if err != nil {
+		return nil, 0, err
+	}
+	return roles, parseETag(resp.header.Get("ETag")), nil
+}
+
+// SetClientUserRoles grants a user of a client read at version (zero for any
+// version) exactly the named roles. Returns the roles and the new version of
+// the user. The user's tokens list the new roles from their next login or
+// refresh.
+func (c *Client) SetClientUserRoles(ctx context.Context, clientID, userID, version uint, names []string) ([]Role, uint, error) {
+	if names == nil {
+		names = []string{}
+	}
+
+	var roles []Role
+	resp, err := c.call(ctx, request{
+		method: http.MethodPut,
+		path:   clientUserPath(clientID, userID) + "/roles",
+		body:   map[string][]string{"roles": names},
+		header: ifMatch(version),
+	}, &roles)
+	if err != nil {
+		return nil, 0, err
+	}
+	return roles, parseETag(resp.header.Get("ETag")), nil
+}
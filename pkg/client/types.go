@@ -0,0 +1,196 @@
+package client
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// User is an admin user, the owner of clients
+type User struct {
+	ID       uint   `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	// Version is the version the user was read at; pass it to the calls
+	// changing the user
+	Version uint `json:"-"`
+}
+
+// CreateUserRequest holds the account details of a new user
+type CreateUserRequest struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// Session is the result of a login
+type Session struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	SessionID string    `json:"sessionId"`
+	User      User      `json:"user"`
+}
+
+// TenantClient is a client of an admin user: an application with its own
+// users, stored in its own schema
+type TenantClient struct {
+	ID         uint   `json:"id"`
+	ClientName string `json:"client_name"`
+	// ClientSecret is returned when the client is created and when its
+	// secret is rotated
+	ClientSecret string     `json:"client_secret"`
+	UserID       uint       `json:"user_id"`
+	SchemaName   string     `json:"schema_name"`
+	SuspendedAt  *time.Time `json:"suspended_at"`
+	Version      uint       `json:"version"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// IsSuspended reports whether the users of the client are barred from logging in
+func (c *TenantClient) IsSuspended() bool {
+	return c.SuspendedAt != nil
+}
+
+// ClientSettings is the configuration of a client
+type ClientSettings struct {
+	// PasswordPolicy overrides the global password policy for the users of
+	// the client; nil keeps the global policy
+	PasswordPolicy *PasswordPolicyOverrides `json:"password_policy,omitempty"`
+	// DefaultLocale is the language of error messages for requests whose
+	// Accept-Language header names no supported language, e.g. "fr"
+	DefaultLocale string `json:"default_locale,omitempty"`
+}
+
+// PasswordPolicyOverrides holds the rules of a client's password policy;
+// nil fields keep their global value
+type PasswordPolicyOverrides struct {
+	MinLength        *int  `json:"min_length,omitempty"`
+	MaxLength        *int  `json:"max_length,omitempty"`
+	RequireUppercase *bool `json:"require_uppercase,omitempty"`
+	RequireLowercase *bool `json:"require_lowercase,omitempty"`
+	RequireDigit     *bool `json:"require_digit,omitempty"`
+	RequireSymbol    *bool `json:"require_symbol,omitempty"`
+	MaxRepeatedChars *int  `json:"max_repeated_chars,omitempty"`
+	DisallowUserInfo *bool `json:"disallow_user_info,omitempty"`
+	HistorySize      *int  `json:"history_size,omitempty"`
+	CheckBreached    *bool `json:"check_breached,omitempty"`
+}
+
+// ClientUser is a user of a client
+type ClientUser struct {
+	ID         uint       `json:"id"`
+	Username   string     `json:"username"`
+	Email      string     `json:"email"`
+	DisabledAt *time.Time `json:"disabled_at"`
+	Version    uint       `json:"version"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// IsDisabled reports whether the user can no longer log in
+func (u *ClientUser) IsDisabled() bool {
+	return u.DisabledAt != nil
+}
+
+// Role is a role a client defines for its users. The roles of a client user
+// are listed in the roles claim of their tokens.
+type Role struct {
+	ID          uint      `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Version     uint      `json:"version"`
+}
+
+// Page is one page of a list
+type Page[T any] struct {
+	Items []T
+	// Page is the page number, zero when the page was fetched by cursor
+	Page       int
+	Limit      int
+	Total      int
+	TotalPages int
+	// NextCursor fetches the following page; empty on the last page
+	NextCursor string
+}
+
+// pagination is the pagination object of list responses
+type pagination struct {
+	Page       int    `json:"page"`
+	Limit      int    `json:"limit"`
+	Total      int    `json:"total"`
+	TotalPages int    `json:"total_pages"`
+	NextCursor string `json:"next_cursor"`
+}
+
+// ListOptions page and order a list. A list is paged either by number or by
+// the cursor of the previous page, which takes precedence over Page. Zero
+// values use the server defaults.
+type ListOptions struct {
+	Page   int
+	Limit  int
+	Cursor string
+	// Sort is a sortable field of the list, prefixed with - for descending order
+	Sort string
+}
+
+func (o ListOptions) values() url.Values {
+	values := url.Values{}
+	if o.Page > 0 {
+		values.Set("page", strconv.Itoa(o.Page))
+	}
+	if o.Limit > 0 {
+		values.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Cursor != "" {
+		values.Set("cursor", o.Cursor)
+	}
+	if o.Sort != "" {
+		values.Set("sort", o.Sort)
+	}
+	return values
+}
+
+// ClientListOptions filter a list of clients
+type ClientListOptions struct {
+	ListOptions
+	// Name only keeps clients whose name contains it, ignoring case
+	Name      string
+	Suspended *bool
+}
+
+func (o ClientListOptions) values() url.Values {
+	values := o.ListOptions.values()
+	if o.Name != "" {
+		values.Set("name", o.Name)
+	}
+	if o.Suspended != nil {
+		values.Set("suspended", strconv.FormatBool(*o.Suspended))
+	}
+	return values
+}
+
+// ClientUserListOptions filter a list of client users
+type ClientUserListOptions struct {
+	ListOptions
+	// Username and Email only keep users whose value contains them, ignoring case
+	Username string
+	Email    string
+	Disabled *bool
+}
+
+func (o ClientUserListOptions) values() url.Values {
+	values := o.ListOptions.values()
+	if o.Username != "" {
+		values.Set("username", o.Username)
+	}
+	if o.Email != "" {
+		values.Set("email", o.Email)
+	}
+	if o.Disabled != nil {
+		values.Set("disabled", strconv.FormatBool(*o.Disabled))
+	}
+	return values
+}
@@ -0,0 +1,57 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// CreateUser signs up a new admin user. It does not log the client in.
+func (c *Client) CreateUser(ctx context.Context, user CreateUserRequest) (*User, error) {
+	var created struct {
+		UserID   uint   `json:"userId"`
+		Username string `json:"username"`
+		Email    string `json:"email"`
+	}
+	_, err := c.call(ctx, request{method: http.MethodPost, path: "/users", body: user, public: true}, &created)
+	if err != nil {
+		return nil, err
+	}
+
+	return &User{ID: created.UserID, Username: created.Username, Email: created.Email, Version: 1}, nil
+}
+
+// CurrentUser returns the admin user the client is logged in as
+func (c *Client) CurrentUser(ctx context.Context) (*User, error) {
+	var user User
+	resp, err := c.call(ctx, request{method: http.MethodGet, path: "/users/me"}, &user)
+	if err != nil {
+		return nil, err
+	}
+
+	user.Version = parseETag(resp.header.Get("ETag"))
+	return &user, nil
+}
+
+// ChangePassword changes the password of the admin user the client is
+// logged in as, from the user read at version (zero for any version). The
+// client logs in with the new password from then on. Returns the new
+// version of the user.
+func (c *Client) ChangePassword(ctx context.Context, version uint, currentPassword, newPassword string) (uint, error) {
+	resp, err := c.call(ctx, request{
+		method: http.MethodPut,
+		path:   "/users/me/password",
+		body:   map[string]string{"current_password": currentPassword, "new_password": newPassword},
+		header: ifMatch(version),
+	}, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	c.mu.Lock()
+	if c.username != "" {
+		c.password = newPassword
+	}
+	c.mu.Unlock()
+
+	return parseETag(resp.header.Get("ETag")), nil
+}
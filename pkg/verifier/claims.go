@@ -0,0 +1,47 @@
+package verifier
+
+import (
+	"context"
+	"slices"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Claims are the claims of a SimpleJWT token
+type Claims struct {
+	// UserID is the admin user, or the client user when ClientID is set
+	UserID uint `json:"user_id"`
+	// ClientID is the client of a client user, zero for admin users
+	ClientID uint `json:"client_id,omitempty"`
+	// SessionID is the session the token was issued for
+	SessionID string `json:"sid"`
+	// Roles are the roles of the client the client user was granted when
+	// the token was issued
+	Roles []string `json:"roles,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// IsClientUser reports whether the token belongs to a user of a client
+// rather than an admin user
+func (c *Claims) IsClientUser() bool {
+	return c.ClientID != 0
+}
+
+// HasRole reports whether the token grants the role
+func (c *Claims) HasRole(role string) bool {
+	return slices.Contains(c.Roles, role)
+}
+
+type claimsContextKey struct{}
+
+// NewContext returns a copy of ctx carrying the claims of a request
+func NewContext(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, claimsContextKey{}, claims)
+}
+
+// ClaimsFromContext returns the claims the middleware stored in the context
+// of a request
+func ClaimsFromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(claimsContextKey{}).(*Claims)
+	return claims, ok
+}
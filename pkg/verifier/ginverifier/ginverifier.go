@@ -0,0 +1,45 @@
+// Package ginverifier adapts the token verifier to gin:
+//
+//	router.Use(ginverifier.Middleware(v))
+//	router.GET("/orders", func(c *gin.Context) {
+//		claims, _ := ginverifier.Claims(c)
+//		...
+//	})
+package ginverifier
+
+import (
+	"github.com/Kantha2004/SimpleJWT/pkg/verifier"
+	"github.com/gin-gonic/gin"
+)
+
+// ClaimsKey is the gin context key holding the claims of a request
+const ClaimsKey = "simplejwt.claims"
+
+// Middleware verifies the bearer token of every request. The claims are
+// stored in the gin context and the request context, so handlers and code
+// given only the request context can read them. Requests without a valid
+// token are aborted with the error handler of the verifier.
+func Middleware(v *verifier.Verifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, err := v.VerifyRequest(c.Request)
+		if err != nil {
+			v.WriteError(c.Writer, c.Request, err)
+			c.Abort()
+			return
+		}
+
+		c.Set(ClaimsKey, claims)
+		c.Request = c.Request.WithContext(verifier.NewContext(c.Request.Context(), claims))
+		c.Next()
+	}
+}
+
+// Claims returns the claims the middleware stored for a request
+func Claims(c *gin.Context) (*verifier.Claims, bool) {
+	claims, ok := c.Get(ClaimsKey)
+	if !ok {
+		return nil, false
+	}
+	typed, ok := claims.(*verifier.Claims)
+	return typed, ok
+}
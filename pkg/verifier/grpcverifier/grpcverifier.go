@@ -0,0 +1,192 @@
+// Package grpcverifier authenticates the calls of gRPC servers with SimpleJWT
+// tokens, sent as "authorization: Bearer <token>" metadata:
+//
+//	server := grpc.NewServer(
+//		grpc.ChainUnaryInterceptor(grpcverifier.UnaryServerInterceptor(v)),
+//		grpc.ChainStreamInterceptor(grpcverifier.StreamServerInterceptor(v)),
+//	)
+//
+// Handlers read the claims of a call with verifier.ClaimsFromContext. A
+// *verifier.Verifier checks tokens offline; a RemoteVerifier asks the
+// SimpleJWT gRPC API, which also rejects tokens of revoked sessions.
+package grpcverifier
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/Kantha2004/SimpleJWT/pkg/authv1"
+	"github.com/Kantha2004/SimpleJWT/pkg/verifier"
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// errorDomain is the domain of the ErrorInfo details of rejected calls
+const errorDomain = "simplejwt"
+
+// TokenVerifier verifies a token and returns its claims. *verifier.Verifier
+// and *RemoteVerifier implement it.
+type TokenVerifier interface {
+	Verify(ctx context.Context, token string) (*verifier.Claims, error)
+}
+
+// UnaryServerInterceptor verifies the token of every unary call, except of
+// the public methods, given by full name, e.g.
+// "/grpc.health.v1.Health/Check"
+func UnaryServerInterceptor(v TokenVerifier, public ...string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if slices.Contains(public, info.FullMethod) {
+			return handler(ctx, req)
+		}
+		ctx, err := authenticate(ctx, v)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor verifies the token of every stream, except of the
+// public methods
+func StreamServerInterceptor(v TokenVerifier, public ...string) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if slices.Contains(public, info.FullMethod) {
+			return handler(srv, stream)
+		}
+		ctx, err := authenticate(stream.Context(), v)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+// authenticatedStream carries the claims of a stream in its context
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+// authenticate verifies the token of a call and returns its context with the
+// claims
+func authenticate(ctx context.Context, v TokenVerifier) (context.Context, error) {
+	token, err := bearerToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := v.Verify(ctx, token)
+	if err != nil {
+		return nil, rejection(err)
+	}
+	return verifier.NewContext(ctx, claims), nil
+}
+
+// bearerToken returns the token of the "authorization" metadata
+func bearerToken(ctx context.Context) (string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return "", unauthenticated(verifier.CodeMissingToken, "Authorization metadata required")
+	}
+	scheme, token, found := strings.Cut(values[0], " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", unauthenticated(verifier.CodeMissingToken, "Authorization metadata must hold a Bearer token")
+	}
+	return strings.TrimSpace(token), nil
+}
+
+// rejection is the status of a call whose token was not verified
+func rejection(err error) error {
+	var rejected *RejectedError
+	switch {
+	case errors.As(err, &rejected):
+		return unauthenticated(rejected.Code, "Token was rejected: "+rejected.Code)
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return unauthenticated(verifier.CodeTokenExpired, "Token has expired")
+	case errors.Is(err, verifier.ErrWrongTenant):
+		return unauthenticated(verifier.CodeInvalidToken, "Token cannot access this resource")
+	case status.Code(err) != codes.Unknown:
+		// The SimpleJWT server could not be asked
+		return status.Error(codes.Unavailable, "Token could not be verified")
+	default:
+		return unauthenticated(verifier.CodeInvalidToken, "Token is invalid")
+	}
+}
+
+// unauthenticated builds an Unauthenticated status carrying the error code
+// in an ErrorInfo detail
+func unauthenticated(code, message string) error {
+	st := status.New(codes.Unauthenticated, message)
+	withInfo, err := st.WithDetails(&errdetails.ErrorInfo{Reason: code, Domain: errorDomain})
+	if err != nil {
+		return st.Err()
+	}
+	return withInfo.Err()
+}
+
+// RejectedError is returned by a RemoteVerifier for tokens the server
+// rejected
+type RejectedError struct {
+	// Code tells why, e.g. "token_expired" or "session_revoked"
+	Code string
+}
+
+func (e *RejectedError) Error() string {
+	return "grpcverifier: token rejected: " + e.Code
+}
+
+// RemoteVerifier verifies tokens with the VerifyToken call of the SimpleJWT
+// gRPC API. Unlike an offline verifier it rejects tokens of revoked
+// sessions, at the cost of a call per token.
+type RemoteVerifier struct {
+	client authv1.AuthServiceClient
+	// clientID restricts the verifier to the users of one client
+	clientID uint
+}
+
+// NewRemoteVerifier verifies tokens with the server client is connected to.
+// A non-zero clientID only accepts the users of that client.
+func NewRemoteVerifier(client authv1.AuthServiceClient, clientID uint) *RemoteVerifier {
+	return &RemoteVerifier{client: client, clientID: clientID}
+}
+
+func (r *RemoteVerifier) Verify(ctx context.Context, token string) (*verifier.Claims, error) {
+	resp, err := r.client.VerifyToken(ctx, &authv1.VerifyTokenRequest{Token: token})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.GetValid() {
+		return nil, &RejectedError{Code: resp.GetErrorCode()}
+	}
+
+	claims := resp.GetClaims()
+	if r.clientID != 0 && uint(claims.GetClientId()) != r.clientID {
+		return nil, verifier.ErrWrongTenant
+	}
+
+	result := &verifier.Claims{
+		UserID:    uint(claims.GetUserId()),
+		ClientID:  uint(claims.GetClientId()),
+		SessionID: claims.GetSessionId(),
+	}
+	result.Issuer = claims.GetIssuer()
+	result.Audience = claims.GetAudience()
+	if claims.GetIssuedAt() != nil {
+		result.IssuedAt = jwt.NewNumericDate(claims.GetIssuedAt().AsTime())
+	}
+	if claims.GetExpiresAt() != nil {
+		result.ExpiresAt = jwt.NewNumericDate(claims.GetExpiresAt().AsTime())
+	}
+	return result, nil
+}
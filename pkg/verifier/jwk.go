@@ -0,0 +1,110 @@
+package verifier
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// JSONWebKey is the public half of a signing key (RFC 7517). Only the ES256
+// (P-256) and RS256 keys SimpleJWT signs with are supported.
+type JSONWebKey struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg,omitempty"`
+	Use       string `json:"use,omitempty"`
+	// Curve and the X and Y coordinates describe EC keys
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+	Y     string `json:"y,omitempty"`
+	// N and E are the modulus and exponent of RSA keys
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+}
+
+// JSONWebKeySet is the document served at the JWKS URL
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// p256CoordinateSize is the length of a P-256 coordinate in bytes
+const p256CoordinateSize = 32
+
+// NewJSONWebKey describes the public key of a signing key
+func NewJSONWebKey(keyID, algorithm string, key crypto.PublicKey) (JSONWebKey, error) {
+	jwk := JSONWebKey{KeyID: keyID, Algorithm: algorithm, Use: "sig"}
+
+	switch key := key.(type) {
+	case *ecdsa.PublicKey:
+		if key.Curve != elliptic.P256() {
+			return JSONWebKey{}, errors.New("only P-256 ECDSA keys are supported")
+		}
+		// The uncompressed point is 0x04 followed by both coordinates
+		point, err := key.Bytes()
+		if err != nil {
+			return JSONWebKey{}, err
+		}
+		jwk.KeyType = "EC"
+		jwk.Curve = "P-256"
+		jwk.X = base64.RawURLEncoding.EncodeToString(point[1 : 1+p256CoordinateSize])
+		jwk.Y = base64.RawURLEncoding.EncodeToString(point[1+p256CoordinateSize:])
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(key.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+	default:
+		return JSONWebKey{}, fmt.Errorf("unsupported key type %T", key)
+	}
+
+	return jwk, nil
+}
+
+// PublicKey decodes the key into an *ecdsa.PublicKey or *rsa.PublicKey
+func (k JSONWebKey) PublicKey() (crypto.PublicKey, error) {
+	switch k.KeyType {
+	case "EC":
+		if k.Curve != "P-256" {
+			return nil, fmt.Errorf("key %s: unsupported curve %q", k.KeyID, k.Curve)
+		}
+		x, errX := decodeCoordinate(k.X)
+		y, errY := decodeCoordinate(k.Y)
+		if errX != nil || errY != nil {
+			return nil, fmt.Errorf("key %s: invalid coordinates", k.KeyID)
+		}
+		point := append(append([]byte{4}, x...), y...)
+		key, err := ecdsa.ParseUncompressedPublicKey(elliptic.P256(), point)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", k.KeyID, err)
+		}
+		return key, nil
+	case "RSA":
+		n, errN := base64.RawURLEncoding.DecodeString(k.N)
+		e, errE := base64.RawURLEncoding.DecodeString(k.E)
+		if errN != nil || errE != nil || len(n) == 0 || len(e) == 0 || len(e) > 4 {
+			return nil, fmt.Errorf("key %s: invalid modulus or exponent", k.KeyID)
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	default:
+		return nil, fmt.Errorf("key %s: unsupported key type %q", k.KeyID, k.KeyType)
+	}
+}
+
+// decodeCoordinate decodes a P-256 coordinate, restoring leading zeros some
+// encoders drop
+func decodeCoordinate(encoded string) ([]byte, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > p256CoordinateSize {
+		return nil, errors.New("coordinate too long")
+	}
+	padded := make([]byte, p256CoordinateSize)
+	copy(padded[p256CoordinateSize-len(data):], data)
+	return padded, nil
+}
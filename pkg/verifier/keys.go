@@ -0,0 +1,177 @@
+package verifier
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// maxKeySetLength bounds how much of a JWKS response is read
+const maxKeySetLength = 1 << 20
+
+// unknownKeyFetchTimeout bounds a fetch for a token of an unknown kid, which
+// the requests verifying tokens wait on
+const unknownKeyFetchTimeout = 5 * time.Second
+
+// verificationKey is a decoded key of the key set
+type verificationKey struct {
+	key       crypto.PublicKey
+	algorithm string
+}
+
+// keyCache holds the key set of a JWKS URL. It is refreshed periodically,
+// and early when a token names a kid it does not know, e.g. right after a
+// key rotation.
+type keyCache struct {
+	url                string
+	http               *http.Client
+	refreshInterval    time.Duration
+	minRefreshInterval time.Duration
+	logger             *slog.Logger
+
+	mu   sync.RWMutex
+	keys map[string]verificationKey
+	// fetchedAt is when the key set was last fetched. Failed fetches leave
+	// it, so the next token of an unknown kid tries again.
+	fetchedAt time.Time
+
+	// fetchMu serializes fetches so a burst of tokens of a new kid causes a
+	// single one
+	fetchMu sync.Mutex
+}
+
+func newKeyCache(config Config) *keyCache {
+	return &keyCache{
+		url:                config.JWKSURL,
+		http:               config.HTTPClient,
+		refreshInterval:    config.RefreshInterval,
+		minRefreshInterval: config.MinRefreshInterval,
+		logger:             config.Logger,
+	}
+}
+
+// get returns the key of a kid, fetching the key set again when the kid is
+// unknown and the last fetch is old enough
+func (k *keyCache) get(ctx context.Context, kid string) (verificationKey, error) {
+	if key, ok := k.lookup(kid); ok {
+		return key, nil
+	}
+
+	k.fetchMu.Lock()
+	defer k.fetchMu.Unlock()
+
+	// Another request may have fetched the key while this one waited
+	if key, ok := k.lookup(kid); ok {
+		return key, nil
+	}
+
+	k.mu.RLock()
+	recent := time.Since(k.fetchedAt) < k.minRefreshInterval
+	k.mu.RUnlock()
+	if recent {
+		return verificationKey{}, ErrUnknownKey
+	}
+
+	// The fetch serves every request waiting for fetchMu, so it must not be
+	// canceled with the request that started it
+	fetchCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), unknownKeyFetchTimeout)
+	defer cancel()
+	if err := k.fetch(fetchCtx); err != nil {
+		k.logger.WarnContext(ctx, "Failed to fetch the JWKS for an unknown kid", "url", k.url, "kid", kid, "error", err)
+		return verificationKey{}, ErrUnknownKey
+	}
+
+	if key, ok := k.lookup(kid); ok {
+		return key, nil
+	}
+	return verificationKey{}, ErrUnknownKey
+}
+
+func (k *keyCache) lookup(kid string) (verificationKey, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	key, ok := k.keys[kid]
+	return key, ok
+}
+
+// run refreshes the key set every refresh interval until ctx is done. A
+// failed refresh keeps the keys fetched before.
+func (k *keyCache) run(ctx context.Context) {
+	ticker := time.NewTicker(k.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := k.refresh(ctx); err != nil && ctx.Err() == nil {
+				k.logger.WarnContext(ctx, "Failed to refresh the JWKS, keeping the previous keys", "url", k.url, "error", err)
+			}
+		}
+	}
+}
+
+// refresh fetches the key set, after any fetch in progress
+func (k *keyCache) refresh(ctx context.Context) error {
+	k.fetchMu.Lock()
+	defer k.fetchMu.Unlock()
+	return k.fetch(ctx)
+}
+
+// fetch downloads and decodes the key set and replaces the cached keys.
+// fetchMu must be held.
+func (k *keyCache) fetch(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, k.url, nil)
+	if err != nil {
+		return fmt.Errorf("verifier: failed to build JWKS request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := k.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("verifier: failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("verifier: failed to fetch JWKS: %s", resp.Status)
+	}
+
+	var set JSONWebKeySet
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxKeySetLength)).Decode(&set); err != nil {
+		return fmt.Errorf("verifier: failed to decode JWKS: %w", err)
+	}
+
+	keys := make(map[string]verificationKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		// Keys for other uses, or of types SimpleJWT does not sign with,
+		// cannot verify its tokens
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.PublicKey()
+		if err != nil {
+			k.logger.WarnContext(ctx, "Skipping unusable JWKS key", "url", k.url, "kid", jwk.KeyID, "error", err)
+			continue
+		}
+		algorithm := jwk.Algorithm
+		if algorithm == "" {
+			algorithm = algorithmOf(key)
+		}
+		keys[jwk.KeyID] = verificationKey{key: key, algorithm: algorithm}
+	}
+
+	k.mu.Lock()
+	k.keys = keys
+	k.fetchedAt = time.Now()
+	k.mu.Unlock()
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package verifier
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Error codes of the problems written for rejected requests; they match the
+// codes of the SimpleJWT API
+const (
+	CodeMissingToken = "missing_token"
+	CodeInvalidToken = "invalid_token"
+	CodeTokenExpired = "token_expired"
+)
+
+// problem is the body of rejected requests (RFC 9457)
+type problem struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	Code   string `json:"code"`
+}
+
+// Middleware verifies the bearer token of every request and stores its claims
+// in the request context. Requests without a valid token are rejected with
+// the error handler.
+func (v *Verifier) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, err := v.VerifyRequest(r)
+		if err != nil {
+			v.WriteError(w, r, err)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), claims)))
+	})
+}
+
+// VerifyRequest verifies the bearer token of the Authorization header of a
+// request
+func (v *Verifier) VerifyRequest(r *http.Request) (*Claims, error) {
+	token, err := BearerToken(r)
+	if err != nil {
+		return nil, err
+	}
+	return v.Verify(r.Context(), token)
+}
+
+// WriteError rejects a request with the error handler of the verifier
+func (v *Verifier) WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	v.config.ErrorHandler(w, r, err)
+}
+
+// BearerToken returns the token of the Authorization header of a request
+func BearerToken(r *http.Request) (string, error) {
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		return "", ErrMissingToken
+	}
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", ErrMissingToken
+	}
+	return strings.TrimSpace(token), nil
+}
+
+// writeProblem is the default error handler: a 401 problem with a
+// WWW-Authenticate challenge (RFC 6750)
+func writeProblem(w http.ResponseWriter, r *http.Request, err error) {
+	body := problem{Type: "about:blank", Title: http.StatusText(http.StatusUnauthorized), Status: http.StatusUnauthorized}
+
+	switch {
+	case errors.Is(err, ErrMissingToken):
+		body.Code = CodeMissingToken
+		body.Detail = "Authorization header must hold a Bearer token"
+		w.Header().Set("WWW-Authenticate", "Bearer")
+	case errors.Is(err, jwt.ErrTokenExpired):
+		body.Code = CodeTokenExpired
+		body.Detail = "Token has expired"
+		w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token", error_description="token has expired"`)
+	case errors.Is(err, ErrWrongTenant):
+		body.Code = CodeInvalidToken
+		body.Detail = "Token cannot access this resource"
+		w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+	default:
+		body.Code = CodeInvalidToken
+		body.Detail = "Token is invalid"
+		w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+	}
+
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(body.Status)
+	_ = json.NewEncoder(w).Encode(body)
+}
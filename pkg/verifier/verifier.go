@@ -0,0 +1,231 @@
+// Package verifier verifies SimpleJWT tokens in resource servers, the
+// services that accept the tokens SimpleJWT issues.
+//
+// A verifier fetches the public keys of the server from its JWKS endpoint
+// and keeps them fresh, or uses a key configured up front:
+//
+//	v, err := verifier.New(ctx, verifier.Config{
+//		JWKSURL:  "https://auth.example.com/.well-known/jwks.json",
+//		Issuer:   "simplejwt",
+//		ClientID: 42,
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	http.Handle("/orders", v.Middleware(orders))
+//
+// Handlers read the claims of the request with ClaimsFromContext. The gin
+// and gRPC adapters live in the ginverifier and grpcverifier subpackages.
+//
+// Tokens are checked offline: a revoked session keeps its token valid here
+// until the token expires.
+package verifier
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	defaultTimeout            = 10 * time.Second
+	defaultRefreshInterval    = time.Hour
+	defaultMinRefreshInterval = time.Minute
+)
+
+var (
+	// ErrMissingToken is returned when a request carries no bearer token
+	ErrMissingToken = errors.New("verifier: missing bearer token")
+	// ErrUnknownKey is returned for tokens whose "kid" names no known key,
+	// even after fetching the key set again
+	ErrUnknownKey = errors.New("verifier: unknown signing key")
+	// ErrWrongTenant is returned for tokens of another client than the one
+	// the verifier is configured for, including admin user tokens
+	ErrWrongTenant = errors.New("verifier: token belongs to another tenant")
+)
+
+type Config struct {
+	// JWKSURL is the JWKS endpoint of the server, e.g.
+	// https://auth.example.com/.well-known/jwks.json. Exactly one of
+	// JWKSURL, PublicKey and Secret must be set.
+	JWKSURL string
+	// PublicKey is the *ecdsa.PublicKey or *rsa.PublicKey tokens are signed
+	// for, whatever their kid
+	PublicKey crypto.PublicKey
+	// Secret is the HS256 secret of servers without signing keys
+	Secret []byte
+
+	// HTTPClient fetches the key set; defaults to a client with a 10s timeout
+	HTTPClient *http.Client
+	// RefreshInterval is how often the key set is fetched in the background;
+	// defaults to one hour
+	RefreshInterval time.Duration
+	// MinRefreshInterval is the least time between two fetches caused by
+	// tokens of an unknown kid, so forged tokens cannot flood the server;
+	// defaults to one minute
+	MinRefreshInterval time.Duration
+
+	// Issuer is the expected "iss" claim (jwt.issuer of the server); empty
+	// accepts any issuer
+	Issuer string
+	// Audience is the expected "aud" claim (jwt.audience of the server);
+	// empty accepts any audience
+	Audience string
+	// ClientID restricts the verifier to the users of one client. Zero
+	// accepts admin users and the users of every client.
+	ClientID uint
+	// Leeway is the clock skew tolerated when checking expiry
+	Leeway time.Duration
+
+	// ErrorHandler writes the response of rejected requests; defaults to
+	// a 401 problem (RFC 9457)
+	ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+	// Logger logs failed key set fetches; defaults to slog.Default()
+	Logger *slog.Logger
+}
+
+// Verifier verifies tokens. It is safe for concurrent use.
+type Verifier struct {
+	config Config
+	parser *jwt.Parser
+	// keys is nil for verifiers of a static key or secret
+	keys *keyCache
+}
+
+// New builds a verifier. With a JWKS URL it fetches the key set before
+// returning, and refreshes it in the background until ctx is done.
+func New(ctx context.Context, config Config) (*Verifier, error) {
+	configured := 0
+	for _, set := range []bool{config.JWKSURL != "", config.PublicKey != nil, len(config.Secret) > 0} {
+		if set {
+			configured++
+		}
+	}
+	if configured != 1 {
+		return nil, errors.New("verifier: exactly one of JWKSURL, PublicKey and Secret must be set")
+	}
+
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: defaultTimeout}
+	}
+	if config.RefreshInterval <= 0 {
+		config.RefreshInterval = defaultRefreshInterval
+	}
+	if config.MinRefreshInterval <= 0 {
+		config.MinRefreshInterval = defaultMinRefreshInterval
+	}
+	if config.ErrorHandler == nil {
+		config.ErrorHandler = writeProblem
+	}
+	if config.Logger == nil {
+		config.Logger = slog.Default()
+	}
+
+	options := []jwt.ParserOption{jwt.WithExpirationRequired(), jwt.WithLeeway(config.Leeway)}
+	if config.Issuer != "" {
+		options = append(options, jwt.WithIssuer(config.Issuer))
+	}
+	if config.Audience != "" {
+		options = append(options, jwt.WithAudience(config.Audience))
+	}
+
+	v := &Verifier{config: config, parser: jwt.NewParser(options...)}
+
+	switch {
+	case config.JWKSURL != "":
+		if u, err := url.Parse(config.JWKSURL); err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("verifier: invalid JWKS URL %q", config.JWKSURL)
+		}
+		v.keys = newKeyCache(config)
+		if err := v.keys.refresh(ctx); err != nil {
+			return nil, err
+		}
+		go v.keys.run(ctx)
+	case config.PublicKey != nil:
+		switch config.PublicKey.(type) {
+		case *ecdsa.PublicKey, *rsa.PublicKey:
+		default:
+			return nil, fmt.Errorf("verifier: unsupported public key type %T", config.PublicKey)
+		}
+	}
+
+	return v, nil
+}
+
+// Verify checks the signature, expiry, issuer, audience and tenant of a
+// token and returns its claims
+func (v *Verifier) Verify(ctx context.Context, token string) (*Claims, error) {
+	claims := &Claims{}
+	_, err := v.parser.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+		return v.key(ctx, token)
+	})
+	if err != nil {
+		if errors.Is(err, ErrUnknownKey) {
+			return nil, ErrUnknownKey
+		}
+		return nil, fmt.Errorf("verifier: invalid token: %w", err)
+	}
+
+	if claims.UserID == 0 || claims.SessionID == "" {
+		return nil, fmt.Errorf("verifier: invalid token: %w", jwt.ErrTokenInvalidClaims)
+	}
+	if v.config.ClientID != 0 && claims.ClientID != v.config.ClientID {
+		return nil, ErrWrongTenant
+	}
+
+	return claims, nil
+}
+
+// key picks the key verifying a token and checks the token is signed with
+// the algorithm of that key
+func (v *Verifier) key(ctx context.Context, token *jwt.Token) (interface{}, error) {
+	var key crypto.PublicKey
+	var algorithm string
+
+	switch {
+	case v.keys != nil:
+		kid, _ := token.Header["kid"].(string)
+		if kid == "" {
+			return nil, ErrUnknownKey
+		}
+		jwk, err := v.keys.get(ctx, kid)
+		if err != nil {
+			return nil, err
+		}
+		key, algorithm = jwk.key, jwk.algorithm
+	case len(v.config.Secret) > 0:
+		if token.Method != jwt.SigningMethodHS256 {
+			return nil, jwt.ErrTokenSignatureInvalid
+		}
+		return v.config.Secret, nil
+	default:
+		key = v.config.PublicKey
+		algorithm = algorithmOf(key)
+	}
+
+	if token.Method.Alg() != algorithm {
+		return nil, jwt.ErrTokenSignatureInvalid
+	}
+	return key, nil
+}
+
+// algorithmOf returns the algorithm SimpleJWT signs with for a key type
+func algorithmOf(key crypto.PublicKey) string {
+	switch key.(type) {
+	case *ecdsa.PublicKey:
+		return jwt.SigningMethodES256.Alg()
+	case *rsa.PublicKey:
+		return jwt.SigningMethodRS256.Alg()
+	default:
+		return ""
+	}
+}
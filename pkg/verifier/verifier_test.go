@@ -0,0 +1,307 @@
+package verifier_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Kantha2004/SimpleJWT/pkg/verifier"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// jwksServer serves the public keys it is given, or fails while failing is
+// set, and counts the requests it answers
+type jwksServer struct {
+	*httptest.Server
+	fetches atomic.Int32
+
+	mu      sync.Mutex
+	keys    []verifier.JSONWebKey
+	failing bool
+}
+
+func newJWKSServer(t *testing.T) *jwksServer {
+	t.Helper()
+
+	s := &jwksServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.fetches.Add(1)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if s.failing {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(verifier.JSONWebKeySet{Keys: s.keys})
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// publish adds the public key of a signing key to the key set
+func (s *jwksServer) publish(t *testing.T, kid string, key crypto.Signer) {
+	t.Helper()
+
+	algorithm := jwt.SigningMethodES256.Alg()
+	if _, ok := key.(*rsa.PrivateKey); ok {
+		algorithm = jwt.SigningMethodRS256.Alg()
+	}
+	jwk, err := verifier.NewJSONWebKey(kid, algorithm, key.Public())
+	if err != nil {
+		t.Fatalf("NewJSONWebKey: %v", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = append(s.keys, jwk)
+}
+
+func (s *jwksServer) setFailing(failing bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failing = failing
+}
+
+func newECKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	return key
+}
+
+func newVerifier(t *testing.T, config verifier.Config) *verifier.Verifier {
+	t.Helper()
+
+	// Canceling stops the background refresh
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	config.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	v, err := verifier.New(ctx, config)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return v
+}
+
+// validClaims are the claims of a token of user 7 of client 42
+func validClaims() *verifier.Claims {
+	now := time.Now()
+	return &verifier.Claims{
+		UserID:    7,
+		ClientID:  42,
+		SessionID: "session",
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    "simplejwt",
+			Audience:  jwt.ClaimStrings{"orders"},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		},
+	}
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, key any, claims jwt.Claims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("SignedString: %v", err)
+	}
+	return signed
+}
+
+func TestUnknownKidRefetchesKeySet(t *testing.T) {
+	server := newJWKSServer(t)
+	first, second := newECKey(t), newECKey(t)
+	server.publish(t, "first", first)
+
+	v := newVerifier(t, verifier.Config{JWKSURL: server.URL, MinRefreshInterval: time.Nanosecond})
+	if n := server.fetches.Load(); n != 1 {
+		t.Fatalf("New fetched the key set %d times, want 1", n)
+	}
+
+	// A key rotated in after the verifier started is fetched on first use
+	server.publish(t, "second", second)
+	if _, err := v.Verify(context.Background(), sign(t, jwt.SigningMethodES256, "second", second, validClaims())); err != nil {
+		t.Fatalf("Verify with a new kid: %v", err)
+	}
+	if _, err := v.Verify(context.Background(), sign(t, jwt.SigningMethodES256, "first", first, validClaims())); err != nil {
+		t.Fatalf("Verify with a known kid: %v", err)
+	}
+	if n := server.fetches.Load(); n != 2 {
+		t.Fatalf("key set fetched %d times, want 2", n)
+	}
+
+	if _, err := v.Verify(context.Background(), sign(t, jwt.SigningMethodES256, "third", newECKey(t), validClaims())); !errors.Is(err, verifier.ErrUnknownKey) {
+		t.Fatalf("Verify with a kid missing after a fetch = %v, want %v", err, verifier.ErrUnknownKey)
+	}
+	if n := server.fetches.Load(); n != 3 {
+		t.Fatalf("key set fetched %d times, want 3", n)
+	}
+}
+
+func TestUnknownKidRefetchIsThrottled(t *testing.T) {
+	server := newJWKSServer(t)
+	server.publish(t, "first", newECKey(t))
+
+	v := newVerifier(t, verifier.Config{JWKSURL: server.URL, MinRefreshInterval: time.Hour})
+
+	// Forged tokens of made-up kids cannot make the verifier fetch the key
+	// set more than once per interval
+	second := newECKey(t)
+	server.publish(t, "second", second)
+	for range 3 {
+		if _, err := v.Verify(context.Background(), sign(t, jwt.SigningMethodES256, "second", second, validClaims())); !errors.Is(err, verifier.ErrUnknownKey) {
+			t.Fatalf("Verify within the interval = %v, want %v", err, verifier.ErrUnknownKey)
+		}
+	}
+	if n := server.fetches.Load(); n != 1 {
+		t.Fatalf("key set fetched %d times, want 1", n)
+	}
+}
+
+func TestFailedRefetchIsNotThrottled(t *testing.T) {
+	const interval = 100 * time.Millisecond
+	server := newJWKSServer(t)
+	server.publish(t, "first", newECKey(t))
+
+	v := newVerifier(t, verifier.Config{JWKSURL: server.URL, MinRefreshInterval: interval})
+	time.Sleep(2 * interval)
+
+	second := newECKey(t)
+	token := sign(t, jwt.SigningMethodES256, "second", second, validClaims())
+
+	server.setFailing(true)
+	if _, err := v.Verify(context.Background(), token); !errors.Is(err, verifier.ErrUnknownKey) {
+		t.Fatalf("Verify while the server fails = %v, want %v", err, verifier.ErrUnknownKey)
+	}
+
+	// The failed fetch does not count as a recent one
+	server.setFailing(false)
+	server.publish(t, "second", second)
+	if _, err := v.Verify(context.Background(), token); err != nil {
+		t.Fatalf("Verify once the server recovered: %v", err)
+	}
+	if n := server.fetches.Load(); n != 3 {
+		t.Fatalf("key set fetched %d times, want 3", n)
+	}
+}
+
+func TestUnknownKidRefetchOutlivesRequest(t *testing.T) {
+	server := newJWKSServer(t)
+	v := newVerifier(t, verifier.Config{JWKSURL: server.URL, MinRefreshInterval: time.Nanosecond})
+
+	key := newECKey(t)
+	server.publish(t, "first", key)
+
+	// The fetch is detached from the request, which has already gone away
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := v.Verify(ctx, sign(t, jwt.SigningMethodES256, "first", key, validClaims())); err != nil {
+		t.Fatalf("Verify with a canceled context: %v", err)
+	}
+}
+
+func TestKeyAlgorithmMismatch(t *testing.T) {
+	server := newJWKSServer(t)
+	ecKey := newECKey(t)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	server.publish(t, "ec", ecKey)
+	server.publish(t, "rsa", rsaKey)
+
+	v := newVerifier(t, verifier.Config{JWKSURL: server.URL, MinRefreshInterval: time.Hour})
+	publicKey, err := json.Marshal(ecKey.Public())
+	if err != nil {
+		t.Fatalf("encoding the public key: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+		want  error
+	}{
+		{"ES256 with the EC key", sign(t, jwt.SigningMethodES256, "ec", ecKey, validClaims()), nil},
+		{"RS256 with the RSA key", sign(t, jwt.SigningMethodRS256, "rsa", rsaKey, validClaims()), nil},
+		{"RS256 with the kid of the EC key", sign(t, jwt.SigningMethodRS256, "ec", rsaKey, validClaims()), jwt.ErrTokenSignatureInvalid},
+		{"ES256 with the kid of the RSA key", sign(t, jwt.SigningMethodES256, "rsa", ecKey, validClaims()), jwt.ErrTokenSignatureInvalid},
+		{"HS256 keyed with the public key", sign(t, jwt.SigningMethodHS256, "ec", publicKey, validClaims()), jwt.ErrTokenSignatureInvalid},
+		{"ES256 with another key", sign(t, jwt.SigningMethodES256, "ec", newECKey(t), validClaims()), jwt.ErrTokenSignatureInvalid},
+		{"no kid", sign(t, jwt.SigningMethodES256, "", ecKey, validClaims()), verifier.ErrUnknownKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := v.Verify(context.Background(), tt.token)
+			if (tt.want == nil) != (err == nil) || (tt.want != nil && !errors.Is(err, tt.want)) {
+				t.Fatalf("Verify = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestClaimsRejection(t *testing.T) {
+	server := newJWKSServer(t)
+	key := newECKey(t)
+	server.publish(t, "key", key)
+
+	v := newVerifier(t, verifier.Config{JWKSURL: server.URL, Issuer: "simplejwt", Audience: "orders", ClientID: 42})
+
+	tests := []struct {
+		name   string
+		change func(*verifier.Claims)
+		want   error
+	}{
+		{"valid", func(*verifier.Claims) {}, nil},
+		{"another issuer", func(c *verifier.Claims) { c.Issuer = "other" }, jwt.ErrTokenInvalidIssuer},
+		{"no issuer", func(c *verifier.Claims) { c.Issuer = "" }, jwt.ErrTokenRequiredClaimMissing},
+		{"another audience", func(c *verifier.Claims) { c.Audience = jwt.ClaimStrings{"billing"} }, jwt.ErrTokenInvalidAudience},
+		{"one of several audiences", func(c *verifier.Claims) { c.Audience = jwt.ClaimStrings{"billing", "orders"} }, nil},
+		{"another client", func(c *verifier.Claims) { c.ClientID = 43 }, verifier.ErrWrongTenant},
+		{"admin user", func(c *verifier.Claims) { c.ClientID = 0 }, verifier.ErrWrongTenant},
+		{"expired", func(c *verifier.Claims) { c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute)) }, jwt.ErrTokenExpired},
+		{"no expiry", func(c *verifier.Claims) { c.ExpiresAt = nil }, jwt.ErrTokenRequiredClaimMissing},
+		{"no session", func(c *verifier.Claims) { c.SessionID = "" }, jwt.ErrTokenInvalidClaims},
+		{"no user", func(c *verifier.Claims) { c.UserID = 0 }, jwt.ErrTokenInvalidClaims},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := validClaims()
+			tt.change(claims)
+
+			got, err := v.Verify(context.Background(), sign(t, jwt.SigningMethodES256, "key", key, claims))
+			if tt.want == nil {
+				if err != nil || got.UserID != claims.UserID || got.ClientID != claims.ClientID {
+					t.Fatalf("Verify = %+v, %v, want the claims", got, err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Verify = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}